@@ -9,12 +9,29 @@ import (
 
 // collections names
 const (
-	AccountsCollection      = "accounts"
-	TokenManagersCollection = "token_managers"
-	RecruitsCollection      = "recruits"
-	IndustriesCollection    = "industries"
-	QuestionsCollection     = "questions"
-	DocumentsCollection     = "documents"
+	AccountsCollection             = "accounts"
+	TokenManagersCollection        = "token_managers"
+	RecruitsCollection             = "recruits"
+	HuntersCollection              = "hunters"
+	IndustriesCollection           = "industries"
+	QuestionsCollection            = "questions"
+	DocumentsCollection            = "documents"
+	CompaniesCollection            = "companies"
+	CompanyInvitesCollection       = "company_invites"
+	VacanciesCollection            = "vacancies"
+	ApplicationsCollection         = "applications"
+	InterviewsCollection           = "interviews"
+	ThreadsCollection              = "threads"
+	MessagesCollection             = "messages"
+	NotificationsCollection        = "notifications"
+	NotificationSettingsCollection = "notification_settings"
+	OutboxCollection               = "outbox"
+	ContactRequestsCollection      = "contact_requests"
+	ContactUnlocksCollection       = "contact_unlocks"
+	CreditsCollection              = "credits"
+	SkillsCollection               = "skills"
+	EndorsementsCollection         = "endorsements"
+	UploadsCollection              = "uploads"
 )
 
 // SetupEnv ...
@@ -0,0 +1,225 @@
+package credits
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	config "../config"
+	db "../database"
+	er "../errors"
+	models "../models"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// prices of premium actions in credits
+const (
+	CostContactRequest = 1
+	CostVacancyPosting = 5
+)
+
+// maxAppendAttempts is how often appending is attempted when another process
+// appended to the same ledger first
+const maxAppendAttempts = 3
+
+// ErrInsufficientCredits is returned when a spend or adjustment would leave a negative balance
+var ErrInsufficientCredits = er.Input("Insufficient credits.")
+
+// errAlreadySpent is returned when credits were spent on the subject of a spend first
+var errAlreadySpent = errors.New("credits: already spent on subject")
+
+// Ledger appends entries to the Hunters' credit ledgers. Appends are serialised by a
+// mutex within the process and by the unique (hunter_id, seq) index across processes,
+// so no two entries can be based on the same balance
+type Ledger struct {
+	crud *db.CRUD
+	mu   sync.Mutex
+}
+
+// NewLedger creates a Ledger
+func NewLedger(crud *db.CRUD) *Ledger {
+	return &Ledger{crud: crud}
+}
+
+// Grant records credits bought by a Hunter
+func (l *Ledger) Grant(hunterID bson.ObjectId, amount int, reason string, by bson.ObjectId) (*models.CreditEntry, error) {
+	return l.append(models.CreditEntry{
+		HunterID:  hunterID,
+		Kind:      models.CreditPurchase,
+		Amount:    amount,
+		Reason:    reason,
+		SubjectID: models.NullObjectID,
+		CreatedBy: by,
+	})
+}
+
+// Adjust corrects a Hunter's balance by the given amount, which may be negative
+func (l *Ledger) Adjust(hunterID bson.ObjectId, amount int, reason string, by bson.ObjectId) (*models.CreditEntry, error) {
+	return l.append(models.CreditEntry{
+		HunterID:  hunterID,
+		Kind:      models.CreditAdjustment,
+		Amount:    amount,
+		Reason:    reason,
+		SubjectID: models.NullObjectID,
+		CreatedBy: by,
+	})
+}
+
+// Spend spends credits on the given subject, credits can only be spent on a subject once
+func (l *Ledger) Spend(hunterID bson.ObjectId, amount int, reason string, subjectID, by bson.ObjectId) (*models.CreditEntry, error) {
+	entry, err := l.append(spendEntry(hunterID, amount, reason, subjectID, by))
+	if err == errAlreadySpent {
+		return nil, er.Input("Credits were already spent on this.")
+	}
+	return entry, err
+}
+
+// SpendOnce spends credits on the given subject unless credits were already spent on it,
+// in which case no entry is made and nil is returned
+func (l *Ledger) SpendOnce(hunterID bson.ObjectId, amount int, reason string, subjectID, by bson.ObjectId) (*models.CreditEntry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.spent(subjectID) {
+		return nil, nil
+	}
+	entry, err := l.appendLocked(spendEntry(hunterID, amount, reason, subjectID, by))
+	if err == errAlreadySpent {
+		return nil, nil
+	}
+	return entry, err
+}
+
+// spendEntry creates the entry of a spend
+func spendEntry(hunterID bson.ObjectId, amount int, reason string, subjectID, by bson.ObjectId) models.CreditEntry {
+	return models.CreditEntry{
+		HunterID:  hunterID,
+		Kind:      models.CreditSpend,
+		Amount:    -amount,
+		Reason:    reason,
+		SubjectID: subjectID,
+		SpendKey:  subjectID,
+		CreatedBy: by,
+	}
+}
+
+// spent checks if credits were spent on the given subject, spends made before
+// they had a spend key are found by their subject
+func (l *Ledger) spent(subjectID bson.ObjectId) bool {
+	_, err := l.crud.FindOne(config.CreditsCollection, bson.M{
+		"kind":       models.CreditSpend,
+		"subject_id": subjectID,
+	})
+	return err == nil
+}
+
+// Refund returns the credits a Hunter spent on the given subject. Subjects are only refunded once
+func (l *Ledger) Refund(hunterID bson.ObjectId, reason string, subjectID, by bson.ObjectId) (*models.CreditEntry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	rawSpends, err := l.crud.FindAll(config.CreditsCollection, bson.M{
+		"hunter_id":  hunterID,
+		"subject_id": subjectID,
+		"kind":       bson.M{"$in": []string{models.CreditSpend, models.CreditRefund}},
+	})
+	if err != nil {
+		return nil, er.Input("Nothing to refund.")
+	}
+	amount := 0
+	for _, raw := range rawSpends {
+		amount -= models.TransformCreditEntry(raw).Amount
+	}
+	if amount <= 0 {
+		return nil, er.Input("Nothing to refund.")
+	}
+
+	return l.appendLocked(models.CreditEntry{
+		HunterID:  hunterID,
+		Kind:      models.CreditRefund,
+		Amount:    amount,
+		Reason:    reason,
+		SubjectID: subjectID,
+		CreatedBy: by,
+	})
+}
+
+// append appends an entry to its Hunter's ledger
+func (l *Ledger) append(entry models.CreditEntry) (*models.CreditEntry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.appendLocked(entry)
+}
+
+// appendLocked appends an entry to its Hunter's ledger, numbering it after the
+// latest entry and rejecting it if the balance would become negative. The caller
+// must hold the mutex
+func (l *Ledger) appendLocked(entry models.CreditEntry) (*models.CreditEntry, error) {
+	if err := entry.OK(); err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		latest, err := Latest(l.crud, entry.HunterID)
+		if err != nil {
+			return nil, err
+		}
+
+		entry.ID = bson.NewObjectId()
+		entry.Seq = latest.Seq + 1
+		entry.Balance = latest.Balance + entry.Amount
+		entry.CreatedAt = time.Now()
+		if entry.Balance < 0 {
+			return nil, ErrInsufficientCredits
+		}
+
+		err = l.crud.Insert(config.CreditsCollection, entry)
+		if err == nil {
+			return &entry, nil
+		}
+		// another process spent on the same subject, or took this seq and the
+		// entry is retried against the new latest entry
+		if mgo.IsDup(err) && entry.Kind == models.CreditSpend && l.spent(entry.SubjectID) {
+			return nil, errAlreadySpent
+		}
+		if !mgo.IsDup(err) || attempt == maxAppendAttempts {
+			log.Println("Failed to append credit entry =>", err)
+			return nil, er.Generic()
+		}
+	}
+}
+
+// Latest returns a Hunter's latest ledger entry, or an empty entry if there are none
+func Latest(crud *db.CRUD, hunterID bson.ObjectId) (models.CreditEntry, error) {
+	rawEntries, _, err := crud.FindPage(config.CreditsCollection, bson.M{"hunter_id": hunterID}, []string{"-seq"}, 0, 1)
+	if err != nil {
+		log.Println("Failed to find latest credit entry =>", err)
+		return models.CreditEntry{}, er.Generic()
+	}
+	if len(rawEntries) == 0 {
+		return models.CreditEntry{}, nil
+	}
+	return models.TransformCreditEntry(rawEntries[0]), nil
+}
+
+// Balance returns a Hunter's current balance
+func Balance(crud *db.CRUD, hunterID bson.ObjectId) (int, error) {
+	latest, err := Latest(crud, hunterID)
+	return latest.Balance, err
+}
+
+// History returns a page of a Hunter's ledger entries, newest first, and their total number
+func History(crud *db.CRUD, hunterID bson.ObjectId, skip, limit int) ([]models.CreditEntry, int, error) {
+	rawEntries, total, err := crud.FindPage(config.CreditsCollection, bson.M{"hunter_id": hunterID}, []string{"-seq"}, skip, limit)
+	if err != nil {
+		log.Println("Failed to find credit entries =>", err)
+		return nil, 0, er.Generic()
+	}
+	entries := make([]models.CreditEntry, 0)
+	for _, raw := range rawEntries {
+		entries = append(entries, models.TransformCreditEntry(raw))
+	}
+	return entries, total, nil
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"os"
+	"reflect"
 
 	er "../errors"
 	"github.com/fatih/structs"
@@ -68,6 +69,44 @@ func (db *CRUD) FindAll(collection string, query bson.M) ([]interface{}, error)
 	return results, err
 }
 
+//FindPage finds a page of matching db entries, sorted by the given mgo
+//style sort fields, along with the total number of matching entries.
+//A limit of 0 returns all entries after skip
+func (db *CRUD) FindPage(collection string, query bson.M, sortFields []string, skip, limit int) ([]interface{}, int, error) {
+	if db.Session == nil { // mock
+
+		// check if collection exists
+		if _, ok := db.TempStorage[collection]; !ok {
+			return nil, 0, er.CRUD(errBadCollection)
+		}
+
+		results := filter(db.TempStorage[collection], matchQuery(query))
+		total := len(results)
+		sortDocs(results, sortFields)
+		if skip > len(results) {
+			skip = len(results)
+		}
+		results = results[skip:]
+		if limit > 0 && limit < len(results) {
+			results = results[:limit]
+		}
+		return results, total, nil
+	}
+
+	db.InitCopy()
+	q := db.CopySession.DB(dbName).C(collection).Find(query)
+	total, err := q.Count()
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(sortFields) > 0 {
+		q = q.Sort(sortFields...)
+	}
+	var results []interface{}
+	err = q.Skip(skip).Limit(limit).All(&results)
+	return results, total, err
+}
+
 //FindOne finds a db entry
 func (db *CRUD) FindOne(collection string, query bson.M) (interface{}, error) {
 	if db.Session == nil { // in the mock
@@ -116,7 +155,7 @@ func (db *CRUD) FindID(collection string, id interface{}) (interface{}, error) {
 	return result, err
 }
 
-//UpdateID updates entry by id
+//UpdateID sets the given fields of the entry with the id, its other fields are kept
 func (db *CRUD) UpdateID(collection string, id bson.ObjectId, updates bson.M) error {
 	if db.Session == nil { // mocking
 
@@ -143,8 +182,67 @@ func (db *CRUD) UpdateID(collection string, id bson.ObjectId, updates bson.M) er
 		return nil
 	}
 
+	// mongo replaces the whole document by an update without operators
+	db.InitCopy()
+	return db.CopySession.DB(dbName).C(collection).UpdateId(id, bson.M{"$set": updates})
+}
+
+//UpdateOne updates the first entry matching the query, it's not found when none does.
+//The update sets fields with $set and appends to array fields with $push, which the mock
+//applies too, so that entries can be changed only while they're in the state they were read in
+func (db *CRUD) UpdateOne(collection string, query bson.M, update bson.M) error {
+	if db.Session == nil { // mocking
+
+		// check if collection exists
+		if _, ok := db.TempStorage[collection]; !ok {
+			return er.CRUD(errBadCollection)
+		}
+
+		// perform update
+		for i, r := range db.TempStorage[collection] {
+			if !matchDoc(r, query) {
+				continue
+			}
+			if sets, ok := update["$set"].(bson.M); ok {
+				for k, v := range sets {
+					r[k] = v
+				}
+			}
+			if pushes, ok := update["$push"].(bson.M); ok {
+				for k, v := range pushes {
+					r[k] = append(asList(r[k]), v)
+				}
+			}
+			db.TempStorage[collection][i] = r
+			return nil
+		}
+		return er.CRUD(errNotFound)
+	}
+
+	db.InitCopy()
+	return db.CopySession.DB(dbName).C(collection).Update(query, update)
+}
+
+//ReplaceID replaces the whole entry with the given id by doc
+func (db *CRUD) ReplaceID(collection string, id bson.ObjectId, doc interface{}) error {
+	if db.Session == nil { // mocking
+
+		// check if collection exists
+		if _, ok := db.TempStorage[collection]; !ok {
+			return er.CRUD(errBadCollection)
+		}
+
+		for i, r := range db.TempStorage[collection] {
+			if r["_id"] == id {
+				db.TempStorage[collection][i] = makeBson(doc)
+				return nil
+			}
+		}
+		return er.CRUD(errNotFound)
+	}
+
 	db.InitCopy()
-	return db.CopySession.DB(dbName).C(collection).UpdateId(id, updates)
+	return db.CopySession.DB(dbName).C(collection).UpdateId(id, doc)
 }
 
 //DeleteID deletes a db entry by id
@@ -177,11 +275,21 @@ func (db *CRUD) DeleteID(collection string, id bson.ObjectId) error {
 	return db.CopySession.DB(dbName).C(collection).RemoveId(id)
 }
 
+//Clone creates a CRUD with its own copy of the db session, for work that runs alongside
+//the requests, which share theirs. Mock CRUDs share their storage
+func (db *CRUD) Clone() *CRUD {
+	clone := &CRUD{TempStorage: db.TempStorage}
+	if db.Session != nil {
+		clone.Session = db.Session.Copy()
+	}
+	return clone
+}
+
 //Close closes both the copy and the original db session
 func (db *CRUD) Close() {
 	if db.Session != nil {
 		db.CloseCopy()
-		db.Close()
+		db.Session.Close()
 		db.Session = nil
 	}
 }
@@ -202,6 +310,22 @@ func makeBson(in interface{}) bson.M {
 	return bson.M(t)
 }
 
+// asList copies an array field into a list that can be appended to, whatever type it was stored with
+func asList(in interface{}) []interface{} {
+	list := make([]interface{}, 0)
+	if in == nil {
+		return list
+	}
+	values := reflect.ValueOf(in)
+	if values.Kind() != reflect.Slice {
+		return list
+	}
+	for i := 0; i < values.Len(); i++ {
+		list = append(list, values.Index(i).Interface())
+	}
+	return list
+}
+
 func filter(in []bson.M, fn func(bson.M) bool) []interface{} {
 	results := make([]interface{}, 0)
 	for _, v := range in {
@@ -235,11 +359,6 @@ func matchQuery(query bson.M) func(bson.M) bool {
 	}
 
 	return func(m bson.M) bool {
-		for k, v := range query {
-			if m[k] != v {
-				return false
-			}
-		}
-		return true
+		return matchDoc(m, query)
 	}
 }
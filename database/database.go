@@ -54,19 +54,167 @@ var collectionIndexes = map[string][]mgo.Index{
 	},
 	config.IndustriesCollection: []mgo.Index{
 		{
-			Key:    []string{"name"},
+			// sub-industries of different industries can share a name
+			Key:    []string{"parent_id", "name"},
 			Unique: true,
 		},
 	},
+	config.RecruitsCollection: []mgo.Index{
+		{
+			Key: []string{"skills.skill_id"},
+		},
+	},
+	config.SkillsCollection: []mgo.Index{
+		{
+			Key:    []string{"industry_id", "name"},
+			Unique: true,
+		},
+	},
+	config.EndorsementsCollection: []mgo.Index{
+		{
+			// a hunter can only endorse a recruit's skill once
+			Key:    []string{"recruit_id", "skill_id", "hunter_id"},
+			Unique: true,
+		},
+		{
+			Key: []string{"skill_id"},
+		},
+	},
 	config.DocumentsCollection: []mgo.Index{
 		{
 			Key:    []string{"url"},
 			Unique: true,
 		},
 	},
+	config.HuntersCollection: []mgo.Index{
+		{
+			Key: []string{"company_id"},
+		},
+	},
+	config.CompaniesCollection: []mgo.Index{
+		{
+			Key:    []string{"registration_number"},
+			Unique: true,
+		},
+	},
+	config.CompanyInvitesCollection: []mgo.Index{
+		{
+			Key: []string{"email", "status"},
+		},
+		{
+			Key: []string{"company_id"},
+		},
+	},
+	config.VacanciesCollection: []mgo.Index{
+		{
+			Key: []string{"company_id"},
+		},
+		{
+			Key: []string{"status", "industry_id", "province"},
+		},
+	},
+	config.ApplicationsCollection: []mgo.Index{
+		{
+			Key:    []string{"vacancy_id", "recruit_id"},
+			Unique: true,
+		},
+		{
+			Key: []string{"recruit_id"},
+		},
+	},
+	config.InterviewsCollection: []mgo.Index{
+		{
+			Key: []string{"application_id", "status"},
+		},
+		{
+			Key: []string{"recruit_id"},
+		},
+		{
+			Key: []string{"company_id", "vacancy_id"},
+		},
+	},
+	config.ThreadsCollection: []mgo.Index{
+		{
+			Key:    []string{"vacancy_id", "recruit_id"},
+			Unique: true,
+		},
+		{
+			Key: []string{"recruit_id", "-updated_at"},
+		},
+		{
+			Key: []string{"company_id", "-updated_at"},
+		},
+	},
+	config.MessagesCollection: []mgo.Index{
+		{
+			Key: []string{"thread_id", "created_at"},
+		},
+	},
+	config.NotificationsCollection: []mgo.Index{
+		{
+			Key: []string{"account_id", "read", "-created_at"},
+		},
+	},
+	config.OutboxCollection: []mgo.Index{
+		{
+			Key: []string{"status", "next_attempt_at"},
+		},
+	},
+	config.NotificationSettingsCollection: []mgo.Index{
+		{
+			Key:    []string{"account_id"},
+			Unique: true,
+		},
+	},
+	config.ContactRequestsCollection: []mgo.Index{
+		{
+			Key:    []string{"recruit_id", "hunter_id"},
+			Unique: true,
+		},
+		{
+			Key: []string{"hunter_id", "-created_at"},
+		},
+	},
+	config.ContactUnlocksCollection: []mgo.Index{
+		{
+			Key: []string{"recruit_id", "-created_at"},
+		},
+	},
+	config.UploadsCollection: []mgo.Index{
+		{
+			Key: []string{"recruit_id", "target"},
+		},
+	},
+	config.CreditsCollection: []mgo.Index{
+		{
+			// prevents two entries from being based on the same balance
+			Key:    []string{"hunter_id", "seq"},
+			Unique: true,
+		},
+		{
+			Key: []string{"subject_id"},
+		},
+		{
+			// credits are spent on a subject once, also when processes spend at the same time
+			Key:    []string{"spend_key"},
+			Unique: true,
+			Sparse: true,
+		},
+	},
+}
+
+// droppedIndexes are indexes that were replaced, by the name mongo gave them
+var droppedIndexes = map[string][]string{
+	config.IndustriesCollection: []string{"name_1"},
 }
 
 func ensureIndexes(session *mgo.Session) {
+	for name, indexes := range droppedIndexes {
+		c := session.DB(os.Getenv("DB_NAME")).C(name)
+		for _, index := range indexes {
+			c.DropIndexName(index)
+		}
+	}
 	for name, indexes := range collectionIndexes {
 		c := session.DB(os.Getenv("DB_NAME")).C(name)
 		for _, index := range indexes {
@@ -0,0 +1,341 @@
+package database
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// The mock evaluates a subset of the mongo query language so that queries
+// built for mgo can be run against TempStorage unchanged. Supported are
+// dotted field paths, implicit equality (matching any element of array
+// fields), bson.RegEx values, the $and, $or and $nor logical operators and
+// the $eq, $ne, $gt, $gte, $lt, $lte, $in, $nin, $regex, $options and
+// $exists field operators.
+
+// lookup resolves a dotted field path within a document. Like mongo, a path
+// that runs into an array of documents resolves to the list of values found in
+// its elements
+func lookup(doc interface{}, path string) (interface{}, bool) {
+	keys := strings.SplitN(path, ".", 2)
+	if m, ok := asMap(doc); ok {
+		value, ok := m[keys[0]]
+		if !ok || len(keys) == 1 {
+			return value, ok
+		}
+		return lookup(value, keys[1])
+	}
+
+	list := reflect.ValueOf(doc)
+	if doc == nil || list.Kind() != reflect.Slice {
+		return nil, false
+	}
+	values := make([]interface{}, 0)
+	for i := 0; i < list.Len(); i++ {
+		value, ok := lookup(list.Index(i).Interface(), path)
+		if !ok {
+			continue
+		}
+		if nested, isList := value.([]interface{}); isList {
+			values = append(values, nested...)
+		} else {
+			values = append(values, value)
+		}
+	}
+	return values, len(values) > 0
+}
+
+// matchDoc checks if a document satisfies a query
+func matchDoc(doc bson.M, query bson.M) bool {
+	for key, cond := range query {
+		switch key {
+		case "$and":
+			for _, sub := range subQueries(cond) {
+				if !matchDoc(doc, sub) {
+					return false
+				}
+			}
+		case "$or":
+			matched := false
+			for _, sub := range subQueries(cond) {
+				if matchDoc(doc, sub) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return false
+			}
+		case "$nor":
+			for _, sub := range subQueries(cond) {
+				if matchDoc(doc, sub) {
+					return false
+				}
+			}
+		default:
+			value, exists := lookup(doc, key)
+			if !matchField(value, exists, cond) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// subQueries converts the operand of a logical operator into a list of queries
+func subQueries(in interface{}) []bson.M {
+	queries := make([]bson.M, 0)
+	switch v := in.(type) {
+	case []bson.M:
+		queries = v
+	case []interface{}:
+		for _, q := range v {
+			if m, ok := asMap(q); ok {
+				queries = append(queries, m)
+			}
+		}
+	}
+	return queries
+}
+
+// asMap converts bson.M and map[string]interface{} values into bson.M, as
+// well as structs that were stored as is by UpdateID
+func asMap(in interface{}) (bson.M, bool) {
+	switch v := in.(type) {
+	case bson.M:
+		return v, true
+	case map[string]interface{}:
+		return bson.M(v), true
+	case time.Time:
+		return nil, false
+	}
+	if in != nil && reflect.TypeOf(in).Kind() == reflect.Struct {
+		return makeBson(in), true
+	}
+	return nil, false
+}
+
+// isOperatorDoc checks if a condition is a document of field operators
+func isOperatorDoc(cond interface{}) (bson.M, bool) {
+	m, ok := asMap(cond)
+	if !ok || len(m) == 0 {
+		return nil, false
+	}
+	for key := range m {
+		if !strings.HasPrefix(key, "$") {
+			return nil, false
+		}
+	}
+	return m, true
+}
+
+// matchField checks if a field value satisfies a condition
+func matchField(value interface{}, exists bool, cond interface{}) bool {
+	ops, ok := isOperatorDoc(cond)
+	if !ok {
+		return matchAny(value, func(v interface{}) bool {
+			return matchValue(v, cond)
+		})
+	}
+
+	for op, operand := range ops {
+		switch op {
+		case "$eq":
+			if !matchAny(value, func(v interface{}) bool { return matchValue(v, operand) }) {
+				return false
+			}
+		case "$ne":
+			if matchAny(value, func(v interface{}) bool { return matchValue(v, operand) }) {
+				return false
+			}
+		case "$gt", "$gte", "$lt", "$lte":
+			if !exists || !matchAny(value, func(v interface{}) bool {
+				c, ok := compare(v, operand)
+				if !ok {
+					return false
+				}
+				switch op {
+				case "$gt":
+					return c > 0
+				case "$gte":
+					return c >= 0
+				case "$lt":
+					return c < 0
+				}
+				return c <= 0
+			}) {
+				return false
+			}
+		case "$in":
+			if !matchIn(value, operand) {
+				return false
+			}
+		case "$nin":
+			if matchIn(value, operand) {
+				return false
+			}
+		case "$regex":
+			options, _ := ops["$options"].(string)
+			pattern, _ := operand.(string)
+			if !matchValue(value, bson.RegEx{Pattern: pattern, Options: options}) {
+				return false
+			}
+		case "$options":
+			// handled along with $regex
+		case "$exists":
+			if want, _ := operand.(bool); want != exists {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// matchIn checks if a field value equals any of the given values
+func matchIn(value interface{}, operand interface{}) bool {
+	list := reflect.ValueOf(operand)
+	if list.Kind() != reflect.Slice {
+		return false
+	}
+	for i := 0; i < list.Len(); i++ {
+		candidate := list.Index(i).Interface()
+		if matchAny(value, func(v interface{}) bool { return matchValue(v, candidate) }) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAny applies fn to a value, or to each of its elements if it is an array
+func matchAny(value interface{}, fn func(interface{}) bool) bool {
+	if fn(value) {
+		return true
+	}
+	if _, isBytes := value.([]byte); isBytes || value == nil {
+		return false
+	}
+	list := reflect.ValueOf(value)
+	if list.Kind() != reflect.Slice {
+		return false
+	}
+	for i := 0; i < list.Len(); i++ {
+		if fn(list.Index(i).Interface()) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchValue checks if a single value equals the expected one
+func matchValue(value, expected interface{}) bool {
+	if re, ok := expected.(bson.RegEx); ok {
+		s, ok := value.(string)
+		if !ok {
+			return false
+		}
+		pattern := re.Pattern
+		if strings.Contains(re.Options, "i") {
+			pattern = "(?i)" + pattern
+		}
+		matched, err := regexp.MatchString(pattern, s)
+		return err == nil && matched
+	}
+	if c, ok := compare(value, expected); ok {
+		return c == 0
+	}
+	return reflect.DeepEqual(value, expected)
+}
+
+// toFloat converts numeric values to float64
+func toFloat(in interface{}) (float64, bool) {
+	switch v := in.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// compare orders two values of comparable types, returning false if they can't be compared
+func compare(a, b interface{}) (int, bool) {
+	if x, ok := toFloat(a); ok {
+		y, ok := toFloat(b)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+		return 0, true
+	}
+
+	switch x := a.(type) {
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	case bson.ObjectId:
+		if y, ok := b.(bson.ObjectId); ok {
+			return strings.Compare(string(x), string(y)), true
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			switch {
+			case x == y:
+				return 0, true
+			case !x:
+				return -1, true
+			}
+			return 1, true
+		}
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			switch {
+			case x.Before(y):
+				return -1, true
+			case x.After(y):
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+	return 0, false
+}
+
+// sortDocs sorts documents by mgo style sort fields, e.g. "-birth_year"
+func sortDocs(docs []interface{}, fields []string) {
+	sort.SliceStable(docs, func(i, j int) bool {
+		for _, field := range fields {
+			desc := strings.HasPrefix(field, "-")
+			field = strings.TrimPrefix(strings.TrimPrefix(field, "-"), "+")
+
+			a, _ := lookup(docs[i], field)
+			b, _ := lookup(docs[j], field)
+			c, ok := compare(a, b)
+			if !ok || c == 0 {
+				continue
+			}
+			if desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+}
@@ -0,0 +1,147 @@
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Calendar methods (RFC 5546)
+const (
+	MethodPublish = "PUBLISH"
+	MethodRequest = "REQUEST"
+	MethodCancel  = "CANCEL"
+)
+
+// Event statuses
+const (
+	StatusTentative = "TENTATIVE"
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+// ProdID identifies the product that created the calendar
+const ProdID = "-//iRecruit//Interviews//EN"
+
+// maxLineLength is the maximum length of a content line in octets, excluding the line break
+const maxLineLength = 75
+
+// timeFormat is the UTC DATE-TIME format
+const timeFormat = "20060102T150405Z"
+
+// Person is an event organizer or attendee
+type Person struct {
+	Name  string
+	Email string
+}
+
+// Event is a VEVENT component
+type Event struct {
+	UID         string
+	Sequence    int
+	Stamp       time.Time
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Location    string
+	Status      string
+	Organizer   *Person
+	Attendees   []Person
+}
+
+// Calendar is a VCALENDAR object
+type Calendar struct {
+	Method string
+	Events []Event
+}
+
+// Bytes encodes the Calendar
+func (c *Calendar) Bytes() []byte {
+	var buf bytes.Buffer
+	w := &writer{&buf}
+
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", ProdID)
+	w.line("CALSCALE", "GREGORIAN")
+	if c.Method != "" {
+		w.line("METHOD", c.Method)
+	}
+	for _, e := range c.Events {
+		w.line("BEGIN", "VEVENT")
+		w.line("UID", escape(e.UID))
+		w.line("SEQUENCE", fmt.Sprint(e.Sequence))
+		w.line("DTSTAMP", formatTime(e.Stamp))
+		w.line("DTSTART", formatTime(e.Start))
+		w.line("DTEND", formatTime(e.End))
+		w.line("SUMMARY", escape(e.Summary))
+		if e.Description != "" {
+			w.line("DESCRIPTION", escape(e.Description))
+		}
+		if e.Location != "" {
+			w.line("LOCATION", escape(e.Location))
+		}
+		if e.Status != "" {
+			w.line("STATUS", e.Status)
+		}
+		if e.Organizer != nil {
+			w.line("ORGANIZER;CN="+paramValue(e.Organizer.Name), "mailto:"+e.Organizer.Email)
+		}
+		for _, a := range e.Attendees {
+			w.line("ATTENDEE;ROLE=REQ-PARTICIPANT;CN="+paramValue(a.Name), "mailto:"+a.Email)
+		}
+		w.line("END", "VEVENT")
+	}
+	w.line("END", "VCALENDAR")
+	return buf.Bytes()
+}
+
+// formatTime formats a time as a UTC DATE-TIME
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeFormat)
+}
+
+// escape escapes a TEXT value
+func escape(s string) string {
+	s = strings.Replace(s, "\\", "\\\\", -1)
+	s = strings.Replace(s, ";", "\\;", -1)
+	s = strings.Replace(s, ",", "\\,", -1)
+	s = strings.Replace(s, "\r\n", "\\n", -1)
+	s = strings.Replace(s, "\n", "\\n", -1)
+	return s
+}
+
+// paramValue quotes a parameter value if it contains characters that aren't allowed unquoted
+func paramValue(s string) string {
+	s = strings.Replace(s, "\"", "'", -1)
+	if strings.ContainsAny(s, ":;,") {
+		return "\"" + s + "\""
+	}
+	return s
+}
+
+// writer writes folded content lines
+type writer struct {
+	buf *bytes.Buffer
+}
+
+// line writes a content line, folding it so that no line is longer than
+// 75 octets and never splitting a multi-byte character
+func (w *writer) line(name, value string) {
+	line := name + ":" + value
+	length := 0
+	for len(line) > 0 {
+		_, size := utf8.DecodeRuneInString(line)
+		if length+size > maxLineLength {
+			w.buf.WriteString("\r\n ")
+			length = 1
+		}
+		w.buf.WriteString(line[:size])
+		line = line[size:]
+		length += size
+	}
+	w.buf.WriteString("\r\n")
+}
@@ -0,0 +1,119 @@
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Message is an email with a plain text and an HTML body
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Sender sends messages
+type Sender interface {
+	Send(msg Message) error
+}
+
+// Validate checks that the message's addresses parse and that none
+// of its headers could be used to inject other headers
+func (m *Message) Validate() error {
+	if _, err := mail.ParseAddress(m.From); err != nil {
+		return fmt.Errorf("invalid from address: %s", err)
+	}
+	if len(m.To) == 0 {
+		return errors.New("no recipients")
+	}
+	for _, to := range m.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return fmt.Errorf("invalid to address: %s", err)
+		}
+	}
+	if strings.ContainsAny(m.Subject, "\r\n") {
+		return errors.New("invalid subject")
+	}
+	return nil
+}
+
+// Recipients returns the bare addresses of the message's recipients
+func (m *Message) Recipients() []string {
+	recipients := make([]string, 0)
+	for _, to := range m.To {
+		if address, err := mail.ParseAddress(to); err == nil {
+			recipients = append(recipients, address.Address)
+		}
+	}
+	return recipients
+}
+
+// Bytes encodes the message as a multipart/alternative MIME message
+func (m *Message) Bytes() ([]byte, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", m.Text},
+		{"text/html; charset=utf-8", m.HTML},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	headers := [][2]string{
+		{"From", m.From},
+		{"To", strings.Join(m.To, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", m.Subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", "<" + bson.NewObjectId().Hex() + "@irecruit>"},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=\"" + parts.Boundary() + "\""},
+	}
+	for _, header := range headers {
+		msg.WriteString(header[0] + ": " + header[1] + "\r\n")
+	}
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+// parseAddress returns the bare address of an RFC 5322 address
+func parseAddress(address string) (string, error) {
+	parsed, err := mail.ParseAddress(address)
+	if err != nil {
+		return "", err
+	}
+	return parsed.Address, nil
+}
@@ -0,0 +1,200 @@
+package mail
+
+import (
+	"log"
+	"os"
+	"time"
+
+	config "../config"
+	db "../database"
+	models "../models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// retry settings of the outbox
+const (
+	// MaxAttempts is the number of times sending an email is attempted before it is marked as failed
+	MaxAttempts = 6
+	// baseBackoff is the wait after the first failed attempt, it doubles after every attempt
+	baseBackoff = time.Minute
+	// maxBackoff is the longest wait between attempts
+	maxBackoff = 2 * time.Hour
+)
+
+// Backoff returns how long to wait after the given number of failed attempts
+func Backoff(attempts int) time.Duration {
+	wait := baseBackoff
+	for i := 1; i < attempts && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > maxBackoff {
+		wait = maxBackoff
+	}
+	return wait
+}
+
+// Outbox stores emails until they have been sent, retrying failed sends with backoff
+type Outbox struct {
+	crud   *db.CRUD
+	sender Sender
+	From   string
+}
+
+// NewOutbox creates an Outbox sending with the sender from the MAIL_FROM address
+func NewOutbox(crud *db.CRUD, sender Sender) *Outbox {
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = "iRecruit <no-reply@irecruit.co.za>"
+	}
+	return &Outbox{crud, sender, from}
+}
+
+// Enqueue renders the named template and stores the email for sending
+func (o *Outbox) Enqueue(to, template string, data interface{}) (*models.Email, error) {
+	content, err := Render(template, data)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	email := models.Email{
+		ID:            bson.NewObjectId(),
+		To:            to,
+		Template:      template,
+		Subject:       content.Subject,
+		Text:          content.Text,
+		HTML:          content.HTML,
+		Status:        models.EmailPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+	if err := email.OK(); err != nil {
+		return nil, err
+	}
+	if err := o.crud.Insert(config.OutboxCollection, email); err != nil {
+		return nil, err
+	}
+	return &email, nil
+}
+
+// Flush attempts to send all pending emails that are due
+func (o *Outbox) Flush() (int, error) {
+	return o.FlushAt(time.Now())
+}
+
+// FlushAt attempts to send all pending emails that are due at the given time,
+// returning the number of emails sent
+func (o *Outbox) FlushAt(now time.Time) (int, error) {
+	defer o.crud.CloseCopy()
+
+	rawEmails, err := o.crud.FindAll(config.OutboxCollection, bson.M{
+		"status":          models.EmailPending,
+		"next_attempt_at": bson.M{"$lte": now},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, raw := range rawEmails {
+		email := models.TransformEmail(raw)
+		updates := o.attempt(&email, now)
+		if err := o.crud.UpdateID(config.OutboxCollection, email.ID, updates); err != nil {
+			return sent, err
+		}
+		if email.Status == models.EmailSent {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// attempt tries to send the email once and returns the resulting updates
+func (o *Outbox) attempt(email *models.Email, now time.Time) bson.M {
+	email.Attempts++
+	err := o.sender.Send(Message{
+		From:    o.From,
+		To:      []string{email.To},
+		Subject: email.Subject,
+		Text:    email.Text,
+		HTML:    email.HTML,
+	})
+
+	switch {
+	case err == nil:
+		email.Status = models.EmailSent
+		email.SentAt = now
+		email.LastError = ""
+	case email.Attempts >= MaxAttempts:
+		log.Printf("Giving up on email %s to %s => %s", email.ID.Hex(), email.To, err)
+		email.Status = models.EmailFailed
+		email.LastError = err.Error()
+	default:
+		email.NextAttemptAt = now.Add(Backoff(email.Attempts))
+		email.LastError = err.Error()
+	}
+
+	return bson.M{
+		"status":          email.Status,
+		"attempts":        email.Attempts,
+		"last_error":      email.LastError,
+		"next_attempt_at": email.NextAttemptAt,
+		"sent_at":         email.SentAt,
+	}
+}
+
+// Run flushes the outbox every interval until stop is closed. It runs alongside
+// the requests, so it uses its own copy of the db session
+func (o *Outbox) Run(interval time.Duration, stop <-chan struct{}) {
+	worker := *o
+	worker.crud = o.crud.Clone()
+	defer worker.crud.Close()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := worker.Flush(); err != nil {
+			log.Println("Failed to flush outbox =>", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// -----------------
+// NotificationBackend
+// -----------------
+
+// NotificationData is the data the notification templates are rendered with
+type NotificationData struct {
+	Name  string
+	Title string
+	Body  string
+}
+
+// NotificationBackend emails notifications through an Outbox
+type NotificationBackend struct {
+	outbox *Outbox
+}
+
+// NewNotificationBackend creates a NotificationBackend
+func NewNotificationBackend(outbox *Outbox) *NotificationBackend {
+	return &NotificationBackend{outbox}
+}
+
+// Deliver queues an email for the notification, new accounts get the welcome email
+func (b *NotificationBackend) Deliver(account models.Account, notification models.Notification) error {
+	template := "notification"
+	if notification.Event == models.EventAccountCreated {
+		template = "welcome"
+	}
+	_, err := b.outbox.Enqueue(account.Email, template, NotificationData{
+		Name:  account.Name,
+		Title: notification.Title,
+		Body:  notification.Body,
+	})
+	return err
+}
@@ -0,0 +1,130 @@
+package mail
+
+import (
+	"io/ioutil"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// -----------------
+// SMTPSender
+// -----------------
+
+// SMTPSender sends messages through an SMTP server
+type SMTPSender struct {
+	Addr string
+	Auth smtp.Auth
+}
+
+// NewSMTPSender creates an SMTPSender, which authenticates if a username is given
+func NewSMTPSender(host, port, username, password string) *SMTPSender {
+	sender := &SMTPSender{Addr: net.JoinHostPort(host, port)}
+	if username != "" {
+		sender.Auth = smtp.PlainAuth("", username, password, host)
+	}
+	return sender
+}
+
+// Send sends the message
+func (s *SMTPSender) Send(msg Message) error {
+	data, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+	from, err := parseAddress(msg.From)
+	if err != nil {
+		return err
+	}
+	return smtp.SendMail(s.Addr, s.Auth, from, msg.Recipients(), data)
+}
+
+// -----------------
+// FileSender
+// -----------------
+
+// FileSender writes messages into a directory as .eml files instead of sending them,
+// for use during development
+type FileSender struct {
+	Dir string
+}
+
+// Send writes the message to a file
+func (s *FileSender) Send(msg Message) error {
+	data, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(s.Dir, bson.NewObjectId().Hex()+".eml")
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// -----------------
+// MemorySender
+// -----------------
+
+// MemorySender keeps messages in memory, for use in tests. While Err is set
+// sending fails with it
+type MemorySender struct {
+	Err error
+
+	mu   sync.Mutex
+	sent []Message
+}
+
+// Send records the message
+func (s *MemorySender) Send(msg Message) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Err != nil {
+		return s.Err
+	}
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+// Sent returns the messages sent so far
+func (s *MemorySender) Sent() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.sent...)
+}
+
+// -----------------
+// configuration
+// -----------------
+
+// NewSenderFromEnv creates the Sender configured by the environment. MAIL_BACKEND
+// selects "smtp", configured by SMTP_HOST, SMTP_PORT, SMTP_USER and SMTP_PASS,
+// or "file", the default, which writes messages into MAIL_DIR
+func NewSenderFromEnv() Sender {
+	switch os.Getenv("MAIL_BACKEND") {
+	case "smtp":
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "25"
+		}
+		return NewSMTPSender(os.Getenv("SMTP_HOST"), port, os.Getenv("SMTP_USER"), os.Getenv("SMTP_PASS"))
+	case "", "file":
+		dir := os.Getenv("MAIL_DIR")
+		if dir == "" {
+			dir = "./outbox"
+		}
+		return &FileSender{Dir: dir}
+	default:
+		log.Printf("Unknown MAIL_BACKEND %q, writing mail to ./outbox instead.", os.Getenv("MAIL_BACKEND"))
+		return &FileSender{Dir: "./outbox"}
+	}
+}
@@ -0,0 +1,93 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+// layout wraps the HTML body of every template
+const layout = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{template "subject" .}}</title></head>
+<body style="font-family: sans-serif; color: #333;">
+{{template "html" .}}
+<p style="color: #999; font-size: small;">You are receiving this email because you have an iRecruit account.</p>
+</body>
+</html>`
+
+// sources holds the subject, plain text and HTML templates of every email
+var sources = map[string][3]string{
+	"welcome": {
+		`Welcome to iRecruit`,
+		`Hi {{.Name}},
+
+Your iRecruit account has been created. Sign in to set up your profile.`,
+		`<p>Hi {{.Name}},</p>
+<p>Your iRecruit account has been created. Sign in to set up your profile.</p>`,
+	},
+	"notification": {
+		`{{.Title}}`,
+		`Hi {{.Name}},
+
+{{.Body}}`,
+		`<p>Hi {{.Name}},</p>
+<p>{{.Body}}</p>`,
+	},
+}
+
+// Template renders an email's subject, plain text and HTML bodies
+type Template struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *htmltemplate.Template
+}
+
+// Content is a rendered email
+type Content struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// templates holds the parsed templates by name
+var templates = make(map[string]*Template)
+
+func init() {
+	for name, source := range sources {
+		t := &Template{
+			subject: texttemplate.Must(texttemplate.New("subject").Parse(source[0])),
+			text:    texttemplate.Must(texttemplate.New("text").Parse(source[1])),
+		}
+		t.html = htmltemplate.Must(htmltemplate.New("layout").Parse(layout))
+		htmltemplate.Must(t.html.New("subject").Parse(source[0]))
+		htmltemplate.Must(t.html.New("html").Parse(source[2]))
+		templates[name] = t
+	}
+}
+
+// Render renders the named template with the given data. Values are
+// HTML-escaped in the HTML body only
+func Render(name string, data interface{}) (*Content, error) {
+	t, ok := templates[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown mail template %q", name)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := t.subject.Execute(&subject, data); err != nil {
+		return nil, err
+	}
+	if err := t.text.Execute(&text, data); err != nil {
+		return nil, err
+	}
+	if err := t.html.Execute(&html, data); err != nil {
+		return nil, err
+	}
+
+	// subjects are single header lines
+	oneLine := strings.Join(strings.Fields(subject.String()), " ")
+	return &Content{oneLine, text.String(), html.String()}, nil
+}
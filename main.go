@@ -11,7 +11,9 @@ import (
 
 	config "./config"
 	db "./database"
+	mail "./mail"
 	mware "./middleware"
+	migrations "./migrations"
 	moc "./mocks"
 	route "./routing"
 	mgo "gopkg.in/mgo.v2"
@@ -63,6 +65,15 @@ func main() {
 		}
 	}()
 
+	// bring stored documents up to date with the models
+	if err := migrations.Run(crud); err != nil {
+		log.Fatal("Failed to migrate the database =>", err)
+	}
+
+	// send queued emails in the background
+	outbox := mail.NewOutbox(crud, mail.NewSenderFromEnv())
+	go outbox.Run(time.Minute, nil)
+
 	// prepare the router
 	router := route.NewRouter(crud, mware.CorsMiddleware, mware.LoggerMiddleware)
 
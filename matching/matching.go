@@ -0,0 +1,167 @@
+package matching
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Score components
+const (
+	ComponentIndustry     = "INDUSTRY"
+	ComponentLocation     = "LOCATION"
+	ComponentAnswers      = "ANSWERS"
+	ComponentAvailability = "AVAILABILITY"
+)
+
+// Candidate availabilities
+const (
+	Available = "AVAILABLE"
+	HasOffer  = "HAS_OFFER"
+	Hired     = "HIRED"
+)
+
+// maxAnswers is the number of answered questions needed for a full answers score
+const maxAnswers = 2
+
+// Weights are the maximum scores of each component
+var Weights = map[string]int{
+	ComponentIndustry:     40,
+	ComponentLocation:     30,
+	ComponentAnswers:      20,
+	ComponentAvailability: 10,
+}
+
+// neighbours lists the provinces sharing a border with each province
+var neighbours = map[string][]string{
+	"GAUTENG":       {"LIMPOPO", "MPUMALANGA", "NORTH_WEST", "FREE_STATE"},
+	"LIMPOPO":       {"GAUTENG", "MPUMALANGA", "NORTH_WEST"},
+	"MPUMALANGA":    {"LIMPOPO", "GAUTENG", "FREE_STATE", "KWAZULU_NATAL"},
+	"NORTH_WEST":    {"LIMPOPO", "GAUTENG", "FREE_STATE", "NORTHERN_CAPE"},
+	"FREE_STATE":    {"GAUTENG", "MPUMALANGA", "KWAZULU_NATAL", "EASTERN_CAPE", "NORTHERN_CAPE", "NORTH_WEST"},
+	"KWAZULU_NATAL": {"MPUMALANGA", "FREE_STATE", "EASTERN_CAPE"},
+	"EASTERN_CAPE":  {"KWAZULU_NATAL", "FREE_STATE", "NORTHERN_CAPE", "WESTERN_CAPE"},
+	"NORTHERN_CAPE": {"NORTH_WEST", "FREE_STATE", "EASTERN_CAPE", "WESTERN_CAPE"},
+	"WESTERN_CAPE":  {"NORTHERN_CAPE", "EASTERN_CAPE"},
+}
+
+// Candidate is a Recruit being matched
+type Candidate struct {
+	Province string
+	City     string
+	// Answers maps industry ids onto the number of questions answered in that industry
+	Answers      map[string]int
+	Availability string
+}
+
+// Opening is a Vacancy being matched
+type Opening struct {
+	IndustryID string
+	Province   string
+	City       string
+}
+
+// Component is the part of a Score contributed by a single criterion
+type Component struct {
+	Name        string
+	Score       int
+	Max         int
+	Explanation string
+}
+
+// Score is how well a Candidate matches an Opening
+type Score struct {
+	Total      int
+	Components []Component
+}
+
+// Match scores a Candidate against an Opening
+func Match(c Candidate, o Opening) Score {
+	components := []Component{
+		industry(c, o),
+		location(c, o),
+		answers(c, o),
+		availability(c),
+	}
+
+	score := Score{Components: components}
+	for _, component := range components {
+		score.Total += component.Score
+	}
+	return score
+}
+
+// industry scores whether the Candidate works in the Opening's industry
+func industry(c Candidate, o Opening) Component {
+	component := Component{Name: ComponentIndustry, Max: Weights[ComponentIndustry]}
+	if c.Answers[o.IndustryID] > 0 {
+		component.Score = component.Max
+		component.Explanation = "Works in the vacancy's industry."
+	} else {
+		component.Explanation = "Works in a different industry."
+	}
+	return component
+}
+
+// location scores how close the Candidate lives to the Opening
+func location(c Candidate, o Opening) Component {
+	component := Component{Name: ComponentLocation, Max: Weights[ComponentLocation]}
+	switch {
+	case c.Province == o.Province && strings.EqualFold(c.City, o.City):
+		component.Score = component.Max
+		component.Explanation = fmt.Sprintf("Lives in %s, where the vacancy is.", c.City)
+	case c.Province == o.Province:
+		component.Score = component.Max * 2 / 3
+		component.Explanation = "Lives in the vacancy's province."
+	case isNeighbour(c.Province, o.Province):
+		component.Score = component.Max / 3
+		component.Explanation = "Lives in a neighbouring province."
+	default:
+		component.Explanation = "Lives far from the vacancy."
+	}
+	return component
+}
+
+// answers scores the questions the Candidate answered in the Opening's industry
+func answers(c Candidate, o Opening) Component {
+	component := Component{Name: ComponentAnswers, Max: Weights[ComponentAnswers]}
+	answered := c.Answers[o.IndustryID]
+	if answered > maxAnswers {
+		answered = maxAnswers
+	}
+	component.Score = component.Max * answered / maxAnswers
+	switch answered {
+	case 0:
+		component.Explanation = "Answered no questions in the vacancy's industry."
+	case 1:
+		component.Explanation = "Answered 1 question in the vacancy's industry."
+	default:
+		component.Explanation = fmt.Sprintf("Answered %d questions in the vacancy's industry.", answered)
+	}
+	return component
+}
+
+// availability scores whether the Candidate is free to take up a position
+func availability(c Candidate) Component {
+	component := Component{Name: ComponentAvailability, Max: Weights[ComponentAvailability]}
+	switch c.Availability {
+	case Hired:
+		component.Explanation = "Was hired for another vacancy."
+	case HasOffer:
+		component.Score = component.Max / 2
+		component.Explanation = "Holds an offer for another vacancy."
+	default:
+		component.Score = component.Max
+		component.Explanation = "Is not committed to another vacancy."
+	}
+	return component
+}
+
+// isNeighbour checks if two provinces share a border
+func isNeighbour(a, b string) bool {
+	for _, n := range neighbours[a] {
+		if n == b {
+			return true
+		}
+	}
+	return false
+}
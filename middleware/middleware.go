@@ -4,6 +4,9 @@ import (
 	"context"
 	"log"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -30,8 +33,9 @@ func LoggerMiddleware(h http.Handler) http.Handler {
 func CorsMiddleware(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, HEAD, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Tus-Resumable, Upload-Length, Upload-Metadata, Upload-Offset, X-HTTP-Method-Override")
+		w.Header().Set("Access-Control-Expose-Headers", "Location, Tus-Resumable, Tus-Version, Tus-Extension, Tus-Max-Size, Upload-Length, Upload-Metadata, Upload-Offset")
 
 		h.ServeHTTP(w, r)
 	})
@@ -43,6 +47,9 @@ type CK string
 //UaKey is lsf
 const UaKey = CK("user_agent")
 
+// LocaleKey holds the locales the request accepts, most preferred first
+const LocaleKey = CK("locale")
+
 //IPKey is asdf
 // const IPKey = CK("ip_address")
 
@@ -50,6 +57,7 @@ const UaKey = CK("user_agent")
 func ReqInfoMiddleware(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := context.WithValue(r.Context(), UaKey, r.Header.Get("User-Agent"))
+		ctx = context.WithValue(ctx, LocaleKey, ParseAcceptLanguage(r.Header.Get("Accept-Language")))
 		// TODO  add ip for logging purposes
 		// ctx = context.WithValue(ctx, IPKey, r.RemoteAddr)
 		// fmt.Println("x-forwarded-for:", r.Header.Get("X-Forwarded-For"))
@@ -59,6 +67,58 @@ func ReqInfoMiddleware(h http.Handler) http.Handler {
 	})
 }
 
+// ParseAcceptLanguage turns an Accept-Language header into a list of locales, most
+// preferred first. Regional locales are followed by their language, so "zu-ZA" accepts "zu" too
+func ParseAcceptLanguage(header string) []string {
+	type accepted struct {
+		locale string
+		q      float64
+	}
+	languages := make([]accepted, 0)
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		locale := strings.ToLower(strings.TrimSpace(fields[0]))
+		if locale == "" || locale == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q > 0 {
+			languages = append(languages, accepted{locale, q})
+		}
+	}
+	sort.SliceStable(languages, func(i, j int) bool { return languages[i].q > languages[j].q })
+
+	locales := make([]string, 0, len(languages))
+	seen := make(map[string]bool)
+	add := func(locale string) {
+		if !seen[locale] {
+			seen[locale] = true
+			locales = append(locales, locale)
+		}
+	}
+	for _, l := range languages {
+		add(l.locale)
+		if i := strings.Index(l.locale, "-"); i > 0 {
+			add(l.locale[:i])
+		}
+	}
+	return locales
+}
+
+// Locales gets the locales a request accepts from its context
+func Locales(ctx context.Context) []string {
+	locales, _ := ctx.Value(LocaleKey).([]string)
+	return locales
+}
+
 //ApplyMiddleware   applies given middleware to router
 func ApplyMiddleware(router http.Handler, middleware ...Middleware) http.Handler {
 	newRouter := router
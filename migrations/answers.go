@@ -0,0 +1,126 @@
+package migrations
+
+import (
+	"log"
+	"strings"
+
+	config "../config"
+	db "../database"
+	models "../models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// legacyQAFields are the fields recruits stored their two answers in, along
+// with a copy of the question's text
+var legacyQAFields = []string{"qa1", "qa2"}
+
+// legacyQuestion is a revision of a Question that legacy answers can be matched to
+type legacyQuestion struct {
+	question models.Question
+	revision int32
+}
+
+// MigrateRecruitAnswers converts the qa1 and qa2 fields of recruits into a list
+// of answers referencing the questions by id. Questions are matched by the
+// wording of their revisions, preferring those in the recruit's industries. Recruits with an answer
+// whose question can't be found are left as they are so no answers are lost,
+// the migration picks them up again once the question exists
+func MigrateRecruitAnswers(crud *db.CRUD) error {
+	rawRecruits, err := crud.FindAll(config.RecruitsCollection, bson.M{"answers": bson.M{"$exists": false}})
+	if err != nil {
+		return err
+	}
+	if len(rawRecruits) == 0 {
+		return nil
+	}
+
+	rawQuestions, err := crud.FindAll(config.QuestionsCollection, nil)
+	if err != nil {
+		return err
+	}
+	questions := make(map[string][]legacyQuestion)
+	for _, raw := range rawQuestions {
+		question := models.TransformQuestion(raw)
+		for _, revision := range question.Revisions {
+			text := strings.TrimSpace(revision.Question)
+			questions[text] = append(questions[text], legacyQuestion{question, revision.Revision})
+		}
+	}
+
+	migrated := 0
+	for _, raw := range rawRecruits {
+		doc := raw.(bson.M)
+		recruit := models.TransformRecruit(doc)
+		answers, ok := legacyAnswers(doc, &recruit, questions)
+		if !ok {
+			continue
+		}
+
+		recruit.Answers = answers
+		if err := crud.ReplaceID(config.RecruitsCollection, recruit.ID, recruit); err != nil {
+			return err
+		}
+		migrated++
+	}
+	log.Printf("Migrated the answers of %d of %d recruits", migrated, len(rawRecruits))
+	return nil
+}
+
+// legacyAnswers converts a recruit's qa1 and qa2 fields into answers, it's not
+// ok when one of the questions can't be found
+func legacyAnswers(doc bson.M, recruit *models.Recruit, questions map[string][]legacyQuestion) ([]models.QA, bool) {
+	answers := make([]models.QA, 0)
+	for _, field := range legacyQAFields {
+		var legacy map[string]interface{}
+		switch v := doc[field].(type) {
+		case bson.M:
+			legacy = v
+		case map[string]interface{}:
+			legacy = v
+		default:
+			continue
+		}
+		text, _ := legacy["question"].(string)
+		answer, _ := legacy["answer"].(string)
+		if strings.TrimSpace(answer) == "" {
+			continue
+		}
+
+		question, found := matchQuestion(questions[strings.TrimSpace(text)], recruit.IndustryIDs)
+		if !found {
+			log.Printf("Recruit %s answered %q which isn't a question, skipping them", recruit.ID.Hex(), text)
+			return nil, false
+		}
+		qa := models.QA{QuestionID: question.question.ID, Revision: question.revision, Answer: answer}
+		if !answered(answers, qa.QuestionID) {
+			answers = append(answers, qa)
+		}
+	}
+	return answers, true
+}
+
+// matchQuestion picks the question in one of the industries if there is one,
+// otherwise the first of the candidates
+func matchQuestion(candidates []legacyQuestion, industryIDs []bson.ObjectId) (legacyQuestion, bool) {
+	if len(candidates) == 0 {
+		return legacyQuestion{}, false
+	}
+	for _, question := range candidates {
+		for _, id := range industryIDs {
+			if question.question.IndustryID == id {
+				return question, true
+			}
+		}
+	}
+	return candidates[0], true
+}
+
+// answered checks if a question is among the answers
+func answered(answers []models.QA, questionID bson.ObjectId) bool {
+	for _, qa := range answers {
+		if qa.QuestionID == questionID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"log"
+
+	db "../database"
+)
+
+// Migration converts stored documents to the shape the current models expect.
+// Migrations run on every start so they need to leave migrated documents alone
+type Migration struct {
+	Name string
+	Run  func(crud *db.CRUD) error
+}
+
+// All are the migrations in the order they run
+var All = []Migration{
+	{Name: "recruit answers", Run: MigrateRecruitAnswers},
+}
+
+// Run runs all migrations, stopping at the first one that fails
+func Run(crud *db.CRUD) error {
+	defer crud.CloseCopy()
+
+	for _, m := range All {
+		if err := m.Run(crud); err != nil {
+			log.Printf("Migration %q failed => %s", m.Name, err)
+			return err
+		}
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"time"
+
 	models "../models"
 	"gopkg.in/mgo.v2/bson"
 )
@@ -34,11 +36,76 @@ var HunterIDs = []bson.ObjectId{
 	bson.NewObjectId(), // sys admin's hunterID
 }
 
+// Companies 2 companies
+var Companies = []models.Company{
+	{
+		ID:                 bson.NewObjectId(),
+		Name:               "Acme Holdings",
+		RegistrationNumber: "2011/123456/07",
+		Province:           "GAUTENG",
+		City:               "Johannesburg",
+	},
+	{
+		ID:                 bson.NewObjectId(),
+		Name:               "Cape Hire",
+		RegistrationNumber: "2015/654321/07",
+		Province:           "WESTERN_CAPE",
+		City:               "Cape Town",
+	},
+}
+
+// Hunters 3 hunter profiles
+var Hunters = []models.Hunter{
+	{ // Companies[0] owner
+		ID:        HunterIDs[0],
+		Phone:     "011 234 5678",
+		Email:     "lisa@acme.co.za",
+		Position:  "Talent Acquisition Manager",
+		CompanyID: Companies[0].ID,
+		Role:      models.CompanyRoleOwner,
+	},
+	{ // Companies[0] recruiter
+		ID:        HunterIDs[1],
+		Phone:     "011 234 5679",
+		Email:     "erin@acme.co.za",
+		Position:  "Recruiter",
+		CompanyID: Companies[0].ID,
+		Role:      models.CompanyRoleRecruiter,
+	},
+	{ // Companies[1] owner
+		ID:        HunterIDs[2],
+		Phone:     "021 234 5670",
+		Email:     "jake@capehire.co.za",
+		Position:  "HR Officer",
+		CompanyID: Companies[1].ID,
+		Role:      models.CompanyRoleOwner,
+	},
+	{ // sysadmin's hunter profile, without a company
+		ID:        HunterIDs[3],
+		Phone:     "014 345 2378",
+		Email:     "thato@irecruit.co.za",
+		Position:  "Administrator",
+		CompanyID: models.NullObjectID,
+	},
+}
+
+// CompanyInvites 1 pending invite from Companies[0] to the sysadmin
+var CompanyInvites = []models.CompanyInvite{
+	{
+		ID:        bson.NewObjectId(),
+		CompanyID: Companies[0].ID,
+		InvitedBy: HunterIDs[0],
+		Email:     "thato@gmail.com",
+		Role:      models.CompanyRoleRecruiter,
+		Status:    models.InvitePending,
+	},
+}
+
 // Recruits 2 recruit profiles
 var Recruits = []models.Recruit{
 	{
 		ID:         bson.NewObjectId(),
-		Province:   "Gauteng",
+		Province:   "GAUTENG",
 		City:       "Pretoria",
 		Gender:     "MALE",
 		Disability: "",
@@ -46,27 +113,60 @@ var Recruits = []models.Recruit{
 		Vid2Url:    "none",
 		Phone:      "012 345 2378",
 		Email:      "mark@gmail.com",
-		Qa1:        models.QA{Question: "What's up?", Answer: "Nothing much."},
-		Qa2:        models.QA{Question: "You good?", Answer: "You know it."},
-		BirthYear:  1985,
+		Answers: []models.QA{
+			{QuestionID: Questions[0].ID, Revision: 1, Answer: "Blue, like the ocean."},
+			{QuestionID: Questions[4].ID, Revision: 1, Answer: "Butternut, I know it's basic."},
+		},
+		BirthYear:   1985,
+		Privacy:     models.DefaultRecruitPrivacy,
+		Skills:      []models.RecruitSkill{{SkillID: Skills[0].ID, Level: 4}},
+		IndustryIDs: []bson.ObjectId{Industries[0].ID},
 	},
 	{
 		ID:         bson.NewObjectId(),
-		Province:   "Gauteng",
+		Province:   "GAUTENG",
 		City:       "Johannesburg",
 		Gender:     "MALE",
-		Disability: "",
+		Disability: "Partial hearing loss",
 		Vid1Url:    "none",
 		Vid2Url:    "none",
 		Phone:      "013 345 2378",
 		Email:      "johndoe@gmail.com",
-		Qa1:        models.QA{Question: "What's in there?", Answer: "I don't know."},
-		Qa2:        models.QA{Question: "Ever seen a turtle without it's shell?", Answer: "Nope."},
-		BirthYear:  1995,
+		Answers: []models.QA{
+			{QuestionID: Questions[3].ID, Revision: 1, Answer: "K, for Kgomotso."},
+			{QuestionID: Questions[1].ID, Revision: 1, Answer: "Anything by Brenda Fassie."},
+		},
+		BirthYear: 1995,
+		Privacy:   models.RecruitPrivacy{AllowRequests: true, SharePhone: true, ShareEmail: false},
+		WorkExperience: []models.WorkExperience{
+			{
+				ID:          bson.NewObjectId(),
+				Employer:    "Pick n Pay",
+				Title:       "Cashier",
+				StartDate:   time.Date(2014, 2, 1, 0, 0, 0, 0, time.UTC),
+				EndDate:     time.Date(2016, 6, 30, 0, 0, 0, 0, time.UTC),
+				Description: "Ran a till and balanced the daily cash-ups.",
+			},
+			{ // current position
+				ID:          bson.NewObjectId(),
+				Employer:    "Vodacom",
+				Title:       "Call Centre Agent",
+				StartDate:   time.Date(2016, 7, 1, 0, 0, 0, 0, time.UTC),
+				Description: "Handle customer billing queries.",
+			},
+		},
+		Education: []models.Education{
+			{ID: bson.NewObjectId(), Institution: "University of Johannesburg", Qualification: "National Diploma in Marketing", NQFLevel: 6, Year: 2016},
+		},
+		Skills: []models.RecruitSkill{
+			{SkillID: Skills[0].ID, Level: 2},
+			{SkillID: Skills[2].ID, Level: 5},
+		},
+		IndustryIDs: []bson.ObjectId{Industries[1].ID},
 	},
 	{ // sysadmin's recruitID
 		ID:         bson.NewObjectId(),
-		Province:   "North West",
+		Province:   "NORTH_WEST",
 		City:       "Mahikeng",
 		Gender:     "MALE",
 		Disability: "",
@@ -74,9 +174,11 @@ var Recruits = []models.Recruit{
 		Vid2Url:    "none",
 		Phone:      "014 345 2378",
 		Email:      "thato@gmail.com",
-		Qa1:        models.QA{Question: "What's this?", Answer: "A dead one of these."},
-		Qa2:        models.QA{Question: "Ever seen a turtle without it's shell?", Answer: "All the time."},
-		BirthYear:  1987,
+		Answers: []models.QA{
+			{QuestionID: Questions[2].ID, Revision: 1, Answer: "Thato, obviously."},
+			{QuestionID: Questions[3].ID, Revision: 1, Answer: "Z, it's the last one I know."},
+		},
+		BirthYear: 1987,
 	},
 }
 
@@ -146,14 +248,39 @@ var Accounts = []models.Account{
 
 // Industries 2 industries
 var Industries = []models.Industry{
-	{ID: bson.NewObjectId(), Name: "Statistics"},
-	{ID: bson.NewObjectId(), Name: "Architecture"},
+	{ID: bson.NewObjectId(), Name: "Statistics", MinAnswers: 1, MaxAnswers: 3},
+	{ID: bson.NewObjectId(), Name: "Architecture", MinAnswers: models.DefaultMinAnswers, MaxAnswers: models.DefaultMaxAnswers},
+}
+
+// Skills 3 skills, industry ids are set by the loader
+var Skills = []models.Skill{
+	{ID: bson.NewObjectId(), Name: "data analysis"}, // Industries[0]
+	{ID: bson.NewObjectId(), Name: "r programming"}, // Industries[0]
+	{ID: bson.NewObjectId(), Name: "autocad"},       // Industries[1]
+}
+
+// Endorsements mock endorsements, hunter and company ids are set by the loader
+var Endorsements = []models.Endorsement{
+	{ // Recruits[0]'s data analysis, by Companies[0] owner
+		ID:        bson.NewObjectId(),
+		RecruitID: Recruits[0].ID,
+		SkillID:   Skills[0].ID,
+		CreatedAt: time.Now().AddDate(0, 0, -1),
+	},
 }
 
 // Questions 5 questions
 var Questions = []models.Question{
 	// Industries[0] questions
-	{ID: bson.NewObjectId(), Question: "What's your favourite colour?"},
+	{ // reworded after Recruits[0] answered it
+		ID:       bson.NewObjectId(),
+		Question: "What's your favourite colour?",
+		Revision: 2,
+		Revisions: []models.QuestionRevision{
+			{Revision: 1, Question: "What's your favorite color?", CreatedAt: time.Date(2019, 1, 7, 9, 0, 0, 0, time.UTC)},
+			{Revision: 2, Question: "What's your favourite colour?", CreatedAt: time.Date(2019, 3, 4, 9, 0, 0, 0, time.UTC)},
+		},
+	},
 	{ID: bson.NewObjectId(), Question: "What's your favourite song?"},
 	{ID: bson.NewObjectId(), Question: "What's your favourite name?"},
 
@@ -171,3 +298,198 @@ var Documents = []models.Document{
 	{ID: bson.NewObjectId(), URL: "http://google.com/d_4", DocType: "QUALIFICATION", OwnerType: "RECRUIT"},
 	{ID: bson.NewObjectId(), URL: "http://google.com/d_5", DocType: "QUALIFICATION", OwnerType: "RECRUIT"},
 }
+
+// Vacancies 3 vacancies
+var Vacancies = []models.Vacancy{
+	{ // Companies[0] open vacancy in Industries[0]
+		ID:             bson.NewObjectId(),
+		Title:          "Junior Data Analyst",
+		Description:    "Crunch numbers for our reporting team.",
+		Province:       "GAUTENG",
+		City:           "Johannesburg",
+		EmploymentType: "FULL_TIME",
+		SalaryMin:      15000,
+		SalaryMax:      22000,
+		ClosingDate:    time.Now().AddDate(0, 1, 0),
+		CreatedAt:      time.Now(),
+		Status:         models.VacancyOpen,
+	},
+	{ // Companies[0] draft vacancy in Industries[1]
+		ID:             bson.NewObjectId(),
+		Title:          "Draughtsman",
+		Description:    "Draw up plans for residential projects.",
+		Province:       "GAUTENG",
+		City:           "Pretoria",
+		EmploymentType: "CONTRACT",
+		SalaryMin:      18000,
+		SalaryMax:      25000,
+		ClosingDate:    time.Now().AddDate(0, 2, 0),
+		CreatedAt:      time.Now(),
+		Status:         models.VacancyDraft,
+	},
+	{ // Companies[1] open vacancy in Industries[0]
+		ID:             bson.NewObjectId(),
+		Title:          "Research Intern",
+		Description:    "Help our survey team capture and clean data.",
+		Province:       "WESTERN_CAPE",
+		City:           "Cape Town",
+		EmploymentType: "INTERNSHIP",
+		SalaryMin:      6000,
+		SalaryMax:      8000,
+		ClosingDate:    time.Now().AddDate(0, 0, 14),
+		CreatedAt:      time.Now(),
+		Status:         models.VacancyOpen,
+	},
+}
+
+// Applications mock applications, vacancy and recruit ids are set by the loader
+var Applications = []models.Application{
+	{ // Recruits[0] applied to Vacancies[0]
+		ID:        bson.NewObjectId(),
+		Stage:     models.StageApplied,
+		History:   []models.StageChange{{Stage: models.StageApplied}},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	},
+	{ // Recruits[1] screened for Vacancies[0]
+		ID:    bson.NewObjectId(),
+		Stage: models.StageScreened,
+		History: []models.StageChange{
+			{Stage: models.StageApplied},
+			{Stage: models.StageScreened, Note: "Strong answers."},
+		},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	},
+	{ // Recruits[0] interviewing for Vacancies[2]
+		ID:    bson.NewObjectId(),
+		Stage: models.StageInterview,
+		History: []models.StageChange{
+			{Stage: models.StageApplied},
+			{Stage: models.StageInterview},
+		},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	},
+}
+
+// Interviews mock interviews, application, vacancy, company, recruit and hunter ids are set by the loader
+var Interviews = []models.Interview{
+	{ // Recruits[0] scheduled interview for Applications[2]
+		ID: bson.NewObjectId(),
+		Slots: []models.InterviewSlot{
+			{Start: time.Now().AddDate(0, 0, 7), End: time.Now().AddDate(0, 0, 7).Add(time.Hour)},
+		},
+		Start:     time.Now().AddDate(0, 0, 7),
+		End:       time.Now().AddDate(0, 0, 7).Add(time.Hour),
+		Location:  "12 Long Street, Cape Town",
+		Notes:     "Bring a copy of your ID.",
+		Status:    models.InterviewScheduled,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	},
+	{ // Recruits[1] proposed interview for Applications[1]
+		ID: bson.NewObjectId(),
+		Slots: []models.InterviewSlot{
+			{Start: time.Now().AddDate(0, 0, 3), End: time.Now().AddDate(0, 0, 3).Add(time.Hour)},
+			{Start: time.Now().AddDate(0, 0, 4), End: time.Now().AddDate(0, 0, 4).Add(time.Hour)},
+		},
+		Location:  "Acme Holdings, Sandton",
+		Status:    models.InterviewProposed,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	},
+}
+
+// Threads mock threads, vacancy, company and recruit ids are set by the loader
+var Threads = []models.Thread{
+	{ // Companies[0] and Recruits[1] about Applications[1]
+		ID:        bson.NewObjectId(),
+		Subject:   "Interview availability",
+		CreatedAt: time.Now().Add(-2 * time.Hour),
+		UpdatedAt: time.Now().Add(-time.Hour),
+	},
+}
+
+// Messages mock messages in Threads[0], thread and sender ids are set by the loader
+var Messages = []models.Message{
+	{ // from Companies[0] owner
+		ID:         bson.NewObjectId(),
+		SenderRole: models.ParticipantHunter,
+		Body:       "Hi, are the proposed interview times suitable?",
+		CreatedAt:  time.Now().Add(-2 * time.Hour),
+	},
+	{ // from Recruits[1]
+		ID:         bson.NewObjectId(),
+		SenderRole: models.ParticipantRecruit,
+		Body:       "The second slot works best for me.",
+		CreatedAt:  time.Now().Add(-time.Hour),
+	},
+}
+
+// Notifications mock notifications for Accounts[0], account and subject ids are set by the loader
+var Notifications = []models.Notification{
+	{
+		ID:        bson.NewObjectId(),
+		Event:     models.EventAccountCreated,
+		Title:     "Welcome to iRecruit",
+		Body:      "Your account has been created.",
+		Read:      true,
+		CreatedAt: time.Now().AddDate(0, -1, 0),
+	},
+	{
+		ID:        bson.NewObjectId(),
+		Event:     models.EventApplicationMoved,
+		Title:     "Application update",
+		Body:      "Your application for Research Intern is now at the interview stage.",
+		CreatedAt: time.Now(),
+	},
+}
+
+// NotificationSettings mock settings for Accounts[1], who doesn't want messages in the app
+var NotificationSettings = []models.NotificationSettings{
+	{
+		ID: bson.NewObjectId(),
+		Preferences: []models.NotificationPreference{
+			{Event: models.EventNewMessage, InApp: false, Email: true},
+		},
+	},
+}
+
+// ContactRequests mock contact requests, recruit, hunter and company ids are set by the loader
+var ContactRequests = []models.ContactRequest{
+	{ // Hunters[0] was granted access to Recruits[0]
+		ID:          bson.NewObjectId(),
+		Message:     "We'd like to discuss the Research Intern position with you.",
+		Status:      models.ContactApproved,
+		Phone:       true,
+		Email:       true,
+		CreatedAt:   time.Now().AddDate(0, 0, -3),
+		RespondedAt: time.Now().AddDate(0, 0, -2),
+	},
+	{ // Hunters[2] is waiting on Recruits[1]
+		ID:        bson.NewObjectId(),
+		Status:    models.ContactPending,
+		CreatedAt: time.Now().AddDate(0, 0, -1),
+	},
+}
+
+// ContactUnlocks mock audit record of ContactRequests[0] being approved, ids are set by the loader
+var ContactUnlocks = []models.ContactUnlock{
+	{
+		ID:        bson.NewObjectId(),
+		Phone:     true,
+		Email:     true,
+		CreatedAt: time.Now().AddDate(0, 0, -2),
+	},
+}
+
+// CreditEntries mock ledger entries: every company hunter bought 10 credits, and
+// Hunters[0] and Hunters[2] paid for ContactRequests. Ids are set by the loader
+var CreditEntries = []models.CreditEntry{
+	{ID: bson.NewObjectId(), Seq: 1, Kind: models.CreditPurchase, Amount: 10, Balance: 10, Reason: "Starter pack"},
+	{ID: bson.NewObjectId(), Seq: 2, Kind: models.CreditSpend, Amount: -1, Balance: 9, Reason: "Contact request"},
+	{ID: bson.NewObjectId(), Seq: 1, Kind: models.CreditPurchase, Amount: 10, Balance: 10, Reason: "Starter pack"},
+	{ID: bson.NewObjectId(), Seq: 1, Kind: models.CreditPurchase, Amount: 10, Balance: 10, Reason: "Starter pack"},
+	{ID: bson.NewObjectId(), Seq: 2, Kind: models.CreditSpend, Amount: -1, Balance: 9, Reason: "Contact request"},
+}
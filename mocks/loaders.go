@@ -2,11 +2,13 @@ package mocks
 
 import (
 	"fmt"
+	"time"
 
 	config "../config"
 	db "../database"
 	models "../models"
 	utils "../utils"
+	"gopkg.in/mgo.v2/bson"
 )
 
 // NewLoadedCRUD returns a crud object loaded with all the data
@@ -17,9 +19,24 @@ func NewLoadedCRUD() *db.CRUD {
 	LoadAccounts(crud)
 	LoadTokenManagers(crud)
 	LoadRecruits(crud)
+	LoadCompanies(crud)
+	LoadHunters(crud)
+	LoadCompanyInvites(crud)
 	LoadIndustries(crud)
 	LoadQuestions(crud)
+	LoadSkills(crud)
+	LoadEndorsements(crud)
 	LoadDocuments(crud)
+	LoadVacancies(crud)
+	LoadApplications(crud)
+	LoadInterviews(crud)
+	LoadThreads(crud)
+	LoadMessages(crud)
+	LoadNotifications(crud)
+	LoadNotificationSettings(crud)
+	LoadContactRequests(crud)
+	LoadContactUnlocks(crud)
+	LoadCreditEntries(crud)
 	return crud
 }
 
@@ -93,6 +110,45 @@ func LoadRecruits(crud *db.CRUD) {
 	}
 }
 
+// LoadCompanies loads mock companies
+func LoadCompanies(crud *db.CRUD) {
+	for i, company := range Companies {
+		// validate before insertion
+		if err := company.OK(); err != nil {
+			fmt.Printf("Mock companies[%v] : %s", i, err.Error())
+			break
+		}
+		Companies[i] = company
+		crud.Insert(config.CompaniesCollection, company)
+	}
+}
+
+// LoadCompanyInvites loads mock company invites
+func LoadCompanyInvites(crud *db.CRUD) {
+	for i, invite := range CompanyInvites {
+		// validate before insertion
+		if err := invite.OK(); err != nil {
+			fmt.Printf("Mock company_invites[%v] : %s", i, err.Error())
+			break
+		}
+		CompanyInvites[i] = invite
+		crud.Insert(config.CompanyInvitesCollection, invite)
+	}
+}
+
+// LoadHunters loads mock hunters
+func LoadHunters(crud *db.CRUD) {
+	for i, hunter := range Hunters {
+		// validate before insertion
+		if err := hunter.OK(); err != nil {
+			fmt.Printf("Mock hunters[%v] : %s", i, err.Error())
+			break
+		}
+		Hunters[i] = hunter
+		crud.Insert(config.HuntersCollection, hunter)
+	}
+}
+
 // LoadIndustries loads mock industries
 func LoadIndustries(crud *db.CRUD) {
 	for i, industry := range Industries {
@@ -112,6 +168,18 @@ func LoadQuestions(crud *db.CRUD) {
 	var numIndustries = len(Industries)
 	for i, q := range Questions {
 		q.IndustryID = Industries[(i % numIndustries)].ID
+		if len(q.Revisions) == 0 {
+			q.Revise(q.Question, time.Now())
+		}
+		if q.Status == "" {
+			q.Status = models.QuestionActive
+		}
+		if q.Weight == 0 {
+			q.Weight = models.DefaultQuestionWeight
+		}
+		if q.Difficulty == "" {
+			q.Difficulty = models.QuestionMedium
+		}
 		// validate before insertion
 		if err := q.OK(); err != nil {
 			fmt.Printf("Mock questions[%v] : %s", i, err.Error())
@@ -123,6 +191,33 @@ func LoadQuestions(crud *db.CRUD) {
 	}
 }
 
+// LoadSkills load mock skills
+func LoadSkills(crud *db.CRUD) {
+	industries := []int{0, 0, 1}
+	for i, skill := range Skills {
+		skill.IndustryID = Industries[industries[i]].ID
+		// validate before insertion
+		if err := skill.OK(); err != nil {
+			fmt.Printf("Mock skills[%v] : %s", i, err.Error())
+			break
+		}
+
+		Skills[i] = skill
+		crud.Insert(config.SkillsCollection, skill)
+	}
+}
+
+// LoadEndorsements load mock endorsements
+func LoadEndorsements(crud *db.CRUD) {
+	crud.Insert(config.EndorsementsCollection)
+	for i, endorsement := range Endorsements {
+		endorsement.HunterID = HunterIDs[0]
+		endorsement.CompanyID = Companies[0].ID
+		Endorsements[i] = endorsement
+		crud.Insert(config.EndorsementsCollection, endorsement)
+	}
+}
+
 // LoadDocuments load mock documents
 func LoadDocuments(crud *db.CRUD) {
 	var numRecruits = len(Recruits)
@@ -138,3 +233,188 @@ func LoadDocuments(crud *db.CRUD) {
 		crud.Insert(config.DocumentsCollection, doc)
 	}
 }
+
+// LoadVacancies load mock vacancies
+func LoadVacancies(crud *db.CRUD) {
+	var numIndustries = len(Industries)
+	var numCompanies = len(Companies)
+	for i, vacancy := range Vacancies {
+		vacancy.IndustryID = Industries[i%numIndustries].ID
+		vacancy.CompanyID = Companies[i/numCompanies].ID
+		vacancy.CreatedBy = HunterIDs[i]
+		// validate before insertion
+		if err := vacancy.OK(); err != nil {
+			fmt.Printf("Mock vacancies[%v] : %s", i, err.Error())
+			break
+		}
+
+		Vacancies[i] = vacancy
+		crud.Insert(config.VacanciesCollection, vacancy)
+	}
+}
+
+// LoadApplications load mock applications
+func LoadApplications(crud *db.CRUD) {
+	vacancies := []int{0, 0, 2}
+	recruits := []int{0, 1, 0}
+	for i, application := range Applications {
+		vacancy := Vacancies[vacancies[i]]
+		application.VacancyID = vacancy.ID
+		application.CompanyID = vacancy.CompanyID
+		application.RecruitID = Recruits[recruits[i]].ID
+		for j := range application.History {
+			application.History[j].ChangedAt = application.CreatedAt
+			if j == 0 {
+				application.History[j].ChangedBy = application.RecruitID
+			} else {
+				application.History[j].ChangedBy = vacancy.CreatedBy
+			}
+		}
+		// validate before insertion
+		if err := application.OK(); err != nil {
+			fmt.Printf("Mock applications[%v] : %s", i, err.Error())
+			break
+		}
+
+		Applications[i] = application
+		crud.Insert(config.ApplicationsCollection, application)
+	}
+}
+
+// LoadInterviews load mock interviews
+func LoadInterviews(crud *db.CRUD) {
+	applications := []int{2, 1}
+	for i, interview := range Interviews {
+		application := Applications[applications[i]]
+		interview.ApplicationID = application.ID
+		interview.VacancyID = application.VacancyID
+		interview.CompanyID = application.CompanyID
+		interview.RecruitID = application.RecruitID
+		for _, vacancy := range Vacancies {
+			if vacancy.ID == application.VacancyID {
+				interview.HunterID = vacancy.CreatedBy
+			}
+		}
+		// validate before insertion
+		if err := interview.OK(); err != nil {
+			fmt.Printf("Mock interviews[%v] : %s", i, err.Error())
+			break
+		}
+
+		Interviews[i] = interview
+		crud.Insert(config.InterviewsCollection, interview)
+	}
+}
+
+// LoadThreads load mock threads, each of which has been read by the Recruit
+func LoadThreads(crud *db.CRUD) {
+	applications := []int{1}
+	for i, thread := range Threads {
+		application := Applications[applications[i]]
+		thread.ApplicationID = application.ID
+		thread.VacancyID = application.VacancyID
+		thread.CompanyID = application.CompanyID
+		thread.RecruitID = application.RecruitID
+		thread.Reads = []models.ThreadRead{}
+		for _, acc := range Accounts {
+			if acc.RecruitID == thread.RecruitID {
+				thread.Reads = append(thread.Reads, models.ThreadRead{AccountID: acc.ID, ReadAt: thread.UpdatedAt})
+			}
+		}
+		// validate before insertion
+		if err := thread.OK(); err != nil {
+			fmt.Printf("Mock threads[%v] : %s", i, err.Error())
+			break
+		}
+
+		Threads[i] = thread
+		crud.Insert(config.ThreadsCollection, thread)
+	}
+}
+
+// LoadMessages load mock messages
+func LoadMessages(crud *db.CRUD) {
+	senders := []int{2, 1}
+	for i, message := range Messages {
+		message.ThreadID = Threads[0].ID
+		message.SenderID = Accounts[senders[i]].ID
+		// validate before insertion
+		if err := message.OK(); err != nil {
+			fmt.Printf("Mock messages[%v] : %s", i, err.Error())
+			break
+		}
+
+		Messages[i] = message
+		crud.Insert(config.MessagesCollection, message)
+	}
+}
+
+// LoadNotifications load mock notifications
+func LoadNotifications(crud *db.CRUD) {
+	subjects := []bson.ObjectId{Accounts[0].ID, Applications[2].ID}
+	for i, notification := range Notifications {
+		notification.AccountID = Accounts[0].ID
+		notification.SubjectID = subjects[i]
+		// validate before insertion
+		if err := notification.OK(); err != nil {
+			fmt.Printf("Mock notifications[%v] : %s", i, err.Error())
+			break
+		}
+
+		Notifications[i] = notification
+		crud.Insert(config.NotificationsCollection, notification)
+	}
+}
+
+// LoadNotificationSettings load mock notification settings
+func LoadNotificationSettings(crud *db.CRUD) {
+	for i, settings := range NotificationSettings {
+		settings.AccountID = Accounts[i+1].ID
+		NotificationSettings[i] = settings
+		crud.Insert(config.NotificationSettingsCollection, settings)
+	}
+}
+
+// LoadContactRequests loads mock contact requests
+func LoadContactRequests(crud *db.CRUD) {
+	targets := []struct{ recruit, hunter int }{{0, 0}, {1, 2}}
+	for i, request := range ContactRequests {
+		request.RecruitID = Recruits[targets[i].recruit].ID
+		request.HunterID = Hunters[targets[i].hunter].ID
+		request.CompanyID = Hunters[targets[i].hunter].CompanyID
+		ContactRequests[i] = request
+		crud.Insert(config.ContactRequestsCollection, request)
+	}
+}
+
+// LoadContactUnlocks loads the mock contact unlock audit records
+func LoadContactUnlocks(crud *db.CRUD) {
+	for i, unlock := range ContactUnlocks {
+		request := ContactRequests[i]
+		unlock.RequestID = request.ID
+		unlock.RecruitID = request.RecruitID
+		unlock.HunterID = request.HunterID
+		unlock.CompanyID = request.CompanyID
+		unlock.GrantedBy = Accounts[0].ID
+		ContactUnlocks[i] = unlock
+		crud.Insert(config.ContactUnlocksCollection, unlock)
+	}
+}
+
+// LoadCreditEntries loads the mock credit ledgers
+func LoadCreditEntries(crud *db.CRUD) {
+	owners := []struct{ hunter, request int }{{0, -1}, {0, 0}, {1, -1}, {2, -1}, {2, 1}}
+	for i, entry := range CreditEntries {
+		owner := owners[i]
+		entry.HunterID = Hunters[owner.hunter].ID
+		entry.SubjectID = models.NullObjectID
+		entry.CreatedBy = Accounts[6].ID // purchases are entered by the sysadmin
+		if owner.request >= 0 {
+			entry.SubjectID = ContactRequests[owner.request].ID
+			entry.CreatedBy = Accounts[owner.hunter+2].ID
+		}
+		entry.CreatedAt = time.Now().AddDate(0, 0, i-10)
+		CreditEntries[i] = entry
+		crud.Insert(config.CreditsCollection, entry)
+	}
+}
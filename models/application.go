@@ -0,0 +1,263 @@
+package models
+
+import (
+	"time"
+
+	er "../errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Application stages
+const (
+	StageApplied   = "APPLIED"
+	StageScreened  = "SCREENED"
+	StageInterview = "INTERVIEW"
+	StageOffer     = "OFFER"
+	StageHired     = "HIRED"
+	StageRejected  = "REJECTED"
+)
+
+// IsApplicationStage checks if given stage is a valid Application stage
+func IsApplicationStage(stage string) bool {
+	return stage == StageApplied ||
+		stage == StageScreened ||
+		stage == StageInterview ||
+		stage == StageOffer ||
+		stage == StageHired ||
+		stage == StageRejected
+}
+
+// DefaultPipeline is the application pipeline of Companies that haven't configured their own
+var DefaultPipeline = Pipeline{
+	{Stage: StageApplied, Next: []string{StageScreened, StageInterview, StageRejected}},
+	{Stage: StageScreened, Next: []string{StageInterview, StageOffer, StageRejected}},
+	{Stage: StageInterview, Next: []string{StageOffer, StageRejected}},
+	{Stage: StageOffer, Next: []string{StageHired, StageRejected}},
+	{Stage: StageHired, Next: []string{}},
+	{Stage: StageRejected, Next: []string{}},
+}
+
+// -----------------
+// Transformer
+// -----------------
+
+// TransformApplication transforms interface into Application model
+func TransformApplication(in interface{}) Application {
+	var application Application
+	switch v := in.(type) {
+	case bson.M:
+		application.ID = v["_id"].(bson.ObjectId)
+		application.VacancyID = v["vacancy_id"].(bson.ObjectId)
+		application.CompanyID = v["company_id"].(bson.ObjectId)
+		application.RecruitID = v["recruit_id"].(bson.ObjectId)
+		application.Stage = v["stage"].(string)
+		application.History = TransformStageChanges(v["history"])
+		application.CreatedAt = v["created_at"].(time.Time)
+		application.UpdatedAt = v["updated_at"].(time.Time)
+
+	case Application:
+		application = v
+	}
+	return application
+}
+
+// TransformPipeline transforms interface into Pipeline model
+func TransformPipeline(in interface{}) Pipeline {
+	pipeline := make(Pipeline, 0)
+	switch v := in.(type) {
+	case []interface{}:
+		for _, s := range v {
+			pipeline = append(pipeline, TransformPipelineStage(s))
+		}
+	case Pipeline:
+		pipeline = append(pipeline, v...)
+	case []PipelineStage:
+		pipeline = append(pipeline, v...)
+	}
+	return pipeline
+}
+
+// TransformPipelineStage transforms interface into PipelineStage model
+func TransformPipelineStage(in interface{}) PipelineStage {
+	var stage PipelineStage
+	switch v := in.(type) {
+	case map[string]interface{}:
+		stage.Stage = v["stage"].(string)
+		stage.Next = asStrings(v["next"])
+	case bson.M:
+		stage.Stage = v["stage"].(string)
+		stage.Next = asStrings(v["next"])
+	case PipelineStage:
+		stage = v
+	}
+	return stage
+}
+
+// TransformStageChange transforms interface into StageChange model
+func TransformStageChange(in interface{}) StageChange {
+	var change StageChange
+	switch v := in.(type) {
+	case map[string]interface{}:
+		change.Stage = v["stage"].(string)
+		change.Note = v["note"].(string)
+		change.ChangedBy = v["changed_by"].(bson.ObjectId)
+		change.ChangedAt = v["changed_at"].(time.Time)
+	case bson.M:
+		change.Stage = v["stage"].(string)
+		change.Note = v["note"].(string)
+		change.ChangedBy = v["changed_by"].(bson.ObjectId)
+		change.ChangedAt = v["changed_at"].(time.Time)
+	case StageChange:
+		change = v
+	}
+	return change
+}
+
+// TransformStageChanges transforms interface into a list of StageChange models
+func TransformStageChanges(in interface{}) []StageChange {
+	changes := make([]StageChange, 0)
+	switch v := in.(type) {
+	case []interface{}:
+		for _, c := range v {
+			changes = append(changes, TransformStageChange(c))
+		}
+	case []StageChange:
+		changes = append(changes, v...)
+	}
+	return changes
+}
+
+// -----------------
+// Model
+// -----------------
+
+// StageChange records an Application being moved into a stage
+type StageChange struct {
+	Stage     string        `json:"stage" bson:"stage"`
+	Note      string        `json:"note" bson:"note"`
+	ChangedBy bson.ObjectId `json:"changed_by" bson:"changed_by"`
+	ChangedAt time.Time     `json:"changed_at" bson:"changed_at"`
+}
+
+// PipelineStage is a stage of an application pipeline along with the stages
+// Applications in it may be moved to, stages without any are final
+type PipelineStage struct {
+	Stage string   `json:"stage" bson:"stage"`
+	Next  []string `json:"next" bson:"next"`
+}
+
+// Pipeline is the stages a Company moves its Applications through
+type Pipeline []PipelineStage
+
+// OK validates the Pipeline. Applications start out APPLIED and end up HIRED or
+// REJECTED, so those stages are required and are the only final ones
+func (p Pipeline) OK() error {
+	stages := make(map[string]bool)
+	for _, s := range p {
+		if !IsApplicationStage(s.Stage) || stages[s.Stage] {
+			return er.InvalidField("stage")
+		}
+		stages[s.Stage] = true
+	}
+	if !stages[StageApplied] || !stages[StageHired] || !stages[StageRejected] {
+		return er.Input("Pipelines need the APPLIED, HIRED and REJECTED stages.")
+	}
+	for _, s := range p {
+		final := s.Stage == StageHired || s.Stage == StageRejected
+		if final != (len(s.Next) == 0) {
+			return er.Input("Only the HIRED and REJECTED stages are final.")
+		}
+		for _, next := range s.Next {
+			if !stages[next] || next == s.Stage {
+				return er.InvalidField("next")
+			}
+		}
+	}
+	return nil
+}
+
+// Has checks if the Pipeline has the given stage
+func (p Pipeline) Has(stage string) bool {
+	for _, s := range p {
+		if s.Stage == stage {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinal checks if Applications in the given stage are closed
+func (p Pipeline) IsFinal(stage string) bool {
+	for _, s := range p {
+		if s.Stage == stage {
+			return len(s.Next) == 0
+		}
+	}
+	return true
+}
+
+// CanMove checks if Applications may be moved from one stage into another
+func (p Pipeline) CanMove(from, to string) bool {
+	for _, s := range p {
+		if s.Stage != from {
+			continue
+		}
+		for _, next := range s.Next {
+			if next == to {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Application is a Recruit's application to a Vacancy
+type Application struct {
+	ID        bson.ObjectId `json:"id" bson:"_id"`
+	VacancyID bson.ObjectId `json:"vacancy_id" bson:"vacancy_id"`
+	CompanyID bson.ObjectId `json:"company_id" bson:"company_id"`
+	RecruitID bson.ObjectId `json:"recruit_id" bson:"recruit_id"`
+	Stage     string        `json:"stage" bson:"stage"`
+	History   []StageChange `json:"history" bson:"history"`
+	CreatedAt time.Time     `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at" bson:"updated_at"`
+}
+
+// OK validates Application fields
+func (a *Application) OK() error {
+	if a.VacancyID == "" {
+		return er.InvalidField("vacancy_id")
+	}
+	if a.CompanyID == "" {
+		return er.InvalidField("company_id")
+	}
+	if a.RecruitID == "" {
+		return er.InvalidField("recruit_id")
+	}
+	if !IsApplicationStage(a.Stage) {
+		return er.InvalidField("stage")
+	}
+	if len(a.History) == 0 {
+		return er.InvalidField("history")
+	}
+	return nil
+}
+
+// MoveTo moves the Application into the given stage of its Company's pipeline, recording
+// the change in its history
+func (a *Application) MoveTo(pipeline Pipeline, stage, note string, by bson.ObjectId) error {
+	if !pipeline.CanMove(a.Stage, stage) {
+		return er.Input("Application cannot be moved from " + a.Stage + " to " + stage + ".")
+	}
+
+	now := time.Now()
+	a.Stage = stage
+	a.UpdatedAt = now
+	a.History = append(a.History, StageChange{
+		Stage:     stage,
+		Note:      note,
+		ChangedBy: by,
+		ChangedAt: now,
+	})
+	return nil
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"strings"
+
+	er "../errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Company roles a Hunter can have within a Company
+const (
+	CompanyRoleOwner     = "OWNER"
+	CompanyRoleRecruiter = "RECRUITER"
+	CompanyRoleViewer    = "VIEWER"
+)
+
+// IsCompanyRole checks if given role is a valid company role
+func IsCompanyRole(role string) bool {
+	return role == CompanyRoleOwner ||
+		role == CompanyRoleRecruiter ||
+		role == CompanyRoleViewer
+}
+
+// -----------------
+// Transformer
+// -----------------
+
+// TransformCompany transforms interface into Company model
+func TransformCompany(in interface{}) Company {
+	var company Company
+	switch v := in.(type) {
+	case bson.M:
+		company.ID = v["_id"].(bson.ObjectId)
+		company.Name = v["name"].(string)
+		company.RegistrationNumber = v["registration_number"].(string)
+		company.Province = v["province"].(string)
+		company.City = v["city"].(string)
+		if pipeline, ok := v["pipeline"]; ok {
+			company.Pipeline = TransformPipeline(pipeline)
+		}
+
+	case Company:
+		company = v
+	}
+	return company
+}
+
+// -----------------
+// Model
+// -----------------
+
+// Company db model
+type Company struct {
+	ID                 bson.ObjectId `json:"id" bson:"_id"`
+	Name               string        `json:"name" bson:"name"`
+	RegistrationNumber string        `json:"registration_number" bson:"registration_number"`
+	Province           string        `json:"province" bson:"province"`
+	City               string        `json:"city" bson:"city"`
+	Pipeline           Pipeline      `json:"pipeline" bson:"pipeline,omitempty"`
+}
+
+// ApplicationPipeline is the pipeline the Company moves its Applications through,
+// which is the DefaultPipeline until the Company configures its own
+func (c *Company) ApplicationPipeline() Pipeline {
+	if len(c.Pipeline) == 0 {
+		return DefaultPipeline
+	}
+	return c.Pipeline
+}
+
+// OK validates Company fields
+func (c *Company) OK() error {
+	c.Name = strings.TrimSpace(c.Name)
+	if c.Name == "" {
+		return er.InvalidField("name")
+	}
+	c.RegistrationNumber = strings.TrimSpace(c.RegistrationNumber)
+	if c.RegistrationNumber == "" {
+		return er.InvalidField("registration_number")
+	}
+	if c.Province == "" {
+		return er.InvalidField("province")
+	}
+	if c.City == "" {
+		return er.InvalidField("city")
+	}
+	return nil
+}
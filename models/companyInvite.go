@@ -0,0 +1,69 @@
+package models
+
+import (
+	"strings"
+
+	er "../errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// CompanyInvite statuses
+const (
+	InvitePending  = "PENDING"
+	InviteAccepted = "ACCEPTED"
+	InviteDeclined = "DECLINED"
+)
+
+// -----------------
+// Transformer
+// -----------------
+
+// TransformCompanyInvite transforms interface into CompanyInvite model
+func TransformCompanyInvite(in interface{}) CompanyInvite {
+	var invite CompanyInvite
+	switch v := in.(type) {
+	case bson.M:
+		invite.ID = v["_id"].(bson.ObjectId)
+		invite.CompanyID = v["company_id"].(bson.ObjectId)
+		invite.InvitedBy = v["invited_by"].(bson.ObjectId)
+		invite.Email = v["email"].(string)
+		invite.Role = v["role"].(string)
+		invite.Status = v["status"].(string)
+
+	case CompanyInvite:
+		invite = v
+	}
+	return invite
+}
+
+// -----------------
+// Model
+// -----------------
+
+// CompanyInvite is an invitation for a Hunter, identified by account email, to join a Company
+type CompanyInvite struct {
+	ID        bson.ObjectId `json:"id" bson:"_id"`
+	CompanyID bson.ObjectId `json:"company_id" bson:"company_id"`
+	InvitedBy bson.ObjectId `json:"invited_by" bson:"invited_by"`
+	Email     string        `json:"email" bson:"email"`
+	Role      string        `json:"role" bson:"role"`
+	Status    string        `json:"status" bson:"status"`
+}
+
+// OK validates CompanyInvite fields
+func (i *CompanyInvite) OK() error {
+	if i.CompanyID == "" {
+		return er.InvalidField("company_id")
+	}
+	i.Email = strings.ToLower(strings.TrimSpace(i.Email))
+	if i.Email == "" {
+		return er.InvalidField("email")
+	}
+	if !IsCompanyRole(i.Role) {
+		return er.InvalidField("role")
+	}
+	if i.Status == "" {
+		i.Status = InvitePending
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"strings"
+)
+
+// Profile items that count towards a Recruit's completeness
+const (
+	ProfileVideo1                = "VIDEO_1"
+	ProfileVideo2                = "VIDEO_2"
+	ProfileAnswers               = "ANSWERS"
+	ProfileQualification         = "QUALIFICATION"
+	ProfileVerifiedQualification = "VERIFIED_QUALIFICATION"
+	ProfileWorkExperience        = "WORK_EXPERIENCE"
+	ProfileEducation             = "EDUCATION"
+)
+
+// QualificationDocument is the doc_type of a Document holding a qualification
+const QualificationDocument = "QUALIFICATION"
+
+// profileItems are the items of a Recruit's profile in the order they're
+// reported, with how much each one counts towards completeness
+var profileItems = []struct {
+	item   string
+	weight int
+}{
+	{ProfileVideo1, 20},
+	{ProfileVideo2, 20},
+	{ProfileAnswers, 20},
+	{ProfileWorkExperience, 10},
+	{ProfileEducation, 10},
+	{ProfileQualification, 15},
+	{ProfileVerifiedQualification, 5},
+}
+
+// Completeness is how much of a Recruit's profile has been filled in
+type Completeness struct {
+	Percent int
+	Missing []string
+}
+
+// HasVideo returns whether a video url has been filled in, recruits without
+// a video have it stored as empty or "none"
+func HasVideo(url string) bool {
+	url = strings.TrimSpace(url)
+	return url != "" && !strings.EqualFold(url, "none")
+}
+
+// RecruitCompleteness computes the Completeness of a Recruit's profile from
+// its fields and the documents it owns
+func RecruitCompleteness(recruit Recruit, documents []Document) Completeness {
+	var qualified, verified bool
+	for _, doc := range documents {
+		if doc.OwnerID != recruit.ID || doc.DocType != QualificationDocument {
+			continue
+		}
+		qualified = true
+		verified = verified || doc.Verified
+	}
+
+	filled := map[string]bool{
+		ProfileVideo1:                HasVideo(recruit.Vid1Url),
+		ProfileVideo2:                HasVideo(recruit.Vid2Url),
+		ProfileAnswers:               len(recruit.Answers) > 0,
+		ProfileQualification:         qualified,
+		ProfileVerifiedQualification: verified,
+		ProfileWorkExperience:        len(recruit.WorkExperience) > 0,
+		ProfileEducation:             len(recruit.Education) > 0,
+	}
+
+	completeness := Completeness{Missing: make([]string, 0)}
+	total, score := 0, 0
+	for _, p := range profileItems {
+		total += p.weight
+		if filled[p.item] {
+			score += p.weight
+		} else {
+			completeness.Missing = append(completeness.Missing, p.item)
+		}
+	}
+	completeness.Percent = score * 100 / total
+	return completeness
+}
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"time"
+
+	er "../errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Contact request statuses
+const (
+	ContactPending  = "PENDING"
+	ContactApproved = "APPROVED"
+	ContactDeclined = "DECLINED"
+	ContactRevoked  = "REVOKED"
+)
+
+// MaxContactMessageLength is the maximum length of a contact request's message
+const MaxContactMessageLength = 1000
+
+// DefaultRecruitPrivacy are the privacy settings of recruits that haven't changed them
+var DefaultRecruitPrivacy = RecruitPrivacy{
+	AllowRequests: true,
+	SharePhone:    true,
+	ShareEmail:    true,
+}
+
+// -----------------
+// Transformer
+// -----------------
+
+// TransformRecruitPrivacy transforms interface into RecruitPrivacy model
+func TransformRecruitPrivacy(in interface{}) RecruitPrivacy {
+	var privacy RecruitPrivacy
+	switch v := in.(type) {
+	case map[string]interface{}:
+		privacy.AllowRequests = v["allow_requests"].(bool)
+		privacy.SharePhone = v["share_phone"].(bool)
+		privacy.ShareEmail = v["share_email"].(bool)
+	case bson.M:
+		privacy.AllowRequests = v["allow_requests"].(bool)
+		privacy.SharePhone = v["share_phone"].(bool)
+		privacy.ShareEmail = v["share_email"].(bool)
+	case RecruitPrivacy:
+		privacy = v
+	}
+	return privacy
+}
+
+// TransformContactRequest transforms interface into ContactRequest model
+func TransformContactRequest(in interface{}) ContactRequest {
+	var request ContactRequest
+	switch v := in.(type) {
+	case bson.M:
+		request.ID = v["_id"].(bson.ObjectId)
+		request.RecruitID = v["recruit_id"].(bson.ObjectId)
+		request.HunterID = v["hunter_id"].(bson.ObjectId)
+		request.CompanyID = v["company_id"].(bson.ObjectId)
+		request.Message = v["message"].(string)
+		request.Status = v["status"].(string)
+		request.Phone = v["phone"].(bool)
+		request.Email = v["email"].(bool)
+		request.CreatedAt = v["created_at"].(time.Time)
+		request.RespondedAt = v["responded_at"].(time.Time)
+
+	case ContactRequest:
+		request = v
+	}
+	return request
+}
+
+// TransformContactUnlock transforms interface into ContactUnlock model
+func TransformContactUnlock(in interface{}) ContactUnlock {
+	var unlock ContactUnlock
+	switch v := in.(type) {
+	case bson.M:
+		unlock.ID = v["_id"].(bson.ObjectId)
+		unlock.RequestID = v["request_id"].(bson.ObjectId)
+		unlock.RecruitID = v["recruit_id"].(bson.ObjectId)
+		unlock.HunterID = v["hunter_id"].(bson.ObjectId)
+		unlock.CompanyID = v["company_id"].(bson.ObjectId)
+		unlock.GrantedBy = v["granted_by"].(bson.ObjectId)
+		unlock.Phone = v["phone"].(bool)
+		unlock.Email = v["email"].(bool)
+		unlock.CreatedAt = v["created_at"].(time.Time)
+
+	case ContactUnlock:
+		unlock = v
+	}
+	return unlock
+}
+
+// -----------------
+// Model
+// -----------------
+
+// RecruitPrivacy holds a Recruit's privacy settings
+type RecruitPrivacy struct {
+	// AllowRequests is whether hunters may request the Recruit's contact details
+	AllowRequests bool `json:"allow_requests" bson:"allow_requests"`
+	// SharePhone is whether approving a request reveals the phone number
+	SharePhone bool `json:"share_phone" bson:"share_phone"`
+	// ShareEmail is whether approving a request reveals the email address
+	ShareEmail bool `json:"share_email" bson:"share_email"`
+}
+
+// ContactRequest is a Hunter's request for access to a Recruit's contact details
+type ContactRequest struct {
+	ID        bson.ObjectId `json:"id" bson:"_id"`
+	RecruitID bson.ObjectId `json:"recruit_id" bson:"recruit_id"`
+	HunterID  bson.ObjectId `json:"hunter_id" bson:"hunter_id"`
+	CompanyID bson.ObjectId `json:"company_id" bson:"company_id"`
+	Message   string        `json:"message" bson:"message"`
+	Status    string        `json:"status" bson:"status"`
+	// Phone and Email are the details that were unlocked when the request was approved
+	Phone       bool      `json:"phone" bson:"phone"`
+	Email       bool      `json:"email" bson:"email"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	RespondedAt time.Time `json:"responded_at" bson:"responded_at"`
+}
+
+// OK validates ContactRequest fields
+func (r *ContactRequest) OK() error {
+	if len(r.Message) > MaxContactMessageLength {
+		return er.InvalidField("message")
+	}
+	switch r.Status {
+	case ContactPending, ContactApproved, ContactDeclined, ContactRevoked:
+	default:
+		return er.InvalidField("status")
+	}
+	return nil
+}
+
+// ContactUnlock is the audit record of a Hunter being granted a Recruit's contact details
+type ContactUnlock struct {
+	ID        bson.ObjectId `json:"id" bson:"_id"`
+	RequestID bson.ObjectId `json:"request_id" bson:"request_id"`
+	RecruitID bson.ObjectId `json:"recruit_id" bson:"recruit_id"`
+	HunterID  bson.ObjectId `json:"hunter_id" bson:"hunter_id"`
+	CompanyID bson.ObjectId `json:"company_id" bson:"company_id"`
+	// GrantedBy is the ID of the account that approved the request
+	GrantedBy bson.ObjectId `json:"granted_by" bson:"granted_by"`
+	Phone     bool          `json:"phone" bson:"phone"`
+	Email     bool          `json:"email" bson:"email"`
+	CreatedAt time.Time     `json:"created_at" bson:"created_at"`
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"time"
+
+	er "../errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Credit entry kinds
+const (
+	CreditPurchase   = "PURCHASE"
+	CreditAdjustment = "ADJUSTMENT"
+	CreditSpend      = "SPEND"
+	CreditRefund     = "REFUND"
+)
+
+// -----------------
+// Transformer
+// -----------------
+
+// TransformCreditEntry transforms interface into CreditEntry model
+func TransformCreditEntry(in interface{}) CreditEntry {
+	var entry CreditEntry
+	switch v := in.(type) {
+	case bson.M:
+		entry.ID = v["_id"].(bson.ObjectId)
+		entry.HunterID = v["hunter_id"].(bson.ObjectId)
+		entry.Seq = v["seq"].(int)
+		entry.Kind = v["kind"].(string)
+		entry.Amount = v["amount"].(int)
+		entry.Balance = v["balance"].(int)
+		entry.Reason = v["reason"].(string)
+		entry.SubjectID = v["subject_id"].(bson.ObjectId)
+		entry.SpendKey, _ = v["spend_key"].(bson.ObjectId)
+		entry.CreatedBy = v["created_by"].(bson.ObjectId)
+		entry.CreatedAt = v["created_at"].(time.Time)
+
+	case CreditEntry:
+		entry = v
+	}
+	return entry
+}
+
+// -----------------
+// Model
+// -----------------
+
+// CreditEntry is an entry in a Hunter's append-only credit ledger. Entries are numbered
+// per Hunter by Seq, and Balance is the Hunter's balance after the entry
+type CreditEntry struct {
+	ID       bson.ObjectId `json:"id" bson:"_id"`
+	HunterID bson.ObjectId `json:"hunter_id" bson:"hunter_id"`
+	Seq      int           `json:"seq" bson:"seq"`
+	Kind     string        `json:"kind" bson:"kind"`
+	Amount   int           `json:"amount" bson:"amount"`
+	Balance  int           `json:"balance" bson:"balance"`
+	Reason   string        `json:"reason" bson:"reason"`
+	// SubjectID is the ID of what the credits were spent on or refunded for
+	SubjectID bson.ObjectId `json:"subject_id" bson:"subject_id"`
+	// SpendKey is the SubjectID of spends, a unique index on it allows one spend per subject
+	SpendKey bson.ObjectId `json:"-" bson:"spend_key,omitempty"`
+	// CreatedBy is the ID of the account that made the entry
+	CreatedBy bson.ObjectId `json:"created_by" bson:"created_by"`
+	CreatedAt time.Time     `json:"created_at" bson:"created_at"`
+}
+
+// OK validates CreditEntry fields
+func (e *CreditEntry) OK() error {
+	switch e.Kind {
+	case CreditPurchase, CreditRefund:
+		if e.Amount <= 0 {
+			return er.InvalidField("amount")
+		}
+	case CreditSpend:
+		if e.Amount >= 0 {
+			return er.InvalidField("amount")
+		}
+	case CreditAdjustment:
+		if e.Amount == 0 {
+			return er.InvalidField("amount")
+		}
+	default:
+		return er.InvalidField("kind")
+	}
+	if e.Reason == "" {
+		return er.InvalidField("reason")
+	}
+	return nil
+}
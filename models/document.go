@@ -19,6 +19,8 @@ func TransformDocument(in interface{}) Document {
 		document.URL = v["url"].(string)
 		document.DocType = v["doc_type"].(string)
 		document.OwnerType = v["owner_type"].(string)
+		// documents stored before verification was introduced are unverified
+		document.Verified, _ = v["verified"].(bool)
 
 	case Document:
 		document = v
@@ -38,6 +40,7 @@ type Document struct {
 	DocType   string        `json:"doc_type" bson:"doc_type"`
 	OwnerType string        `json:"owner_type"  bson:"owner_type"`
 	OwnerID   bson.ObjectId `json:"owner_id" bson:"owner_id"`
+	Verified  bool          `json:"verified" bson:"verified"`
 }
 
 // OK validates fields of document model
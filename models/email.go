@@ -0,0 +1,78 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	er "../errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Email statuses
+const (
+	EmailPending = "PENDING"
+	EmailSent    = "SENT"
+	EmailFailed  = "FAILED"
+)
+
+// -----------------
+// Transformer
+// -----------------
+
+// TransformEmail transforms interface into Email model
+func TransformEmail(in interface{}) Email {
+	var email Email
+	switch v := in.(type) {
+	case bson.M:
+		email.ID = v["_id"].(bson.ObjectId)
+		email.To = v["to"].(string)
+		email.Template = v["template"].(string)
+		email.Subject = v["subject"].(string)
+		email.Text = v["text"].(string)
+		email.HTML = v["html"].(string)
+		email.Status = v["status"].(string)
+		email.Attempts = v["attempts"].(int)
+		email.LastError = v["last_error"].(string)
+		email.NextAttemptAt = v["next_attempt_at"].(time.Time)
+		email.CreatedAt = v["created_at"].(time.Time)
+		email.SentAt = v["sent_at"].(time.Time)
+
+	case Email:
+		email = v
+	}
+	return email
+}
+
+// -----------------
+// Model
+// -----------------
+
+// Email is a rendered email waiting in, or sent from, the outbox
+type Email struct {
+	ID            bson.ObjectId `json:"id" bson:"_id"`
+	To            string        `json:"to" bson:"to"`
+	Template      string        `json:"template" bson:"template"`
+	Subject       string        `json:"subject" bson:"subject"`
+	Text          string        `json:"text" bson:"text"`
+	HTML          string        `json:"html" bson:"html"`
+	Status        string        `json:"status" bson:"status"`
+	Attempts      int           `json:"attempts" bson:"attempts"`
+	LastError     string        `json:"last_error" bson:"last_error"`
+	NextAttemptAt time.Time     `json:"next_attempt_at" bson:"next_attempt_at"`
+	CreatedAt     time.Time     `json:"created_at" bson:"created_at"`
+	SentAt        time.Time     `json:"sent_at" bson:"sent_at"`
+}
+
+// OK validates Email fields
+func (e *Email) OK() error {
+	if e.To == "" || strings.ContainsAny(e.To, "\r\n") {
+		return er.InvalidField("to")
+	}
+	if e.Subject == "" || strings.ContainsAny(e.Subject, "\r\n") {
+		return er.InvalidField("subject")
+	}
+	if !(e.Status == EmailPending || e.Status == EmailSent || e.Status == EmailFailed) {
+		return er.InvalidField("status")
+	}
+	return nil
+}
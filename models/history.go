@@ -0,0 +1,164 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	er "../errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Limits on a Recruit's work and education history
+const (
+	MaxWorkExperiences       = 20
+	MaxEducations            = 20
+	MaxWorkDescriptionLength = 2000
+)
+
+// NQF levels range from a general certificate (1) to a doctorate (10)
+const (
+	MinNQFLevel = 1
+	MaxNQFLevel = 10
+)
+
+// -----------------
+// Transformer
+// -----------------
+
+// TransformWorkExperience transforms interface into WorkExperience model
+func TransformWorkExperience(in interface{}) WorkExperience {
+	var work WorkExperience
+	switch v := in.(type) {
+	case map[string]interface{}:
+		work = TransformWorkExperience(bson.M(v))
+	case bson.M:
+		work.ID = v["_id"].(bson.ObjectId)
+		work.Employer = v["employer"].(string)
+		work.Title = v["title"].(string)
+		work.StartDate = v["start_date"].(time.Time)
+		work.EndDate = v["end_date"].(time.Time)
+		work.Description = v["description"].(string)
+	case WorkExperience:
+		work = v
+	}
+	return work
+}
+
+// TransformWorkExperiences transforms interface into a list of WorkExperience models
+func TransformWorkExperiences(in interface{}) []WorkExperience {
+	history := make([]WorkExperience, 0)
+	switch v := in.(type) {
+	case []interface{}:
+		for _, w := range v {
+			history = append(history, TransformWorkExperience(w))
+		}
+	case []WorkExperience:
+		history = append(history, v...)
+	}
+	return history
+}
+
+// TransformEducation transforms interface into Education model
+func TransformEducation(in interface{}) Education {
+	var education Education
+	switch v := in.(type) {
+	case map[string]interface{}:
+		education = TransformEducation(bson.M(v))
+	case bson.M:
+		education.ID = v["_id"].(bson.ObjectId)
+		education.Institution = v["institution"].(string)
+		education.Qualification = v["qualification"].(string)
+		nqfLevel, _ := asInt(v["nqf_level"])
+		education.NQFLevel = int32(nqfLevel)
+		year, _ := asInt(v["year"])
+		education.Year = int32(year)
+	case Education:
+		education = v
+	}
+	return education
+}
+
+// TransformEducations transforms interface into a list of Education models
+func TransformEducations(in interface{}) []Education {
+	history := make([]Education, 0)
+	switch v := in.(type) {
+	case []interface{}:
+		for _, e := range v {
+			history = append(history, TransformEducation(e))
+		}
+	case []Education:
+		history = append(history, v...)
+	}
+	return history
+}
+
+// -----------------
+// Model
+// -----------------
+
+// WorkExperience is a position a Recruit held, an EndDate that is zero means
+// the Recruit still holds it
+type WorkExperience struct {
+	ID          bson.ObjectId `json:"id" bson:"_id"`
+	Employer    string        `json:"employer" bson:"employer"`
+	Title       string        `json:"title" bson:"title"`
+	StartDate   time.Time     `json:"start_date" bson:"start_date"`
+	EndDate     time.Time     `json:"end_date" bson:"end_date"`
+	Description string        `json:"description" bson:"description"`
+}
+
+// Current returns whether the Recruit still holds the position
+func (w *WorkExperience) Current() bool {
+	return w.EndDate.IsZero()
+}
+
+// OK validates WorkExperience fields
+func (w *WorkExperience) OK() error {
+	w.Employer = strings.TrimSpace(w.Employer)
+	if w.Employer == "" {
+		return er.InvalidField("employer")
+	}
+	w.Title = strings.TrimSpace(w.Title)
+	if w.Title == "" {
+		return er.InvalidField("title")
+	}
+	now := time.Now()
+	if w.StartDate.IsZero() || w.StartDate.After(now) {
+		return er.InvalidField("start_date")
+	}
+	if !w.Current() && (w.EndDate.Before(w.StartDate) || w.EndDate.After(now)) {
+		return er.InvalidField("end_date")
+	}
+	if len(w.Description) > MaxWorkDescriptionLength {
+		return er.InvalidField("description")
+	}
+	return nil
+}
+
+// Education is a qualification a Recruit obtained
+type Education struct {
+	ID            bson.ObjectId `json:"id" bson:"_id"`
+	Institution   string        `json:"institution" bson:"institution"`
+	Qualification string        `json:"qualification" bson:"qualification"`
+	NQFLevel      int32         `json:"nqf_level" bson:"nqf_level"`
+	Year          int32         `json:"year" bson:"year"`
+}
+
+// OK validates Education fields
+func (e *Education) OK() error {
+	e.Institution = strings.TrimSpace(e.Institution)
+	if e.Institution == "" {
+		return er.InvalidField("institution")
+	}
+	e.Qualification = strings.TrimSpace(e.Qualification)
+	if e.Qualification == "" {
+		return er.InvalidField("qualification")
+	}
+	if e.NQFLevel < MinNQFLevel || e.NQFLevel > MaxNQFLevel {
+		return er.InvalidField("nqf_level")
+	}
+	if e.Year < 1900 || e.Year > int32(time.Now().Year()) {
+		return er.InvalidField("year")
+	}
+	return nil
+}
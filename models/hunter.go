@@ -0,0 +1,60 @@
+package models
+
+import (
+	er "../errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// -----------------
+// Transformer
+// -----------------
+
+// TransformHunter transforms interface into Hunter model
+func TransformHunter(in interface{}) Hunter {
+	var hunter Hunter
+	switch v := in.(type) {
+	case bson.M:
+		hunter.ID = v["_id"].(bson.ObjectId)
+		hunter.Phone = v["phone"].(string)
+		hunter.Email = v["email"].(string)
+		hunter.Position = v["position"].(string)
+		hunter.CompanyID = v["company_id"].(bson.ObjectId)
+		hunter.Role = v["role"].(string)
+
+	case Hunter:
+		hunter = v
+	}
+	return hunter
+}
+
+// -----------------
+// Model
+// -----------------
+
+// Hunter db model
+type Hunter struct {
+	ID       bson.ObjectId `json:"id" bson:"_id"`
+	Phone    string        `json:"phone" bson:"phone"`
+	Email    string        `json:"email" bson:"email"`
+	Position string        `json:"position" bson:"position"`
+
+	CompanyID bson.ObjectId `json:"company_id" bson:"company_id"`
+	Role      string        `json:"role" bson:"role"`
+}
+
+//OK validates Hunter fields
+func (h *Hunter) OK() error {
+	if h.Phone == "" {
+		return er.InvalidField("phone")
+	}
+	if h.Email == "" {
+		return er.InvalidField("email")
+	}
+	if h.Position == "" {
+		return er.InvalidField("position")
+	}
+	if h.CompanyID != "" && h.CompanyID != NullObjectID && !IsCompanyRole(h.Role) {
+		return er.InvalidField("role")
+	}
+	return nil
+}
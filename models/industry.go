@@ -7,6 +7,17 @@ import (
 	"gopkg.in/mgo.v2/bson"
 )
 
+// Industries ask recruits for between MinAnswers and MaxAnswers answers to
+// their questions, industries stored before the limits existed use the defaults
+const (
+	DefaultMinAnswers  = 1
+	DefaultMaxAnswers  = 5
+	MaxIndustryAnswers = 20
+)
+
+// MaxIndustryDepth is how many levels deep industries can be nested, top level industries included
+const MaxIndustryDepth = 5
+
 // -----------------
 // Transformer
 // -----------------
@@ -18,6 +29,18 @@ func TransformIndustry(in interface{}) Industry {
 	case bson.M:
 		industry.ID = v["_id"].(bson.ObjectId)
 		industry.Name = v["name"].(string)
+		if parentID, ok := v["parent_id"].(bson.ObjectId); ok {
+			industry.ParentID = parentID
+		}
+		industry.MinAnswers = DefaultMinAnswers
+		if min, ok := asInt(v["min_answers"]); ok {
+			industry.MinAnswers = int32(min)
+		}
+		industry.MaxAnswers = DefaultMaxAnswers
+		if max, ok := asInt(v["max_answers"]); ok {
+			industry.MaxAnswers = int32(max)
+		}
+		industry.Translations = TransformTranslations(v["translations"])
 
 	case Industry:
 		industry = v
@@ -30,10 +53,14 @@ func TransformIndustry(in interface{}) Industry {
 // Model
 // -----------------
 
-// Industry model
+// Industry model, top level industries have no ParentID. Translations are of the Name
 type Industry struct {
-	ID   bson.ObjectId `json:"id" bson:"_id"`
-	Name string        `json:"name" bson:"name"`
+	ID           bson.ObjectId `json:"id" bson:"_id"`
+	ParentID     bson.ObjectId `json:"parent_id,omitempty" bson:"parent_id,omitempty"`
+	Name         string        `json:"name" bson:"name"`
+	MinAnswers   int32         `json:"min_answers" bson:"min_answers"`
+	MaxAnswers   int32         `json:"max_answers" bson:"max_answers"`
+	Translations []Translation `json:"translations" bson:"translations"`
 }
 
 // OK validate Industry model
@@ -41,6 +68,22 @@ func (i *Industry) OK() error {
 	if i.Name == "" {
 		return er.InvalidField("name")
 	}
+	if i.ParentID != "" && (!i.ParentID.Valid() || i.ParentID == i.ID) {
+		return er.InvalidField("parent_id")
+	}
+	if i.MinAnswers < 1 || i.MinAnswers > MaxIndustryAnswers {
+		return er.InvalidField("min_answers")
+	}
+	if i.MaxAnswers < i.MinAnswers || i.MaxAnswers > MaxIndustryAnswers {
+		return er.InvalidField("max_answers")
+	}
+
+	for j := range i.Translations {
+		if err := i.Translations[j].OK(); err != nil {
+			return err
+		}
+		i.Translations[j].Text = strings.ToLower(i.Translations[j].Text)
+	}
 
 	i.Name = strings.ToLower(i.Name)
 	return nil
@@ -0,0 +1,214 @@
+package models
+
+import (
+	"time"
+
+	er "../errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Interview statuses
+const (
+	InterviewProposed  = "PROPOSED"
+	InterviewScheduled = "SCHEDULED"
+	InterviewCancelled = "CANCELLED"
+)
+
+// MaxInterviewSlots is the maximum number of slots that can be proposed for an Interview
+const MaxInterviewSlots = 10
+
+// -----------------
+// Transformer
+// -----------------
+
+// TransformInterview transforms interface into Interview model
+func TransformInterview(in interface{}) Interview {
+	var interview Interview
+	switch v := in.(type) {
+	case bson.M:
+		interview.ID = v["_id"].(bson.ObjectId)
+		interview.ApplicationID = v["application_id"].(bson.ObjectId)
+		interview.VacancyID = v["vacancy_id"].(bson.ObjectId)
+		interview.CompanyID = v["company_id"].(bson.ObjectId)
+		interview.RecruitID = v["recruit_id"].(bson.ObjectId)
+		interview.HunterID = v["hunter_id"].(bson.ObjectId)
+		interview.Slots = TransformInterviewSlots(v["slots"])
+		interview.Start = v["start"].(time.Time)
+		interview.End = v["end"].(time.Time)
+		interview.Location = v["location"].(string)
+		interview.Notes = v["notes"].(string)
+		interview.Status = v["status"].(string)
+		interview.Sequence = v["sequence"].(int)
+		interview.CreatedAt = v["created_at"].(time.Time)
+		interview.UpdatedAt = v["updated_at"].(time.Time)
+
+	case Interview:
+		interview = v
+	}
+	return interview
+}
+
+// TransformInterviewSlot transforms interface into InterviewSlot model
+func TransformInterviewSlot(in interface{}) InterviewSlot {
+	var slot InterviewSlot
+	switch v := in.(type) {
+	case map[string]interface{}:
+		slot.Start = v["start"].(time.Time)
+		slot.End = v["end"].(time.Time)
+	case bson.M:
+		slot.Start = v["start"].(time.Time)
+		slot.End = v["end"].(time.Time)
+	case InterviewSlot:
+		slot = v
+	}
+	return slot
+}
+
+// TransformInterviewSlots transforms interface into a list of InterviewSlot models
+func TransformInterviewSlots(in interface{}) []InterviewSlot {
+	slots := make([]InterviewSlot, 0)
+	switch v := in.(type) {
+	case []interface{}:
+		for _, s := range v {
+			slots = append(slots, TransformInterviewSlot(s))
+		}
+	case []InterviewSlot:
+		slots = append(slots, v...)
+	}
+	return slots
+}
+
+// -----------------
+// Model
+// -----------------
+
+// InterviewSlot is a time period proposed for an Interview
+type InterviewSlot struct {
+	Start time.Time `json:"start" bson:"start"`
+	End   time.Time `json:"end" bson:"end"`
+}
+
+// OK validates InterviewSlot fields
+func (s *InterviewSlot) OK() error {
+	if s.Start.IsZero() || s.End.IsZero() {
+		return er.Input("Interview slots need a start and an end.")
+	}
+	if !s.End.After(s.Start) {
+		return er.Input("Interview slots must end after they start.")
+	}
+	if s.Start.Before(time.Now()) {
+		return er.Input("Interview slots must be in the future.")
+	}
+	return nil
+}
+
+// Interview is a meeting between a Company and a Recruit about an Application.
+// Hunters propose slots, of which the Recruit accepts one
+type Interview struct {
+	ID            bson.ObjectId   `json:"id" bson:"_id"`
+	ApplicationID bson.ObjectId   `json:"application_id" bson:"application_id"`
+	VacancyID     bson.ObjectId   `json:"vacancy_id" bson:"vacancy_id"`
+	CompanyID     bson.ObjectId   `json:"company_id" bson:"company_id"`
+	RecruitID     bson.ObjectId   `json:"recruit_id" bson:"recruit_id"`
+	HunterID      bson.ObjectId   `json:"hunter_id" bson:"hunter_id"`
+	Slots         []InterviewSlot `json:"slots" bson:"slots"`
+	Start         time.Time       `json:"start" bson:"start"`
+	End           time.Time       `json:"end" bson:"end"`
+	Location      string          `json:"location" bson:"location"`
+	Notes         string          `json:"notes" bson:"notes"`
+	Status        string          `json:"status" bson:"status"`
+	Sequence      int             `json:"sequence" bson:"sequence"`
+	CreatedAt     time.Time       `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at" bson:"updated_at"`
+}
+
+// OK validates Interview fields
+func (i *Interview) OK() error {
+	if i.ApplicationID == "" {
+		return er.InvalidField("application_id")
+	}
+	if i.VacancyID == "" {
+		return er.InvalidField("vacancy_id")
+	}
+	if i.CompanyID == "" {
+		return er.InvalidField("company_id")
+	}
+	if i.RecruitID == "" {
+		return er.InvalidField("recruit_id")
+	}
+	if i.HunterID == "" {
+		return er.InvalidField("hunter_id")
+	}
+	if i.Location == "" {
+		return er.InvalidField("location")
+	}
+	if !(i.Status == InterviewProposed || i.Status == InterviewScheduled || i.Status == InterviewCancelled) {
+		return er.InvalidField("status")
+	}
+	if i.Status == InterviewProposed && len(i.Slots) == 0 {
+		return er.Input("No interview slots given.")
+	}
+	if len(i.Slots) > MaxInterviewSlots {
+		return er.Input("Too many interview slots given.")
+	}
+	if i.Status == InterviewScheduled && !i.End.After(i.Start) {
+		return er.Input("Scheduled interviews need a valid time.")
+	}
+	return nil
+}
+
+// Propose replaces the Interview's slots, e.g. when rescheduling
+func (i *Interview) Propose(slots []InterviewSlot) error {
+	if i.Status == InterviewCancelled {
+		return er.Input("Interview has been cancelled.")
+	}
+	if len(slots) == 0 {
+		return er.Input("No interview slots given.")
+	}
+	for _, slot := range slots {
+		if err := slot.OK(); err != nil {
+			return err
+		}
+	}
+
+	// a previously scheduled interview moves back to being a proposal
+	if i.Status == InterviewScheduled {
+		i.Sequence++
+	}
+	i.Slots = slots
+	i.Start = time.Time{}
+	i.End = time.Time{}
+	i.Status = InterviewProposed
+	i.UpdatedAt = time.Now()
+	return nil
+}
+
+// Accept schedules the Interview for the slot with the given index
+func (i *Interview) Accept(slot int) error {
+	if i.Status != InterviewProposed {
+		return er.Input("Interview is not awaiting a response.")
+	}
+	if slot < 0 || slot >= len(i.Slots) {
+		return er.InvalidField("slot")
+	}
+	if i.Slots[slot].Start.Before(time.Now()) {
+		return er.Input("Interview slot has already passed.")
+	}
+
+	i.Start = i.Slots[slot].Start
+	i.End = i.Slots[slot].End
+	i.Status = InterviewScheduled
+	i.UpdatedAt = time.Now()
+	return nil
+}
+
+// Cancel cancels the Interview
+func (i *Interview) Cancel() error {
+	if i.Status == InterviewCancelled {
+		return er.Input("Interview has already been cancelled.")
+	}
+	i.Status = InterviewCancelled
+	i.Sequence++
+	i.UpdatedAt = time.Now()
+	return nil
+}
@@ -11,3 +11,36 @@ var NullObjectID = bson.NewObjectId()
 type Model interface {
 	OK() error
 }
+
+// asInt reads a number of a stored document. mgo decodes int32 values as int and
+// doubles as float64, while the mock CRUD keeps the types they were stored with
+func asInt(in interface{}) (int64, bool) {
+	switch v := in.(type) {
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case float64:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+// asStrings reads a list of strings of a stored document. mgo decodes arrays as
+// []interface{}, while the mock CRUD keeps the types they were stored with
+func asStrings(in interface{}) []string {
+	list := make([]string, 0)
+	switch v := in.(type) {
+	case []interface{}:
+		for _, s := range v {
+			if s, ok := s.(string); ok {
+				list = append(list, s)
+			}
+		}
+	case []string:
+		list = append(list, v...)
+	}
+	return list
+}
@@ -0,0 +1,190 @@
+package models
+
+import (
+	"time"
+
+	er "../errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Notification events
+const (
+	EventAccountCreated   = "ACCOUNT_CREATED"
+	EventProfileUpdated   = "PROFILE_UPDATED"
+	EventApplicationMoved = "APPLICATION_MOVED"
+	EventDocumentVerified = "DOCUMENT_VERIFIED"
+	EventNewMessage       = "NEW_MESSAGE"
+	EventContactRequested = "CONTACT_REQUESTED"
+	EventContactAnswered  = "CONTACT_ANSWERED"
+)
+
+// NotificationEvents lists all notification events
+var NotificationEvents = []string{
+	EventAccountCreated,
+	EventProfileUpdated,
+	EventApplicationMoved,
+	EventDocumentVerified,
+	EventNewMessage,
+	EventContactRequested,
+	EventContactAnswered,
+}
+
+// emailByDefault lists the events that are emailed unless an account opts out,
+// all other events are only emailed once an account opts in
+var emailByDefault = map[string]bool{
+	EventAccountCreated:   true,
+	EventApplicationMoved: true,
+	EventDocumentVerified: true,
+	EventContactRequested: true,
+}
+
+// -----------------
+// Transformer
+// -----------------
+
+// TransformNotification transforms interface into Notification model
+func TransformNotification(in interface{}) Notification {
+	var notification Notification
+	switch v := in.(type) {
+	case bson.M:
+		notification.ID = v["_id"].(bson.ObjectId)
+		notification.AccountID = v["account_id"].(bson.ObjectId)
+		notification.Event = v["event"].(string)
+		notification.Title = v["title"].(string)
+		notification.Body = v["body"].(string)
+		notification.SubjectID = v["subject_id"].(bson.ObjectId)
+		notification.Read = v["read"].(bool)
+		notification.CreatedAt = v["created_at"].(time.Time)
+
+	case Notification:
+		notification = v
+	}
+	return notification
+}
+
+// TransformNotificationSettings transforms interface into NotificationSettings model
+func TransformNotificationSettings(in interface{}) NotificationSettings {
+	var settings NotificationSettings
+	switch v := in.(type) {
+	case bson.M:
+		settings.ID = v["_id"].(bson.ObjectId)
+		settings.AccountID = v["account_id"].(bson.ObjectId)
+		settings.Preferences = TransformNotificationPreferences(v["preferences"])
+
+	case NotificationSettings:
+		settings = v
+	}
+	return settings
+}
+
+// TransformNotificationPreference transforms interface into NotificationPreference model
+func TransformNotificationPreference(in interface{}) NotificationPreference {
+	var preference NotificationPreference
+	switch v := in.(type) {
+	case map[string]interface{}:
+		preference.Event = v["event"].(string)
+		preference.InApp = v["in_app"].(bool)
+		preference.Email = v["email"].(bool)
+	case bson.M:
+		preference.Event = v["event"].(string)
+		preference.InApp = v["in_app"].(bool)
+		preference.Email = v["email"].(bool)
+	case NotificationPreference:
+		preference = v
+	}
+	return preference
+}
+
+// TransformNotificationPreferences transforms interface into a list of NotificationPreference models
+func TransformNotificationPreferences(in interface{}) []NotificationPreference {
+	preferences := make([]NotificationPreference, 0)
+	switch v := in.(type) {
+	case []interface{}:
+		for _, p := range v {
+			preferences = append(preferences, TransformNotificationPreference(p))
+		}
+	case []NotificationPreference:
+		preferences = append(preferences, v...)
+	}
+	return preferences
+}
+
+// -----------------
+// Model
+// -----------------
+
+// Notification is a message in an account's inbox about something that happened
+type Notification struct {
+	ID        bson.ObjectId `json:"id" bson:"_id"`
+	AccountID bson.ObjectId `json:"account_id" bson:"account_id"`
+	Event     string        `json:"event" bson:"event"`
+	Title     string        `json:"title" bson:"title"`
+	Body      string        `json:"body" bson:"body"`
+	SubjectID bson.ObjectId `json:"subject_id" bson:"subject_id"`
+	Read      bool          `json:"read" bson:"read"`
+	CreatedAt time.Time     `json:"created_at" bson:"created_at"`
+}
+
+// OK validates Notification fields
+func (n *Notification) OK() error {
+	if n.AccountID == "" {
+		return er.InvalidField("account_id")
+	}
+	if !isNotificationEvent(n.Event) {
+		return er.InvalidField("event")
+	}
+	if n.Title == "" {
+		return er.MissingField("title")
+	}
+	return nil
+}
+
+// NotificationPreference holds the channels over which an account is notified of an event
+type NotificationPreference struct {
+	Event string `json:"event" bson:"event"`
+	InApp bool   `json:"in_app" bson:"in_app"`
+	Email bool   `json:"email" bson:"email"`
+}
+
+// NotificationSettings holds an account's notification preferences,
+// events without a preference use the defaults
+type NotificationSettings struct {
+	ID          bson.ObjectId            `json:"id" bson:"_id"`
+	AccountID   bson.ObjectId            `json:"account_id" bson:"account_id"`
+	Preferences []NotificationPreference `json:"preferences" bson:"preferences"`
+}
+
+// Preference returns the account's preference for the event
+func (s *NotificationSettings) Preference(event string) NotificationPreference {
+	for _, preference := range s.Preferences {
+		if preference.Event == event {
+			return preference
+		}
+	}
+	return NotificationPreference{Event: event, InApp: true, Email: emailByDefault[event]}
+}
+
+// SetPreference replaces the account's preference for an event
+func (s *NotificationSettings) SetPreference(preference NotificationPreference) error {
+	if !isNotificationEvent(preference.Event) {
+		return er.InvalidField("event")
+	}
+	for i := range s.Preferences {
+		if s.Preferences[i].Event == preference.Event {
+			s.Preferences[i] = preference
+			return nil
+		}
+	}
+	s.Preferences = append(s.Preferences, preference)
+	return nil
+}
+
+// isNotificationEvent checks whether the event is a known notification event
+func isNotificationEvent(event string) bool {
+	for _, e := range NotificationEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
@@ -1,10 +1,15 @@
 package models
 
 import (
+	"strings"
+
 	er "../errors"
 	"gopkg.in/mgo.v2/bson"
 )
 
+// MaxAnswerLength is the maximum length of an answer to a Question
+const MaxAnswerLength = 2000
+
 // -----------------
 // Transformer
 // -----------------
@@ -14,34 +19,58 @@ func TransformQA(in interface{}) QA {
 	var qa QA
 	switch v := in.(type) {
 	case map[string]interface{}:
-		qa.Question = v["question"].(string)
-		qa.Answer = v["answer"].(string)
+		qa = TransformQA(bson.M(v))
 	case bson.M:
-		qa.Question = v["question"].(string)
+		qa.QuestionID = v["question_id"].(bson.ObjectId)
 		qa.Answer = v["answer"].(string)
+
+		// answers given before questions were versioned answered the first revision
+		qa.Revision = 1
+		if revision, ok := asInt(v["revision"]); ok {
+			qa.Revision = int32(revision)
+		}
 	case QA:
 		qa = v
 	}
 	return qa
 }
 
+// TransformQAs transforms interface into a list of QA models
+func TransformQAs(in interface{}) []QA {
+	qas := make([]QA, 0)
+	switch v := in.(type) {
+	case []interface{}:
+		for _, qa := range v {
+			qas = append(qas, TransformQA(qa))
+		}
+	case []QA:
+		qas = append(qas, v...)
+	}
+	return qas
+}
+
 // -----------------
 // Model
 // -----------------
 
-// QA QuestionAnswer db model
+// QA is a Recruit's answer to a revision of a Question
 type QA struct {
-	Question string `json:"question" bson:"question"`
-	Answer   string `json:"answer" bson:"answer"`
+	QuestionID bson.ObjectId `json:"question_id" bson:"question_id"`
+	Revision   int32         `json:"revision" bson:"revision"`
+	Answer     string        `json:"answer" bson:"answer"`
 }
 
 // OK validates QA fields
 func (q *QA) OK() error {
-	if q.Question == "" {
-		return er.InvalidField("question")
+	if q.QuestionID == "" {
+		return er.InvalidField("question_id")
+	}
+	if q.Revision < 1 {
+		return er.InvalidField("revision")
 	}
 
-	if q.Answer == "" {
+	q.Answer = strings.TrimSpace(q.Answer)
+	if q.Answer == "" || len(q.Answer) > MaxAnswerLength {
 		return er.InvalidField("answer")
 	}
 	return nil
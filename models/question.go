@@ -1,10 +1,31 @@
 package models
 
 import (
+	"time"
+
 	er "../errors"
 	"gopkg.in/mgo.v2/bson"
 )
 
+// Question statuses, archived questions keep the answers given to them but aren't asked anymore
+const (
+	QuestionActive   = "ACTIVE"
+	QuestionArchived = "ARCHIVED"
+)
+
+// Question difficulties
+const (
+	QuestionEasy   = "EASY"
+	QuestionMedium = "MEDIUM"
+	QuestionHard   = "HARD"
+)
+
+// A question's weight is how often it's asked compared to the other questions of its industry
+const (
+	DefaultQuestionWeight = 1
+	MaxQuestionWeight     = 100
+)
+
 // -----------------
 // Transformer
 // -----------------
@@ -18,6 +39,31 @@ func TransformQuestion(in interface{}) Question {
 		question.IndustryID = v["industry_id"].(bson.ObjectId)
 		question.Question = v["question"].(string)
 
+		// questions stored before they were versioned are on their first revision
+		question.Revision = 1
+		if revision, ok := asInt(v["revision"]); ok {
+			question.Revision = int32(revision)
+		}
+		question.Revisions = TransformQuestionRevisions(v["revisions"])
+		if len(question.Revisions) == 0 {
+			question.Revisions = []QuestionRevision{{Revision: 1, Question: question.Question}}
+		}
+
+		// as are questions stored before they were pooled
+		question.Status = QuestionActive
+		if status, ok := v["status"].(string); ok {
+			question.Status = status
+		}
+		question.Weight = DefaultQuestionWeight
+		if weight, ok := asInt(v["weight"]); ok {
+			question.Weight = int32(weight)
+		}
+		question.Difficulty = QuestionMedium
+		if difficulty, ok := v["difficulty"].(string); ok {
+			question.Difficulty = difficulty
+		}
+		question.Translations = TransformTranslations(v["translations"])
+
 	case Question:
 		question = v
 	}
@@ -25,15 +71,78 @@ func TransformQuestion(in interface{}) Question {
 	return question
 }
 
+// TransformQuestionRevision transforms interface into QuestionRevision model
+func TransformQuestionRevision(in interface{}) QuestionRevision {
+	var revision QuestionRevision
+	switch v := in.(type) {
+	case map[string]interface{}:
+		revision = TransformQuestionRevision(bson.M(v))
+	case bson.M:
+		number, _ := asInt(v["revision"])
+		revision.Revision = int32(number)
+		revision.Question = v["question"].(string)
+		revision.CreatedAt = v["created_at"].(time.Time)
+	case QuestionRevision:
+		revision = v
+	}
+	return revision
+}
+
+// TransformQuestionRevisions transforms interface into a list of QuestionRevision models
+func TransformQuestionRevisions(in interface{}) []QuestionRevision {
+	revisions := make([]QuestionRevision, 0)
+	switch v := in.(type) {
+	case []interface{}:
+		for _, r := range v {
+			revisions = append(revisions, TransformQuestionRevision(r))
+		}
+	case []QuestionRevision:
+		revisions = append(revisions, v...)
+	}
+	return revisions
+}
+
 // -----------------
 // Model
 // -----------------
 
-// Question model
+// Question model, Question holds the wording of the current Revision and
+// Revisions the wording of every revision so far, oldest first.
+// Translations are of the wording of the current Revision
 type Question struct {
-	ID         bson.ObjectId `json:"id" bson:"_id"`
-	IndustryID bson.ObjectId `json:"industry_id" bson:"industry_id"`
-	Question   string        `json:"question" bson:"question"`
+	ID           bson.ObjectId      `json:"id" bson:"_id"`
+	IndustryID   bson.ObjectId      `json:"industry_id" bson:"industry_id"`
+	Question     string             `json:"question" bson:"question"`
+	Revision     int32              `json:"revision" bson:"revision"`
+	Revisions    []QuestionRevision `json:"revisions" bson:"revisions"`
+	Status       string             `json:"status" bson:"status"`
+	Weight       int32              `json:"weight" bson:"weight"`
+	Difficulty   string             `json:"difficulty" bson:"difficulty"`
+	Translations []Translation      `json:"translations" bson:"translations"`
+}
+
+// QuestionRevision is the wording of a Question from the time it was revised
+type QuestionRevision struct {
+	Revision  int32     `json:"revision" bson:"revision"`
+	Question  string    `json:"question" bson:"question"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+// Revise changes the wording of the Question by adding a new revision
+func (i *Question) Revise(question string, at time.Time) {
+	i.Revision = int32(len(i.Revisions)) + 1
+	i.Question = question
+	i.Revisions = append(i.Revisions, QuestionRevision{Revision: i.Revision, Question: question, CreatedAt: at})
+}
+
+// FindRevision returns the revision with the given number
+func (i *Question) FindRevision(revision int32) (*QuestionRevision, bool) {
+	for j := range i.Revisions {
+		if i.Revisions[j].Revision == revision {
+			return &i.Revisions[j], true
+		}
+	}
+	return nil, false
 }
 
 // OK validate Question model
@@ -44,6 +153,26 @@ func (i *Question) OK() error {
 	if i.IndustryID == "" {
 		return er.InvalidField("industry_id")
 	}
+	current, ok := i.FindRevision(i.Revision)
+	if !ok || current.Question != i.Question || int(i.Revision) != len(i.Revisions) {
+		return er.InvalidField("revision")
+	}
+	if i.Status != QuestionActive && i.Status != QuestionArchived {
+		return er.InvalidField("status")
+	}
+	if i.Weight < 1 || i.Weight > MaxQuestionWeight {
+		return er.InvalidField("weight")
+	}
+	switch i.Difficulty {
+	case QuestionEasy, QuestionMedium, QuestionHard:
+	default:
+		return er.InvalidField("difficulty")
+	}
+	for j := range i.Translations {
+		if err := i.Translations[j].OK(); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -8,6 +8,9 @@ import (
 	"gopkg.in/mgo.v2/bson"
 )
 
+// MaxRecruitIndustries is the maximum number of industries a Recruit can prefer
+const MaxRecruitIndustries = 5
+
 // -----------------
 // Transformer
 // -----------------
@@ -18,7 +21,9 @@ func TransformRecruit(in interface{}) Recruit {
 	switch v := in.(type) {
 	case bson.M:
 		recruit.ID = v["_id"].(bson.ObjectId)
-		recruit.BirthYear = v["birth_year"].(int32)
+		if year, ok := asInt(v["birth_year"]); ok {
+			recruit.BirthYear = int32(year)
+		}
 		recruit.Province = v["province"].(string)
 		recruit.City = v["city"].(string)
 		recruit.Gender = v["gender"].(string)
@@ -27,8 +32,15 @@ func TransformRecruit(in interface{}) Recruit {
 		recruit.Vid2Url = v["vid2_url"].(string)
 		recruit.Phone = v["phone"].(string)
 		recruit.Email = v["email"].(string)
-		recruit.Qa1 = TransformQA(v["qa1"])
-		recruit.Qa2 = TransformQA(v["qa2"])
+		recruit.Answers = TransformQAs(v["answers"])
+		recruit.Privacy = DefaultRecruitPrivacy
+		if privacy, ok := v["privacy"]; ok {
+			recruit.Privacy = TransformRecruitPrivacy(privacy)
+		}
+		recruit.WorkExperience = TransformWorkExperiences(v["work_experience"])
+		recruit.Education = TransformEducations(v["education"])
+		recruit.Skills = TransformRecruitSkills(v["skills"])
+		recruit.IndustryIDs = TransformObjectIDs(v["industry_ids"])
 
 	case Recruit:
 		recruit = v
@@ -36,24 +48,42 @@ func TransformRecruit(in interface{}) Recruit {
 	return recruit
 }
 
+// TransformObjectIDs transforms interface into a list of ids
+func TransformObjectIDs(in interface{}) []bson.ObjectId {
+	ids := make([]bson.ObjectId, 0)
+	switch v := in.(type) {
+	case []interface{}:
+		for _, id := range v {
+			ids = append(ids, id.(bson.ObjectId))
+		}
+	case []bson.ObjectId:
+		ids = append(ids, v...)
+	}
+	return ids
+}
+
 // -----------------
 // Model
 // -----------------
 
 // Recruit db model
 type Recruit struct {
-	ID         bson.ObjectId `json:"id" bson:"_id"`
-	BirthYear  int32         `json:"birth_year" bson:"birth_year"`
-	Province   string        `json:"province" bson:"province"`
-	City       string        `json:"city" bson:"city"`
-	Gender     string        `json:"gender" bson:"gender"`
-	Disability string        `json:"disability" bson:"disability"`
-	Vid1Url    string        `json:"vid1_url" bson:"vid1_url"`
-	Vid2Url    string        `json:"vid2_url" bson:"vid2_url"`
-	Phone      string        `json:"phone" bson:"phone"`
-	Email      string        `json:"email" bson:"email"`
-	Qa1        QA            `json:"qa1" bson:"qa1"`
-	Qa2        QA            `json:"qa2" bson:"qa2"`
+	ID             bson.ObjectId    `json:"id" bson:"_id"`
+	BirthYear      int32            `json:"birth_year" bson:"birth_year"`
+	Province       string           `json:"province" bson:"province"`
+	City           string           `json:"city" bson:"city"`
+	Gender         string           `json:"gender" bson:"gender"`
+	Disability     string           `json:"disability" bson:"disability"`
+	Vid1Url        string           `json:"vid1_url" bson:"vid1_url"`
+	Vid2Url        string           `json:"vid2_url" bson:"vid2_url"`
+	Phone          string           `json:"phone" bson:"phone"`
+	Email          string           `json:"email" bson:"email"`
+	Answers        []QA             `json:"answers" bson:"answers"`
+	Privacy        RecruitPrivacy   `json:"privacy" bson:"privacy"`
+	WorkExperience []WorkExperience `json:"work_experience" bson:"work_experience"`
+	Education      []Education      `json:"education" bson:"education"`
+	Skills         []RecruitSkill   `json:"skills" bson:"skills"`
+	IndustryIDs    []bson.ObjectId  `json:"industry_ids" bson:"industry_ids"`
 }
 
 //OK validates Recruit fields
@@ -76,24 +106,60 @@ func (r *Recruit) OK() error {
 		return er.InvalidField("email")
 	}
 
-	if r.Qa1.Question == "" {
-		return er.InvalidField("qa1.question")
+	if len(r.Answers) == 0 {
+		return er.InvalidField("answers")
+	}
+	questions := make(map[bson.ObjectId]bool)
+	for i := range r.Answers {
+		if err := r.Answers[i].OK(); err != nil {
+			return err
+		}
+		if questions[r.Answers[i].QuestionID] {
+			return er.InvalidField("answers")
+		}
+		questions[r.Answers[i].QuestionID] = true
+	}
+
+	if r.BirthYear < 1900 || r.BirthYear >= int32(time.Now().Year()) {
+		return er.InvalidField("birth_year")
 	}
 
-	if r.Qa1.Answer == "" {
-		return er.InvalidField("qa1.answer")
+	if len(r.WorkExperience) > MaxWorkExperiences {
+		return er.InvalidField("work_experience")
+	}
+	for i := range r.WorkExperience {
+		if err := r.WorkExperience[i].OK(); err != nil {
+			return err
+		}
 	}
 
-	if r.Qa2.Question == "" {
-		return er.InvalidField("qa2.question")
+	if len(r.Education) > MaxEducations {
+		return er.InvalidField("education")
+	}
+	for i := range r.Education {
+		if err := r.Education[i].OK(); err != nil {
+			return err
+		}
 	}
 
-	if r.Qa2.Answer == "" {
-		return er.InvalidField("qa2.answer")
+	if len(r.IndustryIDs) > MaxRecruitIndustries {
+		return er.InvalidField("industry_ids")
+	}
+	seen := make(map[bson.ObjectId]bool)
+	for _, id := range r.IndustryIDs {
+		if !id.Valid() || seen[id] {
+			return er.InvalidField("industry_ids")
+		}
+		seen[id] = true
 	}
 
-	if r.BirthYear < 1900 || r.BirthYear >= int32(time.Now().Year()) {
-		return er.InvalidField("birth_year")
+	if len(r.Skills) > MaxRecruitSkills {
+		return er.InvalidField("skills")
+	}
+	for i := range r.Skills {
+		if err := r.Skills[i].OK(); err != nil {
+			return err
+		}
 	}
 
 	r.Gender = strings.ToUpper(r.Gender)
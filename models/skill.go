@@ -0,0 +1,135 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	er "../errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Self-rated skill levels range from beginner (1) to expert (5)
+const (
+	MinSkillLevel = 1
+	MaxSkillLevel = 5
+)
+
+// MaxRecruitSkills is the maximum number of skills a Recruit can have
+const MaxRecruitSkills = 30
+
+// -----------------
+// Transformer
+// -----------------
+
+// TransformSkill transforms interface into Skill model
+func TransformSkill(in interface{}) Skill {
+	var skill Skill
+	switch v := in.(type) {
+	case bson.M:
+		skill.ID = v["_id"].(bson.ObjectId)
+		skill.IndustryID = v["industry_id"].(bson.ObjectId)
+		skill.Name = v["name"].(string)
+
+	case Skill:
+		skill = v
+	}
+	return skill
+}
+
+// TransformRecruitSkill transforms interface into RecruitSkill model
+func TransformRecruitSkill(in interface{}) RecruitSkill {
+	var skill RecruitSkill
+	switch v := in.(type) {
+	case map[string]interface{}:
+		skill = TransformRecruitSkill(bson.M(v))
+	case bson.M:
+		skill.SkillID = v["skill_id"].(bson.ObjectId)
+		level, _ := asInt(v["level"])
+		skill.Level = int32(level)
+	case RecruitSkill:
+		skill = v
+	}
+	return skill
+}
+
+// TransformRecruitSkills transforms interface into a list of RecruitSkill models
+func TransformRecruitSkills(in interface{}) []RecruitSkill {
+	skills := make([]RecruitSkill, 0)
+	switch v := in.(type) {
+	case []interface{}:
+		for _, s := range v {
+			skills = append(skills, TransformRecruitSkill(s))
+		}
+	case []RecruitSkill:
+		skills = append(skills, v...)
+	}
+	return skills
+}
+
+// TransformEndorsement transforms interface into Endorsement model
+func TransformEndorsement(in interface{}) Endorsement {
+	var endorsement Endorsement
+	switch v := in.(type) {
+	case bson.M:
+		endorsement.ID = v["_id"].(bson.ObjectId)
+		endorsement.RecruitID = v["recruit_id"].(bson.ObjectId)
+		endorsement.SkillID = v["skill_id"].(bson.ObjectId)
+		endorsement.HunterID = v["hunter_id"].(bson.ObjectId)
+		endorsement.CompanyID = v["company_id"].(bson.ObjectId)
+		endorsement.CreatedAt = v["created_at"].(time.Time)
+
+	case Endorsement:
+		endorsement = v
+	}
+	return endorsement
+}
+
+// -----------------
+// Model
+// -----------------
+
+// Skill is an ability within an Industry that recruits can claim
+type Skill struct {
+	ID         bson.ObjectId `json:"id" bson:"_id"`
+	IndustryID bson.ObjectId `json:"industry_id" bson:"industry_id"`
+	Name       string        `json:"name" bson:"name"`
+}
+
+// OK validates Skill fields
+func (s *Skill) OK() error {
+	s.Name = strings.ToLower(strings.TrimSpace(s.Name))
+	if s.Name == "" {
+		return er.InvalidField("name")
+	}
+	if s.IndustryID == "" {
+		return er.InvalidField("industry_id")
+	}
+	return nil
+}
+
+// RecruitSkill is a Skill a Recruit claims to have, with the level they rate themselves at
+type RecruitSkill struct {
+	SkillID bson.ObjectId `json:"skill_id" bson:"skill_id"`
+	Level   int32         `json:"level" bson:"level"`
+}
+
+// OK validates RecruitSkill fields
+func (s *RecruitSkill) OK() error {
+	if s.SkillID == "" {
+		return er.InvalidField("skill_id")
+	}
+	if s.Level < MinSkillLevel || s.Level > MaxSkillLevel {
+		return er.InvalidField("level")
+	}
+	return nil
+}
+
+// Endorsement is a Hunter's confirmation that a Recruit they interviewed has a Skill
+type Endorsement struct {
+	ID        bson.ObjectId `json:"id" bson:"_id"`
+	RecruitID bson.ObjectId `json:"recruit_id" bson:"recruit_id"`
+	SkillID   bson.ObjectId `json:"skill_id" bson:"skill_id"`
+	HunterID  bson.ObjectId `json:"hunter_id" bson:"hunter_id"`
+	CompanyID bson.ObjectId `json:"company_id" bson:"company_id"`
+	CreatedAt time.Time     `json:"created_at" bson:"created_at"`
+}
@@ -0,0 +1,185 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	er "../errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Thread participant roles
+const (
+	ParticipantRecruit = "RECRUIT"
+	ParticipantHunter  = "HUNTER"
+)
+
+// MaxMessageLength is the maximum number of characters in a Message
+const MaxMessageLength = 4000
+
+// -----------------
+// Transformer
+// -----------------
+
+// TransformThread transforms interface into Thread model
+func TransformThread(in interface{}) Thread {
+	var thread Thread
+	switch v := in.(type) {
+	case bson.M:
+		thread.ID = v["_id"].(bson.ObjectId)
+		thread.ApplicationID = v["application_id"].(bson.ObjectId)
+		thread.VacancyID = v["vacancy_id"].(bson.ObjectId)
+		thread.CompanyID = v["company_id"].(bson.ObjectId)
+		thread.RecruitID = v["recruit_id"].(bson.ObjectId)
+		thread.Subject = v["subject"].(string)
+		thread.Reads = TransformThreadReads(v["reads"])
+		thread.CreatedAt = v["created_at"].(time.Time)
+		thread.UpdatedAt = v["updated_at"].(time.Time)
+
+	case Thread:
+		thread = v
+	}
+	return thread
+}
+
+// TransformThreadRead transforms interface into ThreadRead model
+func TransformThreadRead(in interface{}) ThreadRead {
+	var read ThreadRead
+	switch v := in.(type) {
+	case map[string]interface{}:
+		read.AccountID = v["account_id"].(bson.ObjectId)
+		read.ReadAt = v["read_at"].(time.Time)
+	case bson.M:
+		read.AccountID = v["account_id"].(bson.ObjectId)
+		read.ReadAt = v["read_at"].(time.Time)
+	case ThreadRead:
+		read = v
+	}
+	return read
+}
+
+// TransformThreadReads transforms interface into a list of ThreadRead models
+func TransformThreadReads(in interface{}) []ThreadRead {
+	reads := make([]ThreadRead, 0)
+	switch v := in.(type) {
+	case []interface{}:
+		for _, r := range v {
+			reads = append(reads, TransformThreadRead(r))
+		}
+	case []ThreadRead:
+		reads = append(reads, v...)
+	}
+	return reads
+}
+
+// TransformMessage transforms interface into Message model
+func TransformMessage(in interface{}) Message {
+	var message Message
+	switch v := in.(type) {
+	case bson.M:
+		message.ID = v["_id"].(bson.ObjectId)
+		message.ThreadID = v["thread_id"].(bson.ObjectId)
+		message.SenderID = v["sender_id"].(bson.ObjectId)
+		message.SenderRole = v["sender_role"].(string)
+		message.Body = v["body"].(string)
+		message.CreatedAt = v["created_at"].(time.Time)
+
+	case Message:
+		message = v
+	}
+	return message
+}
+
+// -----------------
+// Model
+// -----------------
+
+// ThreadRead records when an account last read a Thread
+type ThreadRead struct {
+	AccountID bson.ObjectId `json:"account_id" bson:"account_id"`
+	ReadAt    time.Time     `json:"read_at" bson:"read_at"`
+}
+
+// Thread is a conversation between a Recruit and the members of a Company about
+// a vacancy. ApplicationID is null when the Recruit hasn't applied to the vacancy
+type Thread struct {
+	ID            bson.ObjectId `json:"id" bson:"_id"`
+	ApplicationID bson.ObjectId `json:"application_id" bson:"application_id"`
+	VacancyID     bson.ObjectId `json:"vacancy_id" bson:"vacancy_id"`
+	CompanyID     bson.ObjectId `json:"company_id" bson:"company_id"`
+	RecruitID     bson.ObjectId `json:"recruit_id" bson:"recruit_id"`
+	Subject       string        `json:"subject" bson:"subject"`
+	Reads         []ThreadRead  `json:"reads" bson:"reads"`
+	CreatedAt     time.Time     `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at" bson:"updated_at"`
+}
+
+// OK validates Thread fields
+func (t *Thread) OK() error {
+	if t.VacancyID == "" {
+		return er.InvalidField("vacancy_id")
+	}
+	if t.CompanyID == "" {
+		return er.InvalidField("company_id")
+	}
+	if t.RecruitID == "" {
+		return er.InvalidField("recruit_id")
+	}
+	if strings.TrimSpace(t.Subject) == "" {
+		return er.InvalidField("subject")
+	}
+	return nil
+}
+
+// ReadAt returns when the account last read the Thread, or the zero time if it never has
+func (t *Thread) ReadAt(accountID bson.ObjectId) time.Time {
+	for _, read := range t.Reads {
+		if read.AccountID == accountID {
+			return read.ReadAt
+		}
+	}
+	return time.Time{}
+}
+
+// MarkRead records that the account has read the Thread up to the given time
+func (t *Thread) MarkRead(accountID bson.ObjectId, at time.Time) {
+	for i, read := range t.Reads {
+		if read.AccountID == accountID {
+			if at.After(read.ReadAt) {
+				t.Reads[i].ReadAt = at
+			}
+			return
+		}
+	}
+	t.Reads = append(t.Reads, ThreadRead{accountID, at})
+}
+
+// Message is a single message in a Thread
+type Message struct {
+	ID         bson.ObjectId `json:"id" bson:"_id"`
+	ThreadID   bson.ObjectId `json:"thread_id" bson:"thread_id"`
+	SenderID   bson.ObjectId `json:"sender_id" bson:"sender_id"`
+	SenderRole string        `json:"sender_role" bson:"sender_role"`
+	Body       string        `json:"body" bson:"body"`
+	CreatedAt  time.Time     `json:"created_at" bson:"created_at"`
+}
+
+// OK validates Message fields
+func (m *Message) OK() error {
+	if m.ThreadID == "" {
+		return er.InvalidField("thread_id")
+	}
+	if m.SenderID == "" {
+		return er.InvalidField("sender_id")
+	}
+	if !(m.SenderRole == ParticipantRecruit || m.SenderRole == ParticipantHunter) {
+		return er.InvalidField("sender_role")
+	}
+	if strings.TrimSpace(m.Body) == "" {
+		return er.MissingField("body")
+	}
+	if len([]rune(m.Body)) > MaxMessageLength {
+		return er.Input("Message is too long.")
+	}
+	return nil
+}
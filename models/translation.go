@@ -0,0 +1,134 @@
+package models
+
+import (
+	"strings"
+
+	er "../errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// DefaultLocale is the locale of the text questions and industries are created with,
+// it's what's shown when there's no translation to a requested locale
+const DefaultLocale = "en"
+
+// Locales are the locales questions and industries can be translated to,
+// South Africa's official languages
+var Locales = []string{
+	"en",  // English
+	"af",  // Afrikaans
+	"zu",  // isiZulu
+	"xh",  // isiXhosa
+	"nr",  // isiNdebele
+	"ss",  // siSwati
+	"nso", // Sepedi
+	"st",  // Sesotho
+	"tn",  // Setswana
+	"ts",  // Xitsonga
+	"ve",  // Tshivenda
+}
+
+// MaxTranslationLength is the longest a translated text can be
+const MaxTranslationLength = 500
+
+// IsLocale checks that locale is one of Locales
+func IsLocale(locale string) bool {
+	for _, l := range Locales {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// -----------------
+// Transformer
+// -----------------
+
+// TransformTranslation transforms interface into Translation model
+func TransformTranslation(in interface{}) Translation {
+	var translation Translation
+	switch v := in.(type) {
+	case map[string]interface{}:
+		translation = TransformTranslation(bson.M(v))
+	case bson.M:
+		translation.Locale = v["locale"].(string)
+		translation.Text = v["text"].(string)
+	case Translation:
+		translation = v
+	}
+	return translation
+}
+
+// TransformTranslations transforms interface into a list of Translation models
+func TransformTranslations(in interface{}) []Translation {
+	translations := make([]Translation, 0)
+	switch v := in.(type) {
+	case []interface{}:
+		for _, t := range v {
+			translations = append(translations, TransformTranslation(t))
+		}
+	case []Translation:
+		translations = append(translations, v...)
+	}
+	return translations
+}
+
+// -----------------
+// Model
+// -----------------
+
+// Translation is a text in another locale than DefaultLocale
+type Translation struct {
+	Locale string `json:"locale" bson:"locale"`
+	Text   string `json:"text" bson:"text"`
+}
+
+// OK validates Translation model
+func (t *Translation) OK() error {
+	if !IsLocale(t.Locale) || t.Locale == DefaultLocale {
+		return er.InvalidField("locale")
+	}
+	t.Text = strings.TrimSpace(t.Text)
+	if t.Text == "" || len(t.Text) > MaxTranslationLength {
+		return er.InvalidField("text")
+	}
+	return nil
+}
+
+// Translate picks the text of the first of locales there's a translation to,
+// text is in DefaultLocale and picked when there's none
+func Translate(text string, translations []Translation, locales []string) string {
+	for _, locale := range locales {
+		if locale == DefaultLocale {
+			return text
+		}
+		for _, t := range translations {
+			if t.Locale == locale {
+				return t.Text
+			}
+		}
+	}
+	return text
+}
+
+// SetTranslation adds the translation to the list, replacing the one to the same locale
+func SetTranslation(translations []Translation, translation Translation) []Translation {
+	result := make([]Translation, 0, len(translations)+1)
+	for _, t := range translations {
+		if t.Locale != translation.Locale {
+			result = append(result, t)
+		}
+	}
+	return append(result, translation)
+}
+
+// RemoveTranslation removes the translation to locale from the list, and reports if there was one
+func RemoveTranslation(translations []Translation, locale string) ([]Translation, bool) {
+	result := make([]Translation, 0, len(translations))
+	for _, t := range translations {
+		if t.Locale != locale {
+			result = append(result, t)
+		}
+	}
+	return result, len(result) != len(translations)
+}
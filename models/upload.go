@@ -0,0 +1,132 @@
+package models
+
+import (
+	"time"
+
+	er "../errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Upload targets, the recruit profile field that's set to the uploaded file's url
+const (
+	UploadVideo1 = "vid1"
+	UploadVideo2 = "vid2"
+)
+
+// UploadTargetFields are the recruit profile fields upload targets set
+var UploadTargetFields = map[string]string{
+	UploadVideo1: "vid1_url",
+	UploadVideo2: "vid2_url",
+}
+
+// UploadSizeLimits are the largest files that can be uploaded of each type, in bytes
+var UploadSizeLimits = map[string]int64{
+	"video/mp4":       500 << 20,
+	"video/quicktime": 500 << 20,
+	"video/webm":      500 << 20,
+	"video/3gpp":      200 << 20,
+}
+
+// UploadExtensions are the file extensions of each upload type
+var UploadExtensions = map[string]string{
+	"video/mp4":       ".mp4",
+	"video/quicktime": ".mov",
+	"video/webm":      ".webm",
+	"video/3gpp":      ".3gp",
+}
+
+// UploadExpiry is how long an unfinished upload is kept after its last part was received
+const UploadExpiry = 24 * time.Hour
+
+// -----------------
+// Transformer
+// -----------------
+
+// TransformUpload transforms interface into Upload model
+func TransformUpload(in interface{}) Upload {
+	var upload Upload
+	switch v := in.(type) {
+	case bson.M:
+		upload.ID = v["_id"].(bson.ObjectId)
+		upload.AccountID = v["account_id"].(bson.ObjectId)
+		upload.RecruitID = v["recruit_id"].(bson.ObjectId)
+		upload.Target = v["target"].(string)
+		upload.Filename = v["filename"].(string)
+		upload.FileType = v["file_type"].(string)
+		upload.Length = v["length"].(int64)
+		upload.Offset = v["offset"].(int64)
+		upload.Metadata = v["metadata"].(string)
+		upload.URL = v["url"].(string)
+		upload.CreatedAt = v["created_at"].(time.Time)
+		upload.UpdatedAt = v["updated_at"].(time.Time)
+		if completedAt, ok := v["completed_at"].(time.Time); ok {
+			upload.CompletedAt = completedAt
+		}
+
+	case Upload:
+		upload = v
+	}
+
+	return upload
+}
+
+// -----------------
+// Model
+// -----------------
+
+// Upload model, a file uploaded in parts by a recruit. Offset is how many of
+// its Length bytes were received so far, URL is set once all of them were
+type Upload struct {
+	ID          bson.ObjectId `json:"id" bson:"_id"`
+	AccountID   bson.ObjectId `json:"account_id" bson:"account_id"`
+	RecruitID   bson.ObjectId `json:"recruit_id" bson:"recruit_id"`
+	Target      string        `json:"target" bson:"target"`
+	Filename    string        `json:"filename" bson:"filename"`
+	FileType    string        `json:"file_type" bson:"file_type"`
+	Length      int64         `json:"length" bson:"length"`
+	Offset      int64         `json:"offset" bson:"offset"`
+	Metadata    string        `json:"metadata" bson:"metadata"`
+	URL         string        `json:"url" bson:"url"`
+	CreatedAt   time.Time     `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at" bson:"updated_at"`
+	CompletedAt time.Time     `json:"completed_at" bson:"completed_at"`
+}
+
+// Complete checks if all of the Upload's bytes were received
+func (u *Upload) Complete() bool {
+	return u.Offset == u.Length
+}
+
+// ExpiresAt is when an unfinished Upload expires
+func (u *Upload) ExpiresAt() time.Time {
+	return u.UpdatedAt.Add(UploadExpiry)
+}
+
+// Expired checks if an unfinished Upload expired by now, complete uploads don't expire
+func (u *Upload) Expired(now time.Time) bool {
+	return !u.Complete() && now.After(u.ExpiresAt())
+}
+
+// OK validates Upload model
+func (u *Upload) OK() error {
+	if u.AccountID == "" {
+		return er.InvalidField("account_id")
+	}
+	if u.RecruitID == "" {
+		return er.InvalidField("recruit_id")
+	}
+	if _, ok := UploadTargetFields[u.Target]; !ok {
+		return er.InvalidField("target")
+	}
+	limit, ok := UploadSizeLimits[u.FileType]
+	if !ok {
+		return er.InvalidField("file_type")
+	}
+	if u.Length <= 0 || u.Length > limit {
+		return er.InvalidField("length")
+	}
+	if u.Offset < 0 || u.Offset > u.Length {
+		return er.InvalidField("offset")
+	}
+	return nil
+}
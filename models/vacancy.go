@@ -0,0 +1,130 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	er "../errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Vacancy statuses
+const (
+	VacancyDraft  = "DRAFT"
+	VacancyOpen   = "OPEN"
+	VacancyClosed = "CLOSED"
+)
+
+// EmploymentTypes lists the valid Vacancy employment types
+var EmploymentTypes = []string{
+	"FULL_TIME",
+	"PART_TIME",
+	"CONTRACT",
+	"TEMPORARY",
+	"INTERNSHIP",
+}
+
+// -----------------
+// Transformer
+// -----------------
+
+// TransformVacancy transforms interface into Vacancy model
+func TransformVacancy(in interface{}) Vacancy {
+	var vacancy Vacancy
+	switch v := in.(type) {
+	case bson.M:
+		vacancy.ID = v["_id"].(bson.ObjectId)
+		vacancy.CompanyID = v["company_id"].(bson.ObjectId)
+		vacancy.IndustryID = v["industry_id"].(bson.ObjectId)
+		vacancy.CreatedBy = v["created_by"].(bson.ObjectId)
+		vacancy.Title = v["title"].(string)
+		vacancy.Description = v["description"].(string)
+		vacancy.Province = v["province"].(string)
+		vacancy.City = v["city"].(string)
+		vacancy.EmploymentType = v["employment_type"].(string)
+		vacancy.SalaryMin = v["salary_min"].(int)
+		vacancy.SalaryMax = v["salary_max"].(int)
+		vacancy.ClosingDate = v["closing_date"].(time.Time)
+		vacancy.CreatedAt = v["created_at"].(time.Time)
+		vacancy.Status = v["status"].(string)
+
+	case Vacancy:
+		vacancy = v
+	}
+	return vacancy
+}
+
+// -----------------
+// Model
+// -----------------
+
+// Vacancy is a job posting by a Company
+type Vacancy struct {
+	ID             bson.ObjectId `json:"id" bson:"_id"`
+	CompanyID      bson.ObjectId `json:"company_id" bson:"company_id"`
+	IndustryID     bson.ObjectId `json:"industry_id" bson:"industry_id"`
+	CreatedBy      bson.ObjectId `json:"created_by" bson:"created_by"`
+	Title          string        `json:"title" bson:"title"`
+	Description    string        `json:"description" bson:"description"`
+	Province       string        `json:"province" bson:"province"`
+	City           string        `json:"city" bson:"city"`
+	EmploymentType string        `json:"employment_type" bson:"employment_type"`
+	SalaryMin      int           `json:"salary_min" bson:"salary_min"`
+	SalaryMax      int           `json:"salary_max" bson:"salary_max"`
+	ClosingDate    time.Time     `json:"closing_date" bson:"closing_date"`
+	CreatedAt      time.Time     `json:"created_at" bson:"created_at"`
+	Status         string        `json:"status" bson:"status"`
+}
+
+// OK validates Vacancy fields
+func (v *Vacancy) OK() error {
+	if v.CompanyID == "" {
+		return er.InvalidField("company_id")
+	}
+	if v.IndustryID == "" {
+		return er.InvalidField("industry_id")
+	}
+	v.Title = strings.TrimSpace(v.Title)
+	if v.Title == "" {
+		return er.InvalidField("title")
+	}
+	if v.Description == "" {
+		return er.InvalidField("description")
+	}
+	if v.Province == "" {
+		return er.InvalidField("province")
+	}
+	if v.City == "" {
+		return er.InvalidField("city")
+	}
+
+	validType := false
+	for _, t := range EmploymentTypes {
+		if v.EmploymentType == t {
+			validType = true
+			break
+		}
+	}
+	if !validType {
+		return er.InvalidField("employment_type")
+	}
+
+	if v.SalaryMin < 0 || v.SalaryMax < v.SalaryMin {
+		return er.Input("Invalid salary range.")
+	}
+	if v.ClosingDate.IsZero() {
+		return er.InvalidField("closing_date")
+	}
+	if !(v.Status == VacancyDraft || v.Status == VacancyOpen || v.Status == VacancyClosed) {
+		return er.InvalidField("status")
+	}
+	if v.Status == VacancyOpen && v.ClosingDate.Before(time.Now()) {
+		return er.Input("Closing date of an open vacancy must be in the future.")
+	}
+	return nil
+}
+
+// IsOpen checks if the Vacancy is accepting applications
+func (v *Vacancy) IsOpen() bool {
+	return v.Status == VacancyOpen && v.ClosingDate.After(time.Now())
+}
@@ -0,0 +1,143 @@
+package notify
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	config "../config"
+	db "../database"
+	models "../models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Event is something that happened which accounts are notified about
+type Event struct {
+	Type      string
+	Title     string
+	Body      string
+	SubjectID bson.ObjectId
+}
+
+// Backend delivers notifications outside of the in-app inbox, e.g. by email
+type Backend interface {
+	Deliver(account models.Account, notification models.Notification) error
+}
+
+// -----------------
+// Dispatcher
+// -----------------
+
+// Dispatcher stores notifications in the inboxes of the accounts that want them
+// and hands them to the backend for the accounts that want them emailed
+type Dispatcher struct {
+	crud    *db.CRUD
+	backend Backend
+}
+
+// NewDispatcher creates a Dispatcher delivering over the backend
+func NewDispatcher(crud *db.CRUD, backend Backend) *Dispatcher {
+	return &Dispatcher{crud, backend}
+}
+
+// Notify notifies the accounts of the event. Failures are logged rather
+// than returned, notifications never fail the action that caused them
+func (d *Dispatcher) Notify(accountIDs []bson.ObjectId, event Event) {
+	for _, id := range accountIDs {
+		if err := d.notify(id, event); err != nil {
+			log.Printf("Failed to notify account %s of %s => %s", id.Hex(), event.Type, err)
+		}
+	}
+}
+
+// notify notifies a single account of the event
+func (d *Dispatcher) notify(accountID bson.ObjectId, event Event) error {
+	rawAccount, err := d.crud.FindID(config.AccountsCollection, accountID)
+	if err != nil {
+		return err
+	}
+	account := models.TransformAccount(rawAccount)
+
+	subjectID := event.SubjectID
+	if subjectID == "" {
+		subjectID = models.NullObjectID
+	}
+	notification := models.Notification{
+		ID:        bson.NewObjectId(),
+		AccountID: account.ID,
+		Event:     event.Type,
+		Title:     event.Title,
+		Body:      event.Body,
+		SubjectID: subjectID,
+		CreatedAt: time.Now(),
+	}
+	if err := notification.OK(); err != nil {
+		return err
+	}
+
+	settings := Settings(d.crud, account.ID)
+	preference := settings.Preference(event.Type)
+	if preference.InApp {
+		if err := d.crud.Insert(config.NotificationsCollection, notification); err != nil {
+			return err
+		}
+	}
+	if preference.Email && d.backend != nil {
+		return d.backend.Deliver(account, notification)
+	}
+	return nil
+}
+
+// Settings retrieves an account's notification settings, accounts
+// without stored settings get empty settings, i.e. the defaults
+func Settings(crud *db.CRUD, accountID bson.ObjectId) models.NotificationSettings {
+	rawSettings, err := crud.FindOne(config.NotificationSettingsCollection, bson.M{"account_id": accountID})
+	if err != nil {
+		return models.NotificationSettings{
+			ID:          bson.NewObjectId(),
+			AccountID:   accountID,
+			Preferences: make([]models.NotificationPreference, 0),
+		}
+	}
+	return models.TransformNotificationSettings(rawSettings)
+}
+
+// -----------------
+// Backends
+// -----------------
+
+// LogBackend logs notifications instead of delivering them
+type LogBackend struct{}
+
+// Deliver logs the notification
+func (LogBackend) Deliver(account models.Account, notification models.Notification) error {
+	log.Printf("Notification for %s: %s", account.Email, notification.Title)
+	return nil
+}
+
+// Delivery is a notification delivered by a MemoryBackend
+type Delivery struct {
+	Account      models.Account
+	Notification models.Notification
+}
+
+// MemoryBackend keeps delivered notifications in memory, for use in tests
+type MemoryBackend struct {
+	mu         sync.Mutex
+	deliveries []Delivery
+}
+
+// Deliver records the notification
+func (b *MemoryBackend) Deliver(account models.Account, notification models.Notification) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.deliveries = append(b.deliveries, Delivery{account, notification})
+	return nil
+}
+
+// Deliveries returns the notifications delivered so far
+func (b *MemoryBackend) Deliveries() []Delivery {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]Delivery(nil), b.deliveries...)
+}
@@ -0,0 +1,138 @@
+package resolvers
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	config "../config"
+	db "../database"
+	er "../errors"
+	models "../models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// -----------------
+// helpers
+// -----------------
+
+// parseAnswers converts answer details into QAs, the questions need to exist and
+// each industry they belong to needs to get between its minimum and maximum number of answers.
+// Answers are to the current revision of their question, unless they're unchanged from
+// the previous answers
+func parseAnswers(crud *db.CRUD, details []qaDetails, previous []models.QA, field string) ([]models.QA, error) {
+	if len(details) == 0 {
+		return nil, er.Input("No QAs given.")
+	}
+
+	answers := make([]models.QA, 0, len(details))
+	for i, d := range details {
+		id := string(d.QuestionID)
+		if !bson.IsObjectIdHex(id) {
+			return nil, er.InvalidField(fmt.Sprintf("%s[%d].question_id", field, i))
+		}
+		qa := models.QA{QuestionID: bson.ObjectIdHex(id), Answer: d.Answer}
+		for _, other := range answers {
+			if other.QuestionID == qa.QuestionID {
+				return nil, er.Input("A question can only be answered once.")
+			}
+		}
+		answers = append(answers, qa)
+	}
+
+	questions, err := answerQuestions(crud, answers)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[bson.ObjectId]int32)
+	for i := range answers {
+		question, ok := questions[answers[i].QuestionID]
+		if !ok {
+			return nil, er.InvalidField(fmt.Sprintf("%s[%d].question_id", field, i))
+		}
+		answers[i].Revision = answeredRevision(answers[i], question, previous)
+		if err := answers[i].OK(); err != nil {
+			return nil, er.InvalidField(fmt.Sprintf("%s[%d].answer", field, i))
+		}
+		counts[question.IndustryID]++
+	}
+
+	// every industry answered in needs enough, but not too many, answers
+	industryIDs := make([]bson.ObjectId, 0, len(counts))
+	for id := range counts {
+		industryIDs = append(industryIDs, id)
+	}
+	rawIndustries, err := crud.FindAll(config.IndustriesCollection, bson.M{"_id": bson.M{"$in": industryIDs}})
+	if err != nil {
+		log.Println("Failed to find answer industries =>", err)
+		return nil, er.Generic()
+	}
+	for _, raw := range rawIndustries {
+		industry := models.TransformIndustry(raw)
+		count := counts[industry.ID]
+		if count < industry.MinAnswers || count > industry.MaxAnswers {
+			return nil, er.Input(fmt.Sprintf("The %s industry needs between %d and %d answers.",
+				industry.Name, industry.MinAnswers, industry.MaxAnswers))
+		}
+	}
+	return answers, nil
+}
+
+// answeredRevision is the revision of the question an answer is to, an answer
+// that didn't change keeps the revision it was first given to
+func answeredRevision(qa models.QA, question *models.Question, previous []models.QA) int32 {
+	for _, p := range previous {
+		if p.QuestionID == qa.QuestionID && p.Answer == strings.TrimSpace(qa.Answer) {
+			return p.Revision
+		}
+	}
+	return question.Revision
+}
+
+// answerQuestions finds the questions that were answered, questions that no
+// longer exist are left out
+func answerQuestions(crud *db.CRUD, answers []models.QA) (map[bson.ObjectId]*models.Question, error) {
+	questions := make(map[bson.ObjectId]*models.Question)
+	if len(answers) == 0 {
+		return questions, nil
+	}
+
+	ids := make([]bson.ObjectId, 0, len(answers))
+	for _, qa := range answers {
+		ids = append(ids, qa.QuestionID)
+	}
+	rawQuestions, err := crud.FindAll(config.QuestionsCollection, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		log.Println("Failed to find answered questions =>", err)
+		return nil, er.Generic()
+	}
+	for _, raw := range rawQuestions {
+		question := models.TransformQuestion(raw)
+		questions[question.ID] = &question
+	}
+	return questions, nil
+}
+
+// answerResolvers creates the resolvers of a Recruit's answers
+func answerResolvers(crud *db.CRUD, answers []models.QA) ([]*QaResolver, error) {
+	questions, err := answerQuestions(crud, answers)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*QaResolver, 0, len(answers))
+	for i := range answers {
+		results = append(results, &QaResolver{&answers[i], questions[answers[i].QuestionID]})
+	}
+	return results, nil
+}
+
+// -----------------
+// Recruit methods
+// -----------------
+
+// Answers resolves Recruit.Answers
+func (r *RecruitResolver) Answers() ([]*QaResolver, error) {
+	defer r.crud.CloseCopy()
+	return answerResolvers(r.crud, r.r.Answers)
+}
@@ -0,0 +1,400 @@
+package resolvers
+
+import (
+	"log"
+	"time"
+
+	config "../config"
+	db "../database"
+	er "../errors"
+	models "../models"
+	utils "../utils"
+	graphql "github.com/graph-gophers/graphql-go"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// -----------------
+// RecruitEditorResolver methods
+// -----------------
+
+// Apply resolves RecruitEditor.Apply which applies the current Recruit to the given Vacancy
+func (r *RecruitEditorResolver) Apply(args struct{ VacancyID graphql.ID }) (*ApplicationResolver, error) {
+	defer r.crud.CloseCopy()
+
+	// check the id
+	id := string(args.VacancyID)
+	if !bson.IsObjectIdHex(id) {
+		return nil, er.InvalidField("vacancy_id")
+	}
+
+	// retrieve vacancy
+	rawVacancy, err := r.crud.FindID(config.VacanciesCollection, bson.ObjectIdHex(id))
+	if err != nil {
+		return nil, er.InvalidField("vacancy_id")
+	}
+	vacancy := models.TransformVacancy(rawVacancy)
+	if !vacancy.IsOpen() {
+		return nil, er.Input("Vacancy is not accepting applications.")
+	}
+
+	// check that the recruit hasn't already applied
+	if _, err := r.crud.FindOne(config.ApplicationsCollection, bson.M{
+		"vacancy_id": vacancy.ID,
+		"recruit_id": r.r.ID,
+	}); err == nil {
+		return nil, er.Input("Already applied to this Vacancy.")
+	}
+
+	// create application
+	now := time.Now()
+	application := models.Application{
+		ID:        bson.NewObjectId(),
+		VacancyID: vacancy.ID,
+		CompanyID: vacancy.CompanyID,
+		RecruitID: r.r.ID,
+		Stage:     models.StageApplied,
+		History: []models.StageChange{
+			{
+				Stage:     models.StageApplied,
+				ChangedBy: r.r.ID,
+				ChangedAt: now,
+			},
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	// validate application
+	if err := application.OK(); err != nil {
+		return nil, err
+	}
+
+	// store application in db
+	if err := r.crud.Insert(config.ApplicationsCollection, application); err != nil {
+		log.Println("Failed to create application =>", err)
+		return nil, er.Generic()
+	}
+
+	return &ApplicationResolver{&application, r.crud, r.a}, nil
+}
+
+// -----------------
+// HunterEditorResolver methods
+// -----------------
+
+// MoveApplication resolves HunterEditor.MoveApplication which moves an Application
+// to one of the current Hunter's Company's vacancies into the given stage
+func (r *HunterEditorResolver) MoveApplication(args struct {
+	ID    graphql.ID
+	Stage string
+	Note  *string
+}) (*ApplicationResolver, error) {
+	defer r.crud.CloseCopy()
+
+	company, err := r.company(models.CompanyRoleOwner, models.CompanyRoleRecruiter)
+	if err != nil {
+		return nil, err
+	}
+
+	// check the id
+	id := string(args.ID)
+	if !bson.IsObjectIdHex(id) {
+		return nil, er.InvalidField("id")
+	}
+
+	// retrieve application
+	rawApplication, err := r.crud.FindID(config.ApplicationsCollection, bson.ObjectIdHex(id))
+	if err != nil {
+		return nil, er.InvalidField("id")
+	}
+	application := models.TransformApplication(rawApplication)
+	if application.CompanyID != company.ID {
+		return nil, er.InvalidField("id")
+	}
+
+	// move application through the company's pipeline
+	note := ""
+	if args.Note != nil {
+		note = *args.Note
+	}
+	from := application.Stage
+	if err := application.MoveTo(company.ApplicationPipeline(), args.Stage, note, r.h.ID); err != nil {
+		return nil, err
+	}
+
+	// perform update, only while the application is still in the stage it was moved from
+	// so that concurrent moves don't overwrite each other's history
+	err = r.crud.UpdateOne(config.ApplicationsCollection, bson.M{"_id": application.ID, "stage": from}, bson.M{
+		"$set": bson.M{
+			"stage":      application.Stage,
+			"updated_at": application.UpdatedAt,
+		},
+		"$push": bson.M{"history": application.History[len(application.History)-1]},
+	})
+	if err != nil {
+		if raw, findErr := r.crud.FindID(config.ApplicationsCollection, application.ID); findErr == nil &&
+			models.TransformApplication(raw).Stage != from {
+			return nil, er.Input("Application has been moved in the meantime, try again.")
+		}
+		log.Println("Failed to move application =>", err)
+		return nil, er.Generic()
+	}
+	rawApplication, err = r.crud.FindID(config.ApplicationsCollection, application.ID)
+	if err != nil {
+		return nil, er.Generic()
+	}
+
+	// return updated application
+	updated := models.TransformApplication(rawApplication)
+	notifyApplicationMoved(r.crud, r.notifier, &updated)
+	return &ApplicationResolver{&updated, r.crud, r.a}, nil
+}
+
+// SetApplicationPipeline resolves HunterEditor.SetApplicationPipeline which configures the stages
+// the current Hunter's Company moves its applications through. Stages that applications are
+// still in can't be removed
+func (r *HunterEditorResolver) SetApplicationPipeline(args struct{ Stages []*pipelineStage }) (*CompanyResolver, error) {
+	defer r.crud.CloseCopy()
+
+	// only owners may configure the pipeline
+	company, err := r.company(models.CompanyRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate pipeline
+	pipeline := make(models.Pipeline, 0, len(args.Stages))
+	for _, stage := range args.Stages {
+		pipeline = append(pipeline, models.PipelineStage{Stage: stage.Stage, Next: append([]string{}, stage.Next...)})
+	}
+	if err := pipeline.OK(); err != nil {
+		return nil, err
+	}
+	rawApplications, err := r.crud.FindAll(config.ApplicationsCollection, bson.M{"company_id": company.ID})
+	if err != nil {
+		log.Println("Failed to find company applications =>", err)
+		return nil, er.Generic()
+	}
+	for _, raw := range rawApplications {
+		if application := models.TransformApplication(raw); !pipeline.Has(application.Stage) {
+			return nil, er.Input("Applications are still in the " + application.Stage + " stage.")
+		}
+	}
+
+	// perform update
+	rawCompany, err := GenericUpdateByID(r.crud, config.CompaniesCollection, company.ID, bson.M{"pipeline": pipeline})
+	if err != nil {
+		return nil, err
+	}
+
+	// return updated company
+	updated := models.TransformCompany(rawCompany)
+	return &CompanyResolver{&updated, r.crud}, nil
+}
+
+// -----------------
+// RecruitViewerResolver methods
+// -----------------
+
+// Applications resolves RecruitViewer.Applications which returns all of the current Recruit's applications
+func (r *RecruitViewerResolver) Applications() ([]*ApplicationResolver, error) {
+	defer r.crud.CloseCopy()
+
+	// fetch applications
+	rawApplications, err := r.crud.FindAll(config.ApplicationsCollection, bson.M{"recruit_id": r.r.ID})
+	if err != nil {
+		log.Println(err)
+		return nil, er.Generic()
+	}
+
+	// process results
+	results := make([]*ApplicationResolver, 0)
+	for _, raw := range rawApplications {
+		application := models.TransformApplication(raw)
+		results = append(results, &ApplicationResolver{&application, r.crud, r.a})
+	}
+	return results, nil
+}
+
+// -----------------
+// HunterViewerResolver methods
+// -----------------
+
+// Applicants resolves HunterViewer.Applicants which returns the applications to one of the
+// current Hunter's Company's vacancies, optionally limited to the given stage
+func (r *HunterViewerResolver) Applicants(args struct {
+	VacancyID graphql.ID
+	Stage     *string
+}) ([]*ApplicationResolver, error) {
+	defer r.crud.CloseCopy()
+
+	if utils.IsNullID(r.h.CompanyID) {
+		return nil, er.Input("Hunter does not belong to a Company.")
+	}
+
+	// check the id
+	id := string(args.VacancyID)
+	if !bson.IsObjectIdHex(id) {
+		return nil, er.InvalidField("vacancy_id")
+	}
+
+	// retrieve vacancy
+	rawVacancy, err := r.crud.FindID(config.VacanciesCollection, bson.ObjectIdHex(id))
+	if err != nil {
+		return nil, er.InvalidField("vacancy_id")
+	}
+	vacancy := models.TransformVacancy(rawVacancy)
+	if vacancy.CompanyID != r.h.CompanyID {
+		return nil, er.InvalidField("vacancy_id")
+	}
+
+	// fetch applications
+	query := bson.M{"vacancy_id": vacancy.ID}
+	if args.Stage != nil {
+		query["stage"] = *args.Stage
+	}
+	rawApplications, err := r.crud.FindAll(config.ApplicationsCollection, query)
+	if err != nil {
+		log.Println(err)
+		return nil, er.Generic()
+	}
+
+	// process results
+	results := make([]*ApplicationResolver, 0)
+	for _, raw := range rawApplications {
+		application := models.TransformApplication(raw)
+		results = append(results, &ApplicationResolver{&application, r.crud, r.a})
+	}
+	return results, nil
+}
+
+// -----------------
+// helpers
+// -----------------
+
+// resolveRecruit creates a RecruitResolver, retrieving the Recruit's account and
+// the contact details the viewer may see
+func resolveRecruit(crud *db.CRUD, recruit *models.Recruit, viewer *models.Account) (*RecruitResolver, error) {
+	rawAccount, err := crud.FindOne(config.AccountsCollection, bson.M{"recruit_id": recruit.ID})
+	if err != nil {
+		log.Println("Failed to find recruit's account =>", err)
+		return nil, er.Generic()
+	}
+	account := models.TransformAccount(rawAccount)
+	return &RecruitResolver{recruit, &account, crud, viewerContactAccess(crud, recruit, viewer)}, nil
+}
+
+// -----------------
+// ApplicationResolver struct
+// -----------------
+
+// ApplicationResolver resolves Application
+type ApplicationResolver struct {
+	app    *models.Application
+	crud   *db.CRUD
+	viewer *models.Account
+}
+
+// ID resolves Application.ID
+func (r *ApplicationResolver) ID() graphql.ID {
+	return graphql.ID(r.app.ID.Hex())
+}
+
+// Vacancy resolves Application.Vacancy
+func (r *ApplicationResolver) Vacancy() (*VacancyResolver, error) {
+	defer r.crud.CloseCopy()
+
+	rawVacancy, err := r.crud.FindID(config.VacanciesCollection, r.app.VacancyID)
+	if err != nil {
+		return nil, nil
+	}
+	vacancy := models.TransformVacancy(rawVacancy)
+	return &VacancyResolver{&vacancy, r.crud}, nil
+}
+
+// Recruit resolves Application.Recruit
+func (r *ApplicationResolver) Recruit() (*RecruitResolver, error) {
+	defer r.crud.CloseCopy()
+
+	rawRecruit, err := r.crud.FindID(config.RecruitsCollection, r.app.RecruitID)
+	if err != nil {
+		return nil, nil
+	}
+	recruit := models.TransformRecruit(rawRecruit)
+	return resolveRecruit(r.crud, &recruit, r.viewer)
+}
+
+// Stage resolves Application.Stage
+func (r *ApplicationResolver) Stage() string {
+	return r.app.Stage
+}
+
+// History resolves Application.History
+func (r *ApplicationResolver) History() []*StageChangeResolver {
+	results := make([]*StageChangeResolver, 0)
+	for i := range r.app.History {
+		results = append(results, &StageChangeResolver{&r.app.History[i]})
+	}
+	return results
+}
+
+// CreatedAt resolves Application.CreatedAt
+func (r *ApplicationResolver) CreatedAt() Date {
+	return Date{r.app.CreatedAt}
+}
+
+// UpdatedAt resolves Application.UpdatedAt
+func (r *ApplicationResolver) UpdatedAt() Date {
+	return Date{r.app.UpdatedAt}
+}
+
+// -----------------
+// pipelineStage struct
+// -----------------
+type pipelineStage struct {
+	Stage string
+	Next  []string
+}
+
+// -----------------
+// PipelineStageResolver struct
+// -----------------
+
+// PipelineStageResolver resolves PipelineStage
+type PipelineStageResolver struct {
+	s *models.PipelineStage
+}
+
+// Stage resolves PipelineStage.Stage
+func (r *PipelineStageResolver) Stage() string {
+	return r.s.Stage
+}
+
+// Next resolves PipelineStage.Next
+func (r *PipelineStageResolver) Next() []string {
+	return r.s.Next
+}
+
+// -----------------
+// StageChangeResolver struct
+// -----------------
+
+// StageChangeResolver resolves StageChange
+type StageChangeResolver struct {
+	c *models.StageChange
+}
+
+// Stage resolves StageChange.Stage
+func (r *StageChangeResolver) Stage() string {
+	return r.c.Stage
+}
+
+// Note resolves StageChange.Note
+func (r *StageChangeResolver) Note() string {
+	return r.c.Note
+}
+
+// ChangedAt resolves StageChange.ChangedAt
+func (r *StageChangeResolver) ChangedAt() Date {
+	return Date{r.c.ChangedAt}
+}
@@ -0,0 +1,679 @@
+package resolvers
+
+import (
+	"log"
+
+	config "../config"
+	db "../database"
+	er "../errors"
+	models "../models"
+	utils "../utils"
+	graphql "github.com/graph-gophers/graphql-go"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// -----------------
+// HunterEditorResolver methods
+// -----------------
+
+// company retrieves the current Hunter's Company, failing if the Hunter
+// does not have one of the given roles within it
+func (r *HunterEditorResolver) company(roles ...string) (*models.Company, error) {
+	if utils.IsNullID(r.h.CompanyID) {
+		return nil, er.Input("Hunter does not belong to a Company.")
+	}
+
+	// check the hunter's role
+	allowed := len(roles) == 0
+	for _, role := range roles {
+		if r.h.Role == role {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, er.Input("Insufficient Company role.")
+	}
+
+	// retrieve company
+	rawCompany, err := r.crud.FindID(config.CompaniesCollection, r.h.CompanyID)
+	if err != nil {
+		log.Println("Failed to find company =>", err)
+		return nil, er.Generic()
+	}
+	company := models.TransformCompany(rawCompany)
+	return &company, nil
+}
+
+// member retrieves a Hunter belonging to the current Hunter's Company
+func (r *HunterEditorResolver) member(company *models.Company, hunterID graphql.ID) (*models.Hunter, error) {
+	id := string(hunterID)
+	if !bson.IsObjectIdHex(id) {
+		return nil, er.InvalidField("hunter_id")
+	}
+
+	rawHunter, err := r.crud.FindID(config.HuntersCollection, bson.ObjectIdHex(id))
+	if err != nil {
+		return nil, er.InvalidField("hunter_id")
+	}
+	hunter := models.TransformHunter(rawHunter)
+	if hunter.CompanyID != company.ID {
+		return nil, er.Input("Hunter is not a member of the Company.")
+	}
+	return &hunter, nil
+}
+
+// setCompany updates the current Hunter's Company and role
+func (r *HunterEditorResolver) setCompany(companyID bson.ObjectId, role string) error {
+	if err := r.crud.UpdateID(config.HuntersCollection, r.h.ID, bson.M{
+		"company_id": companyID,
+		"role":       role,
+	}); err != nil {
+		log.Println("Failed to update hunter company =>", err)
+		return er.Generic()
+	}
+	r.h.CompanyID = companyID
+	r.h.Role = role
+	return nil
+}
+
+// CreateCompany resolves HunterEditor.CreateCompany which creates a Company owned by the current Hunter
+func (r *HunterEditorResolver) CreateCompany(args struct{ Info *companyDetails }) (*CompanyResolver, error) {
+	defer r.crud.CloseCopy()
+
+	// check that the hunter doesn't have a company
+	if !utils.IsNullID(r.h.CompanyID) {
+		return nil, er.Input("Hunter already belongs to a Company.")
+	}
+
+	// check if info is nil
+	info := args.Info
+	if info == nil {
+		return nil, er.MissingField("info")
+	}
+
+	// validate info
+	if info.Name == nil {
+		return nil, er.MissingField("info.name")
+	}
+	if info.RegistrationNumber == nil {
+		return nil, er.MissingField("info.registration_number")
+	}
+	if info.Province == nil {
+		return nil, er.MissingField("info.province")
+	}
+	if info.City == nil {
+		return nil, er.MissingField("info.city")
+	}
+
+	// create company
+	company := models.Company{
+		ID:                 bson.NewObjectId(),
+		Name:               *info.Name,
+		RegistrationNumber: *info.RegistrationNumber,
+		Province:           *info.Province,
+		City:               *info.City,
+	}
+
+	// validate company
+	if err := company.OK(); err != nil {
+		return nil, err
+	}
+
+	// store company in db
+	if err := r.crud.Insert(config.CompaniesCollection, company); err != nil {
+		log.Println("Failed to create company =>", err)
+		return nil, er.Generic()
+	}
+
+	// make the hunter the company's owner
+	if err := r.setCompany(company.ID, models.CompanyRoleOwner); err != nil {
+		return nil, err
+	}
+
+	return &CompanyResolver{&company, r.crud}, nil
+}
+
+// UpdateCompany resolves HunterEditor.UpdateCompany
+func (r *HunterEditorResolver) UpdateCompany(args struct{ Info *companyDetails }) (*CompanyResolver, error) {
+	defer r.crud.CloseCopy()
+
+	// only owners may update the company
+	company, err := r.company(models.CompanyRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	// check if info is nil
+	info := args.Info
+	if info == nil {
+		return nil, er.MissingField("info")
+	}
+
+	// prepare updates
+	updates := bson.M{}
+	if info.Name != nil {
+		company.Name = *info.Name
+	}
+	if info.RegistrationNumber != nil {
+		company.RegistrationNumber = *info.RegistrationNumber
+	}
+	if info.Province != nil {
+		company.Province = *info.Province
+	}
+	if info.City != nil {
+		company.City = *info.City
+	}
+
+	// validate updates
+	if err := company.OK(); err != nil {
+		return nil, err
+	}
+	updates["name"] = company.Name
+	updates["registration_number"] = company.RegistrationNumber
+	updates["province"] = company.Province
+	updates["city"] = company.City
+
+	// perform update
+	rawCompany, err := GenericUpdateByID(r.crud, config.CompaniesCollection, company.ID, updates)
+	if err != nil {
+		return nil, err
+	}
+
+	// return updated company
+	updated := models.TransformCompany(rawCompany)
+	return &CompanyResolver{&updated, r.crud}, nil
+}
+
+// LeaveCompany resolves HunterEditor.LeaveCompany which removes the current Hunter from its Company
+func (r *HunterEditorResolver) LeaveCompany() (*string, error) {
+	defer r.crud.CloseCopy()
+
+	if err := r.leaveCompany(); err != nil {
+		return nil, err
+	}
+
+	result := "Company successfully left."
+	return &result, nil
+}
+
+// leaveCompany removes the current Hunter from its Company, the Company is removed along
+// with its last member. An only owner can't leave while the Company has other members
+func (r *HunterEditorResolver) leaveCompany() error {
+	company, err := r.company()
+	if err != nil {
+		return err
+	}
+
+	// find the company's members
+	rawMembers, err := r.crud.FindAll(config.HuntersCollection, bson.M{"company_id": company.ID})
+	if err != nil {
+		log.Println("Failed to find company members =>", err)
+		return er.Generic()
+	}
+
+	// an owner can't leave others behind without an owner
+	if r.h.Role == models.CompanyRoleOwner && len(rawMembers) > 1 {
+		owners := 0
+		for _, raw := range rawMembers {
+			if models.TransformHunter(raw).Role == models.CompanyRoleOwner {
+				owners++
+			}
+		}
+		if owners < 2 {
+			return er.Input("Company must have another owner before the owner can leave.")
+		}
+	}
+
+	if err := r.setCompany(models.NullObjectID, ""); err != nil {
+		return err
+	}
+
+	// remove the company along with its last member
+	if len(rawMembers) <= 1 {
+		if err := r.crud.DeleteID(config.CompaniesCollection, company.ID); err != nil {
+			log.Println("Failed to remove company =>", err)
+			return er.Generic()
+		}
+	}
+	return nil
+}
+
+// InviteHunter resolves HunterEditor.InviteHunter which invites the account with
+// the given email to join the current Hunter's Company
+func (r *HunterEditorResolver) InviteHunter(args struct {
+	Email string
+	Role  string
+}) (*CompanyInviteResolver, error) {
+	defer r.crud.CloseCopy()
+
+	// only owners may invite
+	company, err := r.company(models.CompanyRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	// create invite
+	invite := models.CompanyInvite{
+		ID:        bson.NewObjectId(),
+		CompanyID: company.ID,
+		InvitedBy: r.h.ID,
+		Email:     args.Email,
+		Role:      args.Role,
+		Status:    models.InvitePending,
+	}
+
+	// validate invite
+	if err := invite.OK(); err != nil {
+		return nil, err
+	}
+
+	// check for an existing pending invite
+	if _, err := r.crud.FindOne(config.CompanyInvitesCollection, bson.M{
+		"company_id": company.ID,
+		"email":      invite.Email,
+		"status":     models.InvitePending,
+	}); err == nil {
+		return nil, er.Input("Account has already been invited.")
+	}
+
+	// store invite in db
+	if err := r.crud.Insert(config.CompanyInvitesCollection, invite); err != nil {
+		log.Println("Failed to create company invite =>", err)
+		return nil, er.Generic()
+	}
+
+	return &CompanyInviteResolver{&invite, r.crud}, nil
+}
+
+// pendingInvite retrieves a pending CompanyInvite addressed to the current account
+func (r *HunterEditorResolver) pendingInvite(inviteID graphql.ID) (*models.CompanyInvite, error) {
+	id := string(inviteID)
+	if !bson.IsObjectIdHex(id) {
+		return nil, er.InvalidField("id")
+	}
+
+	rawInvite, err := r.crud.FindID(config.CompanyInvitesCollection, bson.ObjectIdHex(id))
+	if err != nil {
+		return nil, er.InvalidField("id")
+	}
+	invite := models.TransformCompanyInvite(rawInvite)
+	if invite.Email != r.a.Email || invite.Status != models.InvitePending {
+		return nil, er.InvalidField("id")
+	}
+	return &invite, nil
+}
+
+// AcceptInvite resolves HunterEditor.AcceptInvite which adds the current Hunter to the inviting Company
+func (r *HunterEditorResolver) AcceptInvite(args struct{ ID graphql.ID }) (*CompanyResolver, error) {
+	defer r.crud.CloseCopy()
+
+	// check that the hunter doesn't have a company
+	if !utils.IsNullID(r.h.CompanyID) {
+		return nil, er.Input("Hunter already belongs to a Company.")
+	}
+
+	invite, err := r.pendingInvite(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// retrieve the inviting company
+	rawCompany, err := r.crud.FindID(config.CompaniesCollection, invite.CompanyID)
+	if err != nil {
+		return nil, er.Input("Company no longer exists.")
+	}
+	company := models.TransformCompany(rawCompany)
+
+	// mark invite as accepted
+	if err := r.crud.UpdateID(config.CompanyInvitesCollection, invite.ID, bson.M{
+		"status": models.InviteAccepted,
+	}); err != nil {
+		log.Println("Failed to update company invite =>", err)
+		return nil, er.Generic()
+	}
+
+	// join the company
+	if err := r.setCompany(company.ID, invite.Role); err != nil {
+		return nil, err
+	}
+
+	return &CompanyResolver{&company, r.crud}, nil
+}
+
+// DeclineInvite resolves HunterEditor.DeclineInvite
+func (r *HunterEditorResolver) DeclineInvite(args struct{ ID graphql.ID }) (*string, error) {
+	defer r.crud.CloseCopy()
+
+	invite, err := r.pendingInvite(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// mark invite as declined
+	if err := r.crud.UpdateID(config.CompanyInvitesCollection, invite.ID, bson.M{
+		"status": models.InviteDeclined,
+	}); err != nil {
+		log.Println("Failed to update company invite =>", err)
+		return nil, er.Generic()
+	}
+
+	result := "Invite successfully declined."
+	return &result, nil
+}
+
+// SetMemberRole resolves HunterEditor.SetMemberRole
+func (r *HunterEditorResolver) SetMemberRole(args struct {
+	HunterID graphql.ID
+	Role     string
+}) (*HunterResolver, error) {
+	defer r.crud.CloseCopy()
+
+	// only owners may change roles
+	company, err := r.company(models.CompanyRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	if !models.IsCompanyRole(args.Role) {
+		return nil, er.InvalidField("role")
+	}
+
+	hunter, err := r.member(company, args.HunterID)
+	if err != nil {
+		return nil, err
+	}
+
+	// owners can't demote themselves
+	if hunter.ID == r.h.ID && args.Role != models.CompanyRoleOwner {
+		return nil, er.Input("Owners can't change their own role.")
+	}
+
+	// perform update
+	if err := r.crud.UpdateID(config.HuntersCollection, hunter.ID, bson.M{
+		"role": args.Role,
+	}); err != nil {
+		log.Println("Failed to update hunter role =>", err)
+		return nil, er.Generic()
+	}
+	hunter.Role = args.Role
+
+	return resolveHunter(r.crud, hunter)
+}
+
+// RemoveMember resolves HunterEditor.RemoveMember which removes a Hunter from the current Hunter's Company
+func (r *HunterEditorResolver) RemoveMember(args struct{ HunterID graphql.ID }) (*string, error) {
+	defer r.crud.CloseCopy()
+
+	// only owners may remove members
+	company, err := r.company(models.CompanyRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	hunter, err := r.member(company, args.HunterID)
+	if err != nil {
+		return nil, err
+	}
+	if hunter.ID == r.h.ID {
+		return nil, er.Input("Use 'leaveCompany' to leave the Company.")
+	}
+
+	// perform update
+	if err := r.crud.UpdateID(config.HuntersCollection, hunter.ID, bson.M{
+		"company_id": models.NullObjectID,
+		"role":       "",
+	}); err != nil {
+		log.Println("Failed to remove company member =>", err)
+		return nil, er.Generic()
+	}
+
+	result := "Member successfully removed."
+	return &result, nil
+}
+
+// RemoveCompanyDocument resolves HunterEditor.RemoveCompanyDocument
+func (r *HunterEditorResolver) RemoveCompanyDocument(args struct{ ID graphql.ID }) (*string, error) {
+	company, err := r.company(models.CompanyRoleOwner, models.CompanyRoleRecruiter)
+	if err != nil {
+		r.crud.CloseCopy()
+		return nil, err
+	}
+
+	// check the id
+	id := string(args.ID)
+	if !bson.IsObjectIdHex(id) {
+		return nil, er.InvalidField("id")
+	}
+
+	// check that the document belongs to the company
+	rawDocument, err := r.crud.FindOne(config.DocumentsCollection, bson.M{
+		"_id":      bson.ObjectIdHex(id),
+		"owner_id": company.ID,
+	})
+	if err != nil {
+		r.crud.CloseCopy()
+		return nil, er.InvalidField("id")
+	}
+
+	result, err := ResolveRemoveByID(r.crud, config.DocumentsCollection, "Document", bson.ObjectIdHex(id))
+	if err == nil {
+		r.index.Delete(searchKindDocument, id)
+		removeDocumentFile(r.crud, r.store, models.TransformDocument(rawDocument))
+	}
+	return result, err
+}
+
+// -----------------
+// HunterViewerResolver methods
+// -----------------
+
+// Company resolves HunterViewer.Company which returns the current Hunter's Company
+func (r *HunterViewerResolver) Company() (*CompanyResolver, error) {
+	defer r.crud.CloseCopy()
+
+	if utils.IsNullID(r.h.CompanyID) {
+		return nil, nil
+	}
+
+	rawCompany, err := r.crud.FindID(config.CompaniesCollection, r.h.CompanyID)
+	if err != nil {
+		log.Println("Failed to find company =>", err)
+		return nil, er.Generic()
+	}
+	company := models.TransformCompany(rawCompany)
+	return &CompanyResolver{&company, r.crud}, nil
+}
+
+// Invites resolves HunterViewer.Invites which returns the current account's pending CompanyInvites
+func (r *HunterViewerResolver) Invites() ([]*CompanyInviteResolver, error) {
+	defer r.crud.CloseCopy()
+
+	rawInvites, err := r.crud.FindAll(config.CompanyInvitesCollection, bson.M{
+		"email":  r.a.Email,
+		"status": models.InvitePending,
+	})
+	if err != nil {
+		return nil, er.Generic()
+	}
+
+	// process results
+	results := make([]*CompanyInviteResolver, 0)
+	for _, raw := range rawInvites {
+		invite := models.TransformCompanyInvite(raw)
+		results = append(results, &CompanyInviteResolver{&invite, r.crud})
+	}
+	return results, nil
+}
+
+// -----------------
+// helpers
+// -----------------
+
+// resolveHunter creates a HunterResolver, retrieving the Hunter's account
+func resolveHunter(crud *db.CRUD, hunter *models.Hunter) (*HunterResolver, error) {
+	rawAccount, err := crud.FindOne(config.AccountsCollection, bson.M{"hunter_id": hunter.ID})
+	if err != nil {
+		log.Println("Failed to find hunter's account =>", err)
+		return nil, er.Generic()
+	}
+	account := models.TransformAccount(rawAccount)
+	return &HunterResolver{hunter, &account}, nil
+}
+
+// -----------------
+// companyDetails struct
+// -----------------
+type companyDetails struct {
+	Name               *string
+	RegistrationNumber *string
+	Province           *string
+	City               *string
+}
+
+// -----------------
+// CompanyResolver struct
+// -----------------
+
+// CompanyResolver resolves Company
+type CompanyResolver struct {
+	c    *models.Company
+	crud *db.CRUD
+}
+
+// ID resolves Company.ID
+func (r *CompanyResolver) ID() graphql.ID {
+	return graphql.ID(r.c.ID.Hex())
+}
+
+// Name resolves Company.Name
+func (r *CompanyResolver) Name() string {
+	return r.c.Name
+}
+
+// RegistrationNumber resolves Company.RegistrationNumber
+func (r *CompanyResolver) RegistrationNumber() string {
+	return r.c.RegistrationNumber
+}
+
+// Province resolves Company.Province
+func (r *CompanyResolver) Province() string {
+	return r.c.Province
+}
+
+// City resolves Company.City
+func (r *CompanyResolver) City() string {
+	return r.c.City
+}
+
+// Members resolves Company.Members which returns the Hunters belonging to the Company
+func (r *CompanyResolver) Members() ([]*HunterResolver, error) {
+	defer r.crud.CloseCopy()
+
+	rawHunters, err := r.crud.FindAll(config.HuntersCollection, bson.M{"company_id": r.c.ID})
+	if err != nil {
+		return nil, er.Generic()
+	}
+
+	// process results
+	results := make([]*HunterResolver, 0)
+	for _, raw := range rawHunters {
+		hunter := models.TransformHunter(raw)
+		resolver, err := resolveHunter(r.crud, &hunter)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, resolver)
+	}
+	return results, nil
+}
+
+// Documents resolves Company.Documents which returns the Company's documents
+func (r *CompanyResolver) Documents() ([]*DocumentResolver, error) {
+	defer r.crud.CloseCopy()
+
+	rawDocuments, err := r.crud.FindAll(config.DocumentsCollection, bson.M{"owner_id": r.c.ID})
+	if err != nil {
+		return nil, er.Generic()
+	}
+
+	// process results
+	results := make([]*DocumentResolver, 0)
+	for _, raw := range rawDocuments {
+		document := models.TransformDocument(raw)
+		results = append(results, &DocumentResolver{&document})
+	}
+	return results, nil
+}
+
+// Invites resolves Company.Invites which returns the Company's pending invites
+func (r *CompanyResolver) Invites() ([]*CompanyInviteResolver, error) {
+	defer r.crud.CloseCopy()
+
+	rawInvites, err := r.crud.FindAll(config.CompanyInvitesCollection, bson.M{
+		"company_id": r.c.ID,
+		"status":     models.InvitePending,
+	})
+	if err != nil {
+		return nil, er.Generic()
+	}
+
+	// process results
+	results := make([]*CompanyInviteResolver, 0)
+	for _, raw := range rawInvites {
+		invite := models.TransformCompanyInvite(raw)
+		results = append(results, &CompanyInviteResolver{&invite, r.crud})
+	}
+	return results, nil
+}
+
+// Pipeline resolves Company.Pipeline which returns the stages the Company moves its applications through
+func (r *CompanyResolver) Pipeline() []*PipelineStageResolver {
+	pipeline := r.c.ApplicationPipeline()
+	results := make([]*PipelineStageResolver, 0, len(pipeline))
+	for i := range pipeline {
+		results = append(results, &PipelineStageResolver{&pipeline[i]})
+	}
+	return results
+}
+
+// -----------------
+// CompanyInviteResolver struct
+// -----------------
+
+// CompanyInviteResolver resolves CompanyInvite
+type CompanyInviteResolver struct {
+	i    *models.CompanyInvite
+	crud *db.CRUD
+}
+
+// ID resolves CompanyInvite.ID
+func (r *CompanyInviteResolver) ID() graphql.ID {
+	return graphql.ID(r.i.ID.Hex())
+}
+
+// Company resolves CompanyInvite.Company
+func (r *CompanyInviteResolver) Company() (*CompanyResolver, error) {
+	defer r.crud.CloseCopy()
+
+	rawCompany, err := r.crud.FindID(config.CompaniesCollection, r.i.CompanyID)
+	if err != nil {
+		return nil, nil
+	}
+	company := models.TransformCompany(rawCompany)
+	return &CompanyResolver{&company, r.crud}, nil
+}
+
+// Email resolves CompanyInvite.Email
+func (r *CompanyInviteResolver) Email() string {
+	return r.i.Email
+}
+
+// Role resolves CompanyInvite.Role
+func (r *CompanyInviteResolver) Role() string {
+	return r.i.Role
+}
+
+// Status resolves CompanyInvite.Status
+func (r *CompanyInviteResolver) Status() string {
+	return r.i.Status
+}
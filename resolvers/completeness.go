@@ -0,0 +1,164 @@
+package resolvers
+
+import (
+	"log"
+	"sort"
+
+	config "../config"
+	db "../database"
+	er "../errors"
+	models "../models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	defaultIncompletePageSize = 20
+	maxIncompletePageSize     = 100
+)
+
+// Completeness resolves RecruitViewer.Completeness which is how much of the Recruit's profile is filled in
+func (r *RecruitViewerResolver) Completeness() (*CompletenessResolver, error) {
+	defer r.crud.CloseCopy()
+
+	documents, err := recruitDocuments(r.crud, bson.M{"owner_id": r.r.ID})
+	if err != nil {
+		return nil, err
+	}
+	completeness := models.RecruitCompleteness(*r.r, documents)
+	return &CompletenessResolver{&completeness}, nil
+}
+
+// IncompleteRecruits resolves SysViewer.IncompleteRecruits which returns the recruits whose
+// profiles are less complete than the given percentage, least complete first
+func (r *SysViewerResolver) IncompleteRecruits(args struct {
+	Below int32
+	First *int32
+	Skip  *int32
+}) ([]*IncompleteRecruitResolver, error) {
+	defer r.crud.CloseCopy()
+
+	// check the args
+	if args.Below < 1 || args.Below > 100 {
+		return nil, er.InvalidField("below")
+	}
+	limit := defaultIncompletePageSize
+	if args.First != nil {
+		limit = int(*args.First)
+		if limit < 1 || limit > maxIncompletePageSize {
+			return nil, er.InvalidField("first")
+		}
+	}
+	skip := 0
+	if args.Skip != nil {
+		skip = int(*args.Skip)
+		if skip < 0 {
+			return nil, er.InvalidField("skip")
+		}
+	}
+
+	// fetch recruits and their documents
+	rawRecruits, err := r.crud.FindAll(config.RecruitsCollection, nil)
+	if err != nil {
+		log.Println("Failed to find recruits =>", err)
+		return nil, er.Generic()
+	}
+	documents, err := recruitDocuments(r.crud, nil)
+	if err != nil {
+		return nil, err
+	}
+	owned := make(map[bson.ObjectId][]models.Document)
+	for _, doc := range documents {
+		owned[doc.OwnerID] = append(owned[doc.OwnerID], doc)
+	}
+
+	// keep the incomplete ones
+	type incomplete struct {
+		recruit      models.Recruit
+		completeness models.Completeness
+	}
+	matches := make([]incomplete, 0)
+	for _, raw := range rawRecruits {
+		recruit := models.TransformRecruit(raw)
+		completeness := models.RecruitCompleteness(recruit, owned[recruit.ID])
+		if completeness.Percent < int(args.Below) {
+			matches = append(matches, incomplete{recruit, completeness})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].completeness.Percent < matches[j].completeness.Percent
+	})
+
+	// page the results
+	results := make([]*IncompleteRecruitResolver, 0)
+	for i := skip; i < len(matches) && i < skip+limit; i++ {
+		recruit, err := resolveRecruit(r.crud, &matches[i].recruit, r.a)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, &IncompleteRecruitResolver{recruit, &matches[i].completeness})
+	}
+	return results, nil
+}
+
+// -----------------
+// helpers
+// -----------------
+
+// recruitDocuments retrieves the qualification documents owned by recruits matching the query
+func recruitDocuments(crud *db.CRUD, query bson.M) ([]models.Document, error) {
+	if query == nil {
+		query = bson.M{}
+	}
+	query["owner_type"] = "RECRUIT"
+	query["doc_type"] = models.QualificationDocument
+
+	rawDocuments, err := crud.FindAll(config.DocumentsCollection, query)
+	if err != nil {
+		log.Println("Failed to find recruit documents =>", err)
+		return nil, er.Generic()
+	}
+	documents := make([]models.Document, 0, len(rawDocuments))
+	for _, raw := range rawDocuments {
+		documents = append(documents, models.TransformDocument(raw))
+	}
+	return documents, nil
+}
+
+// -----------------
+// CompletenessResolver struct
+// -----------------
+
+// CompletenessResolver resolves ProfileCompleteness
+type CompletenessResolver struct {
+	c *models.Completeness
+}
+
+// Percent resolves ProfileCompleteness.Percent
+func (r *CompletenessResolver) Percent() int32 {
+	return int32(r.c.Percent)
+}
+
+// Missing resolves ProfileCompleteness.Missing
+func (r *CompletenessResolver) Missing() []string {
+	return r.c.Missing
+}
+
+// -----------------
+// IncompleteRecruitResolver struct
+// -----------------
+
+// IncompleteRecruitResolver resolves IncompleteRecruit
+type IncompleteRecruitResolver struct {
+	recruit      *RecruitResolver
+	completeness *models.Completeness
+}
+
+// Recruit resolves IncompleteRecruit.Recruit
+func (r *IncompleteRecruitResolver) Recruit() *RecruitResolver {
+	return r.recruit
+}
+
+// Completeness resolves IncompleteRecruit.Completeness
+func (r *IncompleteRecruitResolver) Completeness() *CompletenessResolver {
+	return &CompletenessResolver{r.completeness}
+}
@@ -0,0 +1,469 @@
+package resolvers
+
+import (
+	"log"
+	"time"
+
+	config "../config"
+	credits "../credits"
+	db "../database"
+	er "../errors"
+	models "../models"
+	utils "../utils"
+	graphql "github.com/graph-gophers/graphql-go"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// contactAccess describes which of a Recruit's contact details a viewer may see
+type contactAccess struct {
+	phone bool
+	email bool
+}
+
+// fullContactAccess is the access of Recruits to their own profile and of sys admins
+var fullContactAccess = contactAccess{phone: true, email: true}
+
+// viewerContactAccess finds the contact details of the Recruit the viewer may see.
+// Hunters only see the details unlocked by an approved contact request that the
+// Recruit still shares, everyone else sees none
+func viewerContactAccess(crud *db.CRUD, recruit *models.Recruit, viewer *models.Account) contactAccess {
+	if viewer == nil {
+		return contactAccess{}
+	}
+	if viewer.RecruitID == recruit.ID || utils.IsSysAccount(viewer) {
+		return fullContactAccess
+	}
+	if utils.IsNullID(viewer.HunterID) {
+		return contactAccess{}
+	}
+
+	rawRequest, err := crud.FindOne(config.ContactRequestsCollection, bson.M{
+		"recruit_id": recruit.ID,
+		"hunter_id":  viewer.HunterID,
+		"status":     models.ContactApproved,
+	})
+	if err != nil {
+		return contactAccess{}
+	}
+	request := models.TransformContactRequest(rawRequest)
+	return contactAccess{
+		phone: request.Phone && recruit.Privacy.SharePhone,
+		email: request.Email && recruit.Privacy.ShareEmail,
+	}
+}
+
+// -----------------
+// HunterEditorResolver methods
+// -----------------
+
+// RequestContact resolves HunterEditor.RequestContact which asks a Recruit for access to their contact details
+func (r *HunterEditorResolver) RequestContact(args struct {
+	RecruitID graphql.ID
+	Message   *string
+}) (*ContactRequestResolver, error) {
+	defer r.crud.CloseCopy()
+
+	// check the recruit
+	recruitID := string(args.RecruitID)
+	if !bson.IsObjectIdHex(recruitID) {
+		return nil, er.InvalidField("recruit_id")
+	}
+	rawRecruit, err := r.crud.FindID(config.RecruitsCollection, bson.ObjectIdHex(recruitID))
+	if err != nil {
+		return nil, er.Input("Recruit not found.")
+	}
+	recruit := models.TransformRecruit(rawRecruit)
+	if !recruit.Privacy.AllowRequests {
+		return nil, er.Input("Recruit does not accept contact requests.")
+	}
+
+	// hunters may only ask once
+	if _, err := r.crud.FindOne(config.ContactRequestsCollection, bson.M{
+		"recruit_id": recruit.ID,
+		"hunter_id":  r.h.ID,
+	}); err == nil {
+		return nil, er.Input("Contact details already requested.")
+	}
+
+	// store the request
+	request := models.ContactRequest{
+		ID:        bson.NewObjectId(),
+		RecruitID: recruit.ID,
+		HunterID:  r.h.ID,
+		CompanyID: r.h.CompanyID,
+		Status:    models.ContactPending,
+		CreatedAt: time.Now(),
+	}
+	if utils.IsNullID(request.CompanyID) {
+		request.CompanyID = models.NullObjectID
+	}
+	if args.Message != nil {
+		request.Message = *args.Message
+	}
+	if err := request.OK(); err != nil {
+		return nil, err
+	}
+
+	// pay for the request, declined requests are refunded
+	if _, err := r.ledger.Spend(r.h.ID, credits.CostContactRequest, "Contact request", request.ID, r.a.ID); err != nil {
+		return nil, err
+	}
+	if err := r.crud.Insert(config.ContactRequestsCollection, request); err != nil {
+		log.Println("Failed to store contact request =>", err)
+		if _, err := r.ledger.Refund(r.h.ID, "Contact request failed", request.ID, r.a.ID); err != nil {
+			log.Println("Failed to refund contact request =>", err)
+		}
+		return nil, er.Generic()
+	}
+
+	notifyContactRequested(r.crud, r.notifier, r.a, &request)
+	return &ContactRequestResolver{&request, r.a, r.crud}, nil
+}
+
+// -----------------
+// RecruitEditorResolver methods
+// -----------------
+
+// contactRequest finds one of the Recruit's contact requests
+func (r *RecruitEditorResolver) contactRequest(requestID graphql.ID) (*models.ContactRequest, error) {
+	id := string(requestID)
+	if !bson.IsObjectIdHex(id) {
+		return nil, er.InvalidField("id")
+	}
+	rawRequest, err := r.crud.FindOne(config.ContactRequestsCollection, bson.M{
+		"_id":        bson.ObjectIdHex(id),
+		"recruit_id": r.r.ID,
+	})
+	if err != nil {
+		return nil, er.Input("Contact request not found.")
+	}
+	request := models.TransformContactRequest(rawRequest)
+	return &request, nil
+}
+
+// RespondContactRequest resolves RecruitEditor.RespondContactRequest which approves or declines
+// a contact request. Approving unlocks the contact details the Recruit shares and records the unlock
+func (r *RecruitEditorResolver) RespondContactRequest(args struct {
+	ID      graphql.ID
+	Approve bool
+}) (*ContactRequestResolver, error) {
+	defer r.crud.CloseCopy()
+
+	request, err := r.contactRequest(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != models.ContactPending {
+		return nil, er.Input("Contact request already answered.")
+	}
+
+	now := time.Now()
+	request.RespondedAt = now
+	request.Status = models.ContactDeclined
+	if args.Approve {
+		request.Status = models.ContactApproved
+		request.Phone = r.r.Privacy.SharePhone
+		request.Email = r.r.Privacy.ShareEmail
+
+		// record the unlock before granting access
+		unlock := models.ContactUnlock{
+			ID:        bson.NewObjectId(),
+			RequestID: request.ID,
+			RecruitID: request.RecruitID,
+			HunterID:  request.HunterID,
+			CompanyID: request.CompanyID,
+			GrantedBy: r.a.ID,
+			Phone:     request.Phone,
+			Email:     request.Email,
+			CreatedAt: now,
+		}
+		if err := r.crud.Insert(config.ContactUnlocksCollection, unlock); err != nil {
+			log.Println("Failed to record contact unlock =>", err)
+			return nil, er.Generic()
+		}
+	}
+
+	if err := r.crud.UpdateID(config.ContactRequestsCollection, request.ID, bson.M{
+		"status":       request.Status,
+		"phone":        request.Phone,
+		"email":        request.Email,
+		"responded_at": request.RespondedAt,
+	}); err != nil {
+		log.Println("Failed to update contact request =>", err)
+		return nil, er.Generic()
+	}
+	if request.Status == models.ContactDeclined {
+		if _, err := r.ledger.Refund(request.HunterID, "Contact request declined", request.ID, r.a.ID); err != nil {
+			log.Println("Failed to refund contact request =>", err)
+		}
+	}
+
+	notifyContactAnswered(r.crud, r.notifier, request)
+	return &ContactRequestResolver{request, r.a, r.crud}, nil
+}
+
+// RevokeContactAccess resolves RecruitEditor.RevokeContactAccess which hides the Recruit's
+// contact details from a Hunter again
+func (r *RecruitEditorResolver) RevokeContactAccess(args struct{ ID graphql.ID }) (*ContactRequestResolver, error) {
+	defer r.crud.CloseCopy()
+
+	request, err := r.contactRequest(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != models.ContactApproved {
+		return nil, er.Input("Contact access has not been granted.")
+	}
+
+	request.Status = models.ContactRevoked
+	request.RespondedAt = time.Now()
+	if err := r.crud.UpdateID(config.ContactRequestsCollection, request.ID, bson.M{
+		"status":       request.Status,
+		"responded_at": request.RespondedAt,
+	}); err != nil {
+		log.Println("Failed to update contact request =>", err)
+		return nil, er.Generic()
+	}
+	return &ContactRequestResolver{request, r.a, r.crud}, nil
+}
+
+// UpdatePrivacy resolves RecruitEditor.UpdatePrivacy
+func (r *RecruitEditorResolver) UpdatePrivacy(args struct {
+	AllowRequests *bool
+	SharePhone    *bool
+	ShareEmail    *bool
+}) (*RecruitPrivacyResolver, error) {
+	defer r.crud.CloseCopy()
+
+	privacy := r.r.Privacy
+	if args.AllowRequests != nil {
+		privacy.AllowRequests = *args.AllowRequests
+	}
+	if args.SharePhone != nil {
+		privacy.SharePhone = *args.SharePhone
+	}
+	if args.ShareEmail != nil {
+		privacy.ShareEmail = *args.ShareEmail
+	}
+
+	if err := r.crud.UpdateID(config.RecruitsCollection, r.r.ID, bson.M{"privacy": privacy}); err != nil {
+		log.Println("Failed to update privacy settings =>", err)
+		return nil, er.Generic()
+	}
+	r.r.Privacy = privacy
+	return &RecruitPrivacyResolver{&privacy}, nil
+}
+
+// -----------------
+// Viewer methods
+// -----------------
+
+// Privacy resolves RecruitViewer.Privacy
+func (r *RecruitViewerResolver) Privacy() *RecruitPrivacyResolver {
+	return &RecruitPrivacyResolver{&r.r.Privacy}
+}
+
+// ContactRequests resolves RecruitViewer.ContactRequests
+func (r *RecruitViewerResolver) ContactRequests(args struct{ Status *string }) ([]*ContactRequestResolver, error) {
+	return findContactRequests(r.crud, bson.M{"recruit_id": r.r.ID}, args.Status, r.a)
+}
+
+// ContactRequests resolves HunterViewer.ContactRequests
+func (r *HunterViewerResolver) ContactRequests(args struct{ Status *string }) ([]*ContactRequestResolver, error) {
+	return findContactRequests(r.crud, bson.M{"hunter_id": r.h.ID}, args.Status, r.a)
+}
+
+// ContactUnlocks resolves SysViewer.ContactUnlocks which lists the contact unlock audit trail
+func (r *SysViewerResolver) ContactUnlocks(args struct{ RecruitID *graphql.ID }) ([]*ContactUnlockResolver, error) {
+	defer r.crud.CloseCopy()
+
+	query := bson.M{}
+	if args.RecruitID != nil {
+		id := string(*args.RecruitID)
+		if !bson.IsObjectIdHex(id) {
+			return nil, er.InvalidField("recruit_id")
+		}
+		query["recruit_id"] = bson.ObjectIdHex(id)
+	}
+
+	rawUnlocks, _, err := r.crud.FindPage(config.ContactUnlocksCollection, query, []string{"-created_at"}, 0, 0)
+	if err != nil {
+		log.Println(err)
+		return nil, er.Generic()
+	}
+	results := make([]*ContactUnlockResolver, 0)
+	for _, raw := range rawUnlocks {
+		unlock := models.TransformContactUnlock(raw)
+		results = append(results, &ContactUnlockResolver{&unlock})
+	}
+	return results, nil
+}
+
+// -----------------
+// helpers
+// -----------------
+
+// findContactRequests finds the contact requests matching the query, newest first
+func findContactRequests(crud *db.CRUD, query bson.M, status *string, viewer *models.Account) ([]*ContactRequestResolver, error) {
+	defer crud.CloseCopy()
+
+	if status != nil {
+		query["status"] = *status
+	}
+	rawRequests, _, err := crud.FindPage(config.ContactRequestsCollection, query, []string{"-created_at"}, 0, 0)
+	if err != nil {
+		log.Println(err)
+		return nil, er.Generic()
+	}
+	results := make([]*ContactRequestResolver, 0)
+	for _, raw := range rawRequests {
+		request := models.TransformContactRequest(raw)
+		results = append(results, &ContactRequestResolver{&request, viewer, crud})
+	}
+	return results, nil
+}
+
+// -----------------
+// RecruitPrivacyResolver struct
+// -----------------
+
+// RecruitPrivacyResolver resolves RecruitPrivacy
+type RecruitPrivacyResolver struct {
+	p *models.RecruitPrivacy
+}
+
+// AllowRequests resolves RecruitPrivacy.AllowRequests
+func (r *RecruitPrivacyResolver) AllowRequests() bool {
+	return r.p.AllowRequests
+}
+
+// SharePhone resolves RecruitPrivacy.SharePhone
+func (r *RecruitPrivacyResolver) SharePhone() bool {
+	return r.p.SharePhone
+}
+
+// ShareEmail resolves RecruitPrivacy.ShareEmail
+func (r *RecruitPrivacyResolver) ShareEmail() bool {
+	return r.p.ShareEmail
+}
+
+// -----------------
+// ContactRequestResolver struct
+// -----------------
+
+// ContactRequestResolver resolves ContactRequest
+type ContactRequestResolver struct {
+	req    *models.ContactRequest
+	viewer *models.Account
+	crud   *db.CRUD
+}
+
+// ID resolves ContactRequest.ID
+func (r *ContactRequestResolver) ID() graphql.ID {
+	return graphql.ID(r.req.ID.Hex())
+}
+
+// Recruit resolves ContactRequest.Recruit
+func (r *ContactRequestResolver) Recruit() (*RecruitResolver, error) {
+	defer r.crud.CloseCopy()
+
+	rawRecruit, err := r.crud.FindID(config.RecruitsCollection, r.req.RecruitID)
+	if err != nil {
+		return nil, nil
+	}
+	recruit := models.TransformRecruit(rawRecruit)
+	return resolveRecruit(r.crud, &recruit, r.viewer)
+}
+
+// Hunter resolves ContactRequest.Hunter
+func (r *ContactRequestResolver) Hunter() (*HunterResolver, error) {
+	defer r.crud.CloseCopy()
+
+	rawHunter, err := r.crud.FindID(config.HuntersCollection, r.req.HunterID)
+	if err != nil {
+		return nil, nil
+	}
+	hunter := models.TransformHunter(rawHunter)
+	return resolveHunter(r.crud, &hunter)
+}
+
+// Message resolves ContactRequest.Message
+func (r *ContactRequestResolver) Message() string {
+	return r.req.Message
+}
+
+// Status resolves ContactRequest.Status
+func (r *ContactRequestResolver) Status() string {
+	return r.req.Status
+}
+
+// CreatedAt resolves ContactRequest.CreatedAt
+func (r *ContactRequestResolver) CreatedAt() Date {
+	return Date{r.req.CreatedAt}
+}
+
+// RespondedAt resolves ContactRequest.RespondedAt, which is null until the Recruit responds
+func (r *ContactRequestResolver) RespondedAt() *Date {
+	if r.req.RespondedAt.IsZero() {
+		return nil
+	}
+	return &Date{r.req.RespondedAt}
+}
+
+// -----------------
+// ContactUnlockResolver struct
+// -----------------
+
+// ContactUnlockResolver resolves ContactUnlock
+type ContactUnlockResolver struct {
+	u *models.ContactUnlock
+}
+
+// ID resolves ContactUnlock.ID
+func (r *ContactUnlockResolver) ID() graphql.ID {
+	return graphql.ID(r.u.ID.Hex())
+}
+
+// RequestID resolves ContactUnlock.RequestID
+func (r *ContactUnlockResolver) RequestID() graphql.ID {
+	return graphql.ID(r.u.RequestID.Hex())
+}
+
+// RecruitID resolves ContactUnlock.RecruitID
+func (r *ContactUnlockResolver) RecruitID() graphql.ID {
+	return graphql.ID(r.u.RecruitID.Hex())
+}
+
+// HunterID resolves ContactUnlock.HunterID
+func (r *ContactUnlockResolver) HunterID() graphql.ID {
+	return graphql.ID(r.u.HunterID.Hex())
+}
+
+// CompanyID resolves ContactUnlock.CompanyID, which is null if the Hunter had no company
+func (r *ContactUnlockResolver) CompanyID() *graphql.ID {
+	if utils.IsNullID(r.u.CompanyID) {
+		return nil
+	}
+	id := graphql.ID(r.u.CompanyID.Hex())
+	return &id
+}
+
+// GrantedBy resolves ContactUnlock.GrantedBy, the ID of the account that approved the request
+func (r *ContactUnlockResolver) GrantedBy() graphql.ID {
+	return graphql.ID(r.u.GrantedBy.Hex())
+}
+
+// Phone resolves ContactUnlock.Phone
+func (r *ContactUnlockResolver) Phone() bool {
+	return r.u.Phone
+}
+
+// Email resolves ContactUnlock.Email
+func (r *ContactUnlockResolver) Email() bool {
+	return r.u.Email
+}
+
+// CreatedAt resolves ContactUnlock.CreatedAt
+func (r *ContactUnlockResolver) CreatedAt() Date {
+	return Date{r.u.CreatedAt}
+}
@@ -0,0 +1,186 @@
+package resolvers
+
+import (
+	"strings"
+
+	config "../config"
+	credits "../credits"
+	db "../database"
+	er "../errors"
+	models "../models"
+	utils "../utils"
+	graphql "github.com/graph-gophers/graphql-go"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// default and maximum number of credit entries returned at a time
+const (
+	defaultCreditPageSize = 20
+	maxCreditPageSize     = 100
+)
+
+// -----------------
+// HunterViewerResolver methods
+// -----------------
+
+// Credits resolves HunterViewer.Credits, the Hunter's credit balance and history
+func (r *HunterViewerResolver) Credits() *CreditsResolver {
+	return &CreditsResolver{r.h.ID, r.crud}
+}
+
+// -----------------
+// SysEditorResolver methods
+// -----------------
+
+// creditArgs are the arguments of the SysEditor credit mutations
+type creditArgs struct {
+	HunterID graphql.ID
+	Amount   int32
+	Reason   string
+}
+
+// hunterID checks that the Hunter exists
+func (args *creditArgs) hunterID(crud *db.CRUD) (bson.ObjectId, error) {
+	id := string(args.HunterID)
+	if !bson.IsObjectIdHex(id) {
+		return "", er.InvalidField("hunter_id")
+	}
+	if _, err := crud.FindID(config.HuntersCollection, bson.ObjectIdHex(id)); err != nil {
+		return "", er.Input("Hunter not found.")
+	}
+	return bson.ObjectIdHex(id), nil
+}
+
+// GrantCredits resolves SysEditor.GrantCredits which records credits bought by a Hunter
+func (r *SysEditorResolver) GrantCredits(args creditArgs) (*CreditEntryResolver, error) {
+	defer r.crud.CloseCopy()
+
+	hunterID, err := args.hunterID(r.crud)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := r.ledger.Grant(hunterID, int(args.Amount), strings.TrimSpace(args.Reason), r.a.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &CreditEntryResolver{entry}, nil
+}
+
+// AdjustCredits resolves SysEditor.AdjustCredits which corrects a Hunter's balance
+func (r *SysEditorResolver) AdjustCredits(args creditArgs) (*CreditEntryResolver, error) {
+	defer r.crud.CloseCopy()
+
+	hunterID, err := args.hunterID(r.crud)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := r.ledger.Adjust(hunterID, int(args.Amount), strings.TrimSpace(args.Reason), r.a.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &CreditEntryResolver{entry}, nil
+}
+
+// -----------------
+// CreditsResolver struct
+// -----------------
+
+// CreditsResolver resolves Credits
+type CreditsResolver struct {
+	hunterID bson.ObjectId
+	crud     *db.CRUD
+}
+
+// Balance resolves Credits.Balance
+func (r *CreditsResolver) Balance() (int32, error) {
+	defer r.crud.CloseCopy()
+
+	balance, err := credits.Balance(r.crud, r.hunterID)
+	return int32(balance), err
+}
+
+// Entries resolves Credits.Entries which returns a page of the ledger, newest first
+func (r *CreditsResolver) Entries(args struct {
+	First *int32
+	Skip  *int32
+}) ([]*CreditEntryResolver, error) {
+	defer r.crud.CloseCopy()
+
+	limit := defaultCreditPageSize
+	if args.First != nil {
+		limit = int(*args.First)
+		if limit < 1 || limit > maxCreditPageSize {
+			return nil, er.InvalidField("first")
+		}
+	}
+	skip := 0
+	if args.Skip != nil {
+		skip = int(*args.Skip)
+		if skip < 0 {
+			return nil, er.InvalidField("skip")
+		}
+	}
+
+	entries, _, err := credits.History(r.crud, r.hunterID, skip, limit)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*CreditEntryResolver, 0)
+	for i := range entries {
+		results = append(results, &CreditEntryResolver{&entries[i]})
+	}
+	return results, nil
+}
+
+// -----------------
+// CreditEntryResolver struct
+// -----------------
+
+// CreditEntryResolver resolves CreditEntry
+type CreditEntryResolver struct {
+	e *models.CreditEntry
+}
+
+// ID resolves CreditEntry.ID
+func (r *CreditEntryResolver) ID() graphql.ID {
+	return graphql.ID(r.e.ID.Hex())
+}
+
+// Seq resolves CreditEntry.Seq
+func (r *CreditEntryResolver) Seq() int32 {
+	return int32(r.e.Seq)
+}
+
+// Kind resolves CreditEntry.Kind
+func (r *CreditEntryResolver) Kind() string {
+	return r.e.Kind
+}
+
+// Amount resolves CreditEntry.Amount
+func (r *CreditEntryResolver) Amount() int32 {
+	return int32(r.e.Amount)
+}
+
+// Balance resolves CreditEntry.Balance
+func (r *CreditEntryResolver) Balance() int32 {
+	return int32(r.e.Balance)
+}
+
+// Reason resolves CreditEntry.Reason
+func (r *CreditEntryResolver) Reason() string {
+	return r.e.Reason
+}
+
+// SubjectID resolves CreditEntry.SubjectID, which is null for purchases and adjustments
+func (r *CreditEntryResolver) SubjectID() *graphql.ID {
+	if utils.IsNullID(r.e.SubjectID) {
+		return nil
+	}
+	id := graphql.ID(r.e.SubjectID.Hex())
+	return &id
+}
+
+// CreatedAt resolves CreditEntry.CreatedAt
+func (r *CreditEntryResolver) CreatedAt() Date {
+	return Date{r.e.CreatedAt}
+}
@@ -1,9 +1,16 @@
 package resolvers
 
 import (
+	"context"
+	"log"
+	"strings"
+
 	config "../config"
+	db "../database"
 	er "../errors"
 	models "../models"
+	storage "../storage"
+	utils "../utils"
 	graphql "github.com/graph-gophers/graphql-go"
 	"gopkg.in/mgo.v2/bson"
 )
@@ -12,28 +19,57 @@ import (
 // Root Resolver methods
 // -----------------
 
-// CreateDocument resolves "createDocument"  gql mutation
-func (r *RootResolver) CreateDocument(args struct {
-	OwnerID   graphql.ID
-	URL       string
-	DocType   string
-	OwnerType string
-}) (*DocumentResolver, error) {
+// UploadDocument creates the Document of a file uploaded with an access token. Qualifications
+// belong to the token account's recruit profile and company documents to the company of its
+// hunter profile. save stores the file once the uploader is known and returns its url
+func (r *RootResolver) UploadDocument(token, docType string, save func() (string, error)) (*models.Document, error) {
 	defer r.crud.CloseCopy()
 
-	// check that OwnerID is valid
-	id := string(args.OwnerID)
-	if !bson.IsObjectIdHex(id) {
-		return nil, er.InvalidField("owner_id")
+	// check token
+	claims, err := utils.GetTokenClaims(token)
+	if err != nil || claims.Refresh || !bson.IsObjectIdHex(claims.AccountID) {
+		return nil, er.InvalidToken()
+	}
+	rawAccount, err := r.crud.FindID(config.AccountsCollection, bson.ObjectIdHex(claims.AccountID))
+	if err != nil {
+		return nil, er.InvalidToken()
 	}
+	account := models.TransformAccount(rawAccount)
 
-	// create document
-	var document models.Document
-	document.ID = bson.NewObjectId()
-	document.URL = args.URL
-	document.OwnerType = args.OwnerType
-	document.DocType = args.DocType
-	document.OwnerID = bson.ObjectIdHex(id)
+	// find the owner
+	document := models.Document{ID: bson.NewObjectId(), DocType: docType}
+	switch docType {
+	case "QUALIFICATION":
+		if utils.IsNullID(account.RecruitID) {
+			return nil, er.Input("Only recruits can upload qualifications.")
+		}
+		document.OwnerType = "RECRUIT"
+		document.OwnerID = account.RecruitID
+	case "COMPANY":
+		if utils.IsNullID(account.HunterID) {
+			return nil, er.Input("Only hunters can upload company documents.")
+		}
+		rawHunter, err := r.crud.FindID(config.HuntersCollection, account.HunterID)
+		if err != nil {
+			log.Println("Failed to find hunter =>", err)
+			return nil, er.Generic()
+		}
+		hunter := models.TransformHunter(rawHunter)
+		editor := &HunterEditorResolver{h: &hunter, a: &account, crud: r.crud}
+		company, err := editor.company(models.CompanyRoleOwner, models.CompanyRoleRecruiter)
+		if err != nil {
+			return nil, err
+		}
+		document.OwnerType = "COMPANY"
+		document.OwnerID = company.ID
+	default:
+		return nil, er.InvalidField("doc_type")
+	}
+
+	// store the file
+	if document.URL, err = save(); err != nil {
+		return nil, err
+	}
 
 	// validate document
 	if err := document.OK(); err != nil {
@@ -42,10 +78,81 @@ func (r *RootResolver) CreateDocument(args struct {
 
 	// attempt to insert
 	if err := r.crud.Insert(config.DocumentsCollection, document); err != nil {
+		log.Println("Failed to create uploaded document =>", err)
 		return nil, er.Generic()
 	}
+	r.index.Put(documentSearchDoc(document))
 
-	return &DocumentResolver{&document}, nil
+	return &document, nil
+}
+
+// -----------------
+// SysEditorResolver methods
+// -----------------
+
+// VerifyDocument resolves SysEditor.VerifyDocument which marks a Document as verified
+func (r *SysEditorResolver) VerifyDocument(args struct{ ID graphql.ID }) (*DocumentResolver, error) {
+	defer r.crud.CloseCopy()
+
+	// check the id
+	id := string(args.ID)
+	if !bson.IsObjectIdHex(id) {
+		return nil, er.InvalidField("id")
+	}
+
+	rawDocument, err := r.crud.FindID(config.DocumentsCollection, bson.ObjectIdHex(id))
+	if err != nil {
+		return nil, er.InvalidField("id")
+	}
+	document := models.TransformDocument(rawDocument)
+	if document.Verified {
+		return nil, er.Input("Document has already been verified.")
+	}
+
+	// perform update
+	rawDocument, err = GenericUpdateByID(r.crud, config.DocumentsCollection, document.ID, bson.M{"verified": true})
+	if err != nil {
+		return nil, err
+	}
+
+	verified := models.TransformDocument(rawDocument)
+	notifyDocumentVerified(r.crud, r.notifier, &verified)
+	return &DocumentResolver{&verified}, nil
+}
+
+// removeDocumentFile removes the uploaded file of a removed Document. Documents created before
+// they had to be uploaded can link to any url, so only uploaded documents' files are removed,
+// and not while other documents still link to them. Recruit videos are stored under uploads/ and are never removed here
+func removeDocumentFile(crud *db.CRUD, store storage.Store, document models.Document) {
+	defer crud.CloseCopy()
+
+	key, ok := storage.KeyFromURL(document.URL)
+	if !ok || strings.Contains(key, "/") {
+		return
+	}
+	if _, err := crud.FindOne(config.DocumentsCollection, bson.M{"url": document.URL}); err == nil {
+		return
+	}
+	if err := store.Delete(key); err != nil {
+		log.Println("Failed to remove document file =>", err)
+	}
+}
+
+// signedFileURL turns the url of a stored file into a short lived signed url from the request's
+// store. Urls of files that aren't stored, like those of documents created before they had to
+// be uploaded, are left as they are
+func signedFileURL(ctx context.Context, url string) string {
+	key, ok := storage.KeyFromURL(url)
+	store := storage.FromContext(ctx)
+	if !ok || store == nil {
+		return url
+	}
+	signed, err := store.SignedURL(key, storage.SignedURLExpiry)
+	if err != nil {
+		log.Println("Failed to sign file url =>", err)
+		return url
+	}
+	return signed
 }
 
 // -----------------
@@ -72,9 +179,14 @@ func (r *DocumentResolver) OwnerType() string {
 	return r.q.OwnerType
 }
 
-// URL resolves Document.URL
-func (r *DocumentResolver) URL() string {
-	return r.q.URL
+// URL resolves Document.URL, uploaded files are only served at signed urls
+func (r *DocumentResolver) URL(ctx context.Context) string {
+	return signedFileURL(ctx, r.q.URL)
+}
+
+// Verified resolves Document.Verified
+func (r *DocumentResolver) Verified() bool {
+	return r.q.Verified
 }
 
 // DocType resolves Document.DocType
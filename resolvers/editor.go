@@ -2,11 +2,16 @@ package resolvers
 
 import (
 	"log"
+	"time"
 
 	config "../config"
+	credits "../credits"
 	db "../database"
 	er "../errors"
 	models "../models"
+	notify "../notify"
+	search "../search"
+	storage "../storage"
 	utils "../utils"
 	graphql "github.com/graph-gophers/graphql-go"
 	"gopkg.in/mgo.v2/bson"
@@ -59,11 +64,29 @@ func (r *RootResolver) Edit(args struct {
 
 		// return RecruitEditor
 		recruit := models.TransformRecruit(rawRecruit)
-		Editor := &RecruitEditorResolver{&recruit, &account, r.crud}
+		Editor := &RecruitEditorResolver{&recruit, &account, r.crud, r.index, r.notifier, r.ledger}
+		return &EditorResolver{Editor}, nil
+	}
+	editAsHunter := func() (*EditorResolver, error) {
+		// check if account has hunter profile
+		if utils.IsNullID(account.HunterID) {
+			return nil, er.Input("Failed to enforce 'HUNTER'.")
+		}
+
+		// retrieve Hunter profile
+		rawHunter, err := r.crud.FindID(config.HuntersCollection, account.HunterID)
+		if err != nil {
+			log.Println("Failed to find hunter =>", err)
+			return nil, er.Generic()
+		}
+
+		// return HunterEditor
+		hunter := models.TransformHunter(rawHunter)
+		Editor := &HunterEditorResolver{&hunter, &account, r.crud, r.index, r.notifier, r.ledger, r.store}
 		return &EditorResolver{Editor}, nil
 	}
 	editAsAccount := func() (*EditorResolver, error) {
-		return &EditorResolver{&AccountEditorResolver{&account, r.crud}}, nil
+		return &EditorResolver{&AccountEditorResolver{&account, r.crud, r.index}}, nil
 	}
 
 	editAsSys := func() (*EditorResolver, error) {
@@ -73,7 +96,7 @@ func (r *RootResolver) Edit(args struct {
 		}
 
 		// return sysEditor
-		Editor := &SysEditorResolver{&account, r.crud}
+		Editor := &SysEditorResolver{&account, r.crud, r.index, r.notifier, r.ledger, r.store}
 		return &EditorResolver{Editor}, nil
 	}
 
@@ -83,7 +106,7 @@ func (r *RootResolver) Edit(args struct {
 		case "RECRUIT":
 			return editAsRecruit()
 		case "HUNTER": // try to enforce hunter
-			return nil, er.Input("Unimplemented")
+			return editAsHunter()
 
 		case "SYSTEM": // try to enforce system
 			return editAsSys()
@@ -103,6 +126,11 @@ func (r *RootResolver) Edit(args struct {
 		return editor, nil
 	}
 
+	// try to edit as HunterEditor
+	if editor, err := editAsHunter(); err == nil {
+		return editor, nil
+	}
+
 	// if all else fails edit as AccountEditor
 	return editAsAccount()
 }
@@ -119,9 +147,12 @@ type editor interface{}
 
 // RecruitEditorResolver resolves RecruitEditor
 type RecruitEditorResolver struct {
-	r    *models.Recruit
-	a    *models.Account
-	crud *db.CRUD
+	r        *models.Recruit
+	a        *models.Account
+	crud     *db.CRUD
+	index    *search.Index
+	notifier *notify.Dispatcher
+	ledger   *credits.Ledger
 }
 
 // UpdateRecruit resolves RecruitEditor.UpdateRecruit
@@ -160,6 +191,20 @@ func (r *RecruitEditorResolver) UpdateRecruit(args struct {
 	if info.BirthYear != nil {
 		updates["birth_year"] = *info.BirthYear
 	}
+	if info.IndustryIDs != nil {
+		industryIDs, err := parseIndustryIDs(r.crud, *info.IndustryIDs, "info.industry_ids")
+		if err != nil {
+			return nil, err
+		}
+		updates["industry_ids"] = industryIDs
+	}
+	if info.Answers != nil {
+		answers, err := parseAnswers(r.crud, *info.Answers, r.r.Answers, "info.answers")
+		if err != nil {
+			return nil, err
+		}
+		updates["answers"] = answers
+	}
 
 	// perform update
 	rawRecruit, err := GenericUpdateByID(r.crud, config.RecruitsCollection, r.r.ID, updates)
@@ -167,83 +212,38 @@ func (r *RecruitEditorResolver) UpdateRecruit(args struct {
 		return nil, err
 	}
 
-	// return updated recruit profile
+	// keep the search index up to date and return updated recruit profile
 	recruit := models.TransformRecruit(rawRecruit)
-	return &RecruitResolver{&recruit, r.a}, nil
+	r.index.Put(recruitSearchDoc(recruit))
+	notifyProfileUpdated(r.crud, r.notifier, &recruit)
+	return &RecruitResolver{&recruit, r.a, r.crud, fullContactAccess}, nil
 }
 
-// UpdateQAs resolves RecruitEditor.UpdateQAs
-func (r *RecruitEditorResolver) UpdateQAs(args struct {
-	Qa1 *qaDetails
-	Qa2 *qaDetails
-}) ([]*QaResolver, error) {
+// UpdateQAs resolves RecruitEditor.UpdateQAs which replaces the Recruit's answers
+func (r *RecruitEditorResolver) UpdateQAs(args struct{ Answers []qaDetails }) ([]*QaResolver, error) {
 	defer r.crud.CloseCopy()
 
-	// check that we have at least 1 QA
-	qa1 := args.Qa1
-	qa2 := args.Qa2
-	if qa1 == nil && qa2 == nil {
-		return nil, er.Input("No QAs given.")
-	}
-
-	updates := bson.M{}
-	getQuestion := func(id bson.ObjectId) (*models.Question, error) {
-		rawQ, err := r.crud.FindID(config.QuestionsCollection, id)
-		if err != nil {
-			return nil, err
-		}
-		question := models.TransformQuestion(rawQ)
-		return &question, nil
-	}
-
-	results := make([]*QaResolver, 0)
-	// prepare qa1 update
-	if qa1 != nil {
-		id := string(qa1.QuestionID)
-		if !bson.IsObjectIdHex(id) {
-			return nil, er.InvalidField("qa1.question_id")
-		}
-		question, err := getQuestion(bson.ObjectIdHex(id))
-		if err != nil {
-			return nil, er.InvalidField("qa1.question_id")
-		}
-		qa := models.QA{
-			Question: question.Question,
-			Answer:   qa1.Answer,
-		}
-		updates["qa1"] = qa
-		results = append(results, &QaResolver{&qa})
-
-	}
-
-	// prepare qa2 update
-	if qa2 != nil {
-		id := string(qa2.QuestionID)
-		if !bson.IsObjectIdHex(id) {
-			return nil, er.InvalidField("qa2.question_id")
-		}
-		question, err := getQuestion(bson.ObjectIdHex(id))
-		if err != nil {
-			return nil, er.InvalidField("qa2.question_id")
-		}
-		qa := models.QA{
-			Question: question.Question,
-			Answer:   qa2.Answer,
-		}
-		updates["qa2"] = qa
-		results = append(results, &QaResolver{&qa})
+	answers, err := parseAnswers(r.crud, args.Answers, r.r.Answers, "answers")
+	if err != nil {
+		return nil, err
 	}
 
-	if _, err := GenericUpdateByID(
+	rawRecruit, err := GenericUpdateByID(
 		r.crud,
 		config.RecruitsCollection,
 		r.r.ID,
-		updates,
-	); err != nil {
+		bson.M{"answers": answers},
+	)
+	if err != nil {
 		return nil, er.Generic()
 	}
 
-	return results, nil
+	// keep the search index up to date
+	recruit := models.TransformRecruit(rawRecruit)
+	r.index.Put(recruitSearchDoc(recruit))
+	notifyProfileUpdated(r.crud, r.notifier, &recruit)
+
+	return answerResolvers(r.crud, answers)
 }
 
 // RemoveRecruit resolves "removeRecruit" mutation
@@ -261,19 +261,97 @@ func (r *RecruitEditorResolver) RemoveRecruit() (*string, error) {
 	}); err != nil {
 		return nil, er.Generic()
 	}
+	r.index.Delete(searchKindRecruit, r.r.ID.Hex())
 
 	result := "Recruit successfully removed."
 	return &result, nil
 }
 
 // -----------------
-// hunterEditorResolver struct
+// HunterEditorResolver struct
 // -----------------
 
-// type hunterEditorResolver struct {
-// 	r *models.Hunter
-// 	crud *db.CRUD
-// }
+// HunterEditorResolver resolves HunterEditor
+type HunterEditorResolver struct {
+	h        *models.Hunter
+	a        *models.Account
+	crud     *db.CRUD
+	index    *search.Index
+	notifier *notify.Dispatcher
+	ledger   *credits.Ledger
+	store    storage.Store
+}
+
+// UpdateHunter resolves HunterEditor.UpdateHunter
+func (r *HunterEditorResolver) UpdateHunter(args struct {
+	Info *hunterDetails
+}) (*HunterResolver, error) {
+	defer r.crud.CloseCopy()
+
+	// check if info is nil
+	info := args.Info
+	if info == nil {
+		return nil, er.MissingField("info")
+	}
+
+	// prepare updates
+	updates := bson.M{}
+	hunter := *r.h
+	if info.Phone != nil {
+		hunter.Phone = *info.Phone
+		updates["phone"] = *info.Phone
+	}
+	if info.Email != nil {
+		hunter.Email = *info.Email
+		updates["email"] = *info.Email
+	}
+	if info.Position != nil {
+		hunter.Position = *info.Position
+		updates["position"] = *info.Position
+	}
+
+	// validate updates
+	if err := hunter.OK(); err != nil {
+		return nil, err
+	}
+
+	// perform update
+	rawHunter, err := GenericUpdateByID(r.crud, config.HuntersCollection, r.h.ID, updates)
+	if err != nil {
+		return nil, err
+	}
+
+	// return updated hunter profile
+	hunter = models.TransformHunter(rawHunter)
+	return &HunterResolver{&hunter, r.a}, nil
+}
+
+// RemoveHunter resolves "removeHunter" mutation
+func (r *HunterEditorResolver) RemoveHunter() (*string, error) {
+	defer r.crud.CloseCopy()
+
+	// the hunter leaves its company first, which an only owner can't do
+	if !utils.IsNullID(r.h.CompanyID) {
+		if err := r.leaveCompany(); err != nil {
+			return nil, err
+		}
+	}
+
+	// attempt to remove Hunter
+	if err := r.crud.DeleteID(config.HuntersCollection, r.h.ID); err != nil {
+		return nil, er.Generic()
+	}
+
+	// remove the account's hunter_id
+	if err := r.crud.UpdateID(config.AccountsCollection, r.a.ID, bson.M{
+		"hunter_id": models.NullObjectID,
+	}); err != nil {
+		return nil, er.Generic()
+	}
+
+	result := "Hunter successfully removed."
+	return &result, nil
+}
 
 // -----------------
 // SysEditorResolver struct
@@ -281,8 +359,12 @@ func (r *RecruitEditorResolver) RemoveRecruit() (*string, error) {
 
 // SysEditorResolver resolves SysEditor
 type SysEditorResolver struct {
-	a    *models.Account
-	crud *db.CRUD
+	a        *models.Account
+	crud     *db.CRUD
+	index    *search.Index
+	notifier *notify.Dispatcher
+	ledger   *credits.Ledger
+	store    storage.Store
 }
 
 // ID resolves SysEditor.ID
@@ -313,12 +395,16 @@ func (r *SysEditorResolver) RemoveRecruit(args struct{ ID graphql.ID }) (*string
 		return nil, er.InvalidField("id")
 	}
 
-	return ResolveRemoveByID(
+	result, err := ResolveRemoveByID(
 		r.crud,
 		config.RecruitsCollection,
 		"Recruit",
 		bson.ObjectIdHex(id),
 	)
+	if err == nil {
+		r.index.Delete(searchKindRecruit, id)
+	}
+	return result, err
 }
 
 // RemoveAccount resolves SysEditor.RemoveAccount which removes an Account with the given ID
@@ -340,27 +426,54 @@ func (r *SysEditorResolver) RemoveQuestion(args struct{ ID graphql.ID }) (*strin
 		return nil, er.InvalidField("id")
 	}
 
-	return ResolveRemoveByID(r.crud, config.QuestionsCollection, "Question", bson.ObjectIdHex(id))
+	result, err := ResolveRemoveByID(r.crud, config.QuestionsCollection, "Question", bson.ObjectIdHex(id))
+	if err == nil {
+		r.index.Delete(searchKindQuestion, id)
+	}
+	return result, err
 }
 
 // RemoveDocument resolves SysEditor.RemoveDocument which removes a Document with the given ID
+// along with its uploaded file
 func (r *SysEditorResolver) RemoveDocument(args struct{ ID graphql.ID }) (*string, error) {
 	// check the id
 	id := string(args.ID)
 	if !bson.IsObjectIdHex(id) {
 		return nil, er.InvalidField("id")
 	}
+	rawDocument, err := r.crud.FindID(config.DocumentsCollection, bson.ObjectIdHex(id))
+	if err != nil {
+		r.crud.CloseCopy()
+		return nil, er.Input("Document not found.")
+	}
 
-	return ResolveRemoveByID(r.crud, config.DocumentsCollection, "Document", bson.ObjectIdHex(id))
+	result, err := ResolveRemoveByID(r.crud, config.DocumentsCollection, "Document", bson.ObjectIdHex(id))
+	if err == nil {
+		r.index.Delete(searchKindDocument, id)
+		removeDocumentFile(r.crud, r.store, models.TransformDocument(rawDocument))
+	}
+	return result, err
 }
 
-// RemoveIndustry resolves SysEditor.RemoveIndustry which removes an Industry with the given ID
+// RemoveIndustry resolves SysEditor.RemoveIndustry which removes an Industry with the given ID,
+// recruits who prefer it lose the preference and its skills are removed along with it.
+// Its sub-industries move up to its parent
 func (r *SysEditorResolver) RemoveIndustry(args struct{ ID graphql.ID }) (*string, error) {
 	// check the id
 	id := string(args.ID)
 	if !bson.IsObjectIdHex(id) {
 		return nil, er.InvalidField("id")
 	}
+	industry, err := findIndustry(r.crud, bson.ObjectIdHex(id))
+	if err != nil {
+		return nil, err
+	}
+	if err := liftIndustryChildren(r.crud, industry); err != nil {
+		return nil, err
+	}
+	if err := removeIndustryReferences(r.crud, bson.ObjectIdHex(id)); err != nil {
+		return nil, err
+	}
 
 	return ResolveRemoveByID(r.crud, config.IndustriesCollection, "Industry", bson.ObjectIdHex(id))
 }
@@ -369,6 +482,8 @@ func (r *SysEditorResolver) RemoveIndustry(args struct{ ID graphql.ID }) (*strin
 func (r *SysEditorResolver) CreateQuestion(args struct {
 	IndustryID graphql.ID
 	Question   string
+	Weight     *int32
+	Difficulty *string
 }) (*QuestionResolver, error) {
 	defer r.crud.CloseCopy()
 
@@ -380,8 +495,18 @@ func (r *SysEditorResolver) CreateQuestion(args struct {
 
 	// create question
 	question := models.Question{
+		ID:         bson.NewObjectId(),
 		IndustryID: bson.ObjectIdHex(id),
-		Question:   args.Question,
+		Status:     models.QuestionActive,
+		Weight:     models.DefaultQuestionWeight,
+		Difficulty: models.QuestionMedium,
+	}
+	question.Revise(args.Question, time.Now())
+	if args.Weight != nil {
+		question.Weight = *args.Weight
+	}
+	if args.Difficulty != nil {
+		question.Difficulty = *args.Difficulty
 	}
 
 	// validate question
@@ -392,16 +517,38 @@ func (r *SysEditorResolver) CreateQuestion(args struct {
 	if err := r.crud.Insert(config.QuestionsCollection, question); err != nil {
 		return nil, er.Generic()
 	}
+	r.index.Put(questionSearchDoc(question))
 
 	return &QuestionResolver{&question}, nil
 }
 
 // CreateIndustry resolves SysEditor.CreateIndustry
-func (r *SysEditorResolver) CreateIndustry(args struct{ Name string }) (*IndustryResolver, error) {
+func (r *SysEditorResolver) CreateIndustry(args struct {
+	Name       string
+	MinAnswers *int32
+	MaxAnswers *int32
+	ParentID   *graphql.ID
+}) (*IndustryResolver, error) {
 	defer r.crud.CloseCopy()
 
 	// create industry
-	industry := models.Industry{Name: args.Name}
+	industry := models.Industry{
+		ID:         bson.NewObjectId(),
+		Name:       args.Name,
+		MinAnswers: models.DefaultMinAnswers,
+		MaxAnswers: models.DefaultMaxAnswers,
+	}
+	if args.MinAnswers != nil {
+		industry.MinAnswers = *args.MinAnswers
+	}
+	if args.MaxAnswers != nil {
+		industry.MaxAnswers = *args.MaxAnswers
+	}
+	if args.ParentID != nil {
+		if err := setIndustryParent(r.crud, &industry, *args.ParentID); err != nil {
+			return nil, err
+		}
+	}
 
 	// validate industry
 	if err := industry.OK(); err != nil {
@@ -412,13 +559,16 @@ func (r *SysEditorResolver) CreateIndustry(args struct{ Name string }) (*Industr
 		return nil, er.Generic()
 	}
 
-	return &IndustryResolver{&industry}, nil
+	return &IndustryResolver{&industry, r.crud}, nil
 }
 
-// UpdateIndustry resolves SysEditor.UpdateIndustry
+// UpdateIndustry resolves SysEditor.UpdateIndustry, an empty parent ID makes the Industry top level
 func (r *SysEditorResolver) UpdateIndustry(args struct {
-	ID   graphql.ID
-	Name string
+	ID         graphql.ID
+	Name       string
+	MinAnswers *int32
+	MaxAnswers *int32
+	ParentID   *graphql.ID
 }) (*IndustryResolver, error) {
 	defer r.crud.CloseCopy()
 
@@ -440,25 +590,37 @@ func (r *SysEditorResolver) UpdateIndustry(args struct {
 
 	// apply and test updates on industry
 	industry.Name = args.Name
+	if args.MinAnswers != nil {
+		industry.MinAnswers = *args.MinAnswers
+	}
+	if args.MaxAnswers != nil {
+		industry.MaxAnswers = *args.MaxAnswers
+	}
+	if args.ParentID != nil {
+		if err := setIndustryParent(r.crud, &industry, *args.ParentID); err != nil {
+			return nil, err
+		}
+	}
 	if err := industry.OK(); err != nil {
 		return nil, err
 	}
 
-	// run update
-	if err := r.crud.UpdateID(config.IndustriesCollection, bid, bson.M{
-		"name": args.Name,
-	}); err != nil {
+	// run update, the whole industry is replaced so a removed parent is unset
+	if err := r.crud.ReplaceID(config.IndustriesCollection, bid, industry); err != nil {
 		return nil, er.Generic()
 	}
 
 	// return industry
-	return &IndustryResolver{&industry}, nil
+	return &IndustryResolver{&industry, r.crud}, nil
 }
 
-// UpdateQuestion resolves SysEditor.UpdateQuestion
+// UpdateQuestion resolves SysEditor.UpdateQuestion which adds a revision with the new wording,
+// answers keep referring to the revision they answered. Translations of the old wording are dropped
 func (r *SysEditorResolver) UpdateQuestion(args struct {
-	ID       graphql.ID
-	Question string
+	ID         graphql.ID
+	Question   string
+	Weight     *int32
+	Difficulty *string
 }) (*QuestionResolver, error) {
 	defer r.crud.CloseCopy()
 
@@ -476,31 +638,58 @@ func (r *SysEditorResolver) UpdateQuestion(args struct {
 	}
 	question := models.TransformQuestion(rawQuestion)
 
-	// apply and validate updates on question
-	question.Question = args.Question
+	// apply and validate updates on question, the same wording doesn't make a new revision
+	if args.Question != question.Question {
+		question.Revise(args.Question, time.Now())
+		question.Translations = []models.Translation{}
+	}
+	if args.Weight != nil {
+		question.Weight = *args.Weight
+	}
+	if args.Difficulty != nil {
+		question.Difficulty = *args.Difficulty
+	}
 	if err := question.OK(); err != nil {
 		return nil, err
 	}
 
 	// run update
 	if err := r.crud.UpdateID(config.QuestionsCollection, bid, bson.M{
-		"question": args.Question,
+		"question":     question.Question,
+		"revision":     question.Revision,
+		"revisions":    question.Revisions,
+		"weight":       question.Weight,
+		"difficulty":   question.Difficulty,
+		"translations": question.Translations,
 	}); err != nil {
 		return nil, er.Generic()
 	}
+	r.index.Put(questionSearchDoc(question))
 
-	// return industry
+	// return question
 	return &QuestionResolver{&question}, nil
 }
 
+// ArchiveQuestion resolves SysEditor.ArchiveQuestion, archived questions aren't asked
+// anymore but the answers recruits gave to them are kept
+func (r *SysEditorResolver) ArchiveQuestion(args struct{ ID graphql.ID }) (*QuestionResolver, error) {
+	return setQuestionStatus(r.crud, args.ID, models.QuestionArchived)
+}
+
+// ActivateQuestion resolves SysEditor.ActivateQuestion which asks an archived question again
+func (r *SysEditorResolver) ActivateQuestion(args struct{ ID graphql.ID }) (*QuestionResolver, error) {
+	return setQuestionStatus(r.crud, args.ID, models.QuestionActive)
+}
+
 // -----------------
 // AccountEditorResolver struct
 // -----------------
 
 // AccountEditorResolver resolves AccountEditor
 type AccountEditorResolver struct {
-	a    *models.Account
-	crud *db.CRUD
+	a     *models.Account
+	crud  *db.CRUD
+	index *search.Index
 }
 
 // UpdateAccount resolves AccountEditor.UpdateAccount
@@ -581,17 +770,8 @@ func (r *AccountEditorResolver) CreateRecruit(args struct{ Info *recruitDetails
 	if info.BirthYear == nil {
 		return nil, er.MissingField("info.birth_year")
 	}
-	if info.Qa1QuestionID == nil {
-		return nil, er.MissingField("info.qa1_question_id")
-	}
-	if info.Qa1Answer == nil {
-		return nil, er.MissingField("info.qa1_answer")
-	}
-	if info.Qa2QuestionID == nil {
-		return nil, er.MissingField("info.qa2_question_id")
-	}
-	if info.Qa2Answer == nil {
-		return nil, er.MissingField("info.qa2_answer")
+	if info.Answers == nil {
+		return nil, er.MissingField("info.answers")
 	}
 
 	// create recruit profile
@@ -606,43 +786,24 @@ func (r *AccountEditorResolver) CreateRecruit(args struct{ Info *recruitDetails
 	recruit.Phone = *info.Phone
 	recruit.Email = *info.Email
 	recruit.BirthYear = *info.BirthYear
-
-	getQuestion := func(id bson.ObjectId) (*models.Question, error) {
-		rawQ, err := r.crud.FindID(config.QuestionsCollection, id)
+	recruit.Privacy = models.DefaultRecruitPrivacy
+	recruit.WorkExperience = make([]models.WorkExperience, 0)
+	recruit.Education = make([]models.Education, 0)
+	recruit.Skills = make([]models.RecruitSkill, 0)
+	recruit.IndustryIDs = make([]bson.ObjectId, 0)
+	if info.IndustryIDs != nil {
+		industryIDs, err := parseIndustryIDs(r.crud, *info.IndustryIDs, "info.industry_ids")
 		if err != nil {
 			return nil, err
 		}
-		question := models.TransformQuestion(rawQ)
-		return &question, nil
-	}
-
-	// check question ids
-	qa1ID := string(*info.Qa1QuestionID)
-	if !bson.IsObjectIdHex(qa1ID) {
-		return nil, er.InvalidField("info.qa1_question_id")
+		recruit.IndustryIDs = industryIDs
 	}
 
-	qa2ID := string(*info.Qa2QuestionID)
-	if !bson.IsObjectIdHex(qa2ID) {
-		return nil, er.InvalidField("info.qa2_question_id")
-	}
-
-	// get question1 from db
-	qa1, err := getQuestion(bson.ObjectIdHex(qa1ID))
+	answers, err := parseAnswers(r.crud, *info.Answers, nil, "info.answers")
 	if err != nil {
-		return nil, er.InvalidField("info.qa1_question_id")
-	}
-
-	// set QA1
-	recruit.Qa1 = models.QA{Question: qa1.Question, Answer: *info.Qa1Answer}
-
-	// get question2 from db
-	qa2, err := getQuestion(bson.ObjectIdHex(qa2ID))
-	if err != nil {
-		return nil, er.InvalidField("info.qa2_question_id")
+		return nil, err
 	}
-	// set QA2
-	recruit.Qa2 = models.QA{Question: qa2.Question, Answer: *info.Qa2Answer}
+	recruit.Answers = answers
 
 	// validate recruit profile
 	if err := recruit.OK(); err != nil {
@@ -662,9 +823,68 @@ func (r *AccountEditorResolver) CreateRecruit(args struct{ Info *recruitDetails
 		log.Println(err)
 		return nil, er.Generic()
 	}
+	r.index.Put(recruitSearchDoc(recruit))
 
 	// return recruit profile
-	return &RecruitResolver{&recruit, account}, nil
+	return &RecruitResolver{&recruit, account, r.crud, fullContactAccess}, nil
+}
+
+// CreateHunter resolves AccountEditor.CreateHunter which creates a Hunter profile for the current account using the given Info
+func (r *AccountEditorResolver) CreateHunter(args struct{ Info *hunterDetails }) (*HunterResolver, error) {
+	defer r.crud.CloseCopy()
+
+	// check if the account has a hunter profile
+	account := r.a
+	if !utils.IsNullID(account.HunterID) {
+		return nil, er.Input("Account already has a Hunter profile.")
+	}
+
+	// check if info is nil
+	info := args.Info
+	if info == nil {
+		return nil, er.MissingField("info")
+	}
+
+	// validate info
+	if info.Phone == nil {
+		return nil, er.MissingField("info.phone")
+	}
+	if info.Email == nil {
+		return nil, er.MissingField("info.email")
+	}
+	if info.Position == nil {
+		return nil, er.MissingField("info.position")
+	}
+
+	// create hunter profile
+	var hunter models.Hunter
+	hunter.ID = bson.NewObjectId()
+	hunter.Phone = *info.Phone
+	hunter.Email = *info.Email
+	hunter.Position = *info.Position
+	hunter.CompanyID = models.NullObjectID
+
+	// validate hunter profile
+	if err := hunter.OK(); err != nil {
+		return nil, err
+	}
+
+	// store hunter profile in database
+	if err := r.crud.Insert(config.HuntersCollection, hunter); err != nil {
+		log.Println(err)
+		return nil, er.Generic()
+	}
+
+	// attach the hunter profile to the account
+	if err := r.crud.UpdateID(config.AccountsCollection, account.ID, bson.M{
+		"hunter_id": hunter.ID,
+	}); err != nil {
+		log.Println(err)
+		return nil, er.Generic()
+	}
+
+	// return hunter profile
+	return &HunterResolver{&hunter, account}, nil
 }
 
 // -----------------
@@ -682,6 +902,12 @@ func (r *EditorResolver) ToRecruitEditor() (*RecruitEditorResolver, bool) {
 	return v, ok
 }
 
+// ToHunterEditor asserts *EditorResolver to *HunterEditorResolver
+func (r *EditorResolver) ToHunterEditor() (*HunterEditorResolver, bool) {
+	v, ok := r.editor.(*HunterEditorResolver)
+	return v, ok
+}
+
 // ToSysEditor asserts *EditorResolver to *SysEditorResolver
 func (r *EditorResolver) ToSysEditor() (*SysEditorResolver, bool) {
 	v, ok := r.editor.(*SysEditorResolver)
@@ -698,19 +924,26 @@ func (r *EditorResolver) ToAccountEditor() (*AccountEditorResolver, bool) {
 // recruitDetails struct
 // -----------------
 type recruitDetails struct {
-	Phone         *string
-	Email         *string
-	Province      *string
-	City          *string
-	Gender        *string
-	Disability    *string
-	Vid1Url       *string
-	Vid2Url       *string
-	Qa1QuestionID *graphql.ID
-	Qa1Answer     *string
-	Qa2QuestionID *graphql.ID
-	Qa2Answer     *string
-	BirthYear     *int32
+	Phone       *string
+	Email       *string
+	Province    *string
+	City        *string
+	Gender      *string
+	Disability  *string
+	Vid1Url     *string
+	Vid2Url     *string
+	BirthYear   *int32
+	IndustryIDs *[]graphql.ID
+	Answers     *[]qaDetails
+}
+
+// -----------------
+// hunterDetails struct
+// -----------------
+type hunterDetails struct {
+	Phone    *string
+	Email    *string
+	Position *string
 }
 
 // -----------------
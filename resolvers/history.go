@@ -0,0 +1,336 @@
+package resolvers
+
+import (
+	"sort"
+
+	config "../config"
+	er "../errors"
+	models "../models"
+	graphql "github.com/graph-gophers/graphql-go"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// workExperienceDetails are the fields of a WorkExperience entry
+type workExperienceDetails struct {
+	Employer    string
+	Title       string
+	StartDate   Date
+	EndDate     *Date
+	Description *string
+}
+
+// workExperience creates a WorkExperience entry from the details
+func (d *workExperienceDetails) workExperience(id bson.ObjectId) models.WorkExperience {
+	work := models.WorkExperience{
+		ID:        id,
+		Employer:  d.Employer,
+		Title:     d.Title,
+		StartDate: d.StartDate.Time,
+	}
+	if d.EndDate != nil {
+		work.EndDate = d.EndDate.Time
+	}
+	if d.Description != nil {
+		work.Description = *d.Description
+	}
+	return work
+}
+
+// educationDetails are the fields of an Education entry
+type educationDetails struct {
+	Institution   string
+	Qualification string
+	NqfLevel      int32
+	Year          int32
+}
+
+// education creates an Education entry from the details
+func (d *educationDetails) education(id bson.ObjectId) models.Education {
+	return models.Education{
+		ID:            id,
+		Institution:   d.Institution,
+		Qualification: d.Qualification,
+		NQFLevel:      d.NqfLevel,
+		Year:          d.Year,
+	}
+}
+
+// -----------------
+// Editor methods
+// -----------------
+
+// AddWorkExperience resolves RecruitEditor.AddWorkExperience
+func (r *RecruitEditorResolver) AddWorkExperience(args struct{ Info workExperienceDetails }) (*WorkExperienceResolver, error) {
+	defer r.crud.CloseCopy()
+
+	if len(r.r.WorkExperience) >= models.MaxWorkExperiences {
+		return nil, er.Input("Too many work experience entries.")
+	}
+	work := args.Info.workExperience(bson.NewObjectId())
+	if err := work.OK(); err != nil {
+		return nil, err
+	}
+
+	history := append(append(make([]models.WorkExperience, 0), r.r.WorkExperience...), work)
+	if err := r.updateHistory(bson.M{"work_experience": history}); err != nil {
+		return nil, err
+	}
+	return &WorkExperienceResolver{&work}, nil
+}
+
+// UpdateWorkExperience resolves RecruitEditor.UpdateWorkExperience
+func (r *RecruitEditorResolver) UpdateWorkExperience(args struct {
+	ID   graphql.ID
+	Info workExperienceDetails
+}) (*WorkExperienceResolver, error) {
+	defer r.crud.CloseCopy()
+
+	i := findWorkExperience(r.r.WorkExperience, args.ID)
+	if i < 0 {
+		return nil, er.Input("Work experience not found.")
+	}
+	work := args.Info.workExperience(r.r.WorkExperience[i].ID)
+	if err := work.OK(); err != nil {
+		return nil, err
+	}
+
+	history := append(make([]models.WorkExperience, 0), r.r.WorkExperience...)
+	history[i] = work
+	if err := r.updateHistory(bson.M{"work_experience": history}); err != nil {
+		return nil, err
+	}
+	return &WorkExperienceResolver{&work}, nil
+}
+
+// RemoveWorkExperience resolves RecruitEditor.RemoveWorkExperience
+func (r *RecruitEditorResolver) RemoveWorkExperience(args struct{ ID graphql.ID }) (*string, error) {
+	defer r.crud.CloseCopy()
+
+	i := findWorkExperience(r.r.WorkExperience, args.ID)
+	if i < 0 {
+		return nil, er.Input("Work experience not found.")
+	}
+
+	history := append(make([]models.WorkExperience, 0), r.r.WorkExperience[:i]...)
+	history = append(history, r.r.WorkExperience[i+1:]...)
+	if err := r.updateHistory(bson.M{"work_experience": history}); err != nil {
+		return nil, err
+	}
+	result := "Work experience successfully removed."
+	return &result, nil
+}
+
+// AddEducation resolves RecruitEditor.AddEducation
+func (r *RecruitEditorResolver) AddEducation(args struct{ Info educationDetails }) (*EducationResolver, error) {
+	defer r.crud.CloseCopy()
+
+	if len(r.r.Education) >= models.MaxEducations {
+		return nil, er.Input("Too many education entries.")
+	}
+	education := args.Info.education(bson.NewObjectId())
+	if err := education.OK(); err != nil {
+		return nil, err
+	}
+
+	history := append(append(make([]models.Education, 0), r.r.Education...), education)
+	if err := r.updateHistory(bson.M{"education": history}); err != nil {
+		return nil, err
+	}
+	return &EducationResolver{&education}, nil
+}
+
+// UpdateEducation resolves RecruitEditor.UpdateEducation
+func (r *RecruitEditorResolver) UpdateEducation(args struct {
+	ID   graphql.ID
+	Info educationDetails
+}) (*EducationResolver, error) {
+	defer r.crud.CloseCopy()
+
+	i := findEducation(r.r.Education, args.ID)
+	if i < 0 {
+		return nil, er.Input("Education not found.")
+	}
+	education := args.Info.education(r.r.Education[i].ID)
+	if err := education.OK(); err != nil {
+		return nil, err
+	}
+
+	history := append(make([]models.Education, 0), r.r.Education...)
+	history[i] = education
+	if err := r.updateHistory(bson.M{"education": history}); err != nil {
+		return nil, err
+	}
+	return &EducationResolver{&education}, nil
+}
+
+// RemoveEducation resolves RecruitEditor.RemoveEducation
+func (r *RecruitEditorResolver) RemoveEducation(args struct{ ID graphql.ID }) (*string, error) {
+	defer r.crud.CloseCopy()
+
+	i := findEducation(r.r.Education, args.ID)
+	if i < 0 {
+		return nil, er.Input("Education not found.")
+	}
+
+	history := append(make([]models.Education, 0), r.r.Education[:i]...)
+	history = append(history, r.r.Education[i+1:]...)
+	if err := r.updateHistory(bson.M{"education": history}); err != nil {
+		return nil, err
+	}
+	result := "Education successfully removed."
+	return &result, nil
+}
+
+// -----------------
+// helpers
+// -----------------
+
+// updateHistory stores changes to the Recruit's work or education history and
+// keeps the search index up to date
+func (r *RecruitEditorResolver) updateHistory(updates bson.M) error {
+	rawRecruit, err := GenericUpdateByID(r.crud, config.RecruitsCollection, r.r.ID, updates)
+	if err != nil {
+		return err
+	}
+	recruit := models.TransformRecruit(rawRecruit)
+	*r.r = recruit
+	r.index.Put(recruitSearchDoc(recruit))
+	notifyProfileUpdated(r.crud, r.notifier, &recruit)
+	return nil
+}
+
+// findWorkExperience returns the index of the entry with the given id, or -1 if there isn't one
+func findWorkExperience(history []models.WorkExperience, id graphql.ID) int {
+	for i, w := range history {
+		if w.ID.Hex() == string(id) {
+			return i
+		}
+	}
+	return -1
+}
+
+// findEducation returns the index of the entry with the given id, or -1 if there isn't one
+func findEducation(history []models.Education, id graphql.ID) int {
+	for i, e := range history {
+		if e.ID.Hex() == string(id) {
+			return i
+		}
+	}
+	return -1
+}
+
+// -----------------
+// Recruit methods
+// -----------------
+
+// WorkExperience resolves Recruit.WorkExperience, most recent position first
+func (r *RecruitResolver) WorkExperience() []*WorkExperienceResolver {
+	history := append(make([]models.WorkExperience, 0), r.r.WorkExperience...)
+	sort.SliceStable(history, func(i, j int) bool {
+		if history[i].Current() != history[j].Current() {
+			return history[i].Current()
+		}
+		return history[i].StartDate.After(history[j].StartDate)
+	})
+
+	results := make([]*WorkExperienceResolver, 0)
+	for i := range history {
+		results = append(results, &WorkExperienceResolver{&history[i]})
+	}
+	return results
+}
+
+// Education resolves Recruit.Education, most recent qualification first
+func (r *RecruitResolver) Education() []*EducationResolver {
+	history := append(make([]models.Education, 0), r.r.Education...)
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].Year > history[j].Year
+	})
+
+	results := make([]*EducationResolver, 0)
+	for i := range history {
+		results = append(results, &EducationResolver{&history[i]})
+	}
+	return results
+}
+
+// -----------------
+// WorkExperienceResolver struct
+// -----------------
+
+// WorkExperienceResolver resolves WorkExperience
+type WorkExperienceResolver struct {
+	w *models.WorkExperience
+}
+
+// ID resolves WorkExperience.ID
+func (r *WorkExperienceResolver) ID() graphql.ID {
+	return graphql.ID(r.w.ID.Hex())
+}
+
+// Employer resolves WorkExperience.Employer
+func (r *WorkExperienceResolver) Employer() string {
+	return r.w.Employer
+}
+
+// Title resolves WorkExperience.Title
+func (r *WorkExperienceResolver) Title() string {
+	return r.w.Title
+}
+
+// StartDate resolves WorkExperience.StartDate
+func (r *WorkExperienceResolver) StartDate() Date {
+	return Date{r.w.StartDate}
+}
+
+// EndDate resolves WorkExperience.EndDate, which is null for a current position
+func (r *WorkExperienceResolver) EndDate() *Date {
+	if r.w.Current() {
+		return nil
+	}
+	return &Date{r.w.EndDate}
+}
+
+// Current resolves WorkExperience.Current
+func (r *WorkExperienceResolver) Current() bool {
+	return r.w.Current()
+}
+
+// Description resolves WorkExperience.Description
+func (r *WorkExperienceResolver) Description() string {
+	return r.w.Description
+}
+
+// -----------------
+// EducationResolver struct
+// -----------------
+
+// EducationResolver resolves Education
+type EducationResolver struct {
+	e *models.Education
+}
+
+// ID resolves Education.ID
+func (r *EducationResolver) ID() graphql.ID {
+	return graphql.ID(r.e.ID.Hex())
+}
+
+// Institution resolves Education.Institution
+func (r *EducationResolver) Institution() string {
+	return r.e.Institution
+}
+
+// Qualification resolves Education.Qualification
+func (r *EducationResolver) Qualification() string {
+	return r.e.Qualification
+}
+
+// NqfLevel resolves Education.NqfLevel
+func (r *EducationResolver) NqfLevel() int32 {
+	return r.e.NQFLevel
+}
+
+// Year resolves Education.Year
+func (r *EducationResolver) Year() int32 {
+	return r.e.Year
+}
@@ -0,0 +1,305 @@
+package resolvers
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	config "../config"
+	db "../database"
+	er "../errors"
+	models "../models"
+	graphql "github.com/graph-gophers/graphql-go"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// -----------------
+// helpers
+// -----------------
+
+// parseIndustryIDs converts gql ids into the ids of existing industries
+func parseIndustryIDs(crud *db.CRUD, ids []graphql.ID, field string) ([]bson.ObjectId, error) {
+	industryIDs := make([]bson.ObjectId, 0, len(ids))
+	seen := make(map[bson.ObjectId]bool)
+	for _, id := range ids {
+		if !bson.IsObjectIdHex(string(id)) {
+			return nil, er.InvalidField(field)
+		}
+		industryID := bson.ObjectIdHex(string(id))
+		if !seen[industryID] {
+			seen[industryID] = true
+			industryIDs = append(industryIDs, industryID)
+		}
+	}
+	if len(industryIDs) > models.MaxRecruitIndustries {
+		return nil, er.InvalidField(field)
+	}
+	if len(industryIDs) == 0 {
+		return industryIDs, nil
+	}
+
+	rawIndustries, err := crud.FindAll(config.IndustriesCollection, bson.M{"_id": bson.M{"$in": industryIDs}})
+	if err != nil {
+		log.Println("Failed to find industries =>", err)
+		return nil, er.Generic()
+	}
+	if len(rawIndustries) != len(industryIDs) {
+		return nil, er.InvalidField(field)
+	}
+	return industryIDs, nil
+}
+
+// findIndustry finds the Industry with the given id
+func findIndustry(crud *db.CRUD, id bson.ObjectId) (*models.Industry, error) {
+	rawIndustry, err := crud.FindID(config.IndustriesCollection, id)
+	if err != nil {
+		return nil, er.Input("Industry not found.")
+	}
+	industry := models.TransformIndustry(rawIndustry)
+	return &industry, nil
+}
+
+// industryAncestors finds the industries an Industry is nested in, top level
+// industry first. A missing parent ends the chain as if it was top level
+func industryAncestors(crud *db.CRUD, industry *models.Industry) []models.Industry {
+	ancestors := make([]models.Industry, 0)
+	parentID := industry.ParentID
+	for parentID != "" && len(ancestors) < models.MaxIndustryDepth {
+		parent, err := findIndustry(crud, parentID)
+		if err != nil {
+			break
+		}
+		ancestors = append([]models.Industry{*parent}, ancestors...)
+		parentID = parent.ParentID
+	}
+	return ancestors
+}
+
+// industryChildren finds the direct sub-industries of an Industry, by name
+func industryChildren(crud *db.CRUD, id bson.ObjectId) ([]models.Industry, error) {
+	rawIndustries, err := crud.FindAll(config.IndustriesCollection, bson.M{"parent_id": id})
+	if err != nil {
+		log.Println("Failed to find sub-industries =>", err)
+		return nil, er.Generic()
+	}
+	children := make([]models.Industry, 0, len(rawIndustries))
+	for _, raw := range rawIndustries {
+		children = append(children, models.TransformIndustry(raw))
+	}
+	sort.Slice(children, func(i, j int) bool { return children[i].Name < children[j].Name })
+	return children, nil
+}
+
+// industryHeight counts the levels of an Industry and its sub-industries, an
+// Industry without sub-industries is 1 level high
+func industryHeight(crud *db.CRUD, id bson.ObjectId) (int, error) {
+	height := 1
+	level := []bson.ObjectId{id}
+	for height <= models.MaxIndustryDepth {
+		rawIndustries, err := crud.FindAll(config.IndustriesCollection, bson.M{"parent_id": bson.M{"$in": level}})
+		if err != nil {
+			log.Println("Failed to find sub-industries =>", err)
+			return 0, er.Generic()
+		}
+		if len(rawIndustries) == 0 {
+			break
+		}
+		level = make([]bson.ObjectId, 0, len(rawIndustries))
+		for _, raw := range rawIndustries {
+			level = append(level, models.TransformIndustry(raw).ID)
+		}
+		height++
+	}
+	return height, nil
+}
+
+// industryDescendants finds the ids of an Industry and all of its sub-industries
+func industryDescendants(crud *db.CRUD, id bson.ObjectId) ([]bson.ObjectId, error) {
+	ids := []bson.ObjectId{id}
+	level := []bson.ObjectId{id}
+	for depth := 1; depth < models.MaxIndustryDepth && len(level) > 0; depth++ {
+		rawIndustries, err := crud.FindAll(config.IndustriesCollection, bson.M{"parent_id": bson.M{"$in": level}})
+		if err != nil {
+			log.Println("Failed to find sub-industries =>", err)
+			return nil, er.Generic()
+		}
+		level = make([]bson.ObjectId, 0, len(rawIndustries))
+		for _, raw := range rawIndustries {
+			level = append(level, models.TransformIndustry(raw).ID)
+		}
+		ids = append(ids, level...)
+	}
+	return ids, nil
+}
+
+// setIndustryParent nests an Industry in the Industry with parentID, an empty
+// parentID makes it a top level industry. An Industry can't be nested in its own
+// sub-industries or deeper than MaxIndustryDepth
+func setIndustryParent(crud *db.CRUD, industry *models.Industry, parentID graphql.ID) error {
+	id := string(parentID)
+	if id == "" {
+		industry.ParentID = ""
+		return nil
+	}
+	if !bson.IsObjectIdHex(id) || bson.ObjectIdHex(id) == industry.ID {
+		return er.InvalidField("parent_id")
+	}
+	parent, err := findIndustry(crud, bson.ObjectIdHex(id))
+	if err != nil {
+		return er.Input("Parent industry not found.")
+	}
+
+	ancestors := industryAncestors(crud, parent)
+	for _, ancestor := range ancestors {
+		if ancestor.ID == industry.ID {
+			return er.Input("An industry can't be nested in its own sub-industries.")
+		}
+	}
+	height, err := industryHeight(crud, industry.ID)
+	if err != nil {
+		return err
+	}
+	if len(ancestors)+1+height > models.MaxIndustryDepth {
+		return er.Input(fmt.Sprintf("Industries can only be nested %d levels deep.", models.MaxIndustryDepth))
+	}
+
+	industry.ParentID = parent.ID
+	return nil
+}
+
+// liftIndustryChildren moves the sub-industries of an Industry that's being removed
+// up to the Industry's parent, as long as their names don't clash with the industries there
+func liftIndustryChildren(crud *db.CRUD, industry *models.Industry) error {
+	children, err := industryChildren(crud, industry.ID)
+	if err != nil || len(children) == 0 {
+		return err
+	}
+
+	query := bson.M{"parent_id": industry.ParentID}
+	if industry.ParentID == "" {
+		query = bson.M{"parent_id": bson.M{"$exists": false}}
+	}
+	rawSiblings, err := crud.FindAll(config.IndustriesCollection, query)
+	if err != nil {
+		log.Println("Failed to find sibling industries =>", err)
+		return er.Generic()
+	}
+	names := make(map[string]bool)
+	for _, raw := range rawSiblings {
+		if sibling := models.TransformIndustry(raw); sibling.ID != industry.ID {
+			names[sibling.Name] = true
+		}
+	}
+	for _, child := range children {
+		if names[child.Name] {
+			return er.Input(fmt.Sprintf("The %s sub-industry can't be moved up, an industry there has the same name.", child.Name))
+		}
+	}
+
+	for _, child := range children {
+		child.ParentID = industry.ParentID
+		if err := crud.ReplaceID(config.IndustriesCollection, child.ID, child); err != nil {
+			log.Println("Failed to move sub-industry up =>", err)
+			return er.Generic()
+		}
+	}
+	return nil
+}
+
+// removeIndustryReferences removes an Industry from the preferences of the
+// recruits who have it and removes the Industry's skills
+func removeIndustryReferences(crud *db.CRUD, industryID bson.ObjectId) error {
+	rawRecruits, err := crud.FindAll(config.RecruitsCollection, bson.M{"industry_ids": industryID})
+	if err != nil {
+		log.Println("Failed to find recruits in industry =>", err)
+		return er.Generic()
+	}
+	for _, raw := range rawRecruits {
+		recruit := models.TransformRecruit(raw)
+		industryIDs := make([]bson.ObjectId, 0, len(recruit.IndustryIDs))
+		for _, id := range recruit.IndustryIDs {
+			if id != industryID {
+				industryIDs = append(industryIDs, id)
+			}
+		}
+		if err := crud.UpdateID(config.RecruitsCollection, recruit.ID, bson.M{"industry_ids": industryIDs}); err != nil {
+			log.Println("Failed to remove industry from recruit =>", err)
+			return er.Generic()
+		}
+	}
+
+	rawSkills, err := crud.FindAll(config.SkillsCollection, bson.M{"industry_id": industryID})
+	if err != nil {
+		log.Println("Failed to find industry skills =>", err)
+		return er.Generic()
+	}
+	for _, raw := range rawSkills {
+		if err := removeSkill(crud, models.TransformSkill(raw).ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// -----------------
+// Recruit methods
+// -----------------
+
+// Industries resolves Recruit.Industries which are the industries the Recruit prefers to work in
+func (r *RecruitResolver) Industries() ([]*IndustryResolver, error) {
+	defer r.crud.CloseCopy()
+
+	results := make([]*IndustryResolver, 0)
+	if len(r.r.IndustryIDs) == 0 {
+		return results, nil
+	}
+	rawIndustries, err := r.crud.FindAll(config.IndustriesCollection, bson.M{"_id": bson.M{"$in": r.r.IndustryIDs}})
+	if err != nil {
+		log.Println("Failed to find recruit industries =>", err)
+		return nil, er.Generic()
+	}
+	industries := make(map[bson.ObjectId]*models.Industry)
+	for _, raw := range rawIndustries {
+		industry := models.TransformIndustry(raw)
+		industries[industry.ID] = &industry
+	}
+
+	// keep the Recruit's order of preference
+	for _, id := range r.r.IndustryIDs {
+		if industry, ok := industries[id]; ok {
+			results = append(results, &IndustryResolver{industry, r.crud})
+		}
+	}
+	return results, nil
+}
+
+// -----------------
+// IndustryResolver methods
+// -----------------
+
+// Children resolves Industry.Children which are the Industry's direct sub-industries
+func (r *IndustryResolver) Children() ([]*IndustryResolver, error) {
+	defer r.crud.CloseCopy()
+
+	children, err := industryChildren(r.crud, r.i.ID)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*IndustryResolver, 0, len(children))
+	for i := range children {
+		results = append(results, &IndustryResolver{&children[i], r.crud})
+	}
+	return results, nil
+}
+
+// Ancestors resolves Industry.Ancestors, from the top level industry down to the Industry's parent
+func (r *IndustryResolver) Ancestors() []*IndustryResolver {
+	defer r.crud.CloseCopy()
+
+	ancestors := industryAncestors(r.crud, r.i)
+	results := make([]*IndustryResolver, 0, len(ancestors))
+	for i := range ancestors {
+		results = append(results, &IndustryResolver{&ancestors[i], r.crud})
+	}
+	return results
+}
@@ -0,0 +1,530 @@
+package resolvers
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	config "../config"
+	db "../database"
+	er "../errors"
+	ical "../ical"
+	models "../models"
+	utils "../utils"
+	graphql "github.com/graph-gophers/graphql-go"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// -----------------
+// HunterEditorResolver methods
+// -----------------
+
+// interview retrieves an Interview belonging to the current Hunter's Company
+func (r *HunterEditorResolver) interview(interviewID graphql.ID) (*models.Interview, error) {
+	company, err := r.company(models.CompanyRoleOwner, models.CompanyRoleRecruiter)
+	if err != nil {
+		return nil, err
+	}
+
+	interview, err := findInterview(r.crud, interviewID)
+	if err != nil {
+		return nil, err
+	}
+	if interview.CompanyID != company.ID {
+		return nil, er.InvalidField("id")
+	}
+	return interview, nil
+}
+
+// ProposeInterview resolves HunterEditor.ProposeInterview which proposes interview slots
+// to the Recruit of one of the current Hunter's Company's applications
+func (r *HunterEditorResolver) ProposeInterview(args struct {
+	ApplicationID graphql.ID
+	Slots         []*interviewSlot
+	Location      string
+	Notes         *string
+}) (*InterviewResolver, error) {
+	defer r.crud.CloseCopy()
+
+	company, err := r.company(models.CompanyRoleOwner, models.CompanyRoleRecruiter)
+	if err != nil {
+		return nil, err
+	}
+
+	// check the id
+	id := string(args.ApplicationID)
+	if !bson.IsObjectIdHex(id) {
+		return nil, er.InvalidField("application_id")
+	}
+
+	// retrieve application
+	rawApplication, err := r.crud.FindID(config.ApplicationsCollection, bson.ObjectIdHex(id))
+	if err != nil {
+		return nil, er.InvalidField("application_id")
+	}
+	application := models.TransformApplication(rawApplication)
+	if application.CompanyID != company.ID {
+		return nil, er.InvalidField("application_id")
+	}
+	if company.ApplicationPipeline().IsFinal(application.Stage) {
+		return nil, er.Input("Application has already been closed.")
+	}
+
+	// only one interview per application can be active at a time
+	if _, err := r.crud.FindOne(config.InterviewsCollection, bson.M{
+		"application_id": application.ID,
+		"status":         bson.M{"$ne": models.InterviewCancelled},
+	}); err == nil {
+		return nil, er.Input("Application already has an interview, reschedule it instead.")
+	}
+
+	// create interview
+	now := time.Now()
+	interview := models.Interview{
+		ID:            bson.NewObjectId(),
+		ApplicationID: application.ID,
+		VacancyID:     application.VacancyID,
+		CompanyID:     company.ID,
+		RecruitID:     application.RecruitID,
+		HunterID:      r.h.ID,
+		Location:      args.Location,
+		CreatedAt:     now,
+	}
+	if args.Notes != nil {
+		interview.Notes = *args.Notes
+	}
+	if err := interview.Propose(toInterviewSlots(args.Slots)); err != nil {
+		return nil, err
+	}
+
+	// validate interview
+	if err := interview.OK(); err != nil {
+		return nil, err
+	}
+
+	// store interview in db
+	if err := r.crud.Insert(config.InterviewsCollection, interview); err != nil {
+		log.Println("Failed to create interview =>", err)
+		return nil, er.Generic()
+	}
+
+	return &InterviewResolver{&interview, r.crud, r.a}, nil
+}
+
+// RescheduleInterview resolves HunterEditor.RescheduleInterview which proposes new slots for an Interview
+func (r *HunterEditorResolver) RescheduleInterview(args struct {
+	ID       graphql.ID
+	Slots    []*interviewSlot
+	Location *string
+	Notes    *string
+}) (*InterviewResolver, error) {
+	defer r.crud.CloseCopy()
+
+	interview, err := r.interview(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// apply changes
+	if args.Location != nil {
+		interview.Location = *args.Location
+	}
+	if args.Notes != nil {
+		interview.Notes = *args.Notes
+	}
+	if err := interview.Propose(toInterviewSlots(args.Slots)); err != nil {
+		return nil, err
+	}
+	interview.HunterID = r.h.ID
+	if err := interview.OK(); err != nil {
+		return nil, err
+	}
+
+	return updateInterview(r.crud, interview, r.a)
+}
+
+// CancelInterview resolves HunterEditor.CancelInterview
+func (r *HunterEditorResolver) CancelInterview(args struct{ ID graphql.ID }) (*InterviewResolver, error) {
+	defer r.crud.CloseCopy()
+
+	interview, err := r.interview(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := interview.Cancel(); err != nil {
+		return nil, err
+	}
+
+	return updateInterview(r.crud, interview, r.a)
+}
+
+// -----------------
+// RecruitEditorResolver methods
+// -----------------
+
+// interview retrieves one of the current Recruit's Interviews
+func (r *RecruitEditorResolver) interview(interviewID graphql.ID) (*models.Interview, error) {
+	interview, err := findInterview(r.crud, interviewID)
+	if err != nil {
+		return nil, err
+	}
+	if interview.RecruitID != r.r.ID {
+		return nil, er.InvalidField("id")
+	}
+	return interview, nil
+}
+
+// AcceptInterview resolves RecruitEditor.AcceptInterview which schedules an Interview for one of its proposed slots
+func (r *RecruitEditorResolver) AcceptInterview(args struct {
+	ID   graphql.ID
+	Slot int32
+}) (*InterviewResolver, error) {
+	defer r.crud.CloseCopy()
+
+	interview, err := r.interview(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := interview.Accept(int(args.Slot)); err != nil {
+		return nil, err
+	}
+
+	return updateInterview(r.crud, interview, r.a)
+}
+
+// CancelInterview resolves RecruitEditor.CancelInterview
+func (r *RecruitEditorResolver) CancelInterview(args struct{ ID graphql.ID }) (*InterviewResolver, error) {
+	defer r.crud.CloseCopy()
+
+	interview, err := r.interview(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := interview.Cancel(); err != nil {
+		return nil, err
+	}
+
+	return updateInterview(r.crud, interview, r.a)
+}
+
+// -----------------
+// RecruitViewerResolver methods
+// -----------------
+
+// Interviews resolves RecruitViewer.Interviews which returns all of the current Recruit's interviews
+func (r *RecruitViewerResolver) Interviews() ([]*InterviewResolver, error) {
+	defer r.crud.CloseCopy()
+	return findInterviews(r.crud, bson.M{"recruit_id": r.r.ID}, r.a)
+}
+
+// -----------------
+// HunterViewerResolver methods
+// -----------------
+
+// Interviews resolves HunterViewer.Interviews which returns the current Hunter's Company's
+// interviews, optionally limited to a single vacancy
+func (r *HunterViewerResolver) Interviews(args struct{ VacancyID *graphql.ID }) ([]*InterviewResolver, error) {
+	defer r.crud.CloseCopy()
+
+	if utils.IsNullID(r.h.CompanyID) {
+		return make([]*InterviewResolver, 0), nil
+	}
+
+	query := bson.M{"company_id": r.h.CompanyID}
+	if args.VacancyID != nil {
+		id := string(*args.VacancyID)
+		if !bson.IsObjectIdHex(id) {
+			return nil, er.InvalidField("vacancy_id")
+		}
+		query["vacancy_id"] = bson.ObjectIdHex(id)
+	}
+	return findInterviews(r.crud, query, r.a)
+}
+
+// -----------------
+// helpers
+// -----------------
+
+// findInterview retrieves the Interview with the given id
+func findInterview(crud *db.CRUD, interviewID graphql.ID) (*models.Interview, error) {
+	id := string(interviewID)
+	if !bson.IsObjectIdHex(id) {
+		return nil, er.InvalidField("id")
+	}
+
+	rawInterview, err := crud.FindID(config.InterviewsCollection, bson.ObjectIdHex(id))
+	if err != nil {
+		return nil, er.InvalidField("id")
+	}
+	interview := models.TransformInterview(rawInterview)
+	return &interview, nil
+}
+
+// findInterviews retrieves all the interviews matching the query
+func findInterviews(crud *db.CRUD, query bson.M, viewer *models.Account) ([]*InterviewResolver, error) {
+	rawInterviews, err := crud.FindAll(config.InterviewsCollection, query)
+	if err != nil {
+		log.Println(err)
+		return nil, er.Generic()
+	}
+
+	results := make([]*InterviewResolver, 0)
+	for _, raw := range rawInterviews {
+		interview := models.TransformInterview(raw)
+		results = append(results, &InterviewResolver{&interview, crud, viewer})
+	}
+	return results, nil
+}
+
+// updateInterview stores the changes made to an Interview
+func updateInterview(crud *db.CRUD, interview *models.Interview, viewer *models.Account) (*InterviewResolver, error) {
+	rawInterview, err := GenericUpdateByID(crud, config.InterviewsCollection, interview.ID, bson.M{
+		"hunter_id":  interview.HunterID,
+		"slots":      interview.Slots,
+		"start":      interview.Start,
+		"end":        interview.End,
+		"location":   interview.Location,
+		"notes":      interview.Notes,
+		"status":     interview.Status,
+		"sequence":   interview.Sequence,
+		"updated_at": interview.UpdatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	updated := models.TransformInterview(rawInterview)
+	return &InterviewResolver{&updated, crud, viewer}, nil
+}
+
+// toInterviewSlots converts interview slot inputs into models
+func toInterviewSlots(in []*interviewSlot) []models.InterviewSlot {
+	slots := make([]models.InterviewSlot, 0)
+	for _, slot := range in {
+		if slot != nil {
+			slots = append(slots, models.InterviewSlot{Start: slot.Start.Time, End: slot.End.Time})
+		}
+	}
+	return slots
+}
+
+// -----------------
+// calendar
+// -----------------
+
+// InterviewCalendar creates an iCalendar file for a scheduled Interview. The token is a
+// calendar token for the Interview, its account must still be the interviewed Recruit or
+// a member of the interviewing Company
+func InterviewCalendar(crud *db.CRUD, token string, interviewID string) ([]byte, error) {
+	defer crud.CloseCopy()
+
+	// check token
+	claims, err := utils.GetCalendarTokenClaims(token, interviewID)
+	if err != nil || !bson.IsObjectIdHex(claims.AccountID) {
+		return nil, er.InvalidToken()
+	}
+	rawAccount, err := crud.FindID(config.AccountsCollection, bson.ObjectIdHex(claims.AccountID))
+	if err != nil {
+		return nil, er.InvalidToken()
+	}
+	account := models.TransformAccount(rawAccount)
+
+	// retrieve interview
+	interview, err := findInterview(crud, graphql.ID(interviewID))
+	if err != nil {
+		return nil, err
+	}
+	if interview.Start.IsZero() {
+		return nil, er.Input("Interview has not been scheduled.")
+	}
+
+	// check access
+	allowed := account.RecruitID == interview.RecruitID
+	if !allowed && !utils.IsNullID(account.HunterID) {
+		if rawHunter, err := crud.FindID(config.HuntersCollection, account.HunterID); err == nil {
+			allowed = models.TransformHunter(rawHunter).CompanyID == interview.CompanyID
+		}
+	}
+	if !allowed {
+		return nil, er.InvalidField("id")
+	}
+
+	// gather event details
+	summary := "Interview"
+	if rawVacancy, err := crud.FindID(config.VacanciesCollection, interview.VacancyID); err == nil {
+		summary += ": " + models.TransformVacancy(rawVacancy).Title
+	}
+	if rawCompany, err := crud.FindID(config.CompaniesCollection, interview.CompanyID); err == nil {
+		summary += " at " + models.TransformCompany(rawCompany).Name
+	}
+	event := ical.Event{
+		UID:         fmt.Sprintf("%s@irecruit", interview.ID.Hex()),
+		Sequence:    interview.Sequence,
+		Stamp:       interview.UpdatedAt,
+		Start:       interview.Start,
+		End:         interview.End,
+		Summary:     summary,
+		Description: interview.Notes,
+		Location:    interview.Location,
+		Status:      ical.StatusConfirmed,
+	}
+	if rawOrganizer, err := crud.FindOne(config.AccountsCollection, bson.M{"hunter_id": interview.HunterID}); err == nil {
+		organizer := models.TransformAccount(rawOrganizer)
+		event.Organizer = &ical.Person{Name: organizer.Name + " " + organizer.Surname, Email: organizer.Email}
+	}
+	// hunters only get the Recruit's address once they were granted it
+	if rawRecruit, err := crud.FindID(config.RecruitsCollection, interview.RecruitID); err == nil {
+		recruit := models.TransformRecruit(rawRecruit)
+		rawAttendee, err := crud.FindOne(config.AccountsCollection, bson.M{"recruit_id": interview.RecruitID})
+		if err == nil && viewerContactAccess(crud, &recruit, &account).email {
+			attendee := models.TransformAccount(rawAttendee)
+			event.Attendees = []ical.Person{{Name: attendee.Name + " " + attendee.Surname, Email: attendee.Email}}
+		}
+	}
+
+	calendar := ical.Calendar{Method: ical.MethodRequest, Events: []ical.Event{event}}
+	if interview.Status == models.InterviewCancelled {
+		calendar.Method = ical.MethodCancel
+		calendar.Events[0].Status = ical.StatusCancelled
+	}
+	return calendar.Bytes(), nil
+}
+
+// -----------------
+// interviewSlot struct
+// -----------------
+type interviewSlot struct {
+	Start Date
+	End   Date
+}
+
+// -----------------
+// InterviewResolver struct
+// -----------------
+
+// InterviewResolver resolves Interview
+type InterviewResolver struct {
+	i      *models.Interview
+	crud   *db.CRUD
+	viewer *models.Account
+}
+
+// ID resolves Interview.ID
+func (r *InterviewResolver) ID() graphql.ID {
+	return graphql.ID(r.i.ID.Hex())
+}
+
+// Application resolves Interview.Application
+func (r *InterviewResolver) Application() (*ApplicationResolver, error) {
+	defer r.crud.CloseCopy()
+
+	rawApplication, err := r.crud.FindID(config.ApplicationsCollection, r.i.ApplicationID)
+	if err != nil {
+		return nil, nil
+	}
+	application := models.TransformApplication(rawApplication)
+	return &ApplicationResolver{&application, r.crud, r.viewer}, nil
+}
+
+// Vacancy resolves Interview.Vacancy
+func (r *InterviewResolver) Vacancy() (*VacancyResolver, error) {
+	defer r.crud.CloseCopy()
+
+	rawVacancy, err := r.crud.FindID(config.VacanciesCollection, r.i.VacancyID)
+	if err != nil {
+		return nil, nil
+	}
+	vacancy := models.TransformVacancy(rawVacancy)
+	return &VacancyResolver{&vacancy, r.crud}, nil
+}
+
+// Recruit resolves Interview.Recruit
+func (r *InterviewResolver) Recruit() (*RecruitResolver, error) {
+	defer r.crud.CloseCopy()
+
+	rawRecruit, err := r.crud.FindID(config.RecruitsCollection, r.i.RecruitID)
+	if err != nil {
+		return nil, nil
+	}
+	recruit := models.TransformRecruit(rawRecruit)
+	return resolveRecruit(r.crud, &recruit, r.viewer)
+}
+
+// Slots resolves Interview.Slots
+func (r *InterviewResolver) Slots() []*InterviewSlotResolver {
+	results := make([]*InterviewSlotResolver, 0)
+	for i := range r.i.Slots {
+		results = append(results, &InterviewSlotResolver{&r.i.Slots[i]})
+	}
+	return results
+}
+
+// Start resolves Interview.Start which is only set once the Interview has been scheduled
+func (r *InterviewResolver) Start() *Date {
+	if r.i.Start.IsZero() {
+		return nil
+	}
+	return &Date{r.i.Start}
+}
+
+// End resolves Interview.End which is only set once the Interview has been scheduled
+func (r *InterviewResolver) End() *Date {
+	if r.i.End.IsZero() {
+		return nil
+	}
+	return &Date{r.i.End}
+}
+
+// Location resolves Interview.Location
+func (r *InterviewResolver) Location() string {
+	return r.i.Location
+}
+
+// Notes resolves Interview.Notes
+func (r *InterviewResolver) Notes() string {
+	return r.i.Notes
+}
+
+// Status resolves Interview.Status
+func (r *InterviewResolver) Status() string {
+	return r.i.Status
+}
+
+// Sequence resolves Interview.Sequence which is incremented every time a scheduled Interview changes
+func (r *InterviewResolver) Sequence() int32 {
+	return int32(r.i.Sequence)
+}
+
+// IcsURL resolves Interview.IcsURL, the path of the Interview's iCalendar file. It carries
+// a calendar token for the viewer as its "token" query parameter, so it expires shortly
+func (r *InterviewResolver) IcsURL() *string {
+	if r.i.Start.IsZero() || r.viewer == nil {
+		return nil
+	}
+	token, err := utils.CreateCalendarToken(r.viewer.ID.Hex(), r.i.ID.Hex())
+	if err != nil {
+		log.Println("Failed to create calendar token =>", err)
+		return nil
+	}
+	url := "/calendar/" + r.i.ID.Hex() + ".ics?token=" + token
+	return &url
+}
+
+// -----------------
+// InterviewSlotResolver struct
+// -----------------
+
+// InterviewSlotResolver resolves InterviewSlot
+type InterviewSlotResolver struct {
+	s *models.InterviewSlot
+}
+
+// Start resolves InterviewSlot.Start
+func (r *InterviewSlotResolver) Start() Date {
+	return Date{r.s.Start}
+}
+
+// End resolves InterviewSlot.End
+func (r *InterviewSlotResolver) End() Date {
+	return Date{r.s.End}
+}
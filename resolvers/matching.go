@@ -0,0 +1,344 @@
+package resolvers
+
+import (
+	"log"
+	"sort"
+
+	config "../config"
+	db "../database"
+	er "../errors"
+	matching "../matching"
+	models "../models"
+	utils "../utils"
+	graphql "github.com/graph-gophers/graphql-go"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// default and maximum number of recommendations returned
+const (
+	defaultRecommendations = 10
+	maxRecommendations     = 50
+)
+
+// -----------------
+// HunterViewerResolver methods
+// -----------------
+
+// RecommendedRecruits resolves HunterViewer.RecommendedRecruits which ranks the recruits
+// who haven't applied to one of the current Hunter's Company's vacancies, optionally
+// only those who have all of the given skills
+func (r *HunterViewerResolver) RecommendedRecruits(args struct {
+	VacancyID graphql.ID
+	SkillIDs  *[]graphql.ID
+	First     *int32
+}) ([]*RecruitRecommendationResolver, error) {
+	defer r.crud.CloseCopy()
+
+	if utils.IsNullID(r.h.CompanyID) {
+		return nil, er.Input("Hunter does not belong to a Company.")
+	}
+	limit, err := recommendationLimit(args.First)
+	if err != nil {
+		return nil, err
+	}
+
+	skillIDs := make([]bson.ObjectId, 0)
+	if args.SkillIDs != nil {
+		if skillIDs, err = parseSkillIDs(*args.SkillIDs, "skill_ids"); err != nil {
+			return nil, err
+		}
+	}
+
+	// check the id
+	id := string(args.VacancyID)
+	if !bson.IsObjectIdHex(id) {
+		return nil, er.InvalidField("vacancy_id")
+	}
+
+	// retrieve vacancy
+	rawVacancy, err := r.crud.FindID(config.VacanciesCollection, bson.ObjectIdHex(id))
+	if err != nil {
+		return nil, er.InvalidField("vacancy_id")
+	}
+	vacancy := models.TransformVacancy(rawVacancy)
+	if vacancy.CompanyID != r.h.CompanyID {
+		return nil, er.InvalidField("vacancy_id")
+	}
+
+	// gather what's needed to score recruits
+	questions, err := questionIndustries(r.crud)
+	if err != nil {
+		return nil, err
+	}
+	rawApplications, err := r.crud.FindAll(config.ApplicationsCollection, nil)
+	if err != nil {
+		log.Println(err)
+		return nil, er.Generic()
+	}
+	applicants := make(map[bson.ObjectId]bool)
+	availabilities := make(map[bson.ObjectId]string)
+	for _, raw := range rawApplications {
+		application := models.TransformApplication(raw)
+		if application.VacancyID == vacancy.ID {
+			applicants[application.RecruitID] = true
+		}
+		availabilities[application.RecruitID] = mergeAvailability(
+			availabilities[application.RecruitID],
+			application.Stage,
+		)
+	}
+	rawRecruits, err := r.crud.FindAll(config.RecruitsCollection, nil)
+	if err != nil {
+		log.Println(err)
+		return nil, er.Generic()
+	}
+
+	// score recruits
+	opening := vacancyOpening(&vacancy)
+	results := make([]*RecruitRecommendationResolver, 0)
+	for _, raw := range rawRecruits {
+		recruit := models.TransformRecruit(raw)
+		if applicants[recruit.ID] || !recruitHasSkills(&recruit, skillIDs) {
+			continue
+		}
+		score := matching.Match(recruitCandidate(&recruit, questions, availabilities[recruit.ID]), opening)
+		if score.Total == 0 {
+			continue
+		}
+		results = append(results, &RecruitRecommendationResolver{&recruit, score, r.crud, r.a})
+	}
+
+	// best matches first
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score.Total > results[j].score.Total
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// -----------------
+// RecruitViewerResolver methods
+// -----------------
+
+// RecommendedVacancies resolves RecruitViewer.RecommendedVacancies which ranks the open
+// vacancies the current Recruit hasn't applied to
+func (r *RecruitViewerResolver) RecommendedVacancies(args struct{ First *int32 }) ([]*VacancyRecommendationResolver, error) {
+	defer r.crud.CloseCopy()
+
+	limit, err := recommendationLimit(args.First)
+	if err != nil {
+		return nil, err
+	}
+
+	// gather what's needed to score the recruit
+	questions, err := questionIndustries(r.crud)
+	if err != nil {
+		return nil, err
+	}
+	rawApplications, err := r.crud.FindAll(config.ApplicationsCollection, bson.M{"recruit_id": r.r.ID})
+	if err != nil {
+		log.Println(err)
+		return nil, er.Generic()
+	}
+	applied := make(map[bson.ObjectId]bool)
+	availability := ""
+	for _, raw := range rawApplications {
+		application := models.TransformApplication(raw)
+		applied[application.VacancyID] = true
+		availability = mergeAvailability(availability, application.Stage)
+	}
+	rawVacancies, err := r.crud.FindAll(config.VacanciesCollection, bson.M{"status": models.VacancyOpen})
+	if err != nil {
+		log.Println(err)
+		return nil, er.Generic()
+	}
+
+	// score vacancies
+	candidate := recruitCandidate(r.r, questions, availability)
+	results := make([]*VacancyRecommendationResolver, 0)
+	for _, raw := range rawVacancies {
+		vacancy := models.TransformVacancy(raw)
+		if !vacancy.IsOpen() || applied[vacancy.ID] {
+			continue
+		}
+		score := matching.Match(candidate, vacancyOpening(&vacancy))
+		if score.Total == 0 {
+			continue
+		}
+		results = append(results, &VacancyRecommendationResolver{&vacancy, score, r.crud})
+	}
+
+	// best matches first
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score.Total > results[j].score.Total
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// -----------------
+// helpers
+// -----------------
+
+// recommendationLimit validates the requested number of recommendations
+func recommendationLimit(first *int32) (int, error) {
+	if first == nil {
+		return defaultRecommendations, nil
+	}
+	limit := int(*first)
+	if limit < 1 || limit > maxRecommendations {
+		return 0, er.InvalidField("first")
+	}
+	return limit, nil
+}
+
+// questionIndustries maps question ids onto the ids of the industries they're asked in
+func questionIndustries(crud *db.CRUD) (map[bson.ObjectId]string, error) {
+	rawQuestions, err := crud.FindAll(config.QuestionsCollection, nil)
+	if err != nil {
+		log.Println("Failed to find questions =>", err)
+		return nil, er.Generic()
+	}
+
+	industries := make(map[bson.ObjectId]string)
+	for _, raw := range rawQuestions {
+		question := models.TransformQuestion(raw)
+		industries[question.ID] = question.IndustryID.Hex()
+	}
+	return industries, nil
+}
+
+// recruitCandidate creates a matching Candidate from a Recruit
+func recruitCandidate(recruit *models.Recruit, questions map[bson.ObjectId]string, availability string) matching.Candidate {
+	answers := make(map[string]int)
+	for _, qa := range recruit.Answers {
+		if id, ok := questions[qa.QuestionID]; ok {
+			answers[id]++
+		}
+	}
+	if availability == "" {
+		availability = matching.Available
+	}
+	return matching.Candidate{
+		Province:     recruit.Province,
+		City:         recruit.City,
+		Answers:      answers,
+		Availability: availability,
+	}
+}
+
+// vacancyOpening creates a matching Opening from a Vacancy
+func vacancyOpening(vacancy *models.Vacancy) matching.Opening {
+	return matching.Opening{
+		IndustryID: vacancy.IndustryID.Hex(),
+		Province:   vacancy.Province,
+		City:       vacancy.City,
+	}
+}
+
+// mergeAvailability updates a recruit's availability with the stage of one of their applications
+func mergeAvailability(availability, stage string) string {
+	switch {
+	case stage == models.StageHired || availability == matching.Hired:
+		return matching.Hired
+	case stage == models.StageOffer:
+		return matching.HasOffer
+	}
+	return availability
+}
+
+// -----------------
+// RecruitRecommendationResolver struct
+// -----------------
+
+// RecruitRecommendationResolver resolves RecruitRecommendation
+type RecruitRecommendationResolver struct {
+	r      *models.Recruit
+	score  matching.Score
+	crud   *db.CRUD
+	viewer *models.Account
+}
+
+// Recruit resolves RecruitRecommendation.Recruit
+func (r *RecruitRecommendationResolver) Recruit() (*RecruitResolver, error) {
+	defer r.crud.CloseCopy()
+	return resolveRecruit(r.crud, r.r, r.viewer)
+}
+
+// Score resolves RecruitRecommendation.Score
+func (r *RecruitRecommendationResolver) Score() int32 {
+	return int32(r.score.Total)
+}
+
+// Components resolves RecruitRecommendation.Components
+func (r *RecruitRecommendationResolver) Components() []*ScoreComponentResolver {
+	return resolveScoreComponents(r.score)
+}
+
+// -----------------
+// VacancyRecommendationResolver struct
+// -----------------
+
+// VacancyRecommendationResolver resolves VacancyRecommendation
+type VacancyRecommendationResolver struct {
+	v     *models.Vacancy
+	score matching.Score
+	crud  *db.CRUD
+}
+
+// Vacancy resolves VacancyRecommendation.Vacancy
+func (r *VacancyRecommendationResolver) Vacancy() *VacancyResolver {
+	return &VacancyResolver{r.v, r.crud}
+}
+
+// Score resolves VacancyRecommendation.Score
+func (r *VacancyRecommendationResolver) Score() int32 {
+	return int32(r.score.Total)
+}
+
+// Components resolves VacancyRecommendation.Components
+func (r *VacancyRecommendationResolver) Components() []*ScoreComponentResolver {
+	return resolveScoreComponents(r.score)
+}
+
+// -----------------
+// ScoreComponentResolver struct
+// -----------------
+
+// ScoreComponentResolver resolves ScoreComponent
+type ScoreComponentResolver struct {
+	c *matching.Component
+}
+
+// resolveScoreComponents creates resolvers for the components of a score
+func resolveScoreComponents(score matching.Score) []*ScoreComponentResolver {
+	results := make([]*ScoreComponentResolver, 0)
+	for i := range score.Components {
+		results = append(results, &ScoreComponentResolver{&score.Components[i]})
+	}
+	return results
+}
+
+// Name resolves ScoreComponent.Name
+func (r *ScoreComponentResolver) Name() string {
+	return r.c.Name
+}
+
+// Score resolves ScoreComponent.Score
+func (r *ScoreComponentResolver) Score() int32 {
+	return int32(r.c.Score)
+}
+
+// Max resolves ScoreComponent.Max
+func (r *ScoreComponentResolver) Max() int32 {
+	return int32(r.c.Max)
+}
+
+// Explanation resolves ScoreComponent.Explanation
+func (r *ScoreComponentResolver) Explanation() string {
+	return r.c.Explanation
+}
@@ -0,0 +1,416 @@
+package resolvers
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	config "../config"
+	db "../database"
+	er "../errors"
+	models "../models"
+	notify "../notify"
+	utils "../utils"
+	graphql "github.com/graph-gophers/graphql-go"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// default and maximum number of notifications returned at a time
+const (
+	defaultNotificationPageSize = 20
+	maxNotificationPageSize     = 100
+)
+
+// -----------------
+// Viewer methods
+// -----------------
+
+// Notifications resolves RecruitViewer.Notifications
+func (r *RecruitViewerResolver) Notifications() *NotificationsResolver {
+	return &NotificationsResolver{r.a, r.crud}
+}
+
+// Notifications resolves HunterViewer.Notifications
+func (r *HunterViewerResolver) Notifications() *NotificationsResolver {
+	return &NotificationsResolver{r.a, r.crud}
+}
+
+// Notifications resolves SysViewer.Notifications
+func (r *SysViewerResolver) Notifications() *NotificationsResolver {
+	return &NotificationsResolver{r.a, r.crud}
+}
+
+// Notifications resolves AccountViewer.Notifications
+func (r *AccountViewerResolver) Notifications() *NotificationsResolver {
+	return &NotificationsResolver{r.a, r.crud}
+}
+
+// -----------------
+// AccountEditorResolver methods
+// -----------------
+
+// MarkNotificationsRead resolves AccountEditor.MarkNotificationsRead which marks
+// the given notifications, or all of them if none are given, as read
+func (r *AccountEditorResolver) MarkNotificationsRead(args struct{ IDs *[]graphql.ID }) (*NotificationsResolver, error) {
+	defer r.crud.CloseCopy()
+
+	query := bson.M{"account_id": r.a.ID, "read": false}
+	if args.IDs != nil {
+		ids := make([]bson.ObjectId, 0)
+		for _, id := range *args.IDs {
+			if !bson.IsObjectIdHex(string(id)) {
+				return nil, er.InvalidField("ids")
+			}
+			ids = append(ids, bson.ObjectIdHex(string(id)))
+		}
+		query["_id"] = bson.M{"$in": ids}
+	}
+
+	rawNotifications, err := r.crud.FindAll(config.NotificationsCollection, query)
+	if err != nil {
+		log.Println(err)
+		return nil, er.Generic()
+	}
+	for _, raw := range rawNotifications {
+		notification := models.TransformNotification(raw)
+		if err := r.crud.UpdateID(config.NotificationsCollection, notification.ID, bson.M{"read": true}); err != nil {
+			log.Println("Failed to mark notification as read =>", err)
+			return nil, er.Generic()
+		}
+	}
+
+	return &NotificationsResolver{r.a, r.crud}, nil
+}
+
+// SetNotificationPreference resolves AccountEditor.SetNotificationPreference
+func (r *AccountEditorResolver) SetNotificationPreference(args struct {
+	Event string
+	InApp bool
+	Email bool
+}) (*NotificationPreferenceResolver, error) {
+	defer r.crud.CloseCopy()
+
+	settings := notify.Settings(r.crud, r.a.ID)
+	_, err := r.crud.FindID(config.NotificationSettingsCollection, settings.ID)
+	exists := err == nil
+
+	preference := models.NotificationPreference{Event: args.Event, InApp: args.InApp, Email: args.Email}
+	if err := settings.SetPreference(preference); err != nil {
+		return nil, err
+	}
+
+	// store settings in db
+	if exists {
+		err = r.crud.UpdateID(config.NotificationSettingsCollection, settings.ID, bson.M{"preferences": settings.Preferences})
+	} else {
+		err = r.crud.Insert(config.NotificationSettingsCollection, settings)
+	}
+	if err != nil {
+		log.Println("Failed to store notification settings =>", err)
+		return nil, er.Generic()
+	}
+
+	return &NotificationPreferenceResolver{&preference}, nil
+}
+
+// -----------------
+// domain events
+// -----------------
+
+// recruitAccountIDs finds the id of the account owning the Recruit profile
+func recruitAccountIDs(crud *db.CRUD, recruitID bson.ObjectId) []bson.ObjectId {
+	ids := make([]bson.ObjectId, 0)
+	rawAccount, err := crud.FindOne(config.AccountsCollection, bson.M{"recruit_id": recruitID})
+	if err == nil {
+		ids = append(ids, models.TransformAccount(rawAccount).ID)
+	}
+	return ids
+}
+
+// companyAccountIDs finds the ids of the accounts of a Company's members,
+// optionally limited to members with one of the given roles
+func companyAccountIDs(crud *db.CRUD, companyID bson.ObjectId, roles ...string) []bson.ObjectId {
+	ids := make([]bson.ObjectId, 0)
+	query := bson.M{"company_id": companyID}
+	if len(roles) > 0 {
+		query["role"] = bson.M{"$in": roles}
+	}
+	rawHunters, err := crud.FindAll(config.HuntersCollection, query)
+	if err != nil {
+		return ids
+	}
+
+	hunterIDs := make([]bson.ObjectId, 0)
+	for _, raw := range rawHunters {
+		hunterIDs = append(hunterIDs, models.TransformHunter(raw).ID)
+	}
+	rawAccounts, err := crud.FindAll(config.AccountsCollection, bson.M{"hunter_id": bson.M{"$in": hunterIDs}})
+	if err != nil {
+		return ids
+	}
+	for _, raw := range rawAccounts {
+		ids = append(ids, models.TransformAccount(raw).ID)
+	}
+	return ids
+}
+
+// notifyProfileUpdated notifies the companies that the Recruit has active applications with
+func notifyProfileUpdated(crud *db.CRUD, notifier *notify.Dispatcher, recruit *models.Recruit) {
+	rawApplications, err := crud.FindAll(config.ApplicationsCollection, bson.M{
+		"recruit_id": recruit.ID,
+		"stage":      bson.M{"$nin": []string{models.StageHired, models.StageRejected}},
+	})
+	if err != nil {
+		return
+	}
+
+	name := "An applicant"
+	if rawAccount, err := crud.FindOne(config.AccountsCollection, bson.M{"recruit_id": recruit.ID}); err == nil {
+		account := models.TransformAccount(rawAccount)
+		name = strings.TrimSpace(account.Name + " " + account.Surname)
+	}
+
+	notified := make(map[bson.ObjectId]bool)
+	for _, raw := range rawApplications {
+		application := models.TransformApplication(raw)
+		if notified[application.CompanyID] {
+			continue
+		}
+		notified[application.CompanyID] = true
+		notifier.Notify(companyAccountIDs(crud, application.CompanyID), notify.Event{
+			Type:      models.EventProfileUpdated,
+			Title:     "Applicant profile updated",
+			Body:      name + " updated their profile.",
+			SubjectID: recruit.ID,
+		})
+	}
+}
+
+// notifyApplicationMoved notifies the Recruit that their Application moved to a new stage
+func notifyApplicationMoved(crud *db.CRUD, notifier *notify.Dispatcher, application *models.Application) {
+	title := "Your application"
+	if rawVacancy, err := crud.FindID(config.VacanciesCollection, application.VacancyID); err == nil {
+		title = "Your application for " + models.TransformVacancy(rawVacancy).Title
+	}
+	notifier.Notify(recruitAccountIDs(crud, application.RecruitID), notify.Event{
+		Type:      models.EventApplicationMoved,
+		Title:     "Application update",
+		Body:      fmt.Sprintf("%s is now at the %s stage.", title, strings.ToLower(application.Stage)),
+		SubjectID: application.ID,
+	})
+}
+
+// notifyDocumentVerified notifies the owner of a Document that it was verified
+func notifyDocumentVerified(crud *db.CRUD, notifier *notify.Dispatcher, document *models.Document) {
+	var accountIDs []bson.ObjectId
+	if document.OwnerType == "COMPANY" {
+		accountIDs = companyAccountIDs(crud, document.OwnerID, models.CompanyRoleOwner)
+	} else {
+		accountIDs = recruitAccountIDs(crud, document.OwnerID)
+	}
+	notifier.Notify(accountIDs, notify.Event{
+		Type:      models.EventDocumentVerified,
+		Title:     "Document verified",
+		Body:      "Your " + strings.ToLower(document.DocType) + " document has been verified.",
+		SubjectID: document.ID,
+	})
+}
+
+// notifyNewMessage notifies the other side of a Thread of a new Message
+func notifyNewMessage(crud *db.CRUD, notifier *notify.Dispatcher, thread *models.Thread, message *models.Message) {
+	var accountIDs []bson.ObjectId
+	if message.SenderRole == models.ParticipantRecruit {
+		accountIDs = companyAccountIDs(crud, thread.CompanyID)
+	} else {
+		accountIDs = recruitAccountIDs(crud, thread.RecruitID)
+	}
+
+	body := message.Body
+	if runes := []rune(body); len(runes) > 140 {
+		body = string(runes[:140]) + "…"
+	}
+	notifier.Notify(accountIDs, notify.Event{
+		Type:      models.EventNewMessage,
+		Title:     "New message: " + thread.Subject,
+		Body:      body,
+		SubjectID: thread.ID,
+	})
+}
+
+// notifyContactRequested notifies a Recruit that a Hunter asked for their contact details
+func notifyContactRequested(crud *db.CRUD, notifier *notify.Dispatcher, hunter *models.Account, request *models.ContactRequest) {
+	name := strings.TrimSpace(hunter.Name + " " + hunter.Surname)
+	if rawCompany, err := crud.FindID(config.CompaniesCollection, request.CompanyID); err == nil {
+		name += " from " + models.TransformCompany(rawCompany).Name
+	}
+	notifier.Notify(recruitAccountIDs(crud, request.RecruitID), notify.Event{
+		Type:      models.EventContactRequested,
+		Title:     "Contact details requested",
+		Body:      name + " would like to see your contact details.",
+		SubjectID: request.ID,
+	})
+}
+
+// notifyContactAnswered notifies a Hunter that a Recruit answered their contact request
+func notifyContactAnswered(crud *db.CRUD, notifier *notify.Dispatcher, request *models.ContactRequest) {
+	rawAccount, err := crud.FindOne(config.AccountsCollection, bson.M{"hunter_id": request.HunterID})
+	if err != nil {
+		return
+	}
+	notifier.Notify([]bson.ObjectId{models.TransformAccount(rawAccount).ID}, notify.Event{
+		Type:      models.EventContactAnswered,
+		Title:     "Contact request " + strings.ToLower(request.Status),
+		Body:      "Your request for a recruit's contact details was " + strings.ToLower(request.Status) + ".",
+		SubjectID: request.ID,
+	})
+}
+
+// -----------------
+// NotificationsResolver struct
+// -----------------
+
+// NotificationsResolver resolves Notifications, an account's inbox
+type NotificationsResolver struct {
+	a    *models.Account
+	crud *db.CRUD
+}
+
+// Unread resolves Notifications.Unread
+func (r *NotificationsResolver) Unread() (int32, error) {
+	defer r.crud.CloseCopy()
+
+	_, total, err := r.crud.FindPage(config.NotificationsCollection, bson.M{"account_id": r.a.ID, "read": false}, nil, 0, 1)
+	if err != nil {
+		log.Println(err)
+		return 0, er.Generic()
+	}
+	return int32(total), nil
+}
+
+// Items resolves Notifications.Items, newest first
+func (r *NotificationsResolver) Items(args struct {
+	UnreadOnly *bool
+	First      *int32
+	Skip       *int32
+}) ([]*NotificationResolver, error) {
+	defer r.crud.CloseCopy()
+
+	// prepare paging
+	limit := defaultNotificationPageSize
+	if args.First != nil {
+		limit = int(*args.First)
+	}
+	if limit <= 0 || limit > maxNotificationPageSize {
+		return nil, er.InvalidField("first")
+	}
+	skip := 0
+	if args.Skip != nil {
+		skip = int(*args.Skip)
+	}
+	if skip < 0 {
+		return nil, er.InvalidField("skip")
+	}
+
+	query := bson.M{"account_id": r.a.ID}
+	if args.UnreadOnly != nil && *args.UnreadOnly {
+		query["read"] = false
+	}
+	rawNotifications, _, err := r.crud.FindPage(config.NotificationsCollection, query, []string{"-created_at", "-_id"}, skip, limit)
+	if err != nil {
+		log.Println(err)
+		return nil, er.Generic()
+	}
+
+	results := make([]*NotificationResolver, 0)
+	for _, raw := range rawNotifications {
+		notification := models.TransformNotification(raw)
+		results = append(results, &NotificationResolver{&notification})
+	}
+	return results, nil
+}
+
+// Preferences resolves Notifications.Preferences, which lists the
+// account's preference for every event
+func (r *NotificationsResolver) Preferences() []*NotificationPreferenceResolver {
+	defer r.crud.CloseCopy()
+
+	settings := notify.Settings(r.crud, r.a.ID)
+	results := make([]*NotificationPreferenceResolver, 0)
+	for _, event := range models.NotificationEvents {
+		preference := settings.Preference(event)
+		results = append(results, &NotificationPreferenceResolver{&preference})
+	}
+	return results
+}
+
+// -----------------
+// NotificationResolver struct
+// -----------------
+
+// NotificationResolver resolves Notification
+type NotificationResolver struct {
+	n *models.Notification
+}
+
+// ID resolves Notification.ID
+func (r *NotificationResolver) ID() graphql.ID {
+	return graphql.ID(r.n.ID.Hex())
+}
+
+// Event resolves Notification.Event
+func (r *NotificationResolver) Event() string {
+	return r.n.Event
+}
+
+// Title resolves Notification.Title
+func (r *NotificationResolver) Title() string {
+	return r.n.Title
+}
+
+// Body resolves Notification.Body
+func (r *NotificationResolver) Body() string {
+	return r.n.Body
+}
+
+// SubjectID resolves Notification.SubjectID, the id of what the notification is about
+func (r *NotificationResolver) SubjectID() *graphql.ID {
+	if utils.IsNullID(r.n.SubjectID) {
+		return nil
+	}
+	id := graphql.ID(r.n.SubjectID.Hex())
+	return &id
+}
+
+// Read resolves Notification.Read
+func (r *NotificationResolver) Read() bool {
+	return r.n.Read
+}
+
+// CreatedAt resolves Notification.CreatedAt
+func (r *NotificationResolver) CreatedAt() Date {
+	return Date{r.n.CreatedAt}
+}
+
+// -----------------
+// NotificationPreferenceResolver struct
+// -----------------
+
+// NotificationPreferenceResolver resolves NotificationPreference
+type NotificationPreferenceResolver struct {
+	p *models.NotificationPreference
+}
+
+// Event resolves NotificationPreference.Event
+func (r *NotificationPreferenceResolver) Event() string {
+	return r.p.Event
+}
+
+// InApp resolves NotificationPreference.InApp
+func (r *NotificationPreferenceResolver) InApp() bool {
+	return r.p.InApp
+}
+
+// Email resolves NotificationPreference.Email
+func (r *NotificationPreferenceResolver) Email() bool {
+	return r.p.Email
+}
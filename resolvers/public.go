@@ -3,11 +3,14 @@ package resolvers
 import (
 	"context"
 	"log"
+	"sort"
 
 	config "../config"
+	db "../database"
 	er "../errors"
 	mware "../middleware"
 	models "../models"
+	notify "../notify"
 	utils "../utils"
 	graphql "github.com/graph-gophers/graphql-go"
 	"gopkg.in/mgo.v2/bson"
@@ -96,11 +99,26 @@ func (r *RootResolver) CreateAccount(ctx context.Context, args struct{ Info *acc
 		return nil, er.Internal(genericErr)
 	}
 
+	r.notifier.Notify([]bson.ObjectId{account.ID}, notify.Event{
+		Type:      models.EventAccountCreated,
+		Title:     "Welcome to iRecruit",
+		Body:      "Your account has been created, " + account.Name + ".",
+		SubjectID: account.ID,
+	})
+
 	return &TokensResolver{refresh: refresh, access: access}, nil
 }
 
-// RandomQuestions resolves "randomQuestions" gql query
-func (r *RootResolver) RandomQuestions(args struct{ IndustryID graphql.ID }) ([]*QuestionResolver, error) {
+// RandomQuestions resolves "randomQuestions" gql query, active questions of the industry and,
+// unless inherited is false, of the industries it's nested in are picked by weight.
+// With a token, questions the token's recruit already answered are left out
+func (r *RootResolver) RandomQuestions(args struct {
+	IndustryID graphql.ID
+	Count      *int32
+	Difficulty *string
+	Token      *string
+	Inherited  *bool
+}) ([]*QuestionResolver, error) {
 	defer r.crud.CloseCopy()
 
 	// check that the ID is valid
@@ -108,24 +126,67 @@ func (r *RootResolver) RandomQuestions(args struct{ IndustryID graphql.ID }) ([]
 	if !bson.IsObjectIdHex(id) {
 		return nil, er.InvalidField("id")
 	}
+	count := int32(defaultRandomQuestions)
+	if args.Count != nil {
+		count = *args.Count
+	}
+	if count < 1 || count > maxRandomQuestions {
+		return nil, er.InvalidField("count")
+	}
 
-	// get industries
-	rawQuestions, err := r.crud.FindAll(config.QuestionsCollection, bson.M{"industry_id": bson.ObjectIdHex(id)})
+	// questions that were already answered aren't asked again
+	answered := make(map[bson.ObjectId]bool)
+	if args.Token != nil {
+		ids, err := tokenAnsweredQuestions(r.crud, *args.Token)
+		if err != nil {
+			return nil, err
+		}
+		answered = ids
+	}
+
+	// get questions, sub-industries inherit the questions of their parents
+	industryIDs := []bson.ObjectId{bson.ObjectIdHex(id)}
+	if args.Inherited == nil || *args.Inherited {
+		if industry, err := findIndustry(r.crud, bson.ObjectIdHex(id)); err == nil {
+			for _, ancestor := range industryAncestors(r.crud, industry) {
+				industryIDs = append(industryIDs, ancestor.ID)
+			}
+		}
+	}
+	query := bson.M{"industry_id": bson.M{"$in": industryIDs}}
+	rawQuestions, err := r.crud.FindAll(config.QuestionsCollection, query)
 	if err != nil {
 		log.Println(err)
 		return nil, er.Generic()
 	}
 
-	// process results
-	randomQuestions := make([]*QuestionResolver, 0)
-	rawQuestions = utils.PickRandomN(2, rawQuestions)
+	// keep the questions that can be asked, in a stable order so a seeded
+	// selection picks the same ones
+	questions := make([]models.Question, 0, len(rawQuestions))
 	for _, raw := range rawQuestions {
 		question := models.TransformQuestion(raw)
-		randomQuestions = append(randomQuestions, &QuestionResolver{&question})
+		if question.Status != models.QuestionActive || answered[question.ID] {
+			continue
+		}
+		if args.Difficulty != nil && question.Difficulty != *args.Difficulty {
+			continue
+		}
+		questions = append(questions, question)
+	}
+	sort.Slice(questions, func(i, j int) bool { return questions[i].ID < questions[j].ID })
+
+	// process results
+	weights := make([]int, len(questions))
+	for i, question := range questions {
+		weights[i] = int(question.Weight)
+	}
+	randomQuestions := make([]*QuestionResolver, 0, count)
+	for _, i := range r.rand.PickWeightedN(int(count), weights) {
+		randomQuestions = append(randomQuestions, &QuestionResolver{&questions[i]})
 	}
 
 	// return randomQuestions
-	return randomQuestions, err
+	return randomQuestions, nil
 }
 
 // Industries resolves "industries" gql query
@@ -143,7 +204,7 @@ func (r *RootResolver) Industries() ([]*IndustryResolver, error) {
 	results := make([]*IndustryResolver, 0)
 	for _, raw := range rawIndustries {
 		industry := models.TransformIndustry(raw)
-		results = append(results, &IndustryResolver{&industry})
+		results = append(results, &IndustryResolver{&industry, r.crud})
 	}
 	return results, err
 }
@@ -248,9 +309,39 @@ func (r *QuestionResolver) IndustryID() graphql.ID {
 	return graphql.ID(r.q.IndustryID.Hex())
 }
 
-// Question resolves Question.Question
-func (r *QuestionResolver) Question() string {
-	return r.q.Question
+// Question resolves Question.Question which is the wording of the current revision,
+// translated to the request's locale when there's a translation to it
+func (r *QuestionResolver) Question(ctx context.Context) string {
+	return models.Translate(r.q.Question, r.q.Translations, mware.Locales(ctx))
+}
+
+// Revision resolves Question.Revision
+func (r *QuestionResolver) Revision() int32 {
+	return r.q.Revision
+}
+
+// Revisions resolves Question.Revisions, oldest first
+func (r *QuestionResolver) Revisions() []*QuestionRevisionResolver {
+	results := make([]*QuestionRevisionResolver, 0, len(r.q.Revisions))
+	for i := range r.q.Revisions {
+		results = append(results, &QuestionRevisionResolver{&r.q.Revisions[i]})
+	}
+	return results
+}
+
+// Status resolves Question.Status
+func (r *QuestionResolver) Status() string {
+	return r.q.Status
+}
+
+// Weight resolves Question.Weight
+func (r *QuestionResolver) Weight() int32 {
+	return r.q.Weight
+}
+
+// Difficulty resolves Question.Difficulty
+func (r *QuestionResolver) Difficulty() string {
+	return r.q.Difficulty
 }
 
 // -----------------
@@ -259,7 +350,8 @@ func (r *QuestionResolver) Question() string {
 
 // IndustryResolver resolves Industry
 type IndustryResolver struct {
-	i *models.Industry
+	i    *models.Industry
+	crud *db.CRUD
 }
 
 // ID resolves Industry.ID
@@ -267,7 +359,26 @@ func (r *IndustryResolver) ID() graphql.ID {
 	return graphql.ID(r.i.ID.Hex())
 }
 
-// Name resolves Industry.Name
-func (r *IndustryResolver) Name() string {
-	return r.i.Name
+// Name resolves Industry.Name, translated to the request's locale when there's a translation to it
+func (r *IndustryResolver) Name(ctx context.Context) string {
+	return models.Translate(r.i.Name, r.i.Translations, mware.Locales(ctx))
+}
+
+// MinAnswers resolves Industry.MinAnswers
+func (r *IndustryResolver) MinAnswers() int32 {
+	return r.i.MinAnswers
+}
+
+// MaxAnswers resolves Industry.MaxAnswers
+func (r *IndustryResolver) MaxAnswers() int32 {
+	return r.i.MaxAnswers
+}
+
+// ParentID resolves Industry.ParentID
+func (r *IndustryResolver) ParentID() *graphql.ID {
+	if r.i.ParentID == "" {
+		return nil
+	}
+	id := graphql.ID(r.i.ParentID.Hex())
+	return &id
 }
@@ -0,0 +1,162 @@
+package resolvers
+
+import (
+	"log"
+
+	config "../config"
+	db "../database"
+	er "../errors"
+	models "../models"
+	utils "../utils"
+	graphql "github.com/graph-gophers/graphql-go"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// randomQuestions limits
+const (
+	defaultRandomQuestions = 2
+	maxRandomQuestions     = 20
+)
+
+// -----------------
+// helpers
+// -----------------
+
+// tokenAnsweredQuestions finds the IDs of the questions answered by the recruit
+// profile of the token's account, accounts without one haven't answered any
+func tokenAnsweredQuestions(crud *db.CRUD, token string) (map[bson.ObjectId]bool, error) {
+	claims, err := utils.GetTokenClaims(token)
+	if err != nil || claims.Refresh || !bson.IsObjectIdHex(claims.AccountID) {
+		return nil, er.InvalidToken()
+	}
+	rawAccount, err := crud.FindID(config.AccountsCollection, bson.ObjectIdHex(claims.AccountID))
+	if err != nil {
+		return nil, er.InvalidToken()
+	}
+	account := models.TransformAccount(rawAccount)
+
+	answered := make(map[bson.ObjectId]bool)
+	if utils.IsNullID(account.RecruitID) {
+		return answered, nil
+	}
+	rawRecruit, err := crud.FindID(config.RecruitsCollection, account.RecruitID)
+	if err != nil {
+		log.Println("Failed to find recruit =>", err)
+		return nil, er.Generic()
+	}
+	for _, qa := range models.TransformRecruit(rawRecruit).Answers {
+		answered[qa.QuestionID] = true
+	}
+	return answered, nil
+}
+
+// setQuestionStatus sets the status of the Question with the given ID
+func setQuestionStatus(crud *db.CRUD, id graphql.ID, status string) (*QuestionResolver, error) {
+	defer crud.CloseCopy()
+
+	if !bson.IsObjectIdHex(string(id)) {
+		return nil, er.InvalidField("id")
+	}
+	bid := bson.ObjectIdHex(string(id))
+	rawQuestion, err := crud.FindID(config.QuestionsCollection, bid)
+	if err != nil {
+		return nil, er.Input("Question not found.")
+	}
+	question := models.TransformQuestion(rawQuestion)
+
+	question.Status = status
+	if err := crud.UpdateID(config.QuestionsCollection, bid, bson.M{"status": status}); err != nil {
+		log.Println("Failed to update question status =>", err)
+		return nil, er.Generic()
+	}
+	return &QuestionResolver{&question}, nil
+}
+
+// -----------------
+// Viewer methods
+// -----------------
+
+// QuestionRevisions resolves SysViewer.QuestionRevisions which counts the
+// answers recruits gave to each revision of a Question
+func (r *SysViewerResolver) QuestionRevisions(args struct{ QuestionID graphql.ID }) ([]*QuestionRevisionAnswersResolver, error) {
+	defer r.crud.CloseCopy()
+
+	id := string(args.QuestionID)
+	if !bson.IsObjectIdHex(id) {
+		return nil, er.InvalidField("question_id")
+	}
+	rawQuestion, err := r.crud.FindID(config.QuestionsCollection, bson.ObjectIdHex(id))
+	if err != nil {
+		return nil, er.Input("Question not found.")
+	}
+	question := models.TransformQuestion(rawQuestion)
+
+	rawRecruits, err := r.crud.FindAll(config.RecruitsCollection, bson.M{"answers.question_id": question.ID})
+	if err != nil {
+		log.Println("Failed to find recruits who answered the question =>", err)
+		return nil, er.Generic()
+	}
+	answers := make(map[int32]int32)
+	for _, raw := range rawRecruits {
+		for _, qa := range models.TransformRecruit(raw).Answers {
+			if qa.QuestionID == question.ID {
+				answers[qa.Revision]++
+			}
+		}
+	}
+
+	results := make([]*QuestionRevisionAnswersResolver, 0, len(question.Revisions))
+	for i := range question.Revisions {
+		revision := &question.Revisions[i]
+		results = append(results, &QuestionRevisionAnswersResolver{revision, answers[revision.Revision]})
+	}
+	return results, nil
+}
+
+// -----------------
+// QuestionRevisionResolver struct
+// -----------------
+
+// QuestionRevisionResolver resolves QuestionRevision
+type QuestionRevisionResolver struct {
+	r *models.QuestionRevision
+}
+
+// Revision resolves QuestionRevision.Revision
+func (r *QuestionRevisionResolver) Revision() int32 {
+	return r.r.Revision
+}
+
+// Question resolves QuestionRevision.Question
+func (r *QuestionRevisionResolver) Question() string {
+	return r.r.Question
+}
+
+// CreatedAt resolves QuestionRevision.CreatedAt, which is null for questions
+// created before they were versioned
+func (r *QuestionRevisionResolver) CreatedAt() *Date {
+	if r.r.CreatedAt.IsZero() {
+		return nil
+	}
+	return &Date{r.r.CreatedAt}
+}
+
+// -----------------
+// QuestionRevisionAnswersResolver struct
+// -----------------
+
+// QuestionRevisionAnswersResolver resolves QuestionRevisionAnswers
+type QuestionRevisionAnswersResolver struct {
+	revision *models.QuestionRevision
+	answers  int32
+}
+
+// Revision resolves QuestionRevisionAnswers.Revision
+func (r *QuestionRevisionAnswersResolver) Revision() *QuestionRevisionResolver {
+	return &QuestionRevisionResolver{r.revision}
+}
+
+// Answers resolves QuestionRevisionAnswers.Answers
+func (r *QuestionRevisionAnswersResolver) Answers() int32 {
+	return r.answers
+}
@@ -0,0 +1,235 @@
+package resolvers
+
+import (
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	config "../config"
+	db "../database"
+	er "../errors"
+	models "../models"
+	graphql "github.com/graph-gophers/graphql-go"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// default and maximum number of recruits returned by a search
+const (
+	defaultSearchPageSize = 20
+	maxSearchPageSize     = 100
+)
+
+// recruitSortFields maps RecruitSort values onto recruit sort fields
+var recruitSortFields = map[string][]string{
+	"YOUNGEST": {"-birth_year", "_id"},
+	"OLDEST":   {"birth_year", "_id"},
+	"PROVINCE": {"province", "city", "_id"},
+	"CITY":     {"city", "_id"},
+}
+
+// noDisability matches disability values which indicate that a Recruit has no disability
+var noDisability = bson.RegEx{Pattern: `^\s*(none)?\s*$`, Options: "i"}
+
+// -----------------
+// HunterViewerResolver methods
+// -----------------
+
+// SearchRecruits resolves HunterViewer.SearchRecruits
+func (r *HunterViewerResolver) SearchRecruits(args recruitSearchArgs) (*RecruitSearchResultResolver, error) {
+	return searchRecruits(r.crud, args, r.a)
+}
+
+// -----------------
+// SysViewerResolver methods
+// -----------------
+
+// SearchRecruits resolves SysViewer.SearchRecruits
+func (r *SysViewerResolver) SearchRecruits(args recruitSearchArgs) (*RecruitSearchResultResolver, error) {
+	return searchRecruits(r.crud, args, r.a)
+}
+
+// searchRecruits finds a page of recruits matching the given filter
+func searchRecruits(crud *db.CRUD, args recruitSearchArgs, viewer *models.Account) (*RecruitSearchResultResolver, error) {
+	defer crud.CloseCopy()
+
+	// prepare query
+	query, err := args.Filter.query(crud)
+	if err != nil {
+		return nil, err
+	}
+
+	// prepare sorting
+	sortFields := []string{"_id"}
+	if args.Sort != nil {
+		sortFields = recruitSortFields[*args.Sort]
+	}
+
+	// prepare paging
+	limit := defaultSearchPageSize
+	if args.First != nil {
+		limit = int(*args.First)
+		if limit < 1 || limit > maxSearchPageSize {
+			return nil, er.InvalidField("first")
+		}
+	}
+	skip := 0
+	if args.Skip != nil {
+		skip = int(*args.Skip)
+		if skip < 0 {
+			return nil, er.InvalidField("skip")
+		}
+	}
+
+	// fetch recruits
+	rawRecruits, total, err := crud.FindPage(config.RecruitsCollection, query, sortFields, skip, limit)
+	if err != nil {
+		log.Println("Failed to search recruits =>", err)
+		return nil, er.Generic()
+	}
+
+	// process results
+	recruits := make([]*RecruitResolver, 0)
+	for _, raw := range rawRecruits {
+		recruit := models.TransformRecruit(raw)
+		resolver, err := resolveRecruit(crud, &recruit, viewer)
+		if err != nil {
+			return nil, err
+		}
+		recruits = append(recruits, resolver)
+	}
+	return &RecruitSearchResultResolver{int32(total), recruits}, nil
+}
+
+// -----------------
+// recruitSearchArgs struct
+// -----------------
+type recruitSearchArgs struct {
+	Filter *recruitFilter
+	Sort   *string
+	First  *int32
+	Skip   *int32
+}
+
+// -----------------
+// recruitFilter struct
+// -----------------
+type recruitFilter struct {
+	Province      *string
+	City          *string
+	Gender        *string
+	MinAge        *int32
+	MaxAge        *int32
+	HasDisability *bool
+	IndustryID    *graphql.ID
+	SkillIDs      *[]graphql.ID
+	Text          *string
+}
+
+// query builds the recruits query for the filter
+func (f *recruitFilter) query(crud *db.CRUD) (bson.M, error) {
+	query := bson.M{}
+	if f == nil {
+		return query, nil
+	}
+	and := make([]bson.M, 0)
+
+	if f.Province != nil {
+		query["province"] = *f.Province
+	}
+	if f.City != nil {
+		query["city"] = bson.RegEx{Pattern: "^" + regexp.QuoteMeta(strings.TrimSpace(*f.City)) + "$", Options: "i"}
+	}
+	// genders are stored upper cased, but recruits stored before that are lower cased
+	if f.Gender != nil {
+		query["gender"] = bson.RegEx{Pattern: "^" + regexp.QuoteMeta(*f.Gender) + "$", Options: "i"}
+	}
+
+	// ages are derived from birth years
+	if f.MinAge != nil || f.MaxAge != nil {
+		year := time.Now().Year()
+		birthYear := bson.M{}
+		if f.MinAge != nil {
+			birthYear["$lte"] = year - int(*f.MinAge)
+		}
+		if f.MaxAge != nil {
+			birthYear["$gte"] = year - int(*f.MaxAge)
+		}
+		query["birth_year"] = birthYear
+	}
+
+	if f.HasDisability != nil {
+		if *f.HasDisability {
+			and = append(and, bson.M{"$nor": []bson.M{{"disability": noDisability}}})
+		} else {
+			query["disability"] = noDisability
+		}
+	}
+
+	// recruits belong to the industries they prefer and those of the questions they answered,
+	// and through them to the industries those are sub-industries of
+	if f.IndustryID != nil {
+		id := string(*f.IndustryID)
+		if !bson.IsObjectIdHex(id) {
+			return nil, er.InvalidField("filter.industry_id")
+		}
+		industryIDs, err := industryDescendants(crud, bson.ObjectIdHex(id))
+		if err != nil {
+			return nil, err
+		}
+		rawQuestions, err := crud.FindAll(config.QuestionsCollection, bson.M{"industry_id": bson.M{"$in": industryIDs}})
+		if err != nil {
+			log.Println("Failed to find industry questions =>", err)
+			return nil, er.Generic()
+		}
+		questions := make([]bson.ObjectId, 0)
+		for _, raw := range rawQuestions {
+			questions = append(questions, models.TransformQuestion(raw).ID)
+		}
+		and = append(and, bson.M{"$or": []bson.M{
+			{"industry_ids": bson.M{"$in": industryIDs}},
+			{"answers.question_id": bson.M{"$in": questions}},
+		}})
+	}
+
+	// recruits need all of the skills
+	if f.SkillIDs != nil {
+		skillIDs, err := parseSkillIDs(*f.SkillIDs, "filter.skill_ids")
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range skillIDs {
+			and = append(and, bson.M{"skills.skill_id": id})
+		}
+	}
+
+	if f.Text != nil && strings.TrimSpace(*f.Text) != "" {
+		text := bson.RegEx{Pattern: regexp.QuoteMeta(strings.TrimSpace(*f.Text)), Options: "i"}
+		and = append(and, bson.M{"answers.answer": text})
+	}
+
+	if len(and) > 0 {
+		query["$and"] = and
+	}
+	return query, nil
+}
+
+// -----------------
+// RecruitSearchResultResolver struct
+// -----------------
+
+// RecruitSearchResultResolver resolves RecruitSearchResult
+type RecruitSearchResultResolver struct {
+	total    int32
+	recruits []*RecruitResolver
+}
+
+// Total resolves RecruitSearchResult.Total which is the number of matches across all pages
+func (r *RecruitSearchResultResolver) Total() int32 {
+	return r.total
+}
+
+// Recruits resolves RecruitSearchResult.Recruits
+func (r *RecruitSearchResultResolver) Recruits() []*RecruitResolver {
+	return r.recruits
+}
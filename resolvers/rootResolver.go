@@ -1,15 +1,32 @@
 package resolvers
 
 import (
+	"os"
+	"strconv"
+	"time"
+
+	credits "../credits"
 	db "../database"
+	mail "../mail"
+	notify "../notify"
+	search "../search"
+	storage "../storage"
+	utils "../utils"
 )
 
 // RootResolver contains functions that resolve graphql queries
 type RootResolver struct {
-	crud *db.CRUD
+	crud     *db.CRUD
+	index    *search.Index
+	notifier *notify.Dispatcher
+	ledger   *credits.Ledger
+	rand     *utils.Rand
+	store    storage.Store
 }
 
-// Init initialises the crud system
+// Init initialises the crud system, builds the search index,
+// prepares the notification dispatcher, the credit ledger and the file store and seeds
+// question selection from QUESTION_SEED, or the clock when it's not set
 func (r *RootResolver) Init(crud *db.CRUD) {
 	if crud == nil {
 		// create a mock CRUD instance if nil provided
@@ -17,4 +34,15 @@ func (r *RootResolver) Init(crud *db.CRUD) {
 	}
 
 	r.crud = crud
+	r.index = buildSearchIndex(crud)
+	outbox := mail.NewOutbox(crud, mail.NewSenderFromEnv())
+	r.notifier = notify.NewDispatcher(crud, mail.NewNotificationBackend(outbox))
+	r.ledger = credits.NewLedger(crud)
+	r.store = storage.NewStoreFromEnv()
+
+	seed := time.Now().UnixNano()
+	if s, err := strconv.ParseInt(os.Getenv("QUESTION_SEED"), 10, 64); err == nil {
+		seed = s
+	}
+	r.rand = utils.NewRand(seed)
 }
@@ -0,0 +1,54 @@
+package resolvers
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// dateLayout is the short form accepted for Date inputs
+const dateLayout = "2006-01-02"
+
+// -----------------
+// Date scalar
+// -----------------
+
+// Date resolves the gql Date scalar, it accepts either a short "2006-01-02" date
+// or an RFC3339 timestamp, and is always output as an RFC3339 timestamp
+type Date struct {
+	time.Time
+}
+
+// ImplementsGraphQLType maps Date to the gql Date scalar
+func (Date) ImplementsGraphQLType(name string) bool {
+	return name == "Date"
+}
+
+// UnmarshalGraphQL unmarshals a Date input
+func (d *Date) UnmarshalGraphQL(input interface{}) error {
+	switch v := input.(type) {
+	case time.Time:
+		d.Time = v
+		return nil
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			t, err = time.Parse(dateLayout, v)
+		}
+		d.Time = t
+		return err
+	case int32:
+		d.Time = time.Unix(int64(v), 0)
+		return nil
+	case float64:
+		d.Time = time.Unix(int64(v), 0)
+		return nil
+	default:
+		return fmt.Errorf("Invalid Date '%v'", input)
+	}
+}
+
+// MarshalJSON marshals a Date output
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Time.UTC().Format(time.RFC3339))
+}
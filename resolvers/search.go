@@ -0,0 +1,304 @@
+package resolvers
+
+import (
+	"log"
+	"strings"
+
+	config "../config"
+	db "../database"
+	er "../errors"
+	models "../models"
+	search "../search"
+	graphql "github.com/graph-gophers/graphql-go"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// search document kinds
+const (
+	searchKindRecruit  = "RECRUIT"
+	searchKindQuestion = "QUESTION"
+	searchKindDocument = "DOCUMENT"
+)
+
+// hunterSearchKinds are the kinds hunters can search. Documents are left out since
+// their files are served to anyone who knows their url
+var hunterSearchKinds = []string{searchKindRecruit, searchKindQuestion}
+
+// default and maximum number of search hits returned
+const (
+	defaultSearchHits = 20
+	maxSearchHits     = 100
+)
+
+// buildSearchIndex indexes all the recruits, questions and documents in the db.
+// The index lives in memory, so it is rebuilt from the db on start up and then
+// kept up to date by the mutations that change indexed data
+func buildSearchIndex(crud *db.CRUD) *search.Index {
+	defer crud.CloseCopy()
+	index := search.NewIndex()
+
+	// missing collections are expected for a fresh db, so errors are only logged
+	if rawRecruits, err := crud.FindAll(config.RecruitsCollection, nil); err == nil {
+		for _, raw := range rawRecruits {
+			index.Put(recruitSearchDoc(models.TransformRecruit(raw)))
+		}
+	} else {
+		log.Println("Failed to index recruits =>", err)
+	}
+
+	if rawQuestions, err := crud.FindAll(config.QuestionsCollection, nil); err == nil {
+		for _, raw := range rawQuestions {
+			index.Put(questionSearchDoc(models.TransformQuestion(raw)))
+		}
+	} else {
+		log.Println("Failed to index questions =>", err)
+	}
+
+	if rawDocuments, err := crud.FindAll(config.DocumentsCollection, nil); err == nil {
+		for _, raw := range rawDocuments {
+			index.Put(documentSearchDoc(models.TransformDocument(raw)))
+		}
+	} else {
+		log.Println("Failed to index documents =>", err)
+	}
+	return index
+}
+
+// recruitSearchDoc creates a search document from a Recruit's answers and history
+func recruitSearchDoc(recruit models.Recruit) search.Document {
+	doc := search.Document{
+		ID:     recruit.ID.Hex(),
+		Kind:   searchKindRecruit,
+		Fields: map[string]string{},
+	}
+
+	answers := make([]string, 0, len(recruit.Answers))
+	for _, qa := range recruit.Answers {
+		answers = append(answers, qa.Answer)
+	}
+	if len(answers) > 0 {
+		doc.Fields["answers"] = strings.Join(answers, "\n")
+	}
+
+	work := make([]string, 0, len(recruit.WorkExperience))
+	for _, w := range recruit.WorkExperience {
+		work = append(work, strings.Join([]string{w.Title, w.Employer, w.Description}, ". "))
+	}
+	if len(work) > 0 {
+		doc.Fields["work_experience"] = strings.Join(work, "\n")
+	}
+
+	education := make([]string, 0, len(recruit.Education))
+	for _, e := range recruit.Education {
+		education = append(education, e.Qualification+", "+e.Institution)
+	}
+	if len(education) > 0 {
+		doc.Fields["education"] = strings.Join(education, "\n")
+	}
+	return doc
+}
+
+// questionSearchDoc creates a search document from a Question's text
+func questionSearchDoc(question models.Question) search.Document {
+	doc := search.Document{
+		ID:   question.ID.Hex(),
+		Kind: searchKindQuestion,
+		Fields: map[string]string{
+			"question": question.Question,
+		},
+	}
+
+	// translations make questions findable in other languages too
+	if len(question.Translations) > 0 {
+		texts := make([]string, 0, len(question.Translations))
+		for _, t := range question.Translations {
+			texts = append(texts, t.Text)
+		}
+		doc.Fields["translations"] = strings.Join(texts, "\n")
+	}
+	return doc
+}
+
+// documentSearchDoc creates a search document from a Document's metadata
+func documentSearchDoc(document models.Document) search.Document {
+	return search.Document{
+		ID:   document.ID.Hex(),
+		Kind: searchKindDocument,
+		Fields: map[string]string{
+			"url":        document.URL,
+			"doc_type":   document.DocType,
+			"owner_type": document.OwnerType,
+		},
+	}
+}
+
+// -----------------
+// HunterViewerResolver methods
+// -----------------
+
+// Search resolves HunterViewer.Search
+func (r *HunterViewerResolver) Search(args searchArgs) ([]*SearchHitResolver, error) {
+	return runSearch(r.crud, r.index, args, r.a, hunterSearchKinds)
+}
+
+// -----------------
+// SysViewerResolver methods
+// -----------------
+
+// Search resolves SysViewer.Search
+func (r *SysViewerResolver) Search(args searchArgs) ([]*SearchHitResolver, error) {
+	return runSearch(r.crud, r.index, args, r.a, nil)
+}
+
+// runSearch searches the index for the viewer, returning the ranked hits. Only hits
+// of the allowed kinds are returned, unless allowed is nil
+func runSearch(crud *db.CRUD, index *search.Index, args searchArgs, viewer *models.Account, allowed []string) ([]*SearchHitResolver, error) {
+	limit := defaultSearchHits
+	if args.First != nil {
+		limit = int(*args.First)
+		if limit < 1 || limit > maxSearchHits {
+			return nil, er.InvalidField("first")
+		}
+	}
+	var kinds []string
+	if args.Kinds != nil {
+		kinds = *args.Kinds
+	}
+
+	results := make([]*SearchHitResolver, 0)
+	if allowed != nil {
+		kinds = allowedKinds(kinds, allowed)
+		if len(kinds) == 0 {
+			return results, nil
+		}
+	}
+	for _, hit := range index.Search(args.Query, kinds, limit) {
+		results = append(results, &SearchHitResolver{hit, crud, viewer})
+	}
+	return results, nil
+}
+
+// allowedKinds keeps the allowed ones of the requested kinds, requesting no kinds requests them all
+func allowedKinds(kinds, allowed []string) []string {
+	if len(kinds) == 0 {
+		return allowed
+	}
+	kept := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		for _, a := range allowed {
+			if kind == a {
+				kept = append(kept, kind)
+				break
+			}
+		}
+	}
+	return kept
+}
+
+// -----------------
+// searchArgs struct
+// -----------------
+type searchArgs struct {
+	Query string
+	Kinds *[]string
+	First *int32
+}
+
+// -----------------
+// SearchHitResolver struct
+// -----------------
+
+// SearchHitResolver resolves SearchHit
+type SearchHitResolver struct {
+	hit    search.Hit
+	crud   *db.CRUD
+	viewer *models.Account
+}
+
+// ID resolves SearchHit.ID which is the ID of the matched entity
+func (r *SearchHitResolver) ID() graphql.ID {
+	return graphql.ID(r.hit.ID)
+}
+
+// Kind resolves SearchHit.Kind
+func (r *SearchHitResolver) Kind() string {
+	return r.hit.Kind
+}
+
+// Score resolves SearchHit.Score
+func (r *SearchHitResolver) Score() float64 {
+	return r.hit.Score
+}
+
+// Highlights resolves SearchHit.Highlights
+func (r *SearchHitResolver) Highlights() []*HighlightResolver {
+	results := make([]*HighlightResolver, 0)
+	for i := range r.hit.Highlights {
+		results = append(results, &HighlightResolver{&r.hit.Highlights[i]})
+	}
+	return results
+}
+
+// Recruit resolves SearchHit.Recruit
+func (r *SearchHitResolver) Recruit() (*RecruitResolver, error) {
+	if r.hit.Kind != searchKindRecruit {
+		return nil, nil
+	}
+	defer r.crud.CloseCopy()
+
+	rawRecruit, err := r.crud.FindID(config.RecruitsCollection, bson.ObjectIdHex(r.hit.ID))
+	if err != nil {
+		return nil, nil
+	}
+	recruit := models.TransformRecruit(rawRecruit)
+	return resolveRecruit(r.crud, &recruit, r.viewer)
+}
+
+// Question resolves SearchHit.Question
+func (r *SearchHitResolver) Question() *QuestionResolver {
+	if r.hit.Kind != searchKindQuestion {
+		return nil
+	}
+	defer r.crud.CloseCopy()
+
+	rawQuestion, err := r.crud.FindID(config.QuestionsCollection, bson.ObjectIdHex(r.hit.ID))
+	if err != nil {
+		return nil
+	}
+	question := models.TransformQuestion(rawQuestion)
+	return &QuestionResolver{&question}
+}
+
+// Document resolves SearchHit.Document
+func (r *SearchHitResolver) Document() *DocumentResolver {
+	if r.hit.Kind != searchKindDocument {
+		return nil
+	}
+	defer r.crud.CloseCopy()
+
+	rawDocument, err := r.crud.FindID(config.DocumentsCollection, bson.ObjectIdHex(r.hit.ID))
+	if err != nil {
+		return nil
+	}
+	document := models.TransformDocument(rawDocument)
+	return &DocumentResolver{&document}
+}
+
+// -----------------
+// HighlightResolver struct
+// -----------------
+
+// HighlightResolver resolves Highlight
+type HighlightResolver struct {
+	h *search.Highlight
+}
+
+// Field resolves Highlight.Field
+func (r *HighlightResolver) Field() string {
+	return r.h.Field
+}
+
+// Fragment resolves Highlight.Fragment
+func (r *HighlightResolver) Fragment() string {
+	return r.h.Fragment
+}
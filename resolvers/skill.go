@@ -0,0 +1,491 @@
+package resolvers
+
+import (
+	"log"
+	"time"
+
+	config "../config"
+	db "../database"
+	er "../errors"
+	models "../models"
+	utils "../utils"
+	graphql "github.com/graph-gophers/graphql-go"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// -----------------
+// Root Resolver methods
+// -----------------
+
+// Skills resolves "skills" gql query which lists the skills, optionally of a single Industry
+func (r *RootResolver) Skills(args struct{ IndustryID *graphql.ID }) ([]*SkillResolver, error) {
+	defer r.crud.CloseCopy()
+
+	query := bson.M{}
+	if args.IndustryID != nil {
+		id := string(*args.IndustryID)
+		if !bson.IsObjectIdHex(id) {
+			return nil, er.InvalidField("industry_id")
+		}
+		query["industry_id"] = bson.ObjectIdHex(id)
+	}
+
+	rawSkills, err := r.crud.FindAll(config.SkillsCollection, query)
+	if err != nil {
+		log.Println("Failed to find skills =>", err)
+		return nil, er.Generic()
+	}
+	results := make([]*SkillResolver, 0)
+	for _, raw := range rawSkills {
+		skill := models.TransformSkill(raw)
+		results = append(results, &SkillResolver{&skill})
+	}
+	return results, nil
+}
+
+// -----------------
+// Editor methods
+// -----------------
+
+// CreateSkill resolves SysEditor.CreateSkill
+func (r *SysEditorResolver) CreateSkill(args struct {
+	IndustryID graphql.ID
+	Name       string
+}) (*SkillResolver, error) {
+	defer r.crud.CloseCopy()
+
+	// check the industry
+	id := string(args.IndustryID)
+	if !bson.IsObjectIdHex(id) {
+		return nil, er.InvalidField("industry_id")
+	}
+	if _, err := r.crud.FindID(config.IndustriesCollection, bson.ObjectIdHex(id)); err != nil {
+		return nil, er.Input("Industry not found.")
+	}
+
+	skill := models.Skill{ID: bson.NewObjectId(), IndustryID: bson.ObjectIdHex(id), Name: args.Name}
+	if err := skill.OK(); err != nil {
+		return nil, err
+	}
+	if skillExists(r.crud, &skill) {
+		return nil, er.Input("Skill already exists.")
+	}
+
+	if err := r.crud.Insert(config.SkillsCollection, skill); err != nil {
+		log.Println("Failed to create skill =>", err)
+		return nil, er.Generic()
+	}
+	return &SkillResolver{&skill}, nil
+}
+
+// UpdateSkill resolves SysEditor.UpdateSkill
+func (r *SysEditorResolver) UpdateSkill(args struct {
+	ID   graphql.ID
+	Name string
+}) (*SkillResolver, error) {
+	defer r.crud.CloseCopy()
+
+	skill, err := findSkill(r.crud, args.ID, "id")
+	if err != nil {
+		return nil, err
+	}
+	skill.Name = args.Name
+	if err := skill.OK(); err != nil {
+		return nil, err
+	}
+	if skillExists(r.crud, skill) {
+		return nil, er.Input("Skill already exists.")
+	}
+
+	if err := r.crud.UpdateID(config.SkillsCollection, skill.ID, bson.M{"name": skill.Name}); err != nil {
+		log.Println("Failed to update skill =>", err)
+		return nil, er.Generic()
+	}
+	return &SkillResolver{skill}, nil
+}
+
+// RemoveSkill resolves SysEditor.RemoveSkill which removes the skill from the
+// taxonomy and from the recruits who had it, along with its endorsements
+func (r *SysEditorResolver) RemoveSkill(args struct{ ID graphql.ID }) (*string, error) {
+	defer r.crud.CloseCopy()
+
+	skill, err := findSkill(r.crud, args.ID, "id")
+	if err != nil {
+		return nil, err
+	}
+	if err := removeSkill(r.crud, skill.ID); err != nil {
+		return nil, err
+	}
+
+	result := "Skill successfully removed."
+	return &result, nil
+}
+
+// SetSkill resolves RecruitEditor.SetSkill which adds a skill to the Recruit's
+// profile or changes the level they rate themselves at
+func (r *RecruitEditorResolver) SetSkill(args struct {
+	SkillID graphql.ID
+	Level   int32
+}) (*RecruitSkillResolver, error) {
+	defer r.crud.CloseCopy()
+
+	skill, err := findSkill(r.crud, args.SkillID, "skill_id")
+	if err != nil {
+		return nil, err
+	}
+	recruitSkill := models.RecruitSkill{SkillID: skill.ID, Level: args.Level}
+	if err := recruitSkill.OK(); err != nil {
+		return nil, err
+	}
+
+	skills := withoutSkill(r.r.Skills, skill.ID)
+	if len(skills) == len(r.r.Skills) && len(skills) >= models.MaxRecruitSkills {
+		return nil, er.Input("Too many skills.")
+	}
+	skills = append(skills, recruitSkill)
+	if err := r.crud.UpdateID(config.RecruitsCollection, r.r.ID, bson.M{"skills": skills}); err != nil {
+		log.Println("Failed to set recruit skill =>", err)
+		return nil, er.Generic()
+	}
+	r.r.Skills = skills
+
+	endorsements, err := countEndorsements(r.crud, r.r.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &RecruitSkillResolver{&recruitSkill, skill, endorsements[skill.ID]}, nil
+}
+
+// RemoveSkill resolves RecruitEditor.RemoveSkill which removes a skill, and its
+// endorsements, from the Recruit's profile
+func (r *RecruitEditorResolver) RemoveSkill(args struct{ SkillID graphql.ID }) (*string, error) {
+	defer r.crud.CloseCopy()
+
+	id := string(args.SkillID)
+	if !bson.IsObjectIdHex(id) {
+		return nil, er.InvalidField("skill_id")
+	}
+	skills := withoutSkill(r.r.Skills, bson.ObjectIdHex(id))
+	if len(skills) == len(r.r.Skills) {
+		return nil, er.Input("Skill not found.")
+	}
+
+	if err := r.crud.UpdateID(config.RecruitsCollection, r.r.ID, bson.M{"skills": skills}); err != nil {
+		log.Println("Failed to remove recruit skill =>", err)
+		return nil, er.Generic()
+	}
+	r.r.Skills = skills
+	if err := removeEndorsements(r.crud, bson.M{"recruit_id": r.r.ID, "skill_id": bson.ObjectIdHex(id)}); err != nil {
+		return nil, err
+	}
+
+	result := "Skill successfully removed."
+	return &result, nil
+}
+
+// EndorseSkill resolves HunterEditor.EndorseSkill which lets a Hunter who has
+// interviewed a Recruit vouch for one of their skills
+func (r *HunterEditorResolver) EndorseSkill(args struct {
+	RecruitID graphql.ID
+	SkillID   graphql.ID
+}) (*EndorsementResolver, error) {
+	defer r.crud.CloseCopy()
+
+	if utils.IsNullID(r.h.CompanyID) {
+		return nil, er.Input("Hunter does not belong to a Company.")
+	}
+
+	// check the ids
+	recruitID := string(args.RecruitID)
+	if !bson.IsObjectIdHex(recruitID) {
+		return nil, er.InvalidField("recruit_id")
+	}
+	skillID := string(args.SkillID)
+	if !bson.IsObjectIdHex(skillID) {
+		return nil, er.InvalidField("skill_id")
+	}
+
+	// the recruit must claim the skill
+	rawRecruit, err := r.crud.FindID(config.RecruitsCollection, bson.ObjectIdHex(recruitID))
+	if err != nil {
+		return nil, er.Input("Recruit not found.")
+	}
+	recruit := models.TransformRecruit(rawRecruit)
+	if !recruitHasSkills(&recruit, []bson.ObjectId{bson.ObjectIdHex(skillID)}) {
+		return nil, er.Input("Recruit does not have the skill.")
+	}
+
+	// and the hunter must have interviewed them
+	if _, err := r.crud.FindOne(config.InterviewsCollection, bson.M{
+		"recruit_id": recruit.ID,
+		"hunter_id":  r.h.ID,
+		"status":     models.InterviewScheduled,
+		"start":      bson.M{"$lte": time.Now()},
+	}); err != nil {
+		return nil, er.Input("Only hunters who interviewed the Recruit can endorse their skills.")
+	}
+
+	query := bson.M{"recruit_id": recruit.ID, "skill_id": bson.ObjectIdHex(skillID), "hunter_id": r.h.ID}
+	if _, err := r.crud.FindOne(config.EndorsementsCollection, query); err == nil {
+		return nil, er.Input("Skill already endorsed.")
+	}
+
+	endorsement := models.Endorsement{
+		ID:        bson.NewObjectId(),
+		RecruitID: recruit.ID,
+		SkillID:   bson.ObjectIdHex(skillID),
+		HunterID:  r.h.ID,
+		CompanyID: r.h.CompanyID,
+		CreatedAt: time.Now(),
+	}
+	if err := r.crud.Insert(config.EndorsementsCollection, endorsement); err != nil {
+		log.Println("Failed to endorse skill =>", err)
+		return nil, er.Generic()
+	}
+	return &EndorsementResolver{&endorsement}, nil
+}
+
+// -----------------
+// helpers
+// -----------------
+
+// findSkill retrieves the Skill with the given id
+func findSkill(crud *db.CRUD, id graphql.ID, field string) (*models.Skill, error) {
+	if !bson.IsObjectIdHex(string(id)) {
+		return nil, er.InvalidField(field)
+	}
+	rawSkill, err := crud.FindID(config.SkillsCollection, bson.ObjectIdHex(string(id)))
+	if err != nil {
+		return nil, er.Input("Skill not found.")
+	}
+	skill := models.TransformSkill(rawSkill)
+	return &skill, nil
+}
+
+// removeSkill removes a skill from the taxonomy, the recruits who have it and their endorsements
+func removeSkill(crud *db.CRUD, skillID bson.ObjectId) error {
+	rawRecruits, err := crud.FindAll(config.RecruitsCollection, bson.M{"skills.skill_id": skillID})
+	if err != nil {
+		log.Println("Failed to find recruits with skill =>", err)
+		return er.Generic()
+	}
+	for _, raw := range rawRecruits {
+		recruit := models.TransformRecruit(raw)
+		skills := withoutSkill(recruit.Skills, skillID)
+		if err := crud.UpdateID(config.RecruitsCollection, recruit.ID, bson.M{"skills": skills}); err != nil {
+			log.Println("Failed to remove skill from recruit =>", err)
+			return er.Generic()
+		}
+	}
+	if err := removeEndorsements(crud, bson.M{"skill_id": skillID}); err != nil {
+		return err
+	}
+	if err := crud.DeleteID(config.SkillsCollection, skillID); err != nil {
+		log.Println("Failed to remove skill =>", err)
+		return er.Generic()
+	}
+	return nil
+}
+
+// skillExists checks if another skill in the same Industry has the same name
+func skillExists(crud *db.CRUD, skill *models.Skill) bool {
+	_, err := crud.FindOne(config.SkillsCollection, bson.M{
+		"_id":         bson.M{"$ne": skill.ID},
+		"industry_id": skill.IndustryID,
+		"name":        skill.Name,
+	})
+	return err == nil
+}
+
+// parseSkillIDs converts gql ids into skill ids
+func parseSkillIDs(ids []graphql.ID, field string) ([]bson.ObjectId, error) {
+	skillIDs := make([]bson.ObjectId, 0, len(ids))
+	for _, id := range ids {
+		if !bson.IsObjectIdHex(string(id)) {
+			return nil, er.InvalidField(field)
+		}
+		skillIDs = append(skillIDs, bson.ObjectIdHex(string(id)))
+	}
+	return skillIDs, nil
+}
+
+// recruitHasSkills checks if a Recruit has all of the given skills
+func recruitHasSkills(recruit *models.Recruit, skillIDs []bson.ObjectId) bool {
+	for _, id := range skillIDs {
+		if len(withoutSkill(recruit.Skills, id)) == len(recruit.Skills) {
+			return false
+		}
+	}
+	return true
+}
+
+// withoutSkill returns a copy of a Recruit's skills without the given skill
+func withoutSkill(skills []models.RecruitSkill, skillID bson.ObjectId) []models.RecruitSkill {
+	results := make([]models.RecruitSkill, 0, len(skills))
+	for _, s := range skills {
+		if s.SkillID != skillID {
+			results = append(results, s)
+		}
+	}
+	return results
+}
+
+// countEndorsements counts the endorsements of each of a Recruit's skills
+func countEndorsements(crud *db.CRUD, recruitID bson.ObjectId) (map[bson.ObjectId]int32, error) {
+	rawEndorsements, err := crud.FindAll(config.EndorsementsCollection, bson.M{"recruit_id": recruitID})
+	if err != nil {
+		log.Println("Failed to find endorsements =>", err)
+		return nil, er.Generic()
+	}
+	counts := make(map[bson.ObjectId]int32)
+	for _, raw := range rawEndorsements {
+		counts[models.TransformEndorsement(raw).SkillID]++
+	}
+	return counts, nil
+}
+
+// removeEndorsements deletes the endorsements matching the query
+func removeEndorsements(crud *db.CRUD, query bson.M) error {
+	rawEndorsements, err := crud.FindAll(config.EndorsementsCollection, query)
+	if err != nil {
+		log.Println("Failed to find endorsements =>", err)
+		return er.Generic()
+	}
+	for _, raw := range rawEndorsements {
+		if err := crud.DeleteID(config.EndorsementsCollection, models.TransformEndorsement(raw).ID); err != nil {
+			log.Println("Failed to remove endorsement =>", err)
+			return er.Generic()
+		}
+	}
+	return nil
+}
+
+// -----------------
+// Recruit methods
+// -----------------
+
+// Skills resolves Recruit.Skills
+func (r *RecruitResolver) Skills() ([]*RecruitSkillResolver, error) {
+	defer r.crud.CloseCopy()
+
+	results := make([]*RecruitSkillResolver, 0)
+	if len(r.r.Skills) == 0 {
+		return results, nil
+	}
+
+	ids := make([]bson.ObjectId, 0, len(r.r.Skills))
+	for _, s := range r.r.Skills {
+		ids = append(ids, s.SkillID)
+	}
+	rawSkills, err := r.crud.FindAll(config.SkillsCollection, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		log.Println("Failed to find recruit skills =>", err)
+		return nil, er.Generic()
+	}
+	skills := make(map[bson.ObjectId]*models.Skill)
+	for _, raw := range rawSkills {
+		skill := models.TransformSkill(raw)
+		skills[skill.ID] = &skill
+	}
+	endorsements, err := countEndorsements(r.crud, r.r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range r.r.Skills {
+		recruitSkill := &r.r.Skills[i]
+		if skill, ok := skills[recruitSkill.SkillID]; ok {
+			results = append(results, &RecruitSkillResolver{recruitSkill, skill, endorsements[skill.ID]})
+		}
+	}
+	return results, nil
+}
+
+// -----------------
+// SkillResolver struct
+// -----------------
+
+// SkillResolver resolves Skill
+type SkillResolver struct {
+	s *models.Skill
+}
+
+// ID resolves Skill.ID
+func (r *SkillResolver) ID() graphql.ID {
+	return graphql.ID(r.s.ID.Hex())
+}
+
+// IndustryID resolves Skill.IndustryID
+func (r *SkillResolver) IndustryID() graphql.ID {
+	return graphql.ID(r.s.IndustryID.Hex())
+}
+
+// Name resolves Skill.Name
+func (r *SkillResolver) Name() string {
+	return r.s.Name
+}
+
+// -----------------
+// RecruitSkillResolver struct
+// -----------------
+
+// RecruitSkillResolver resolves RecruitSkill
+type RecruitSkillResolver struct {
+	rs           *models.RecruitSkill
+	skill        *models.Skill
+	endorsements int32
+}
+
+// Skill resolves RecruitSkill.Skill
+func (r *RecruitSkillResolver) Skill() *SkillResolver {
+	return &SkillResolver{r.skill}
+}
+
+// Level resolves RecruitSkill.Level
+func (r *RecruitSkillResolver) Level() int32 {
+	return r.rs.Level
+}
+
+// Endorsements resolves RecruitSkill.Endorsements which is the number of hunters who endorsed the skill
+func (r *RecruitSkillResolver) Endorsements() int32 {
+	return r.endorsements
+}
+
+// -----------------
+// EndorsementResolver struct
+// -----------------
+
+// EndorsementResolver resolves Endorsement
+type EndorsementResolver struct {
+	e *models.Endorsement
+}
+
+// ID resolves Endorsement.ID
+func (r *EndorsementResolver) ID() graphql.ID {
+	return graphql.ID(r.e.ID.Hex())
+}
+
+// RecruitID resolves Endorsement.RecruitID
+func (r *EndorsementResolver) RecruitID() graphql.ID {
+	return graphql.ID(r.e.RecruitID.Hex())
+}
+
+// SkillID resolves Endorsement.SkillID
+func (r *EndorsementResolver) SkillID() graphql.ID {
+	return graphql.ID(r.e.SkillID.Hex())
+}
+
+// HunterID resolves Endorsement.HunterID
+func (r *EndorsementResolver) HunterID() graphql.ID {
+	return graphql.ID(r.e.HunterID.Hex())
+}
+
+// CompanyID resolves Endorsement.CompanyID
+func (r *EndorsementResolver) CompanyID() graphql.ID {
+	return graphql.ID(r.e.CompanyID.Hex())
+}
+
+// CreatedAt resolves Endorsement.CreatedAt
+func (r *EndorsementResolver) CreatedAt() Date {
+	return Date{r.e.CreatedAt}
+}
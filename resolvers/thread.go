@@ -0,0 +1,631 @@
+package resolvers
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	config "../config"
+	db "../database"
+	er "../errors"
+	models "../models"
+	notify "../notify"
+	utils "../utils"
+	graphql "github.com/graph-gophers/graphql-go"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// default and maximum number of messages returned for a thread
+const (
+	defaultMessagePageSize = 50
+	maxMessagePageSize     = 200
+)
+
+// -----------------
+// participant struct
+// -----------------
+
+// participant is the account taking part in threads, either as a Recruit
+// or as a member of a Company
+type participant struct {
+	account   *models.Account
+	role      string
+	recruitID bson.ObjectId
+	companyID bson.ObjectId
+}
+
+// recruitParticipant makes a participant out of a Recruit's account
+func recruitParticipant(account *models.Account, recruit *models.Recruit) *participant {
+	return &participant{account, models.ParticipantRecruit, recruit.ID, models.NullObjectID}
+}
+
+// hunterParticipant makes a participant out of a Hunter's account
+func hunterParticipant(account *models.Account, hunter *models.Hunter) *participant {
+	return &participant{account, models.ParticipantHunter, models.NullObjectID, hunter.CompanyID}
+}
+
+// canAccess checks whether the participant takes part in the Thread
+func (p *participant) canAccess(thread *models.Thread) bool {
+	if p.role == models.ParticipantRecruit {
+		return thread.RecruitID == p.recruitID
+	}
+	return !utils.IsNullID(p.companyID) && thread.CompanyID == p.companyID
+}
+
+// query returns the query matching all of the participant's threads
+func (p *participant) query() bson.M {
+	if p.role == models.ParticipantRecruit {
+		return bson.M{"recruit_id": p.recruitID}
+	}
+	return bson.M{"company_id": p.companyID}
+}
+
+// thread retrieves one of the participant's threads
+func (p *participant) thread(crud *db.CRUD, threadID graphql.ID) (*models.Thread, error) {
+	id := string(threadID)
+	if !bson.IsObjectIdHex(id) {
+		return nil, er.InvalidField("id")
+	}
+
+	rawThread, err := crud.FindID(config.ThreadsCollection, bson.ObjectIdHex(id))
+	if err != nil {
+		return nil, er.InvalidField("id")
+	}
+	thread := models.TransformThread(rawThread)
+	if !p.canAccess(&thread) {
+		return nil, er.InvalidField("id")
+	}
+	return &thread, nil
+}
+
+// threads retrieves all of the participant's threads, most recently active first
+func (p *participant) threads(crud *db.CRUD) ([]*ThreadResolver, error) {
+	results := make([]*ThreadResolver, 0)
+	if p.role == models.ParticipantHunter && utils.IsNullID(p.companyID) {
+		return results, nil
+	}
+
+	rawThreads, _, err := crud.FindPage(config.ThreadsCollection, p.query(), []string{"-updated_at", "_id"}, 0, 0)
+	if err != nil {
+		log.Println(err)
+		return nil, er.Generic()
+	}
+	for _, raw := range rawThreads {
+		thread := models.TransformThread(raw)
+		results = append(results, &ThreadResolver{&thread, p, crud})
+	}
+	return results, nil
+}
+
+// unread counts the messages in the Thread that the participant hasn't read
+func (p *participant) unread(crud *db.CRUD, thread *models.Thread) (int, error) {
+	_, total, err := crud.FindPage(config.MessagesCollection, bson.M{
+		"thread_id":  thread.ID,
+		"sender_id":  bson.M{"$ne": p.account.ID},
+		"created_at": bson.M{"$gt": thread.ReadAt(p.account.ID)},
+	}, nil, 0, 1)
+	return total, err
+}
+
+// startThread starts a Thread about a vacancy, or the application to it, and sends its
+// first message. If the Recruit and the Company already have a Thread about
+// the vacancy, the message is sent to that Thread instead
+func (p *participant) startThread(crud *db.CRUD, notifier *notify.Dispatcher, args startThreadArgs) (*ThreadResolver, error) {
+	thread := models.Thread{ApplicationID: models.NullObjectID}
+
+	switch {
+	case args.ApplicationID != nil:
+		// retrieve the application
+		id := string(*args.ApplicationID)
+		if !bson.IsObjectIdHex(id) {
+			return nil, er.InvalidField("application_id")
+		}
+		rawApplication, err := crud.FindID(config.ApplicationsCollection, bson.ObjectIdHex(id))
+		if err != nil {
+			return nil, er.InvalidField("application_id")
+		}
+		application := models.TransformApplication(rawApplication)
+		thread.ApplicationID = application.ID
+		thread.VacancyID = application.VacancyID
+		thread.CompanyID = application.CompanyID
+		thread.RecruitID = application.RecruitID
+
+	case args.VacancyID != nil:
+		// retrieve the vacancy
+		id := string(*args.VacancyID)
+		if !bson.IsObjectIdHex(id) {
+			return nil, er.InvalidField("vacancy_id")
+		}
+		rawVacancy, err := crud.FindID(config.VacanciesCollection, bson.ObjectIdHex(id))
+		if err != nil {
+			return nil, er.InvalidField("vacancy_id")
+		}
+		vacancy := models.TransformVacancy(rawVacancy)
+		thread.VacancyID = vacancy.ID
+		thread.CompanyID = vacancy.CompanyID
+
+		// recruits can only ask about published vacancies
+		if p.role == models.ParticipantRecruit {
+			if !vacancy.IsOpen() {
+				return nil, er.InvalidField("vacancy_id")
+			}
+			thread.RecruitID = p.recruitID
+			break
+		}
+
+		// hunters need to say which recruit they're contacting
+		if args.RecruitID == nil || !bson.IsObjectIdHex(string(*args.RecruitID)) {
+			return nil, er.InvalidField("recruit_id")
+		}
+		rawRecruit, err := crud.FindID(config.RecruitsCollection, bson.ObjectIdHex(string(*args.RecruitID)))
+		if err != nil {
+			return nil, er.InvalidField("recruit_id")
+		}
+		thread.RecruitID = models.TransformRecruit(rawRecruit).ID
+
+	default:
+		return nil, er.MissingField("vacancy_id")
+	}
+
+	// only the recruit and the vacancy's company can start the thread
+	if !p.canAccess(&thread) {
+		return nil, er.InvalidField("id")
+	}
+
+	// reuse an existing thread
+	rawThread, err := crud.FindOne(config.ThreadsCollection, bson.M{
+		"vacancy_id": thread.VacancyID,
+		"recruit_id": thread.RecruitID,
+	})
+	if err == nil {
+		existing := models.TransformThread(rawThread)
+		if utils.IsNullID(existing.ApplicationID) && !utils.IsNullID(thread.ApplicationID) {
+			existing.ApplicationID = thread.ApplicationID
+			if err := crud.UpdateID(config.ThreadsCollection, existing.ID, bson.M{"application_id": existing.ApplicationID}); err != nil {
+				log.Println("Failed to update thread =>", err)
+				return nil, er.Generic()
+			}
+		}
+		if _, err := p.send(crud, notifier, &existing, args.Message); err != nil {
+			return nil, err
+		}
+		return &ThreadResolver{&existing, p, crud}, nil
+	}
+
+	// create the thread
+	now := time.Now()
+	thread.ID = bson.NewObjectId()
+	thread.Subject = strings.TrimSpace(args.Subject)
+	thread.Reads = make([]models.ThreadRead, 0)
+	thread.CreatedAt = now
+	thread.UpdatedAt = now
+	if err := thread.OK(); err != nil {
+		return nil, err
+	}
+
+	// validate the first message before storing anything
+	message := p.message(&thread, args.Message)
+	if err := message.OK(); err != nil {
+		return nil, err
+	}
+
+	// store thread in db
+	if err := crud.Insert(config.ThreadsCollection, thread); err != nil {
+		log.Println("Failed to create thread =>", err)
+		return nil, er.Generic()
+	}
+	if _, err := p.send(crud, notifier, &thread, args.Message); err != nil {
+		return nil, err
+	}
+
+	return &ThreadResolver{&thread, p, crud}, nil
+}
+
+// message creates a Message from the participant to the Thread
+func (p *participant) message(thread *models.Thread, body string) models.Message {
+	return models.Message{
+		ID:         bson.NewObjectId(),
+		ThreadID:   thread.ID,
+		SenderID:   p.account.ID,
+		SenderRole: p.role,
+		Body:       strings.TrimSpace(body),
+		CreatedAt:  time.Now(),
+	}
+}
+
+// send sends a Message to the Thread, which also marks the Thread as read by the sender
+func (p *participant) send(crud *db.CRUD, notifier *notify.Dispatcher, thread *models.Thread, body string) (*models.Message, error) {
+	message := p.message(thread, body)
+	if err := message.OK(); err != nil {
+		return nil, err
+	}
+
+	// store message in db
+	if err := crud.Insert(config.MessagesCollection, message); err != nil {
+		log.Println("Failed to send message =>", err)
+		return nil, er.Generic()
+	}
+
+	// bump thread
+	thread.UpdatedAt = message.CreatedAt
+	thread.MarkRead(p.account.ID, message.CreatedAt)
+	if err := crud.UpdateID(config.ThreadsCollection, thread.ID, bson.M{
+		"updated_at": thread.UpdatedAt,
+		"reads":      thread.Reads,
+	}); err != nil {
+		log.Println("Failed to update thread =>", err)
+		return nil, er.Generic()
+	}
+	notifyNewMessage(crud, notifier, thread, &message)
+
+	return &message, nil
+}
+
+// markRead marks the Thread as read by the participant
+func (p *participant) markRead(crud *db.CRUD, thread *models.Thread) error {
+	thread.MarkRead(p.account.ID, time.Now())
+	if err := crud.UpdateID(config.ThreadsCollection, thread.ID, bson.M{"reads": thread.Reads}); err != nil {
+		log.Println("Failed to update thread =>", err)
+		return er.Generic()
+	}
+	return nil
+}
+
+// totalUnread counts the unread messages in all of the participant's threads
+func (p *participant) totalUnread(crud *db.CRUD) (int32, error) {
+	threads, err := p.threads(crud)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, thread := range threads {
+		unread, err := p.unread(crud, thread.t)
+		if err != nil {
+			log.Println(err)
+			return 0, er.Generic()
+		}
+		total += unread
+	}
+	return int32(total), nil
+}
+
+// -----------------
+// RecruitEditorResolver methods
+// -----------------
+
+// StartThread resolves RecruitEditor.StartThread
+func (r *RecruitEditorResolver) StartThread(args startThreadArgs) (*ThreadResolver, error) {
+	defer r.crud.CloseCopy()
+	return recruitParticipant(r.a, r.r).startThread(r.crud, r.notifier, args)
+}
+
+// SendMessage resolves RecruitEditor.SendMessage
+func (r *RecruitEditorResolver) SendMessage(args sendMessageArgs) (*MessageResolver, error) {
+	defer r.crud.CloseCopy()
+	return sendMessage(r.crud, r.notifier, recruitParticipant(r.a, r.r), args)
+}
+
+// MarkThreadRead resolves RecruitEditor.MarkThreadRead
+func (r *RecruitEditorResolver) MarkThreadRead(args struct{ ID graphql.ID }) (*ThreadResolver, error) {
+	defer r.crud.CloseCopy()
+	return markThreadRead(r.crud, recruitParticipant(r.a, r.r), args.ID)
+}
+
+// -----------------
+// HunterEditorResolver methods
+// -----------------
+
+// StartThread resolves HunterEditor.StartThread
+func (r *HunterEditorResolver) StartThread(args startThreadArgs) (*ThreadResolver, error) {
+	defer r.crud.CloseCopy()
+	if _, err := r.company(); err != nil {
+		return nil, err
+	}
+	return hunterParticipant(r.a, r.h).startThread(r.crud, r.notifier, args)
+}
+
+// SendMessage resolves HunterEditor.SendMessage
+func (r *HunterEditorResolver) SendMessage(args sendMessageArgs) (*MessageResolver, error) {
+	defer r.crud.CloseCopy()
+	return sendMessage(r.crud, r.notifier, hunterParticipant(r.a, r.h), args)
+}
+
+// MarkThreadRead resolves HunterEditor.MarkThreadRead
+func (r *HunterEditorResolver) MarkThreadRead(args struct{ ID graphql.ID }) (*ThreadResolver, error) {
+	defer r.crud.CloseCopy()
+	return markThreadRead(r.crud, hunterParticipant(r.a, r.h), args.ID)
+}
+
+// sendMessage sends a message to one of the participant's threads
+func sendMessage(crud *db.CRUD, notifier *notify.Dispatcher, p *participant, args sendMessageArgs) (*MessageResolver, error) {
+	thread, err := p.thread(crud, args.ThreadID)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := p.send(crud, notifier, thread, args.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &MessageResolver{message, p, crud}, nil
+}
+
+// markThreadRead marks one of the participant's threads as read
+func markThreadRead(crud *db.CRUD, p *participant, threadID graphql.ID) (*ThreadResolver, error) {
+	thread, err := p.thread(crud, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.markRead(crud, thread); err != nil {
+		return nil, err
+	}
+	return &ThreadResolver{thread, p, crud}, nil
+}
+
+// -----------------
+// RecruitViewerResolver methods
+// -----------------
+
+// Threads resolves RecruitViewer.Threads
+func (r *RecruitViewerResolver) Threads() ([]*ThreadResolver, error) {
+	defer r.crud.CloseCopy()
+	return recruitParticipant(r.a, r.r).threads(r.crud)
+}
+
+// Thread resolves RecruitViewer.Thread
+func (r *RecruitViewerResolver) Thread(args struct{ ID graphql.ID }) (*ThreadResolver, error) {
+	defer r.crud.CloseCopy()
+
+	p := recruitParticipant(r.a, r.r)
+	thread, err := p.thread(r.crud, args.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &ThreadResolver{thread, p, r.crud}, nil
+}
+
+// UnreadMessages resolves RecruitViewer.UnreadMessages
+func (r *RecruitViewerResolver) UnreadMessages() (int32, error) {
+	defer r.crud.CloseCopy()
+	return recruitParticipant(r.a, r.r).totalUnread(r.crud)
+}
+
+// -----------------
+// HunterViewerResolver methods
+// -----------------
+
+// Threads resolves HunterViewer.Threads
+func (r *HunterViewerResolver) Threads() ([]*ThreadResolver, error) {
+	defer r.crud.CloseCopy()
+	return hunterParticipant(r.a, r.h).threads(r.crud)
+}
+
+// Thread resolves HunterViewer.Thread
+func (r *HunterViewerResolver) Thread(args struct{ ID graphql.ID }) (*ThreadResolver, error) {
+	defer r.crud.CloseCopy()
+
+	p := hunterParticipant(r.a, r.h)
+	thread, err := p.thread(r.crud, args.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &ThreadResolver{thread, p, r.crud}, nil
+}
+
+// UnreadMessages resolves HunterViewer.UnreadMessages
+func (r *HunterViewerResolver) UnreadMessages() (int32, error) {
+	defer r.crud.CloseCopy()
+	return hunterParticipant(r.a, r.h).totalUnread(r.crud)
+}
+
+// -----------------
+// startThreadArgs struct
+// -----------------
+type startThreadArgs struct {
+	ApplicationID *graphql.ID
+	VacancyID     *graphql.ID
+	RecruitID     *graphql.ID
+	Subject       string
+	Message       string
+}
+
+// -----------------
+// sendMessageArgs struct
+// -----------------
+type sendMessageArgs struct {
+	ThreadID graphql.ID
+	Body     string
+}
+
+// -----------------
+// ThreadResolver struct
+// -----------------
+
+// ThreadResolver resolves Thread as seen by one of its participants
+type ThreadResolver struct {
+	t    *models.Thread
+	p    *participant
+	crud *db.CRUD
+}
+
+// ID resolves Thread.ID
+func (r *ThreadResolver) ID() graphql.ID {
+	return graphql.ID(r.t.ID.Hex())
+}
+
+// Subject resolves Thread.Subject
+func (r *ThreadResolver) Subject() string {
+	return r.t.Subject
+}
+
+// Application resolves Thread.Application
+func (r *ThreadResolver) Application() (*ApplicationResolver, error) {
+	defer r.crud.CloseCopy()
+
+	if utils.IsNullID(r.t.ApplicationID) {
+		return nil, nil
+	}
+	rawApplication, err := r.crud.FindID(config.ApplicationsCollection, r.t.ApplicationID)
+	if err != nil {
+		return nil, nil
+	}
+	application := models.TransformApplication(rawApplication)
+	return &ApplicationResolver{&application, r.crud, r.p.account}, nil
+}
+
+// Vacancy resolves Thread.Vacancy
+func (r *ThreadResolver) Vacancy() (*VacancyResolver, error) {
+	defer r.crud.CloseCopy()
+
+	rawVacancy, err := r.crud.FindID(config.VacanciesCollection, r.t.VacancyID)
+	if err != nil {
+		return nil, nil
+	}
+	vacancy := models.TransformVacancy(rawVacancy)
+	return &VacancyResolver{&vacancy, r.crud}, nil
+}
+
+// Company resolves Thread.Company
+func (r *ThreadResolver) Company() (*CompanyResolver, error) {
+	defer r.crud.CloseCopy()
+
+	rawCompany, err := r.crud.FindID(config.CompaniesCollection, r.t.CompanyID)
+	if err != nil {
+		return nil, nil
+	}
+	company := models.TransformCompany(rawCompany)
+	return &CompanyResolver{&company, r.crud}, nil
+}
+
+// Recruit resolves Thread.Recruit
+func (r *ThreadResolver) Recruit() (*RecruitResolver, error) {
+	defer r.crud.CloseCopy()
+
+	rawRecruit, err := r.crud.FindID(config.RecruitsCollection, r.t.RecruitID)
+	if err != nil {
+		return nil, nil
+	}
+	recruit := models.TransformRecruit(rawRecruit)
+	return resolveRecruit(r.crud, &recruit, r.p.account)
+}
+
+// Messages resolves Thread.Messages, oldest first
+func (r *ThreadResolver) Messages(args struct {
+	First *int32
+	Skip  *int32
+}) ([]*MessageResolver, error) {
+	defer r.crud.CloseCopy()
+
+	// prepare paging
+	limit := defaultMessagePageSize
+	if args.First != nil {
+		limit = int(*args.First)
+	}
+	if limit <= 0 || limit > maxMessagePageSize {
+		return nil, er.InvalidField("first")
+	}
+	skip := 0
+	if args.Skip != nil {
+		skip = int(*args.Skip)
+	}
+	if skip < 0 {
+		return nil, er.InvalidField("skip")
+	}
+
+	rawMessages, _, err := r.crud.FindPage(config.MessagesCollection, bson.M{"thread_id": r.t.ID}, []string{"created_at", "_id"}, skip, limit)
+	if err != nil {
+		log.Println(err)
+		return nil, er.Generic()
+	}
+
+	results := make([]*MessageResolver, 0)
+	for _, raw := range rawMessages {
+		message := models.TransformMessage(raw)
+		results = append(results, &MessageResolver{&message, r.p, r.crud})
+	}
+	return results, nil
+}
+
+// LastMessage resolves Thread.LastMessage
+func (r *ThreadResolver) LastMessage() (*MessageResolver, error) {
+	defer r.crud.CloseCopy()
+
+	rawMessages, _, err := r.crud.FindPage(config.MessagesCollection, bson.M{"thread_id": r.t.ID}, []string{"-created_at", "-_id"}, 0, 1)
+	if err != nil || len(rawMessages) == 0 {
+		return nil, nil
+	}
+	message := models.TransformMessage(rawMessages[0])
+	return &MessageResolver{&message, r.p, r.crud}, nil
+}
+
+// Unread resolves Thread.Unread, the number of messages the viewer hasn't read
+func (r *ThreadResolver) Unread() (int32, error) {
+	defer r.crud.CloseCopy()
+
+	unread, err := r.p.unread(r.crud, r.t)
+	if err != nil {
+		log.Println(err)
+		return 0, er.Generic()
+	}
+	return int32(unread), nil
+}
+
+// CreatedAt resolves Thread.CreatedAt
+func (r *ThreadResolver) CreatedAt() Date {
+	return Date{r.t.CreatedAt}
+}
+
+// UpdatedAt resolves Thread.UpdatedAt, the time of the last message
+func (r *ThreadResolver) UpdatedAt() Date {
+	return Date{r.t.UpdatedAt}
+}
+
+// -----------------
+// MessageResolver struct
+// -----------------
+
+// MessageResolver resolves Message as seen by one of its thread's participants
+type MessageResolver struct {
+	m    *models.Message
+	p    *participant
+	crud *db.CRUD
+}
+
+// ID resolves Message.ID
+func (r *MessageResolver) ID() graphql.ID {
+	return graphql.ID(r.m.ID.Hex())
+}
+
+// Body resolves Message.Body
+func (r *MessageResolver) Body() string {
+	return r.m.Body
+}
+
+// SenderRole resolves Message.SenderRole
+func (r *MessageResolver) SenderRole() string {
+	return r.m.SenderRole
+}
+
+// SenderName resolves Message.SenderName
+func (r *MessageResolver) SenderName() string {
+	defer r.crud.CloseCopy()
+
+	rawAccount, err := r.crud.FindID(config.AccountsCollection, r.m.SenderID)
+	if err != nil {
+		return ""
+	}
+	account := models.TransformAccount(rawAccount)
+	return strings.TrimSpace(account.Name + " " + account.Surname)
+}
+
+// Mine resolves Message.Mine which is true when the viewer sent the message
+func (r *MessageResolver) Mine() bool {
+	return r.m.SenderID == r.p.account.ID
+}
+
+// CreatedAt resolves Message.CreatedAt
+func (r *MessageResolver) CreatedAt() Date {
+	return Date{r.m.CreatedAt}
+}
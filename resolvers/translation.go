@@ -0,0 +1,179 @@
+package resolvers
+
+import (
+	"log"
+
+	config "../config"
+	er "../errors"
+	models "../models"
+	graphql "github.com/graph-gophers/graphql-go"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// -----------------
+// SysEditor methods
+// -----------------
+
+// SetQuestionTranslation resolves SysEditor.SetQuestionTranslation which translates the
+// current wording of a Question to a locale, replacing an earlier translation to it
+func (r *SysEditorResolver) SetQuestionTranslation(args struct {
+	ID     graphql.ID
+	Locale string
+	Text   string
+}) (*QuestionResolver, error) {
+	question, err := r.findQuestion(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	translation := models.Translation{Locale: args.Locale, Text: args.Text}
+	if err := translation.OK(); err != nil {
+		return nil, err
+	}
+	question.Translations = models.SetTranslation(question.Translations, translation)
+	return r.updateQuestionTranslations(question)
+}
+
+// RemoveQuestionTranslation resolves SysEditor.RemoveQuestionTranslation
+func (r *SysEditorResolver) RemoveQuestionTranslation(args struct {
+	ID     graphql.ID
+	Locale string
+}) (*QuestionResolver, error) {
+	question, err := r.findQuestion(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	translations, ok := models.RemoveTranslation(question.Translations, args.Locale)
+	if !ok {
+		return nil, er.Input("Translation not found.")
+	}
+	question.Translations = translations
+	return r.updateQuestionTranslations(question)
+}
+
+// SetIndustryTranslation resolves SysEditor.SetIndustryTranslation which translates the
+// name of an Industry to a locale, replacing an earlier translation to it
+func (r *SysEditorResolver) SetIndustryTranslation(args struct {
+	ID     graphql.ID
+	Locale string
+	Name   string
+}) (*IndustryResolver, error) {
+	industry, err := r.findIndustry(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	industry.Translations = models.SetTranslation(industry.Translations, models.Translation{Locale: args.Locale, Text: args.Name})
+	return r.updateIndustryTranslations(industry)
+}
+
+// RemoveIndustryTranslation resolves SysEditor.RemoveIndustryTranslation
+func (r *SysEditorResolver) RemoveIndustryTranslation(args struct {
+	ID     graphql.ID
+	Locale string
+}) (*IndustryResolver, error) {
+	industry, err := r.findIndustry(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	translations, ok := models.RemoveTranslation(industry.Translations, args.Locale)
+	if !ok {
+		return nil, er.Input("Translation not found.")
+	}
+	industry.Translations = translations
+	return r.updateIndustryTranslations(industry)
+}
+
+// findQuestion finds the Question to translate
+func (r *SysEditorResolver) findQuestion(id graphql.ID) (*models.Question, error) {
+	if !bson.IsObjectIdHex(string(id)) {
+		return nil, er.InvalidField("id")
+	}
+	rawQuestion, err := r.crud.FindID(config.QuestionsCollection, bson.ObjectIdHex(string(id)))
+	if err != nil {
+		return nil, er.Input("Question not found.")
+	}
+	question := models.TransformQuestion(rawQuestion)
+	return &question, nil
+}
+
+// findIndustry finds the Industry to translate
+func (r *SysEditorResolver) findIndustry(id graphql.ID) (*models.Industry, error) {
+	if !bson.IsObjectIdHex(string(id)) {
+		return nil, er.InvalidField("id")
+	}
+	return findIndustry(r.crud, bson.ObjectIdHex(string(id)))
+}
+
+// updateQuestionTranslations stores the translations of a Question
+func (r *SysEditorResolver) updateQuestionTranslations(question *models.Question) (*QuestionResolver, error) {
+	defer r.crud.CloseCopy()
+
+	if err := question.OK(); err != nil {
+		return nil, err
+	}
+	if err := r.crud.UpdateID(config.QuestionsCollection, question.ID, bson.M{"translations": question.Translations}); err != nil {
+		log.Println("Failed to update question translations =>", err)
+		return nil, er.Generic()
+	}
+	r.index.Put(questionSearchDoc(*question))
+	return &QuestionResolver{question}, nil
+}
+
+// updateIndustryTranslations stores the translations of an Industry
+func (r *SysEditorResolver) updateIndustryTranslations(industry *models.Industry) (*IndustryResolver, error) {
+	defer r.crud.CloseCopy()
+
+	if err := industry.OK(); err != nil {
+		return nil, err
+	}
+	if err := r.crud.ReplaceID(config.IndustriesCollection, industry.ID, industry); err != nil {
+		log.Println("Failed to update industry translations =>", err)
+		return nil, er.Generic()
+	}
+	return &IndustryResolver{industry, r.crud}, nil
+}
+
+// -----------------
+// Question and Industry methods
+// -----------------
+
+// Translations resolves Question.Translations
+func (r *QuestionResolver) Translations() []*TranslationResolver {
+	return translationResolvers(r.q.Translations)
+}
+
+// Translations resolves Industry.Translations
+func (r *IndustryResolver) Translations() []*TranslationResolver {
+	return translationResolvers(r.i.Translations)
+}
+
+// translationResolvers creates the resolvers of a list of translations
+func translationResolvers(translations []models.Translation) []*TranslationResolver {
+	results := make([]*TranslationResolver, 0, len(translations))
+	for i := range translations {
+		results = append(results, &TranslationResolver{&translations[i]})
+	}
+	return results
+}
+
+// -----------------
+// TranslationResolver struct
+// -----------------
+
+// TranslationResolver resolves Translation
+type TranslationResolver struct {
+	t *models.Translation
+}
+
+// Locale resolves Translation.Locale
+func (r *TranslationResolver) Locale() string {
+	return r.t.Locale
+}
+
+// Text resolves Translation.Text
+func (r *TranslationResolver) Text() string {
+	return r.t.Text
+}
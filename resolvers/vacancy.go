@@ -0,0 +1,465 @@
+package resolvers
+
+import (
+	"log"
+	"time"
+
+	config "../config"
+	credits "../credits"
+	db "../database"
+	er "../errors"
+	models "../models"
+	utils "../utils"
+	graphql "github.com/graph-gophers/graphql-go"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// -----------------
+// Root Resolver methods
+// -----------------
+
+// Vacancies resolves "vacancies" gql query, which lists open vacancies matching the given filter
+func (r *RootResolver) Vacancies(args struct{ Filter *vacancyFilter }) ([]*VacancyResolver, error) {
+	defer r.crud.CloseCopy()
+
+	// prepare query
+	query := bson.M{"status": models.VacancyOpen}
+	filter := args.Filter
+	var minSalary int
+	if filter != nil {
+		if filter.IndustryID != nil {
+			id := string(*filter.IndustryID)
+			if !bson.IsObjectIdHex(id) {
+				return nil, er.InvalidField("filter.industry_id")
+			}
+			query["industry_id"] = bson.ObjectIdHex(id)
+		}
+		if filter.CompanyID != nil {
+			id := string(*filter.CompanyID)
+			if !bson.IsObjectIdHex(id) {
+				return nil, er.InvalidField("filter.company_id")
+			}
+			query["company_id"] = bson.ObjectIdHex(id)
+		}
+		if filter.Province != nil {
+			query["province"] = *filter.Province
+		}
+		if filter.City != nil {
+			query["city"] = *filter.City
+		}
+		if filter.EmploymentType != nil {
+			query["employment_type"] = *filter.EmploymentType
+		}
+		if filter.MinSalary != nil {
+			minSalary = int(*filter.MinSalary)
+		}
+	}
+
+	// fetch vacancies
+	rawVacancies, err := r.crud.FindAll(config.VacanciesCollection, query)
+	if err != nil {
+		log.Println(err)
+		return nil, er.Generic()
+	}
+
+	// process results, skipping vacancies past their closing date
+	results := make([]*VacancyResolver, 0)
+	for _, raw := range rawVacancies {
+		vacancy := models.TransformVacancy(raw)
+		if !vacancy.IsOpen() || vacancy.SalaryMax < minSalary {
+			continue
+		}
+		results = append(results, &VacancyResolver{&vacancy, r.crud})
+	}
+	return results, nil
+}
+
+// -----------------
+// HunterEditorResolver methods
+// -----------------
+
+// vacancy retrieves a Vacancy belonging to the current Hunter's Company
+func (r *HunterEditorResolver) vacancy(vacancyID graphql.ID) (*models.Vacancy, error) {
+	company, err := r.company(models.CompanyRoleOwner, models.CompanyRoleRecruiter)
+	if err != nil {
+		return nil, err
+	}
+
+	// check the id
+	id := string(vacancyID)
+	if !bson.IsObjectIdHex(id) {
+		return nil, er.InvalidField("id")
+	}
+
+	// retrieve vacancy
+	rawVacancy, err := r.crud.FindID(config.VacanciesCollection, bson.ObjectIdHex(id))
+	if err != nil {
+		return nil, er.InvalidField("id")
+	}
+	vacancy := models.TransformVacancy(rawVacancy)
+	if vacancy.CompanyID != company.ID {
+		return nil, er.InvalidField("id")
+	}
+	return &vacancy, nil
+}
+
+// chargePosting charges the Hunter for publishing a Vacancy. Vacancies are only charged
+// the first time they are opened
+func (r *HunterEditorResolver) chargePosting(vacancy *models.Vacancy) error {
+	if vacancy.Status != models.VacancyOpen {
+		return nil
+	}
+	_, err := r.ledger.SpendOnce(r.h.ID, credits.CostVacancyPosting, "Vacancy posting: "+vacancy.Title, vacancy.ID, r.a.ID)
+	return err
+}
+
+// vacancyFields are the editable fields of a Vacancy, as they're stored
+func vacancyFields(vacancy *models.Vacancy) bson.M {
+	return bson.M{
+		"title":           vacancy.Title,
+		"description":     vacancy.Description,
+		"industry_id":     vacancy.IndustryID,
+		"province":        vacancy.Province,
+		"city":            vacancy.City,
+		"employment_type": vacancy.EmploymentType,
+		"salary_min":      vacancy.SalaryMin,
+		"salary_max":      vacancy.SalaryMax,
+		"closing_date":    vacancy.ClosingDate,
+		"status":          vacancy.Status,
+	}
+}
+
+// CreateVacancy resolves HunterEditor.CreateVacancy which posts a Vacancy for the current Hunter's Company
+func (r *HunterEditorResolver) CreateVacancy(args struct{ Info *vacancyDetails }) (*VacancyResolver, error) {
+	defer r.crud.CloseCopy()
+
+	company, err := r.company(models.CompanyRoleOwner, models.CompanyRoleRecruiter)
+	if err != nil {
+		return nil, err
+	}
+
+	// check if info is nil
+	info := args.Info
+	if info == nil {
+		return nil, er.MissingField("info")
+	}
+
+	// validate info
+	if info.Title == nil {
+		return nil, er.MissingField("info.title")
+	}
+	if info.Description == nil {
+		return nil, er.MissingField("info.description")
+	}
+	if info.IndustryID == nil {
+		return nil, er.MissingField("info.industry_id")
+	}
+	if info.Province == nil {
+		return nil, er.MissingField("info.province")
+	}
+	if info.City == nil {
+		return nil, er.MissingField("info.city")
+	}
+	if info.EmploymentType == nil {
+		return nil, er.MissingField("info.employment_type")
+	}
+	if info.ClosingDate == nil {
+		return nil, er.MissingField("info.closing_date")
+	}
+
+	// create vacancy
+	vacancy := models.Vacancy{
+		ID:        bson.NewObjectId(),
+		CompanyID: company.ID,
+		CreatedBy: r.h.ID,
+		CreatedAt: time.Now(),
+		Status:    models.VacancyDraft,
+	}
+	if err := info.apply(r.crud, &vacancy); err != nil {
+		return nil, err
+	}
+
+	// validate vacancy
+	if err := vacancy.OK(); err != nil {
+		return nil, err
+	}
+
+	// store vacancy in db, it's only paid for once it's stored
+	if err := r.crud.Insert(config.VacanciesCollection, vacancy); err != nil {
+		log.Println("Failed to create vacancy =>", err)
+		return nil, er.Generic()
+	}
+	if err := r.chargePosting(&vacancy); err != nil {
+		if err := r.crud.DeleteID(config.VacanciesCollection, vacancy.ID); err != nil {
+			log.Println("Failed to remove unpaid vacancy =>", err)
+		}
+		return nil, err
+	}
+
+	return &VacancyResolver{&vacancy, r.crud}, nil
+}
+
+// UpdateVacancy resolves HunterEditor.UpdateVacancy
+func (r *HunterEditorResolver) UpdateVacancy(args struct {
+	ID   graphql.ID
+	Info *vacancyDetails
+}) (*VacancyResolver, error) {
+	defer r.crud.CloseCopy()
+
+	vacancy, err := r.vacancy(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// check if info is nil
+	info := args.Info
+	if info == nil {
+		return nil, er.MissingField("info")
+	}
+
+	// apply and validate updates
+	previous := *vacancy
+	if err := info.apply(r.crud, vacancy); err != nil {
+		return nil, err
+	}
+	if err := vacancy.OK(); err != nil {
+		return nil, err
+	}
+
+	// perform update, opening the vacancy is only paid for once it's stored
+	rawVacancy, err := GenericUpdateByID(r.crud, config.VacanciesCollection, vacancy.ID, vacancyFields(vacancy))
+	if err != nil {
+		return nil, err
+	}
+	if previous.Status != models.VacancyOpen {
+		if err := r.chargePosting(vacancy); err != nil {
+			if err := r.crud.UpdateID(config.VacanciesCollection, vacancy.ID, vacancyFields(&previous)); err != nil {
+				log.Println("Failed to restore unpaid vacancy =>", err)
+			}
+			return nil, err
+		}
+	}
+
+	// return updated vacancy
+	updated := models.TransformVacancy(rawVacancy)
+	return &VacancyResolver{&updated, r.crud}, nil
+}
+
+// CloseVacancy resolves HunterEditor.CloseVacancy which stops a Vacancy from accepting applications
+func (r *HunterEditorResolver) CloseVacancy(args struct{ ID graphql.ID }) (*VacancyResolver, error) {
+	defer r.crud.CloseCopy()
+
+	vacancy, err := r.vacancy(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// perform update
+	rawVacancy, err := GenericUpdateByID(r.crud, config.VacanciesCollection, vacancy.ID, bson.M{
+		"status": models.VacancyClosed,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// return updated vacancy
+	updated := models.TransformVacancy(rawVacancy)
+	return &VacancyResolver{&updated, r.crud}, nil
+}
+
+// RemoveVacancy resolves HunterEditor.RemoveVacancy
+func (r *HunterEditorResolver) RemoveVacancy(args struct{ ID graphql.ID }) (*string, error) {
+	vacancy, err := r.vacancy(args.ID)
+	if err != nil {
+		r.crud.CloseCopy()
+		return nil, err
+	}
+
+	return ResolveRemoveByID(r.crud, config.VacanciesCollection, "Vacancy", vacancy.ID)
+}
+
+// -----------------
+// HunterViewerResolver methods
+// -----------------
+
+// Vacancies resolves HunterViewer.Vacancies which returns all of the current Hunter's Company's vacancies
+func (r *HunterViewerResolver) Vacancies() ([]*VacancyResolver, error) {
+	defer r.crud.CloseCopy()
+
+	results := make([]*VacancyResolver, 0)
+	if utils.IsNullID(r.h.CompanyID) {
+		return results, nil
+	}
+
+	// fetch company vacancies
+	rawVacancies, err := r.crud.FindAll(config.VacanciesCollection, bson.M{"company_id": r.h.CompanyID})
+	if err != nil {
+		return nil, er.Generic()
+	}
+
+	// process results
+	for _, raw := range rawVacancies {
+		vacancy := models.TransformVacancy(raw)
+		results = append(results, &VacancyResolver{&vacancy, r.crud})
+	}
+	return results, nil
+}
+
+// -----------------
+// vacancyDetails struct
+// -----------------
+type vacancyDetails struct {
+	Title          *string
+	Description    *string
+	IndustryID     *graphql.ID
+	Province       *string
+	City           *string
+	EmploymentType *string
+	SalaryMin      *int32
+	SalaryMax      *int32
+	ClosingDate    *Date
+	Status         *string
+}
+
+// apply applies the given details onto a vacancy
+func (info *vacancyDetails) apply(crud *db.CRUD, vacancy *models.Vacancy) error {
+	if info.IndustryID != nil {
+		id := string(*info.IndustryID)
+		if !bson.IsObjectIdHex(id) {
+			return er.InvalidField("info.industry_id")
+		}
+		if _, err := crud.FindID(config.IndustriesCollection, bson.ObjectIdHex(id)); err != nil {
+			return er.InvalidField("info.industry_id")
+		}
+		vacancy.IndustryID = bson.ObjectIdHex(id)
+	}
+	if info.Title != nil {
+		vacancy.Title = *info.Title
+	}
+	if info.Description != nil {
+		vacancy.Description = *info.Description
+	}
+	if info.Province != nil {
+		vacancy.Province = *info.Province
+	}
+	if info.City != nil {
+		vacancy.City = *info.City
+	}
+	if info.EmploymentType != nil {
+		vacancy.EmploymentType = *info.EmploymentType
+	}
+	if info.SalaryMin != nil {
+		vacancy.SalaryMin = int(*info.SalaryMin)
+	}
+	if info.SalaryMax != nil {
+		vacancy.SalaryMax = int(*info.SalaryMax)
+	}
+	if info.ClosingDate != nil {
+		vacancy.ClosingDate = info.ClosingDate.Time
+	}
+	if info.Status != nil {
+		vacancy.Status = *info.Status
+	}
+	return nil
+}
+
+// -----------------
+// vacancyFilter struct
+// -----------------
+type vacancyFilter struct {
+	IndustryID     *graphql.ID
+	CompanyID      *graphql.ID
+	Province       *string
+	City           *string
+	EmploymentType *string
+	MinSalary      *int32
+}
+
+// -----------------
+// VacancyResolver struct
+// -----------------
+
+// VacancyResolver resolves Vacancy
+type VacancyResolver struct {
+	v    *models.Vacancy
+	crud *db.CRUD
+}
+
+// ID resolves Vacancy.ID
+func (r *VacancyResolver) ID() graphql.ID {
+	return graphql.ID(r.v.ID.Hex())
+}
+
+// Title resolves Vacancy.Title
+func (r *VacancyResolver) Title() string {
+	return r.v.Title
+}
+
+// Description resolves Vacancy.Description
+func (r *VacancyResolver) Description() string {
+	return r.v.Description
+}
+
+// Industry resolves Vacancy.Industry
+func (r *VacancyResolver) Industry() (*IndustryResolver, error) {
+	defer r.crud.CloseCopy()
+
+	rawIndustry, err := r.crud.FindID(config.IndustriesCollection, r.v.IndustryID)
+	if err != nil {
+		return nil, nil
+	}
+	industry := models.TransformIndustry(rawIndustry)
+	return &IndustryResolver{&industry, r.crud}, nil
+}
+
+// Company resolves Vacancy.Company
+func (r *VacancyResolver) Company() (*CompanyResolver, error) {
+	defer r.crud.CloseCopy()
+
+	rawCompany, err := r.crud.FindID(config.CompaniesCollection, r.v.CompanyID)
+	if err != nil {
+		return nil, nil
+	}
+	company := models.TransformCompany(rawCompany)
+	return &CompanyResolver{&company, r.crud}, nil
+}
+
+// Province resolves Vacancy.Province
+func (r *VacancyResolver) Province() string {
+	return r.v.Province
+}
+
+// City resolves Vacancy.City
+func (r *VacancyResolver) City() string {
+	return r.v.City
+}
+
+// EmploymentType resolves Vacancy.EmploymentType
+func (r *VacancyResolver) EmploymentType() string {
+	return r.v.EmploymentType
+}
+
+// SalaryMin resolves Vacancy.SalaryMin
+func (r *VacancyResolver) SalaryMin() int32 {
+	return int32(r.v.SalaryMin)
+}
+
+// SalaryMax resolves Vacancy.SalaryMax
+func (r *VacancyResolver) SalaryMax() int32 {
+	return int32(r.v.SalaryMax)
+}
+
+// ClosingDate resolves Vacancy.ClosingDate
+func (r *VacancyResolver) ClosingDate() Date {
+	return Date{r.v.ClosingDate}
+}
+
+// CreatedAt resolves Vacancy.CreatedAt
+func (r *VacancyResolver) CreatedAt() Date {
+	return Date{r.v.CreatedAt}
+}
+
+// Status resolves Vacancy.Status
+func (r *VacancyResolver) Status() string {
+	return r.v.Status
+}
@@ -1,6 +1,7 @@
 package resolvers
 
 import (
+	"context"
 	"log"
 	"time"
 
@@ -8,6 +9,7 @@ import (
 	db "../database"
 	er "../errors"
 	models "../models"
+	search "../search"
 	utils "../utils"
 	graphql "github.com/graph-gophers/graphql-go"
 	"gopkg.in/mgo.v2/bson"
@@ -67,6 +69,26 @@ func (r *RootResolver) View(args struct {
 		return &ViewerResolver{viewer}, nil
 	}
 
+	// func to resolve Viewer as HunterViewer
+	viewAsHunter := func() (*ViewerResolver, error) {
+		// check if account has hunter profile
+		if utils.IsNullID(account.HunterID) {
+			return nil, er.Input("Failed to enforce 'HUNTER'.")
+		}
+
+		// retrieve Hunter profile
+		rawHunter, err := r.crud.FindID(config.HuntersCollection, account.HunterID)
+		if err != nil {
+			log.Println("Failed to find hunter =>", err)
+			return nil, er.Generic()
+		}
+
+		// return HunterViewer
+		hunter := models.TransformHunter(rawHunter)
+		viewer := &HunterViewerResolver{&hunter, &account, r.crud, r.index}
+		return &ViewerResolver{viewer}, nil
+	}
+
 	// func to resolve Viewer as SysViewer
 	viewAsSys := func() (*ViewerResolver, error) {
 		// check if account is sys account
@@ -75,14 +97,14 @@ func (r *RootResolver) View(args struct {
 		}
 
 		// return sysViewer
-		viewer := &SysViewerResolver{&account, r.crud}
+		viewer := &SysViewerResolver{&account, r.crud, r.index}
 		return &ViewerResolver{viewer}, nil
 	}
 
 	//func to resolve Viewer as AccountViewer
 	viewAsAccount := func() (*ViewerResolver, error) {
 		// return accountViewer
-		viewer := &AccountViewerResolver{&account}
+		viewer := &AccountViewerResolver{&account, r.crud}
 		return &ViewerResolver{viewer}, nil
 	}
 
@@ -92,7 +114,7 @@ func (r *RootResolver) View(args struct {
 		case "RECRUIT":
 			return viewAsRecruit()
 		case "HUNTER":
-			return nil, er.Input("Unimplemented")
+			return viewAsHunter()
 		case "SYSTEM":
 			return viewAsSys()
 		case "ACCOUNT":
@@ -112,6 +134,11 @@ func (r *RootResolver) View(args struct {
 		return viewer, nil
 	}
 
+	// try to view as HunterViewer
+	if viewer, err := viewAsHunter(); err == nil {
+		return viewer, nil
+	}
+
 	// if all else fails view as AccountEditor
 	return viewAsAccount()
 }
@@ -124,6 +151,7 @@ type viewer interface {
 	Name() string
 	Surname() string
 	Email() string
+	Notifications() *NotificationsResolver
 }
 
 // -----------------
@@ -169,57 +197,65 @@ func (r *RecruitViewerResolver) Profile() (*RecruitResolver, error) {
 	}
 	account := models.TransformAccount(rawAccount)
 
-	return &RecruitResolver{r.r, &account}, nil
+	return &RecruitResolver{r.r, &account, r.crud, fullContactAccess}, nil
 }
 
 // -----------------
-// hunterViewerResolver struct
+// HunterViewerResolver struct
 // -----------------
-// type hunterViewerResolver struct {
-// 	r *models.Hunter
-// 	crud *db.CRUD
-// }
-
-// func (r *hunterViewerResolver) ID() graphql.ID {
-// 	return graphql.ID(r.r.ID.Hex())
-// }
-
-// func (r *hunterViewerResolver) Name() string {
-// 	return graphql.ID(r.r.Name)
-// }
-
-// func (r *hunterViewerResolver) Surname() string {
-// 	return graphql.ID(r.r.Surname)
-// }
-
-// func (r *hunterViewerResolver) Email() string {
-// 	return graphql.ID(r.r.Email)
-// }
-
-// func (r *hunterViewerResolver) Recruit(args struct{ID graphql.ID}) (*RecruitResolver, error){
-// 	id := string(args.ID)
-// 	if !bson.IsObjectIdHex(id){
-// 		log.Println("Invalid id!")
-// 		return nil, er.GenericError()
-// 	}
-
-// 	rawRecruit, err := r.crud.FindID(config.RecruitsCollection, account.RecruitID)
-// 	if err !=  nil{
-// 		log.Println(err)
-// 		return nil, er.GenericError()
-// 	}
-// 	recruit  := models.TransformRecruit(rawRecruit)
-// 	rawAccount, err := r.crud.FindOne(
-// 		config.AccountsCollection,
-// 		&bson.M{"recruit_id": recruit.ID},
-// 	)
-// 	if err !=  nil{
-// 		log.Println(err)
-// 		return nil, er.GenericError()
-// 	}
-// 	account :=  models.TransformAccount(rawAccount)
-// 	return &RecruitResolver{&recruit, &account}, nil
-// }
+
+// HunterViewerResolver resolves HunterViewer
+type HunterViewerResolver struct {
+	h     *models.Hunter
+	a     *models.Account
+	crud  *db.CRUD
+	index *search.Index
+}
+
+// ID resolves HunterViewer.ID
+func (r *HunterViewerResolver) ID() graphql.ID {
+	return graphql.ID(r.h.ID.Hex())
+}
+
+// Name resolves HunterViewer.Name
+func (r *HunterViewerResolver) Name() string {
+	return r.a.Name
+}
+
+// Surname resolves HunterViewer.Surname
+func (r *HunterViewerResolver) Surname() string {
+	return r.a.Surname
+}
+
+// Email resolves HunterViewer.Email
+func (r *HunterViewerResolver) Email() string {
+	return r.a.Email
+}
+
+// Profile resolves HunterViewer.Profile which returns the current account's Hunter profile
+func (r *HunterViewerResolver) Profile() *HunterResolver {
+	return &HunterResolver{r.h, r.a}
+}
+
+// Recruit resolves HunterViewer.Recruit which returns the Recruit with the given ID
+func (r *HunterViewerResolver) Recruit(args struct{ ID graphql.ID }) (*RecruitResolver, error) {
+	defer r.crud.CloseCopy()
+
+	// check the id
+	id := string(args.ID)
+	if !bson.IsObjectIdHex(id) {
+		return nil, er.InvalidField("id")
+	}
+
+	// retrieve recruit
+	rawRecruit, err := r.crud.FindID(config.RecruitsCollection, bson.ObjectIdHex(id))
+	if err != nil {
+		return nil, er.Input("Recruit not found.")
+	}
+	recruit := models.TransformRecruit(rawRecruit)
+
+	return resolveRecruit(r.crud, &recruit, r.a)
+}
 
 // -----------------
 // SysViewerResolver struct
@@ -227,8 +263,9 @@ func (r *RecruitViewerResolver) Profile() (*RecruitResolver, error) {
 
 // SysViewerResolver resolves SysViewer
 type SysViewerResolver struct {
-	a    *models.Account
-	crud *db.CRUD
+	a     *models.Account
+	crud  *db.CRUD
+	index *search.Index
 }
 
 // ID resolves SysViewer.ID
@@ -279,7 +316,7 @@ func (r *SysViewerResolver) Recruits() ([]*RecruitResolver, error) {
 	results := make([]*RecruitResolver, 0)
 	for _, raw := range rawRecruits {
 		recruit := models.TransformRecruit(raw)
-		results = append(results, &RecruitResolver{&recruit, r.a})
+		results = append(results, &RecruitResolver{&recruit, r.a, r.crud, fullContactAccess})
 	}
 
 	// return results
@@ -334,7 +371,8 @@ func (r *SysViewerResolver) Documents() ([]*DocumentResolver, error) {
 
 // AccountViewerResolver resolves AccountViewer
 type AccountViewerResolver struct {
-	a *models.Account
+	a    *models.Account
+	crud *db.CRUD
 }
 
 // ID resolves AccountViewer.ID
@@ -387,6 +425,12 @@ func (r *ViewerResolver) ToRecruitViewer() (*RecruitViewerResolver, bool) {
 	return v, ok
 }
 
+// ToHunterViewer asserts *ViewerResolver to *HunterViewerResolver
+func (r *ViewerResolver) ToHunterViewer() (*HunterViewerResolver, bool) {
+	v, ok := r.viewer.(*HunterViewerResolver)
+	return v, ok
+}
+
 // ToSysViewer asserts *ViewerResolver to *SysViewerResolver
 func (r *ViewerResolver) ToSysViewer() (*SysViewerResolver, bool) {
 	v, ok := r.viewer.(*SysViewerResolver)
@@ -405,12 +449,38 @@ func (r *ViewerResolver) ToAccountViewer() (*AccountViewerResolver, bool) {
 
 // QaResolver resolve Qa
 type QaResolver struct {
-	qa *models.QA
+	qa       *models.QA
+	question *models.Question
+}
+
+// QuestionID resolves Qa.QuestionID
+func (r *QaResolver) QuestionID() graphql.ID {
+	return graphql.ID(r.qa.QuestionID.Hex())
+}
+
+// Question resolves Qa.Question which is null when the Question has been removed
+func (r *QaResolver) Question() *QuestionResolver {
+	if r.question == nil {
+		return nil
+	}
+	return &QuestionResolver{r.question}
+}
+
+// Revision resolves Qa.Revision
+func (r *QaResolver) Revision() int32 {
+	return r.qa.Revision
 }
 
-// Question resolves Qa.Question
-func (r *QaResolver) Question() string {
-	return r.qa.Question
+// QuestionRevision resolves Qa.QuestionRevision which is the wording the Recruit answered
+func (r *QaResolver) QuestionRevision() *QuestionRevisionResolver {
+	if r.question == nil {
+		return nil
+	}
+	revision, ok := r.question.FindRevision(r.qa.Revision)
+	if !ok {
+		return nil
+	}
+	return &QuestionRevisionResolver{revision}
 }
 
 // Answer resolves Qa.Answer
@@ -424,8 +494,10 @@ func (r *QaResolver) Answer() string {
 
 // RecruitResolver resolves Recruit
 type RecruitResolver struct {
-	r *models.Recruit
-	a *models.Account
+	r      *models.Recruit
+	a      *models.Account
+	crud   *db.CRUD
+	access contactAccess
 }
 
 // ID resolves Recruit.ID
@@ -449,16 +521,27 @@ func (r *RecruitResolver) Surname() string {
 	return r.a.Surname
 }
 
-// Phone resolves Recruit.Phone
+// Phone resolves Recruit.Phone, which is masked unless the viewer has been granted access
 func (r *RecruitResolver) Phone() string {
+	if !r.access.phone {
+		return utils.MaskPhone(r.r.Phone)
+	}
 	return r.r.Phone
 }
 
-// Email resolves Recruit.Email
+// Email resolves Recruit.Email, which is masked unless the viewer has been granted access
 func (r *RecruitResolver) Email() string {
+	if !r.access.email {
+		return utils.MaskEmail(r.r.Email)
+	}
 	return r.r.Email
 }
 
+// ContactUnlocked resolves Recruit.ContactUnlocked which is whether the viewer can see any contact details
+func (r *RecruitResolver) ContactUnlocked() bool {
+	return r.access.phone || r.access.email
+}
+
 // Province resolves Recruit.Province
 func (r *RecruitResolver) Province() string {
 	return r.r.Province
@@ -479,22 +562,68 @@ func (r *RecruitResolver) Disability() string {
 	return r.r.Disability
 }
 
-// Vid1Url resolves Recruit.Vid1Url
-func (r *RecruitResolver) Vid1Url() string {
-	return r.r.Vid1Url
+// Vid1Url resolves Recruit.Vid1Url, uploaded videos are only served at signed urls
+func (r *RecruitResolver) Vid1Url(ctx context.Context) string {
+	return signedFileURL(ctx, r.r.Vid1Url)
+}
+
+// Vid2Url resolves Recruit.Vid2Url, uploaded videos are only served at signed urls
+func (r *RecruitResolver) Vid2Url(ctx context.Context) string {
+	return signedFileURL(ctx, r.r.Vid2Url)
 }
 
-// Vid2Url resolves Recruit.Vid2Url
-func (r *RecruitResolver) Vid2Url() string {
-	return r.r.Vid2Url
+// -----------------
+// HunterResolver struct
+// -----------------
+
+// HunterResolver resolves Hunter
+type HunterResolver struct {
+	h *models.Hunter
+	a *models.Account
+}
+
+// ID resolves Hunter.ID
+func (r *HunterResolver) ID() graphql.ID {
+	return graphql.ID(r.h.ID.Hex())
+}
+
+// Name resolves Hunter.Name
+func (r *HunterResolver) Name() string {
+	return r.a.Name
+}
+
+// Surname resolves Hunter.Surname
+func (r *HunterResolver) Surname() string {
+	return r.a.Surname
+}
+
+// Phone resolves Hunter.Phone
+func (r *HunterResolver) Phone() string {
+	return r.h.Phone
+}
+
+// Email resolves Hunter.Email
+func (r *HunterResolver) Email() string {
+	return r.h.Email
 }
 
-// Qa1 resolves Recruit.Qa1
-func (r *RecruitResolver) Qa1() *QaResolver {
-	return &QaResolver{&r.r.Qa1}
+// Position resolves Hunter.Position
+func (r *HunterResolver) Position() string {
+	return r.h.Position
 }
 
-// Qa2 resolves Recruit.Qa2
-func (r *RecruitResolver) Qa2() *QaResolver {
-	return &QaResolver{&r.r.Qa2}
+// CompanyID resolves Hunter.CompanyID
+func (r *HunterResolver) CompanyID() graphql.ID {
+	if utils.IsNullID(r.h.CompanyID) {
+		return graphql.ID("")
+	}
+	return graphql.ID(r.h.CompanyID.Hex())
+}
+
+// Role resolves Hunter.Role
+func (r *HunterResolver) Role() *string {
+	if utils.IsNullID(r.h.CompanyID) {
+		return nil
+	}
+	return &r.h.Role
 }
@@ -2,17 +2,20 @@ package routing
 
 import (
 	"encoding/json"
-	"fmt"
-	"io/ioutil"
+	"errors"
+	"io"
 	"log"
 	"mime"
 	"net/http"
 	"os"
-	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"gopkg.in/mgo.v2/bson"
 
 	db "../database"
+	er "../errors"
 	mware "../middleware"
 	"github.com/gorilla/mux"
 	graphql "github.com/graph-gophers/graphql-go"
@@ -20,14 +23,28 @@ import (
 
 	resolver "../resolvers"
 	schemas "../schemas"
+	storage "../storage"
+	upload "../upload"
 )
 
+// errCantWriteFile is returned when an uploaded file can't be stored
+var errCantWriteFile = errors.New("can't write file")
+
 // NewGqlHandler creates a graphql handler
 func NewGqlHandler(crud *db.CRUD) http.Handler {
-	// prepare graphql root resolver
+	return newGqlHandler(newRootResolver(crud), storage.NewStoreFromEnv())
+}
+
+// newRootResolver prepares a graphql root resolver
+func newRootResolver(crud *db.CRUD) *resolver.RootResolver {
 	gqlResolver := &resolver.RootResolver{}
 	gqlResolver.Init(crud)
+	return gqlResolver
+}
 
+// newGqlHandler creates a graphql handler for a root resolver, the urls of the
+// files in its responses are signed by store
+func newGqlHandler(gqlResolver *resolver.RootResolver, store storage.Store) http.Handler {
 	// create schema
 	schema := graphql.MustParseSchema(
 		schemas.CreateSchema(schemas.DefaultSchemas...),
@@ -35,66 +52,171 @@ func NewGqlHandler(crud *db.CRUD) http.Handler {
 	)
 
 	// make handler
-	return mware.ReqInfoMiddleware(&relay.Handler{Schema: schema})
+	handler := mware.ReqInfoMiddleware(&relay.Handler{Schema: schema})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r.WithContext(storage.NewContext(r.Context(), store)))
+	})
 }
 
-// NewUploadHandler creates an upload handler
-func NewUploadHandler() func(http.ResponseWriter, *http.Request) {
-	const maxUploadSize = 100 * 1024
-	const uploadPath = "./files"
+// DefaultMaxDocumentSize is the largest request the upload handler accepts when
+// MAX_DOCUMENT_MB isn't set, enough for scanned documents
+const DefaultMaxDocumentSize = 10 << 20
+
+// uploadMemory is how much of an upload is kept in memory, the rest is written to a temporary file
+const uploadMemory = 1 << 20
+
+// NewUploadHandler creates an upload handler which puts files in store, the access token in
+// the Authorization header decides who owns the uploaded document. It responds with the created
+// Document, requests larger than maxSize bytes are refused
+func NewUploadHandler(gqlResolver *resolver.RootResolver, store storage.Store, maxSize int64) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
-		r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
-		if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		if r.ContentLength > maxSize {
+			jsonEncode(w, "FILE_TOO_LARGE", http.StatusRequestEntityTooLarge)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+		if err := r.ParseMultipartForm(uploadMemory); err != nil {
 			log.Println(err)
-			jsonEncode(w, "File too large.", http.StatusBadRequest)
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				jsonEncode(w, "FILE_TOO_LARGE", http.StatusRequestEntityTooLarge)
+				return
+			}
+			jsonEncode(w, "INVALID_FILE", http.StatusBadRequest)
 			return
 		}
+		defer r.MultipartForm.RemoveAll()
 
-		file, _, err := r.FormFile("uploadFile")
+		file, header, err := r.FormFile("uploadFile")
 		if err != nil {
 			log.Println(err)
 			jsonEncode(w, "INVALID_FILE", http.StatusBadRequest)
 			return
 		}
 		defer file.Close()
-		fileBytes, err := ioutil.ReadAll(file)
-		if err != nil {
+
+		// the type is told from the start of the file, which is then read again to be stored
+		head := make([]byte, 512)
+		n, err := io.ReadFull(file, head)
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
 			log.Println(err)
 			jsonEncode(w, "INVALID_FILE", http.StatusBadRequest)
 			return
 		}
-		filetype := http.DetectContentType(fileBytes)
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			log.Println(err)
+			jsonEncode(w, "INVALID_FILE", http.StatusBadRequest)
+			return
+		}
+		filetype := http.DetectContentType(head[:n])
 		if filetype != "image/jpeg" && filetype != "image/jpg" &&
 			filetype != "image/gif" && filetype != "image/png" &&
 			filetype != "application/pdf" {
 			jsonEncode(w, "INVALID_FILE_TYPE", http.StatusBadRequest)
 			return
 		}
-
-		fileName := bson.NewObjectId().Hex()
 		fileEndings, err := mime.ExtensionsByType(filetype)
-		if err != nil {
+		if err != nil || len(fileEndings) == 0 {
 			log.Println(err)
 			jsonEncode(w, "CANT_READ_FILE_TYPE", http.StatusInternalServerError)
 			return
 		}
-		fmt.Println(fileEndings)
-		newPath := filepath.Join(uploadPath, fileName+fileEndings[0])
-		fmt.Printf("FileType: %s, File: %s\n", fileEndings[0], newPath)
 
-		newFile, err := os.Create(newPath)
+		// the file is only written once the uploader is known
+		fileName := bson.NewObjectId().Hex() + fileEndings[0]
+		saved := false
+		save := func() (string, error) {
+			if err := store.Put(fileName, file, header.Size, filetype); err != nil {
+				log.Println("Failed to store uploaded file =>", err)
+				return "", errCantWriteFile
+			}
+			saved = true
+			return storage.URL(fileName), nil
+		}
+
+		docType := r.FormValue("doc_type")
+		if docType == "" {
+			docType = "QUALIFICATION"
+		}
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		document, err := gqlResolver.UploadDocument(token, docType, save)
 		if err != nil {
-			log.Println(err)
-			jsonEncode(w, "CANT_WRITE_FILE", http.StatusInternalServerError)
+			if saved {
+				store.Delete(fileName)
+			}
+			switch {
+			case err == errCantWriteFile:
+				jsonEncode(w, "CANT_WRITE_FILE", http.StatusInternalServerError)
+			case err == er.InvalidToken():
+				jsonEncode(w, err.Error(), http.StatusUnauthorized)
+			default:
+				jsonEncode(w, err.Error(), http.StatusBadRequest)
+			}
 			return
 		}
-		defer newFile.Close()
-		if _, err := newFile.Write(fileBytes); err != nil {
-			log.Println(err)
-			jsonEncode(w, "CANT_WRITE_FILE", http.StatusInternalServerError)
+		jsonEncode(w, document, http.StatusCreated)
+	}
+}
+
+// NewFileHandler creates a handler serving stored files by their key, the path after the
+// handler's prefix. Files are only served at urls signed by the store, stores that don't
+// verify urls themselves serve none here. Files that can seek are served with support
+// for range requests
+func NewFileHandler(store storage.Store) http.Handler {
+	verifier, _ := store.(storage.URLVerifier)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, "/")
+		if verifier == nil || verifier.VerifyURL(key, r.URL.Query()) != nil {
+			http.Error(w, "Invalid or expired file url.", http.StatusForbidden)
 			return
 		}
-		w.Write([]byte("SUCCESS"))
+		var file io.ReadCloser
+		info, err := store.Stat(key)
+		if err == nil && r.Method != http.MethodHead {
+			file, info, err = store.Get(key)
+		}
+		if err == storage.ErrNotFound || err == storage.ErrInvalidKey {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			log.Println("Failed to read stored file =>", err)
+			http.Error(w, "Failed to read file.", http.StatusInternalServerError)
+			return
+		}
+
+		if info.ContentType != "" {
+			w.Header().Set("Content-Type", info.ContentType)
+		}
+		if file == nil {
+			w.Header().Set("Content-Length", strconv.FormatInt(info.Size, 10))
+			w.Header().Set("Last-Modified", info.ModTime.UTC().Format(http.TimeFormat))
+			return
+		}
+		defer file.Close()
+		if seeker, ok := file.(io.ReadSeeker); ok {
+			http.ServeContent(w, r, key, info.ModTime, seeker)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.FormatInt(info.Size, 10))
+		w.Header().Set("Last-Modified", info.ModTime.UTC().Format(http.TimeFormat))
+		io.Copy(w, file)
+	})
+}
+
+// NewCalendarHandler creates a handler serving interviews as iCalendar files
+func NewCalendarHandler(crud *db.CRUD) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := mux.Vars(r)["id"]
+		calendar, err := resolver.InterviewCalendar(crud, r.URL.Query().Get("token"), id)
+		if err != nil {
+			jsonEncode(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Content-Disposition", "attachment; filename=\"interview-"+id+".ics\"")
+		w.Write(calendar)
 	}
 }
 
@@ -107,30 +229,57 @@ func jsonEncode(w http.ResponseWriter, v interface{}, statusCode int) {
 	}
 }
 
+// maxDocumentSize is the largest document upload in MAX_DOCUMENT_MB, or DefaultMaxDocumentSize
+func maxDocumentSize() int64 {
+	if mb, err := strconv.ParseInt(os.Getenv("MAX_DOCUMENT_MB"), 10, 64); err == nil && mb > 0 {
+		return mb << 20
+	}
+	return DefaultMaxDocumentSize
+}
+
 // NewRouter prepares a new router with necessary endpoints
 func NewRouter(crud *db.CRUD, middleware ...mware.Middleware) http.Handler {
 	// prepare router
 	router := mux.NewRouter()
 	mware.ApplyMiddleware(router, middleware...)
+	gqlResolver := newRootResolver(crud)
+	store := storage.NewStoreFromEnv()
 
 	// attach graphql handler
 	router.
 		Path("/graphql").
 		// Methods(http.MethodPost). // strictly post for production in production
-		Handler(NewGqlHandler(crud))
+		Handler(newGqlHandler(gqlResolver, store))
 
 	// attach upload handler
 	router.
 		Path("/upload").
 		Methods(http.MethodPost).
-		HandlerFunc(NewUploadHandler())
+		HandlerFunc(NewUploadHandler(gqlResolver, store, maxDocumentSize()))
+
+	// attach resumable video upload handler, unfinished uploads are kept in UPLOAD_DIR
+	// and completed videos are stored with the other files, expired uploads are removed hourly
+	uploadDir := os.Getenv("UPLOAD_DIR")
+	if uploadDir == "" {
+		uploadDir = "./uploads"
+	}
+	uploads := upload.NewHandler(crud, store, uploadDir, "/uploads")
+	go uploads.Run(time.Hour, nil)
+	router.
+		PathPrefix("/uploads").
+		Handler(uploads)
+
+	// attach stored file handler
+	router.
+		PathPrefix(storage.URLPrefix).
+		Handler(http.StripPrefix(storage.URLPrefix, NewFileHandler(store))).
+		Methods(http.MethodGet, http.MethodHead)
 
-	// attach static file  handler
-	dir := http.Dir("./files")
+	// attach interview calendar handler
 	router.
-		PathPrefix("/file/").
-		Handler(http.StripPrefix("/file/", http.FileServer(dir))).
-		Methods(http.MethodGet)
+		Path("/calendar/{id:[0-9a-f]{24}}.ics").
+		Methods(http.MethodGet).
+		HandlerFunc(NewCalendarHandler(crud))
 
 	return router
 }
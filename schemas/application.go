@@ -0,0 +1,45 @@
+package schemas
+
+// ApplicationSchema graphql schema for vacancy applications
+var ApplicationSchema = Schema{
+	Types: `
+		type Application{
+			id: ID!
+			vacancy: Vacancy
+			recruit: Recruit
+			stage: ApplicationStage!
+			history: [StageChange]!
+			created_at: Date!
+			updated_at: Date!
+		}
+
+		type StageChange{
+			stage: ApplicationStage!
+			note: String!
+			changed_at: Date!
+		}
+
+		type PipelineStage{
+			stage: ApplicationStage!
+			next: [ApplicationStage!]!
+		}
+
+		input PipelineStageInput{
+			stage: ApplicationStage!
+			next: [ApplicationStage!]!
+		}
+
+		enum ApplicationStage{
+			APPLIED
+			SCREENED
+			INTERVIEW
+			OFFER
+			HIRED
+			REJECTED
+		}
+	`,
+	Queries: `
+	`,
+	Mutations: `
+	`,
+}
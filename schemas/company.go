@@ -0,0 +1,49 @@
+package schemas
+
+// CompanySchema graphql schema for companies
+var CompanySchema = Schema{
+	Types: `
+		type Company{
+			id: ID!
+			name: String!
+			registration_number: String!
+			province: String!
+			city: String!
+			members: [Hunter]!
+			documents: [Document]!
+			invites: [CompanyInvite]!
+			pipeline: [PipelineStage]!
+		}
+
+		type CompanyInvite{
+			id: ID!
+			company: Company
+			email: String!
+			role: CompanyRole!
+			status: InviteStatus!
+		}
+
+		input CompanyDetails{
+			name: String
+			registration_number: String
+			province: Province
+			city: String
+		}
+
+		enum CompanyRole{
+			OWNER
+			RECRUITER
+			VIEWER
+		}
+
+		enum InviteStatus{
+			PENDING
+			ACCEPTED
+			DECLINED
+		}
+	`,
+	Queries: `
+	`,
+	Mutations: `
+	`,
+}
@@ -0,0 +1,30 @@
+package schemas
+
+// CompletenessSchema graphql schema for recruit profile completeness
+var CompletenessSchema = Schema{
+	Types: `
+		type ProfileCompleteness{
+			percent: Int!
+			missing: [ProfileItem!]!
+		}
+
+		type IncompleteRecruit{
+			recruit: Recruit!
+			completeness: ProfileCompleteness!
+		}
+
+		enum ProfileItem{
+			VIDEO_1
+			VIDEO_2
+			ANSWERS
+			WORK_EXPERIENCE
+			EDUCATION
+			QUALIFICATION
+			VERIFIED_QUALIFICATION
+		}
+	`,
+	Queries: `
+	`,
+	Mutations: `
+	`,
+}
@@ -0,0 +1,45 @@
+package schemas
+
+// ContactSchema graphql schema for recruit contact privacy
+var ContactSchema = Schema{
+	Types: `
+		type RecruitPrivacy{
+			allow_requests: Boolean!
+			share_phone: Boolean!
+			share_email: Boolean!
+		}
+
+		type ContactRequest{
+			id: ID!
+			recruit: Recruit
+			hunter: Hunter
+			message: String!
+			status: ContactRequestStatus!
+			created_at: Date!
+			responded_at: Date
+		}
+
+		type ContactUnlock{
+			id: ID!
+			request_id: ID!
+			recruit_id: ID!
+			hunter_id: ID!
+			company_id: ID
+			granted_by: ID!
+			phone: Boolean!
+			email: Boolean!
+			created_at: Date!
+		}
+
+		enum ContactRequestStatus{
+			PENDING
+			APPROVED
+			DECLINED
+			REVOKED
+		}
+	`,
+	Queries: `
+	`,
+	Mutations: `
+	`,
+}
@@ -0,0 +1,33 @@
+package schemas
+
+// CreditSchema graphql schema for the hunters' credit ledger
+var CreditSchema = Schema{
+	Types: `
+		type Credits{
+			balance: Int!
+			entries(first: Int, skip: Int): [CreditEntry]!
+		}
+
+		type CreditEntry{
+			id: ID!
+			seq: Int!
+			kind: CreditKind!
+			amount: Int!
+			balance: Int!
+			reason: String!
+			subject_id: ID
+			created_at: Date!
+		}
+
+		enum CreditKind{
+			PURCHASE
+			ADJUSTMENT
+			SPEND
+			REFUND
+		}
+	`,
+	Queries: `
+	`,
+	Mutations: `
+	`,
+}
@@ -9,6 +9,7 @@ var DocumentSchema = Schema{
 			doc_type: DocType!
 			owner_type: OwnerType!
 			owner_id: ID!
+			verified: Boolean!
 		}
 
 		enum  DocType{
@@ -24,11 +25,5 @@ var DocumentSchema = Schema{
 	Queries: `
 	`,
 	Mutations: `
-		createDocument(
-			url: String!,
-			doc_type: DocType!,
-			owner_type: OwnerType!,
-			owner_id:ID!
-		): Document
 	`,
 }
@@ -3,7 +3,7 @@ package schemas
 // EditorSchema schema
 var EditorSchema = Schema{
 	Types: `
-		union Editor = RecruitEditor | SysEditor | AccountEditor
+		union Editor = RecruitEditor | HunterEditor | SysEditor | AccountEditor
 
 		input QaDetails{
 			question_id: ID!
@@ -12,28 +12,94 @@ var EditorSchema = Schema{
 
 		type AccountEditor{
 			createRecruit(info: RecruitDetails!): Recruit
+			createHunter(info: HunterDetails!): Hunter
 			removeAccount(): String
 			updateAccount(info: AccountDetails): Account
+			markNotificationsRead(ids: [ID!]): Notifications
+			setNotificationPreference(event: NotificationEvent!, in_app: Boolean!, email: Boolean!): NotificationPreference
 		}
 
 		type RecruitEditor{
 			removeRecruit: String
 			updateRecruit(info: RecruitDetails): Recruit
-			updateQAs(qa1: QaDetails, qa2: QaDetails): [QA]!
+			updateQAs(answers: [QaDetails!]!): [QA]!
+			apply(vacancy_id: ID!): Application
+			acceptInterview(id: ID!, slot: Int!): Interview
+			cancelInterview(id: ID!): Interview
+			startThread(application_id: ID, vacancy_id: ID, subject: String!, message: String!): Thread
+			sendMessage(thread_id: ID!, body: String!): Message
+			markThreadRead(id: ID!): Thread
+			respondContactRequest(id: ID!, approve: Boolean!): ContactRequest
+			revokeContactAccess(id: ID!): ContactRequest
+			updatePrivacy(allow_requests: Boolean, share_phone: Boolean, share_email: Boolean): RecruitPrivacy
+			addWorkExperience(info: WorkExperienceDetails!): WorkExperience
+			updateWorkExperience(id: ID!, info: WorkExperienceDetails!): WorkExperience
+			removeWorkExperience(id: ID!): String
+			addEducation(info: EducationDetails!): Education
+			updateEducation(id: ID!, info: EducationDetails!): Education
+			removeEducation(id: ID!): String
+			setSkill(skill_id: ID!, level: Int!): RecruitSkill
+			removeSkill(skill_id: ID!): String
+		}
+
+		type HunterEditor{
+			removeHunter: String
+			updateHunter(info: HunterDetails): Hunter
+
+			createCompany(info: CompanyDetails!): Company
+			updateCompany(info: CompanyDetails!): Company
+			leaveCompany: String
+			inviteHunter(email: String!, role: CompanyRole!): CompanyInvite
+			acceptInvite(id: ID!): Company
+			declineInvite(id: ID!): String
+			setMemberRole(hunter_id: ID!, role: CompanyRole!): Hunter
+			removeMember(hunter_id: ID!): String
+			removeCompanyDocument(id: ID!): String
+
+			createVacancy(info: VacancyDetails!): Vacancy
+			updateVacancy(id: ID!, info: VacancyDetails!): Vacancy
+			closeVacancy(id: ID!): Vacancy
+			removeVacancy(id: ID!): String
+			moveApplication(id: ID!, stage: ApplicationStage!, note: String): Application
+			setApplicationPipeline(stages: [PipelineStageInput!]!): Company
+
+			proposeInterview(application_id: ID!, slots: [InterviewSlotInput!]!, location: String!, notes: String): Interview
+			rescheduleInterview(id: ID!, slots: [InterviewSlotInput!]!, location: String, notes: String): Interview
+			cancelInterview(id: ID!): Interview
+
+			startThread(application_id: ID, vacancy_id: ID, recruit_id: ID, subject: String!, message: String!): Thread
+			sendMessage(thread_id: ID!, body: String!): Message
+			markThreadRead(id: ID!): Thread
+
+			requestContact(recruit_id: ID!, message: String): ContactRequest
+			endorseSkill(recruit_id: ID!, skill_id: ID!): Endorsement
 		}
 		
 		type SysEditor{
-			createQuestion(industry_id: ID!, question: String!): Question
-			createIndustry(name: String!): Industry
+			createQuestion(industry_id: ID!, question: String!, weight: Int, difficulty: QuestionDifficulty): Question
+			createIndustry(name: String!, min_answers: Int, max_answers: Int, parent_id: ID): Industry
+			createSkill(industry_id: ID!, name: String!): Skill
 
 			removeAccount(id: ID!): String
 			removeRecruit(id: ID!): String
 			removeIndustry(id: ID!): String
 			removeQuestion(id: ID!): String
 			removeDocument(id: ID!): String
+			removeSkill(id: ID!): String
+			verifyDocument(id: ID!): Document
 			
-			updateIndustry(id: ID!, name: String!): Industry
-			updateQuestion(id: ID!, question: String!): Question
+			updateIndustry(id: ID!, name: String!, min_answers: Int, max_answers: Int, parent_id: ID): Industry
+			updateQuestion(id: ID!, question: String!, weight: Int, difficulty: QuestionDifficulty): Question
+			archiveQuestion(id: ID!): Question
+			activateQuestion(id: ID!): Question
+			setQuestionTranslation(id: ID!, locale: String!, text: String!): Question
+			removeQuestionTranslation(id: ID!, locale: String!): Question
+			setIndustryTranslation(id: ID!, locale: String!, name: String!): Industry
+			removeIndustryTranslation(id: ID!, locale: String!): Industry
+			updateSkill(id: ID!, name: String!): Skill
+
+			grantCredits(hunter_id: ID!, amount: Int!, reason: String!): CreditEntry
+			adjustCredits(hunter_id: ID!, amount: Int!, reason: String!): CreditEntry
 		}
 	`,
 	Queries: `
@@ -0,0 +1,41 @@
+package schemas
+
+// InterviewSchema graphql schema for interviews
+var InterviewSchema = Schema{
+	Types: `
+		type Interview{
+			id: ID!
+			application: Application
+			vacancy: Vacancy
+			recruit: Recruit
+			slots: [InterviewSlot]!
+			start: Date
+			end: Date
+			location: String!
+			notes: String!
+			status: InterviewStatus!
+			sequence: Int!
+			ics_url: String
+		}
+
+		type InterviewSlot{
+			start: Date!
+			end: Date!
+		}
+
+		input InterviewSlotInput{
+			start: Date!
+			end: Date!
+		}
+
+		enum InterviewStatus{
+			PROPOSED
+			SCHEDULED
+			CANCELLED
+		}
+	`,
+	Queries: `
+	`,
+	Mutations: `
+	`,
+}
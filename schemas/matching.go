@@ -0,0 +1,36 @@
+package schemas
+
+// MatchingSchema graphql schema for recruit and vacancy recommendations
+var MatchingSchema = Schema{
+	Types: `
+		type RecruitRecommendation{
+			recruit: Recruit
+			score: Int!
+			components: [ScoreComponent]!
+		}
+
+		type VacancyRecommendation{
+			vacancy: Vacancy
+			score: Int!
+			components: [ScoreComponent]!
+		}
+
+		type ScoreComponent{
+			name: MatchComponent!
+			score: Int!
+			max: Int!
+			explanation: String!
+		}
+
+		enum MatchComponent{
+			INDUSTRY
+			LOCATION
+			ANSWERS
+			AVAILABILITY
+		}
+	`,
+	Queries: `
+	`,
+	Mutations: `
+	`,
+}
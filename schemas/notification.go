@@ -0,0 +1,42 @@
+package schemas
+
+// NotificationSchema graphql schema for notifications
+var NotificationSchema = Schema{
+	Types: `
+		type Notifications{
+			unread: Int!
+			items(unread_only: Boolean, first: Int, skip: Int): [Notification]!
+			preferences: [NotificationPreference]!
+		}
+
+		type Notification{
+			id: ID!
+			event: NotificationEvent!
+			title: String!
+			body: String!
+			subject_id: ID
+			read: Boolean!
+			created_at: Date!
+		}
+
+		type NotificationPreference{
+			event: NotificationEvent!
+			in_app: Boolean!
+			email: Boolean!
+		}
+
+		enum NotificationEvent{
+			ACCOUNT_CREATED
+			PROFILE_UPDATED
+			APPLICATION_MOVED
+			DOCUMENT_VERIFIED
+			NEW_MESSAGE
+			CONTACT_REQUESTED
+			CONTACT_ANSWERED
+		}
+	`,
+	Queries: `
+	`,
+	Mutations: `
+	`,
+}
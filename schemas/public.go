@@ -27,16 +27,58 @@ var PublicSchema = Schema{
 		type Industry{
 			id: ID!
 			name: String!
+			min_answers: Int!
+			max_answers: Int!
+			parent_id: ID
+			children: [Industry]!
+			ancestors: [Industry]!
+			translations: [Translation]!
 		}
 
 		type Question{
 			id: ID!
 			industry_id: ID!
 			question: String!
+			revision: Int!
+			revisions: [QuestionRevision]!
+			status: QuestionStatus!
+			weight: Int!
+			difficulty: QuestionDifficulty!
+			translations: [Translation]!
 		}
 
-		type QA{
+		type Translation{
+			locale: String!
+			text: String!
+		}
+
+		enum QuestionStatus{
+			ACTIVE
+			ARCHIVED
+		}
+
+		enum QuestionDifficulty{
+			EASY
+			MEDIUM
+			HARD
+		}
+
+		type QuestionRevision{
+			revision: Int!
 			question: String!
+			created_at: Date
+		}
+
+		type QuestionRevisionAnswers{
+			revision: QuestionRevision!
+			answers: Int!
+		}
+
+		type QA{
+			question_id: ID!
+			question: Question
+			revision: Int!
+			question_revision: QuestionRevision
 			answer: String!
 		}
 
@@ -55,8 +97,45 @@ var PublicSchema = Schema{
 			disability: String!
 			vid1_url: String!
 			vid2_url: String!		
-			qa1: QA!
-			qa2: QA!
+			answers: [QA]!
+			contact_unlocked: Boolean!
+			work_experience: [WorkExperience]!
+			education: [Education]!
+			skills: [RecruitSkill]!
+			industries: [Industry]!
+		}
+
+		type WorkExperience{
+			id: ID!
+			employer: String!
+			title: String!
+			start_date: Date!
+			end_date: Date
+			current: Boolean!
+			description: String!
+		}
+
+		input WorkExperienceDetails{
+			employer: String!
+			title: String!
+			start_date: Date!
+			end_date: Date
+			description: String
+		}
+
+		type Education{
+			id: ID!
+			institution: String!
+			qualification: String!
+			nqf_level: Int!
+			year: Int!
+		}
+
+		input EducationDetails{
+			institution: String!
+			qualification: String!
+			nqf_level: Int!
+			year: Int!
 		}
 
 		input RecruitDetails{
@@ -69,10 +148,25 @@ var PublicSchema = Schema{
 			phone: String
 			email: String
 			birth_year: Int
-			qa1_question_id: ID
-			qa1_answer: String
-			qa2_question_id: ID
-			qa2_answer: String
+			answers: [QaDetails!]
+			industry_ids: [ID!]
+		}
+
+		type Hunter{
+			id: ID!
+			name: String!
+			surname: String!
+			phone: String!
+			email: String!
+			position: String!
+			company_id: ID!
+			role: CompanyRole
+		}
+
+		input HunterDetails{
+			phone: String
+			email: String
+			position: String
 		}
 
 		enum Province{
@@ -94,7 +188,7 @@ var PublicSchema = Schema{
 	`,
 	Queries: `
 		industries:[Industry]!
-		randomQuestions(industry_id: ID!): [Question]!
+		randomQuestions(industry_id: ID!, count: Int, difficulty: QuestionDifficulty, token: String, inherited: Boolean): [Question]!
 	`,
 	Mutations: `
 		createAccount(info: AccountDetails!): Tokens
@@ -0,0 +1,34 @@
+package schemas
+
+// RecruitSearchSchema graphql schema for searching recruits
+var RecruitSearchSchema = Schema{
+	Types: `
+		input RecruitFilter{
+			province: Province
+			city: String
+			gender: Gender
+			min_age: Int
+			max_age: Int
+			has_disability: Boolean
+			industry_id: ID
+			skill_ids: [ID!]
+			text: String
+		}
+
+		enum RecruitSort{
+			YOUNGEST
+			OLDEST
+			PROVINCE
+			CITY
+		}
+
+		type RecruitSearchResult{
+			total: Int!
+			recruits: [Recruit]!
+		}
+	`,
+	Queries: `
+	`,
+	Mutations: `
+	`,
+}
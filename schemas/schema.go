@@ -16,6 +16,19 @@ var DefaultSchemas = []Schema{
 	PublicSchema,
 	ViewerSchema,
 	DocumentSchema,
+	CompanySchema,
+	VacancySchema,
+	ApplicationSchema,
+	InterviewSchema,
+	ThreadSchema,
+	ContactSchema,
+	CreditSchema,
+	CompletenessSchema,
+	SkillSchema,
+	NotificationSchema,
+	RecruitSearchSchema,
+	SearchSchema,
+	MatchingSchema,
 	EditorSchema,
 }
 
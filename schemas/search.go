@@ -0,0 +1,31 @@
+package schemas
+
+// SearchSchema graphql schema for full-text search
+var SearchSchema = Schema{
+	Types: `
+		type SearchHit{
+			id: ID!
+			kind: SearchKind!
+			score: Float!
+			highlights: [Highlight]!
+			recruit: Recruit
+			question: Question
+			document: Document
+		}
+
+		type Highlight{
+			field: String!
+			fragment: String!
+		}
+
+		enum SearchKind{
+			RECRUIT
+			QUESTION
+			DOCUMENT
+		}
+	`,
+	Queries: `
+	`,
+	Mutations: `
+	`,
+}
@@ -0,0 +1,32 @@
+package schemas
+
+// SkillSchema graphql schema for the skills taxonomy and endorsements
+var SkillSchema = Schema{
+	Types: `
+		type Skill{
+			id: ID!
+			industry_id: ID!
+			name: String!
+		}
+
+		type RecruitSkill{
+			skill: Skill!
+			level: Int!
+			endorsements: Int!
+		}
+
+		type Endorsement{
+			id: ID!
+			recruit_id: ID!
+			skill_id: ID!
+			hunter_id: ID!
+			company_id: ID!
+			created_at: Date!
+		}
+	`,
+	Queries: `
+		skills(industry_id: ID): [Skill]!
+	`,
+	Mutations: `
+	`,
+}
@@ -0,0 +1,38 @@
+package schemas
+
+// ThreadSchema graphql schema for messaging threads
+var ThreadSchema = Schema{
+	Types: `
+		type Thread{
+			id: ID!
+			subject: String!
+			application: Application
+			vacancy: Vacancy
+			company: Company
+			recruit: Recruit
+			messages(first: Int, skip: Int): [Message]!
+			last_message: Message
+			unread: Int!
+			created_at: Date!
+			updated_at: Date!
+		}
+
+		type Message{
+			id: ID!
+			body: String!
+			sender_role: Participant!
+			sender_name: String!
+			mine: Boolean!
+			created_at: Date!
+		}
+
+		enum Participant{
+			RECRUIT
+			HUNTER
+		}
+	`,
+	Queries: `
+	`,
+	Mutations: `
+	`,
+}
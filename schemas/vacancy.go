@@ -0,0 +1,63 @@
+package schemas
+
+// VacancySchema graphql schema for vacancies
+var VacancySchema = Schema{
+	Types: `
+		type Vacancy{
+			id: ID!
+			title: String!
+			description: String!
+			industry: Industry
+			company: Company
+			province: Province!
+			city: String!
+			employment_type: EmploymentType!
+			salary_min: Int!
+			salary_max: Int!
+			closing_date: Date!
+			created_at: Date!
+			status: VacancyStatus!
+		}
+
+		input VacancyDetails{
+			title: String
+			description: String
+			industry_id: ID
+			province: Province
+			city: String
+			employment_type: EmploymentType
+			salary_min: Int
+			salary_max: Int
+			closing_date: Date
+			status: VacancyStatus
+		}
+
+		input VacancyFilter{
+			industry_id: ID
+			company_id: ID
+			province: Province
+			city: String
+			employment_type: EmploymentType
+			min_salary: Int
+		}
+
+		enum EmploymentType{
+			FULL_TIME
+			PART_TIME
+			CONTRACT
+			TEMPORARY
+			INTERNSHIP
+		}
+
+		enum VacancyStatus{
+			DRAFT
+			OPEN
+			CLOSED
+		}
+	`,
+	Queries: `
+		vacancies(filter: VacancyFilter): [Vacancy]!
+	`,
+	Mutations: `
+	`,
+}
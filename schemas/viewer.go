@@ -9,6 +9,7 @@ var ViewerSchema = Schema{
 			name: String!
 			surname: String!
 			email: String!
+			notifications: Notifications!
 		}
 
 		type AccountViewer implements Viewer{
@@ -16,6 +17,7 @@ var ViewerSchema = Schema{
 			name: String!
 			surname: String!
 			email:  String!
+			notifications: Notifications!
 			is_hunter: Boolean!
 			is_recruit:  Boolean!
 			checkPassword(password: String!): Boolean!
@@ -27,26 +29,57 @@ var ViewerSchema = Schema{
 			name: String!
 			surname: String!
 			email: String!
+			notifications: Notifications!
 			profile: Recruit
+			completeness: ProfileCompleteness!
+			applications: [Application]!
+			recommendedVacancies(first: Int): [VacancyRecommendation]!
+			interviews: [Interview]!
+			threads: [Thread]!
+			thread(id: ID!): Thread
+			unread_messages: Int!
+			privacy: RecruitPrivacy!
+			contact_requests(status: ContactRequestStatus): [ContactRequest]!
 		}
 		
-		#type HunterViewer implements Viewer{
-		#	id: ID!
-		#	name: String!
-		#	surname: String!
-		#	email: String!
-		#	recruit(id:ID!): Recruit
-		#}
+		type HunterViewer implements Viewer{
+			id: ID!
+			name: String!
+			surname: String!
+			email: String!
+			notifications: Notifications!
+			profile: Hunter
+			recruit(id: ID!): Recruit
+			searchRecruits(filter: RecruitFilter, sort: RecruitSort, first: Int, skip: Int): RecruitSearchResult!
+			search(query: String!, kinds: [SearchKind!], first: Int): [SearchHit]!
+			company: Company
+			invites: [CompanyInvite]!
+			vacancies: [Vacancy]!
+			applicants(vacancy_id: ID!, stage: ApplicationStage): [Application]!
+			recommendedRecruits(vacancy_id: ID!, skill_ids: [ID!], first: Int): [RecruitRecommendation]!
+			interviews(vacancy_id: ID): [Interview]!
+			threads: [Thread]!
+			thread(id: ID!): Thread
+			unread_messages: Int!
+			contact_requests(status: ContactRequestStatus): [ContactRequest]!
+			credits: Credits!
+		}
 
 		type SysViewer implements Viewer{
 			id: ID!
 			name: String!
 			surname: String!
 			email: String!
+			notifications: Notifications!
 			accounts: [Account]!
 			recruits: [Recruit]!
+			incompleteRecruits(below: Int!, first: Int, skip: Int): [IncompleteRecruit]!
+			searchRecruits(filter: RecruitFilter, sort: RecruitSort, first: Int, skip: Int): RecruitSearchResult!
+			search(query: String!, kinds: [SearchKind!], first: Int): [SearchHit]!
 			questions: [Question]!
+			questionRevisions(question_id: ID!): [QuestionRevisionAnswers]!
 			documents: [Document]!
+			contact_unlocks(recruit_id: ID): [ContactUnlock]!
 		}
 
 		enum Enforce{
@@ -0,0 +1,94 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stopWords are terms too common to be worth indexing
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "in": true, "is": true,
+	"it": true, "of": true, "on": true, "or": true, "that": true, "the": true,
+	"this": true, "to": true, "was": true, "with": true,
+}
+
+// token is a term found in a piece of text, along with its byte offsets
+type token struct {
+	term  string
+	start int
+	end   int
+}
+
+// tokenize splits text into normalised terms, skipping stop words
+func tokenize(text string) []token {
+	tokens := make([]token, 0)
+	start := -1
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		word := text[start:end]
+		start = -1
+
+		term := normalize(word)
+		if term == "" || stopWords[term] {
+			return
+		}
+		tokens = append(tokens, token{term, end - len(word), end})
+	}
+
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		// keep contractions like "don't" together
+		if r == '\'' && start >= 0 && i+1 < len(text) && isWordByte(text[i+1]) {
+			continue
+		}
+		flush(i)
+	}
+	flush(len(text))
+	return tokens
+}
+
+// isWordByte checks if an ASCII byte is a letter or digit
+func isWordByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9'
+}
+
+// normalize lower cases a word and strips common English suffixes so that
+// e.g. "designs", "designed" and "designing" all match "design"
+func normalize(word string) string {
+	term := strings.ToLower(word)
+	term = strings.TrimSuffix(term, "'s")
+	term = strings.Replace(term, "'", "", -1)
+
+	switch {
+	case strings.HasSuffix(term, "ies") && len(term) > 4:
+		return strings.TrimSuffix(term, "ies") + "y"
+	case hasAnySuffix(term, "sses", "xes", "ches", "shes", "zes"):
+		return strings.TrimSuffix(term, "es")
+	case strings.HasSuffix(term, "ss") || strings.HasSuffix(term, "us"):
+		return term
+	}
+	for _, suffix := range []string{"ing", "ed", "s"} {
+		if strings.HasSuffix(term, suffix) && len(term)-len(suffix) >= 3 {
+			return strings.TrimSuffix(term, suffix)
+		}
+	}
+	return term
+}
+
+// hasAnySuffix checks if s ends with any of the given suffixes
+func hasAnySuffix(s string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
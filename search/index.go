@@ -0,0 +1,250 @@
+package search
+
+import (
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BM25 ranking parameters
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// fragmentRadius is the number of bytes of context kept either side of a highlighted match
+const fragmentRadius = 40
+
+// Document is a searchable entity, made up of named text fields
+type Document struct {
+	ID     string
+	Kind   string
+	Fields map[string]string
+}
+
+// Highlight is a fragment of a matching field, with the matched terms wrapped in <em> tags
+type Highlight struct {
+	Field    string
+	Fragment string
+}
+
+// Hit is a Document matching a search
+type Hit struct {
+	ID         string
+	Kind       string
+	Score      float64
+	Highlights []Highlight
+}
+
+// entry is an indexed Document
+type entry struct {
+	doc    Document
+	length int
+}
+
+// Index is an in-memory inverted index, safe for concurrent use
+type Index struct {
+	mu          sync.RWMutex
+	entries     map[string]*entry
+	postings    map[string]map[string]int
+	totalLength int
+}
+
+// NewIndex creates an empty Index
+func NewIndex() *Index {
+	return &Index{
+		entries:  make(map[string]*entry),
+		postings: make(map[string]map[string]int),
+	}
+}
+
+// key identifies a Document within the Index
+func key(kind, id string) string {
+	return kind + ":" + id
+}
+
+// Put adds a Document to the Index, replacing any Document with the same kind and ID
+func (idx *Index) Put(doc Document) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	k := key(doc.Kind, doc.ID)
+	idx.remove(k)
+
+	e := &entry{doc: doc}
+	for _, text := range doc.Fields {
+		for _, t := range tokenize(text) {
+			if idx.postings[t.term] == nil {
+				idx.postings[t.term] = make(map[string]int)
+			}
+			idx.postings[t.term][k]++
+			e.length++
+		}
+	}
+	idx.entries[k] = e
+	idx.totalLength += e.length
+}
+
+// Delete removes a Document from the Index
+func (idx *Index) Delete(kind, id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(key(kind, id))
+}
+
+// remove removes an entry, the caller must hold the write lock
+func (idx *Index) remove(k string) {
+	e, ok := idx.entries[k]
+	if !ok {
+		return
+	}
+	for _, text := range e.doc.Fields {
+		for _, t := range tokenize(text) {
+			delete(idx.postings[t.term], k)
+			if len(idx.postings[t.term]) == 0 {
+				delete(idx.postings, t.term)
+			}
+		}
+	}
+	idx.totalLength -= e.length
+	delete(idx.entries, k)
+}
+
+// Len returns the number of indexed Documents
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.entries)
+}
+
+// Search finds the Documents matching any of the query's terms, ranked by
+// BM25 relevance. Only Documents of the given kinds are returned, unless
+// no kinds are given. A limit of 0 returns all hits
+func (idx *Index) Search(query string, kinds []string, limit int) []Hit {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	hits := make([]Hit, 0)
+	terms := make(map[string]bool)
+	for _, t := range tokenize(query) {
+		terms[t.term] = true
+	}
+	if len(terms) == 0 || len(idx.entries) == 0 {
+		return hits
+	}
+
+	allowed := make(map[string]bool)
+	for _, kind := range kinds {
+		allowed[kind] = true
+	}
+
+	// score matching documents
+	n := float64(len(idx.entries))
+	avgLength := float64(idx.totalLength) / n
+	scores := make(map[string]float64)
+	for term := range terms {
+		postings := idx.postings[term]
+		df := float64(len(postings))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for k, tf := range postings {
+			e := idx.entries[k]
+			if len(allowed) > 0 && !allowed[e.doc.Kind] {
+				continue
+			}
+			norm := k1 * (1 - b + b*float64(e.length)/avgLength)
+			scores[k] += idf * float64(tf) * (k1 + 1) / (float64(tf) + norm)
+		}
+	}
+
+	for k, score := range scores {
+		e := idx.entries[k]
+		hits = append(hits, Hit{
+			ID:         e.doc.ID,
+			Kind:       e.doc.Kind,
+			Score:      score,
+			Highlights: highlight(e.doc, terms),
+		})
+	}
+
+	// best matches first, ties broken by kind and id to keep results stable
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return key(hits[i].Kind, hits[i].ID) < key(hits[j].Kind, hits[j].ID)
+	})
+	if limit > 0 && limit < len(hits) {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// highlight creates a Highlight for each of the Document's fields which contain any of the terms
+func highlight(doc Document, terms map[string]bool) []Highlight {
+	fields := make([]string, 0, len(doc.Fields))
+	for field := range doc.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	highlights := make([]Highlight, 0)
+	for _, field := range fields {
+		text := doc.Fields[field]
+		matches := make([]token, 0)
+		for _, t := range tokenize(text) {
+			if terms[t.term] {
+				matches = append(matches, t)
+			}
+		}
+		if len(matches) == 0 {
+			continue
+		}
+
+		// keep some context around the matches
+		start := matches[0].start - fragmentRadius
+		end := matches[len(matches)-1].end + fragmentRadius
+		if start < 0 {
+			start = 0
+		}
+		if end > len(text) {
+			end = len(text)
+		}
+		start, end = wordBoundary(text, start, end)
+
+		var fragment strings.Builder
+		if start > 0 {
+			fragment.WriteString("…")
+		}
+		pos := start
+		for _, m := range matches {
+			if m.start < pos || m.end > end {
+				continue
+			}
+			fragment.WriteString(html.EscapeString(text[pos:m.start]))
+			fragment.WriteString("<em>" + html.EscapeString(text[m.start:m.end]) + "</em>")
+			pos = m.end
+		}
+		fragment.WriteString(html.EscapeString(text[pos:end]))
+		if end < len(text) {
+			fragment.WriteString("…")
+		}
+
+		highlights = append(highlights, Highlight{field, fragment.String()})
+	}
+	return highlights
+}
+
+// wordBoundary widens a fragment's bounds so that it doesn't start or end mid-word
+func wordBoundary(text string, start, end int) (int, int) {
+	// non ASCII bytes are treated as part of a word, which also keeps
+	// the bounds from splitting multi-byte characters
+	for start > 0 && (isWordByte(text[start-1]) || text[start-1] >= 0x80) {
+		start--
+	}
+	for end < len(text) && (isWordByte(text[end]) || text[end] >= 0x80) {
+		end++
+	}
+	return start, end
+}
@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// processSecret signs the urls of LocalStores that aren't given a secret
+var processSecret = func() []byte {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic(err)
+	}
+	return secret
+}()
+
+// LocalStore keeps files in a directory on disk
+type LocalStore struct {
+	Dir string
+	// Secret signs the urls of the files, stores sharing a directory need the same secret
+	Secret []byte
+}
+
+// NewLocalStore creates a LocalStore signing urls with a secret of the process, the
+// directory is created when the first file is stored
+func NewLocalStore(dir string) *LocalStore {
+	return &LocalStore{Dir: dir, Secret: processSecret}
+}
+
+// Put writes a file, it's written to a temporary file first so that a partly
+// written file is never served
+func (s *LocalStore) Put(key string, r io.Reader, size int64, contentType string) error {
+	filePath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+	file, err := ioutil.TempFile(filepath.Dir(filePath), ".put-")
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(file, r)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(file.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), filePath)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+	}
+	return err
+}
+
+// Get opens a file, the returned reader is an *os.File so it can seek
+func (s *LocalStore) Get(key string) (io.ReadCloser, *FileInfo, error) {
+	info, err := s.Stat(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	filePath, _ := s.path(key)
+	file, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		return nil, nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, info, nil
+}
+
+// Stat describes a file, its content type is told by its extension
+func (s *LocalStore) Stat(key string) (*FileInfo, error) {
+	filePath, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	stat, err := os.Stat(filePath)
+	if os.IsNotExist(err) || (err == nil && stat.IsDir()) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &FileInfo{
+		Key:         key,
+		Size:        stat.Size(),
+		ContentType: mime.TypeByExtension(path.Ext(key)),
+		ModTime:     stat.ModTime(),
+	}, nil
+}
+
+// Delete removes a file
+func (s *LocalStore) Delete(key string) error {
+	filePath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// SignedURL is the url the file is served at along with the time it expires and a signature of both
+func (s *LocalStore) SignedURL(key string, expires time.Duration) (string, error) {
+	if err := checkKey(key); err != nil {
+		return "", err
+	}
+	expiry := strconv.FormatInt(time.Now().Add(expires).Unix(), 10)
+	query := url.Values{"expires": {expiry}, "signature": {s.signature(key, expiry)}}
+	return URL(key) + "?" + query.Encode(), nil
+}
+
+// VerifyURL checks that the query of a file's url was signed by the store and hasn't expired,
+// stores without a secret accept none
+func (s *LocalStore) VerifyURL(key string, query url.Values) error {
+	expiry := query.Get("expires")
+	expires, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || len(s.Secret) == 0 || !hmac.Equal([]byte(query.Get("signature")), []byte(s.signature(key, expiry))) ||
+		time.Now().Unix() > expires {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// signature signs a file's key and the time its url expires
+func (s *LocalStore) signature(key, expiry string) string {
+	mac := hmac.New(sha256.New, s.Secret)
+	mac.Write([]byte(key + "\n" + expiry))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path is the path of a file on disk
+func (s *LocalStore) path(key string) (string, error) {
+	if err := checkKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.Dir, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// S3Store keeps files in a bucket of a service that speaks the S3 API, like AWS S3 or MinIO.
+// Objects are addressed by path, https://endpoint/bucket/key, which every such service supports
+type S3Store struct {
+	Endpoint string
+	Bucket   string
+	Signer   *Signer
+	Client   *http.Client
+}
+
+// NewS3Store creates an S3Store for a bucket, endpoint is the service's base url like
+// "https://s3.af-south-1.amazonaws.com" or "http://localhost:9000"
+func NewS3Store(endpoint, region, bucket, accessKey, secretKey string) *S3Store {
+	return &S3Store{
+		Endpoint: strings.TrimSuffix(endpoint, "/"),
+		Bucket:   bucket,
+		Signer:   &Signer{AccessKey: accessKey, SecretKey: secretKey, Region: region, Service: "s3"},
+		Client:   &http.Client{Timeout: 10 * time.Minute},
+	}
+}
+
+// Put uploads a file, files of unknown size are read into memory first since S3 needs the length
+func (s *S3Store) Put(key string, r io.Reader, size int64, contentType string) error {
+	if size < 0 {
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		r, size = bytes.NewReader(data), int64(len(data))
+	}
+	req, err := s.newRequest(http.MethodPut, key, r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if size == 0 {
+		req.Body = http.NoBody
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	// the body isn't signed so that large files don't have to be read twice
+	res, err := s.do(req, UnsignedPayload)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
+
+// Get downloads a file
+func (s *S3Store) Get(key string) (io.ReadCloser, *FileInfo, error) {
+	req, err := s.newRequest(http.MethodGet, key, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	res, err := s.do(req, EmptyPayload)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res.Body, fileInfo(key, res), nil
+}
+
+// Stat describes a file from the headers of a HEAD request
+func (s *S3Store) Stat(key string) (*FileInfo, error) {
+	req, err := s.newRequest(http.MethodHead, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.do(req, EmptyPayload)
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+	return fileInfo(key, res), nil
+}
+
+// Delete removes a file
+func (s *S3Store) Delete(key string) error {
+	req, err := s.newRequest(http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	res, err := s.do(req, EmptyPayload)
+	if err == ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
+
+// SignedURL creates a presigned GET url, S3 allows them to be valid for up to a week
+func (s *S3Store) SignedURL(key string, expires time.Duration) (string, error) {
+	req, err := s.newRequest(http.MethodGet, key, nil)
+	if err != nil {
+		return "", err
+	}
+	return s.Signer.Presign(req, expires, time.Now()), nil
+}
+
+// newRequest creates a request for an object
+func (s *S3Store) newRequest(method, key string, body io.Reader) (*http.Request, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	return http.NewRequest(method, s.Endpoint+"/"+uriEncode(s.Bucket, true)+"/"+uriEncode(key, false), body)
+}
+
+// do signs and sends a request, responses that aren't successful are turned into errors
+func (s *S3Store) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	s.Signer.Sign(req, payloadHash, time.Now())
+	res, err := s.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return res, nil
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+
+	// S3 describes errors in an xml body, HEAD responses don't have one
+	var s3Err struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	xml.NewDecoder(io.LimitReader(res.Body, 64<<10)).Decode(&s3Err)
+	return nil, fmt.Errorf("storage: s3 %s %s: %s %s %s", req.Method, req.URL.Path, res.Status, s3Err.Code, s3Err.Message)
+}
+
+// fileInfo describes a file from the headers of a response
+func fileInfo(key string, res *http.Response) *FileInfo {
+	info := &FileInfo{Key: key, ContentType: res.Header.Get("Content-Type")}
+	if size, err := strconv.ParseInt(res.Header.Get("Content-Length"), 10, 64); err == nil {
+		info.Size = size
+	}
+	if modTime, err := http.ParseTime(res.Header.Get("Last-Modified")); err == nil {
+		info.ModTime = modTime
+	}
+	return info
+}
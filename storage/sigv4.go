@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UnsignedPayload is the payload hash of requests whose body isn't signed
+const UnsignedPayload = "UNSIGNED-PAYLOAD"
+
+// EmptyPayload is the payload hash of requests without a body
+const EmptyPayload = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// sigV4Algorithm names the signing algorithm in requests
+const sigV4Algorithm = "AWS4-HMAC-SHA256"
+
+// amzDateFormat is the format of the time requests are signed at
+const amzDateFormat = "20060102T150405Z"
+
+// Signer signs requests with AWS Signature Version 4, see
+// https://docs.aws.amazon.com/general/latest/gr/signature-version-4.html.
+// Services like MinIO that speak the S3 API accept the same signatures
+type Signer struct {
+	AccessKey string
+	SecretKey string
+	Region    string
+	Service   string
+}
+
+// Sign signs a request in its Authorization header, payloadHash is the hex sha256
+// of the body or UnsignedPayload. Its host, content type, range and x-amz-* headers
+// are signed, so they can't be changed after signing
+func (s *Signer) Sign(req *http.Request, payloadHash string, t time.Time) {
+	t = t.UTC()
+	req.Header.Set("X-Amz-Date", t.Format(amzDateFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	names, canonicalHeaders := s.canonicalHeaders(req)
+	signedHeaders := strings.Join(names, ";")
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	req.Header.Set("Authorization", sigV4Algorithm+" Credential="+s.AccessKey+"/"+s.scope(t)+
+		",SignedHeaders="+signedHeaders+",Signature="+s.signature(t, canonicalRequest))
+}
+
+// Presign creates a url that can be requested without credentials until it expires,
+// only the request's method, url and host are signed
+func (s *Signer) Presign(req *http.Request, expires time.Duration, t time.Time) string {
+	t = t.UTC()
+	query := req.URL.Query()
+	query.Set("X-Amz-Algorithm", sigV4Algorithm)
+	query.Set("X-Amz-Credential", s.AccessKey+"/"+s.scope(t))
+	query.Set("X-Amz-Date", t.Format(amzDateFormat))
+	query.Set("X-Amz-Expires", strconv.FormatInt(int64(expires/time.Second), 10))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL),
+		canonicalQuery(query),
+		"host:" + requestHost(req) + "\n",
+		"host",
+		UnsignedPayload,
+	}, "\n")
+
+	presigned := *req.URL
+	presigned.RawQuery = canonicalQuery(query) + "&X-Amz-Signature=" + s.signature(t, canonicalRequest)
+	return presigned.String()
+}
+
+// scope is the credential scope of requests signed at t
+func (s *Signer) scope(t time.Time) string {
+	return t.Format("20060102") + "/" + s.Region + "/" + s.Service + "/aws4_request"
+}
+
+// signature signs a canonical request with a key derived from the secret key and the request's scope
+func (s *Signer) signature(t time.Time, canonicalRequest string) string {
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := sigV4Algorithm + "\n" + t.Format(amzDateFormat) + "\n" + s.scope(t) + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretKey), t.Format("20060102"))
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, s.Service)
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// canonicalHeaders lists the names of the signed headers and formats them as name:value lines
+func (s *Signer) canonicalHeaders(req *http.Request) ([]string, string) {
+	values := map[string]string{"host": requestHost(req)}
+	for name, v := range req.Header {
+		name = strings.ToLower(name)
+		if name == "content-type" || name == "content-md5" || name == "range" || strings.HasPrefix(name, "x-amz-") {
+			values[name] = strings.Join(strings.Fields(strings.Join(v, ",")), " ")
+		}
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var lines strings.Builder
+	for _, name := range names {
+		lines.WriteString(name + ":" + values[name] + "\n")
+	}
+	return names, lines.String()
+}
+
+// requestHost is the host a request is sent to
+func requestHost(req *http.Request) string {
+	if req.Host != "" {
+		return req.Host
+	}
+	return req.URL.Host
+}
+
+// canonicalURI is the escaped path of a url
+func canonicalURI(u *url.URL) string {
+	if p := u.EscapedPath(); p != "" {
+		return p
+	}
+	return "/"
+}
+
+// canonicalQuery sorts query parameters by name and escapes them the way signatures expect
+func canonicalQuery(query url.Values) string {
+	pairs := make([][2]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, [2]string{uriEncode(name, true), uriEncode(value, true)})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i][0] != pairs[j][0] {
+			return pairs[i][0] < pairs[j][0]
+		}
+		return pairs[i][1] < pairs[j][1]
+	})
+
+	encoded := make([]string, len(pairs))
+	for i, pair := range pairs {
+		encoded[i] = pair[0] + "=" + pair[1]
+	}
+	return strings.Join(encoded, "&")
+}
+
+// uriEncode escapes everything but unreserved characters, and slashes unless encodeSlash is set
+func uriEncode(s string, encodeSlash bool) string {
+	const hexDigits = "0123456789ABCDEF"
+	var encoded strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && !encodeSlash:
+			encoded.WriteByte(c)
+		default:
+			encoded.WriteByte('%')
+			encoded.WriteByte(hexDigits[c>>4])
+			encoded.WriteByte(hexDigits[c&15])
+		}
+	}
+	return encoded.String()
+}
+
+// hmacSHA256 signs data with a key
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// URLPrefix is the path stored files are served under, a file's url is the prefix followed by its key
+const URLPrefix = "/file/"
+
+// ErrNotFound is returned for files that aren't stored
+var ErrNotFound = errors.New("storage: file not found")
+
+// ErrInvalidKey is returned for keys that aren't relative slash separated paths
+var ErrInvalidKey = errors.New("storage: invalid key")
+
+// ErrInvalidSignature is returned for file urls that weren't signed by the store or have expired
+var ErrInvalidSignature = errors.New("storage: invalid or expired signature")
+
+// SignedURLExpiry is how long the signed urls handed out to clients can be used
+const SignedURLExpiry = time.Hour
+
+// Store keeps files by key, a slash separated path like "uploads/5c1a.mp4"
+type Store interface {
+	// Put stores a file of size bytes, replacing the file with the same key.
+	// A negative size means the size isn't known
+	Put(key string, r io.Reader, size int64, contentType string) error
+	// Get opens a file, the caller closes it
+	Get(key string) (io.ReadCloser, *FileInfo, error)
+	// Stat describes a file
+	Stat(key string) (*FileInfo, error)
+	// Delete removes a file, removing a file that isn't stored isn't an error
+	Delete(key string) error
+	// SignedURL creates a url the file can be downloaded from without credentials until it expires
+	SignedURL(key string, expires time.Duration) (string, error)
+}
+
+// URLVerifier is implemented by stores whose signed urls are served by this server
+// rather than by their backend
+type URLVerifier interface {
+	// VerifyURL checks the query of a signed url of a file
+	VerifyURL(key string, query url.Values) error
+}
+
+// FileInfo describes a stored file
+type FileInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// NewStoreFromEnv creates the Store configured by the environment. STORAGE_BACKEND
+// selects "s3", configured by S3_ENDPOINT, S3_REGION, S3_BUCKET, S3_ACCESS_KEY and
+// S3_SECRET_KEY, or "local", the default, which keeps files in STATIC_FILE_DIR and signs
+// their urls with FILE_URL_SECRET. Without a secret urls are signed with a random one, so
+// they're only accepted by the process that signed them
+func NewStoreFromEnv() Store {
+	dir := os.Getenv("STATIC_FILE_DIR")
+	if dir == "" {
+		dir = "./files"
+	}
+	switch os.Getenv("STORAGE_BACKEND") {
+	case "s3":
+		region := os.Getenv("S3_REGION")
+		if region == "" {
+			region = "us-east-1"
+		}
+		return NewS3Store(os.Getenv("S3_ENDPOINT"), region, os.Getenv("S3_BUCKET"), os.Getenv("S3_ACCESS_KEY"), os.Getenv("S3_SECRET_KEY"))
+	case "", "local":
+	default:
+		log.Printf("Unknown STORAGE_BACKEND %q, storing files in %s instead.", os.Getenv("STORAGE_BACKEND"), dir)
+	}
+	store := NewLocalStore(dir)
+	if secret := os.Getenv("FILE_URL_SECRET"); secret != "" {
+		store.Secret = []byte(secret)
+	}
+	return store
+}
+
+// contextKey keys the Store of a request's context
+type contextKey struct{}
+
+// NewContext returns a context carrying the store that signs the urls of the files a request refers to
+func NewContext(ctx context.Context, store Store) context.Context {
+	return context.WithValue(ctx, contextKey{}, store)
+}
+
+// FromContext gets the store a context carries, it's nil when there's none
+func FromContext(ctx context.Context) Store {
+	store, _ := ctx.Value(contextKey{}).(Store)
+	return store
+}
+
+// URL is the url a stored file is served at
+func URL(key string) string {
+	return URLPrefix + key
+}
+
+// KeyFromURL finds the key of a file served at url, it's false for urls that aren't of stored files
+func KeyFromURL(url string) (string, bool) {
+	if !strings.HasPrefix(url, URLPrefix) {
+		return "", false
+	}
+	key := strings.TrimPrefix(url, URLPrefix)
+	if checkKey(key) != nil {
+		return "", false
+	}
+	return key, true
+}
+
+// checkKey checks that a key is a clean relative path that stays within the store
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") || path.Clean(key) != key ||
+		key == ".." || strings.HasPrefix(key, "../") {
+		return ErrInvalidKey
+	}
+	return nil
+}
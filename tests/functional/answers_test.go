@@ -0,0 +1,127 @@
+package functionaltests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	config "../../config"
+	moc "../../mocks"
+	models "../../models"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// answerDetails formats the details of answers to the given questions
+func answerDetails(questions ...models.Question) string {
+	details := ""
+	for _, q := range questions {
+		details += fmt.Sprintf(`{question_id: "%s", answer: "An answer."},`, q.ID.Hex())
+	}
+	return "[" + details + "]"
+}
+
+// tests that the answers of recruits stay within their industries' limits
+func TestRecruitEditor_UpdateQAsLimits(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	recruitToken, _ := login(crud, moc.Accounts[0].ID, "none")
+	sysToken, _ := login(crud, getSysUserAccount().ID, "none")
+	edit := func(token, enforce, editor, field string) map[string]interface{} {
+		response, err := gqlRequestAndRespond(handler, fmt.Sprintf(editMutation, token, enforce, editor, field), nil)
+		failOnError(assert, err)
+		return response
+	}
+
+	// Industries[0] takes at most 3 answers
+	extra := models.Question{ID: bson.NewObjectId(), IndustryID: moc.Industries[0].ID}
+	extra.Revise("What's your favourite number?", time.Now())
+	failOnError(assert, crud.Insert(config.QuestionsCollection, extra))
+	response := edit(recruitToken, "RECRUIT", "Recruit", fmt.Sprintf(`updateQAs(answers: %s){ answer }`,
+		answerDetails(moc.Questions[0], moc.Questions[2], moc.Questions[4], extra)))
+	assert.NotNil(response["errors"], msgInvalidResult)
+
+	response = edit(recruitToken, "RECRUIT", "Recruit", fmt.Sprintf(`updateQAs(answers: %s){ answer }`,
+		answerDetails(moc.Questions[0], moc.Questions[2], extra, moc.Questions[1])))
+	data := assertGqlData("edit", response, assert)
+	assert.Len(data["edit"].(map[string]interface{})["updateQAs"], 4)
+
+	// after raising the minimum a single answer isn't enough
+	response = edit(sysToken, "SYSTEM", "Sys", fmt.Sprintf(`updateIndustry(id: "%s", name: "Architecture", min_answers: 2){ min_answers max_answers }`,
+		moc.Industries[1].ID.Hex()))
+	data = assertGqlData("edit", response, assert)
+	assert.Equal(map[string]interface{}{"min_answers": float64(2), "max_answers": float64(models.DefaultMaxAnswers)},
+		data["edit"].(map[string]interface{})["updateIndustry"], msgInvalidResult)
+	response = edit(recruitToken, "RECRUIT", "Recruit", fmt.Sprintf(`updateQAs(answers: %s){ answer }`,
+		answerDetails(moc.Questions[0], moc.Questions[1])))
+	assert.NotNil(response["errors"], msgInvalidResult)
+
+	// the minimum can't exceed the maximum
+	response = edit(sysToken, "SYSTEM", "Sys", fmt.Sprintf(`updateIndustry(id: "%s", name: "Architecture", min_answers: 4, max_answers: 3){ id }`,
+		moc.Industries[1].ID.Hex()))
+	assert.NotNil(response["errors"], msgInvalidResult)
+
+	// questions need to exist and can only be answered once
+	missing := models.Question{ID: bson.NewObjectId()}
+	for _, answers := range []string{
+		answerDetails(missing),
+		answerDetails(moc.Questions[0], moc.Questions[0]),
+		"[]",
+	} {
+		response = edit(recruitToken, "RECRUIT", "Recruit", fmt.Sprintf(`updateQAs(answers: %s){ answer }`, answers))
+		assert.NotNil(response["errors"], msgInvalidResult)
+	}
+
+	rawRecruit, err := crud.FindID(config.RecruitsCollection, moc.Recruits[0].ID)
+	failOnError(assert, err)
+	assert.Len(models.TransformRecruit(rawRecruit).Answers, 4)
+}
+
+// tests that hunters see which question was answered, even after it was removed
+func TestRecruit_Answers(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	failOnError(assert, crud.DeleteID(config.QuestionsCollection, moc.Questions[4].ID))
+
+	token, _ := login(crud, moc.Accounts[0].ID, "none")
+	query := fmt.Sprintf(`
+		query{
+			view(token: "%s", enforce: RECRUIT){
+				... on RecruitViewer{
+					profile{
+						answers{
+							question_id
+							question{
+								id
+								industry_id
+							}
+							answer
+						}
+					}
+				}
+			}
+		}
+	`, token)
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+	data := assertGqlData("view", response, assert)
+	assert.Equal([]interface{}{
+		map[string]interface{}{
+			"question_id": moc.Questions[0].ID.Hex(),
+			"question": map[string]interface{}{
+				"id":          moc.Questions[0].ID.Hex(),
+				"industry_id": moc.Industries[0].ID.Hex(),
+			},
+			"answer": "Blue, like the ocean.",
+		},
+		map[string]interface{}{
+			"question_id": moc.Questions[4].ID.Hex(),
+			"question":    nil,
+			"answer":      "Butternut, I know it's basic.",
+		},
+	}, data["view"].(map[string]interface{})["profile"].(map[string]interface{})["answers"], msgInvalidResult)
+}
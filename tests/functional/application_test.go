@@ -0,0 +1,443 @@
+package functionaltests
+
+import (
+	"fmt"
+	"testing"
+
+	config "../../config"
+	moc "../../mocks"
+	models "../../models"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// tests that RecruitViewer.Applications lists the Recruit's applications
+func TestRecruitViewer_Applications(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Recruits[0]
+	token, _ := login(crud, moc.Accounts[0].ID, "none")
+
+	// prepare query
+	query := fmt.Sprintf(`
+		query{
+			view(token: "%s", enforce: RECRUIT){
+				... on RecruitViewer{
+					applications{
+						id
+						stage
+						vacancy{
+							id
+						}
+					}
+				}
+			}
+		}
+	`, token)
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	// prepare expected
+	applications := make([]interface{}, 0)
+	for _, i := range []int{0, 2} {
+		application := moc.Applications[i]
+		applications = append(applications, map[string]interface{}{
+			"id":      application.ID.Hex(),
+			"stage":   application.Stage,
+			"vacancy": map[string]interface{}{"id": application.VacancyID.Hex()},
+		})
+	}
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"view": map[string]interface{}{
+				"applications": applications,
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests that HunterViewer.Applicants lists the applications to a Company's vacancy
+func TestHunterViewer_Applicants(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Companies[0] owner
+	token, _ := login(crud, moc.Accounts[2].ID, "none")
+
+	// prepare query
+	queryFormat := `
+		query{
+			view(token: "%s", enforce: HUNTER){
+				... on HunterViewer{
+					applicants(vacancy_id: "%s"%s){
+						id
+						recruit{
+							id
+						}
+					}
+				}
+			}
+		}
+	`
+
+	input := []string{``, `, stage: SCREENED`, `, stage: HIRED`}
+	output := [][]int{{0, 1}, {1}, {}}
+
+	for i, in := range input {
+		// request
+		query := fmt.Sprintf(queryFormat, token, moc.Vacancies[0].ID.Hex(), in)
+		response, err := gqlRequestAndRespond(handler, query, nil)
+		failOnError(assert, err)
+
+		// prepare expected
+		applicants := make([]interface{}, 0)
+		for _, index := range output[i] {
+			application := moc.Applications[index]
+			applicants = append(applicants, map[string]interface{}{
+				"id":      application.ID.Hex(),
+				"recruit": map[string]interface{}{"id": application.RecruitID.Hex()},
+			})
+		}
+		expected := map[string]interface{}{
+			"data": map[string]interface{}{
+				"view": map[string]interface{}{
+					"applicants": applicants,
+				},
+			},
+		}
+
+		assert.Equal(expected, response, fmt.Sprintf("Case [%v]: %s", i+1, msgInvalidResult))
+	}
+}
+
+// tests that RecruitEditor.Apply creates an Application in the APPLIED stage
+func TestRecruitEditor_Apply(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Recruits[1]
+	token, _ := login(crud, moc.Accounts[1].ID, "none")
+	vacancy := moc.Vacancies[2]
+
+	// prepare query
+	query := fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: RECRUIT){
+				... on RecruitEditor{
+					apply(vacancy_id: "%s"){
+						stage
+						vacancy{
+							id
+						}
+						recruit{
+							id
+						}
+						history{
+							stage
+						}
+					}
+				}
+			}
+		}
+	`, token, vacancy.ID.Hex())
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	// prepare expected
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"edit": map[string]interface{}{
+				"apply": map[string]interface{}{
+					"stage":   "APPLIED",
+					"vacancy": map[string]interface{}{"id": vacancy.ID.Hex()},
+					"recruit": map[string]interface{}{"id": moc.Recruits[1].ID.Hex()},
+					"history": []interface{}{
+						map[string]interface{}{"stage": "APPLIED"},
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests that HunterEditor.MoveApplication moves an Application and records the change
+func TestHunterEditor_MoveApplication(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Companies[0] recruiter
+	token, _ := login(crud, moc.Accounts[3].ID, "none")
+	application := moc.Applications[1]
+	note := "Invited for Tuesday."
+
+	// prepare query
+	query := fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: HUNTER){
+				... on HunterEditor{
+					moveApplication(id: "%s", stage: INTERVIEW, note: "%s"){
+						id
+						stage
+						history{
+							stage
+							note
+						}
+					}
+				}
+			}
+		}
+	`, token, application.ID.Hex(), note)
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	// prepare expected
+	history := make([]interface{}, 0)
+	for _, change := range application.History {
+		history = append(history, map[string]interface{}{
+			"stage": change.Stage,
+			"note":  change.Note,
+		})
+	}
+	history = append(history, map[string]interface{}{
+		"stage": "INTERVIEW",
+		"note":  note,
+	})
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"edit": map[string]interface{}{
+				"moveApplication": map[string]interface{}{
+					"id":      application.ID.Hex(),
+					"stage":   "INTERVIEW",
+					"history": history,
+				},
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests that HunterEditor.SetApplicationPipeline configures the stages applications are moved through
+func TestHunterEditor_SetApplicationPipeline(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Companies[0] owner and recruiter
+	ownerToken, _ := login(crud, moc.Accounts[2].ID, "none")
+	recruiterToken, _ := login(crud, moc.Accounts[3].ID, "none")
+	move := func(id bson.ObjectId, stage string) map[string]interface{} {
+		response, err := gqlRequestAndRespond(handler, fmt.Sprintf(`
+			mutation{
+				edit(token: "%s", enforce: HUNTER){
+					... on HunterEditor{
+						moveApplication(id: "%s", stage: %s){ stage }
+					}
+				}
+			}
+		`, recruiterToken, id.Hex(), stage), nil)
+		failOnError(assert, err)
+		return response
+	}
+
+	// configure a pipeline without interviews that can hire straight away
+	response, err := gqlRequestAndRespond(handler, fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: HUNTER){
+				... on HunterEditor{
+					setApplicationPipeline(stages: [
+						{stage: APPLIED, next: [SCREENED, HIRED, REJECTED]},
+						{stage: SCREENED, next: [HIRED, REJECTED]},
+						{stage: HIRED, next: []},
+						{stage: REJECTED, next: []}
+					]){
+						pipeline{
+							stage
+							next
+						}
+					}
+				}
+			}
+		}
+	`, ownerToken), nil)
+	failOnError(assert, err)
+	pipeline := []interface{}{
+		map[string]interface{}{"stage": "APPLIED", "next": []interface{}{"SCREENED", "HIRED", "REJECTED"}},
+		map[string]interface{}{"stage": "SCREENED", "next": []interface{}{"HIRED", "REJECTED"}},
+		map[string]interface{}{"stage": "HIRED", "next": []interface{}{}},
+		map[string]interface{}{"stage": "REJECTED", "next": []interface{}{}},
+	}
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"edit": map[string]interface{}{
+				"setApplicationPipeline": map[string]interface{}{
+					"pipeline": pipeline,
+				},
+			},
+		},
+	}
+	assert.Equal(expected, response, msgInvalidResult)
+
+	// applications are moved through the configured stages
+	assert.Contains(move(moc.Applications[1].ID, "INTERVIEW"), "errors", msgNoError)
+	response = move(moc.Applications[0].ID, "HIRED")
+	assert.NotContains(response, "errors", msgUnexpectedError)
+	rawApplication, err := crud.FindID(config.ApplicationsCollection, moc.Applications[0].ID)
+	failOnError(assert, err)
+	application := models.TransformApplication(rawApplication)
+	assert.Equal("HIRED", application.Stage)
+	assert.Len(application.History, len(moc.Applications[0].History)+1)
+
+	// other companies keep the default pipeline
+	otherToken, _ := login(crud, moc.Accounts[4].ID, "none")
+	response, err = gqlRequestAndRespond(handler, fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: HUNTER){
+				... on HunterEditor{
+					moveApplication(id: "%s", stage: OFFER){ stage }
+				}
+			}
+		}
+	`, otherToken, moc.Applications[2].ID.Hex()), nil)
+	failOnError(assert, err)
+	assert.NotContains(response, "errors", msgUnexpectedError)
+}
+
+// tests that invalid application pipelines are rejected
+func TestHunterEditor_SetApplicationPipelineInvalid(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	ownerToken, _ := login(crud, moc.Accounts[2].ID, "none")
+	recruiterToken, _ := login(crud, moc.Accounts[3].ID, "none")
+
+	// invalid inputs
+	input := []struct {
+		token  string
+		stages string
+	}{
+		// case 1 only owners configure the pipeline
+		{recruiterToken, `{stage: APPLIED, next: [HIRED, REJECTED]}, {stage: HIRED, next: []}, {stage: REJECTED, next: []}`},
+		// case 2 no way to hire
+		{ownerToken, `{stage: APPLIED, next: [REJECTED]}, {stage: REJECTED, next: []}`},
+		// case 3 a hired application can't move on
+		{ownerToken, `{stage: APPLIED, next: [SCREENED, HIRED, REJECTED]}, {stage: SCREENED, next: [HIRED]}, {stage: HIRED, next: [REJECTED]}, {stage: REJECTED, next: []}`},
+		// case 4 applications can't get stuck
+		{ownerToken, `{stage: APPLIED, next: [SCREENED, HIRED, REJECTED]}, {stage: SCREENED, next: []}, {stage: HIRED, next: []}, {stage: REJECTED, next: []}`},
+		// case 5 moves to stages outside the pipeline
+		{ownerToken, `{stage: APPLIED, next: [SCREENED, OFFER, REJECTED]}, {stage: SCREENED, next: [HIRED]}, {stage: HIRED, next: []}, {stage: REJECTED, next: []}`},
+		// case 6 repeated stages
+		{ownerToken, `{stage: APPLIED, next: [SCREENED, HIRED]}, {stage: SCREENED, next: [HIRED]}, {stage: SCREENED, next: [REJECTED]}, {stage: HIRED, next: []}, {stage: REJECTED, next: []}`},
+		// case 7 Applications[1] is still screened
+		{ownerToken, `{stage: APPLIED, next: [HIRED, REJECTED]}, {stage: HIRED, next: []}, {stage: REJECTED, next: []}`},
+	}
+
+	for i, in := range input {
+		// request
+		response, err := gqlRequestAndRespond(handler, fmt.Sprintf(`
+			mutation{
+				edit(token: "%s", enforce: HUNTER){
+					... on HunterEditor{
+						setApplicationPipeline(stages: [%s]){ id }
+					}
+				}
+			}
+		`, in.token, in.stages), nil)
+		failOnError(assert, err)
+		assert.Contains(response, "errors", fmt.Sprintf("Case [%v]: %s", i+1, msgNoError))
+	}
+
+	// the default pipeline is kept
+	rawCompany, err := crud.FindID(config.CompaniesCollection, moc.Companies[0].ID)
+	failOnError(assert, err)
+	company := models.TransformCompany(rawCompany)
+	assert.Equal(models.DefaultPipeline, company.ApplicationPipeline())
+}
+
+// tests that invalid application requests fail
+func TestApplicationInvalid(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// get access tokens
+	recruitToken, _ := login(crud, moc.Accounts[0].ID, "none")
+	ownerToken, _ := login(crud, moc.Accounts[2].ID, "none")
+
+	// invalid inputs
+	input := []string{
+		fmt.Sprintf(`
+			# case 1 apply to a draft vacancy
+			mutation{
+				edit(token: "%s", enforce: RECRUIT){
+					... on RecruitEditor{
+						apply(vacancy_id: "%s"){ id }
+					}
+				}
+			}
+		`, recruitToken, moc.Vacancies[1].ID.Hex()),
+		fmt.Sprintf(`
+			# case 2 apply to the same vacancy twice
+			mutation{
+				edit(token: "%s", enforce: RECRUIT){
+					... on RecruitEditor{
+						apply(vacancy_id: "%s"){ id }
+					}
+				}
+			}
+		`, recruitToken, moc.Vacancies[0].ID.Hex()),
+		fmt.Sprintf(`
+			# case 3 skip stages
+			mutation{
+				edit(token: "%s", enforce: HUNTER){
+					... on HunterEditor{
+						moveApplication(id: "%s", stage: HIRED){ id }
+					}
+				}
+			}
+		`, ownerToken, moc.Applications[0].ID.Hex()),
+		fmt.Sprintf(`
+			# case 4 move another company's application
+			mutation{
+				edit(token: "%s", enforce: HUNTER){
+					... on HunterEditor{
+						moveApplication(id: "%s", stage: OFFER){ id }
+					}
+				}
+			}
+		`, ownerToken, moc.Applications[2].ID.Hex()),
+		fmt.Sprintf(`
+			# case 5 view applicants of another company's vacancy
+			query{
+				view(token: "%s", enforce: HUNTER){
+					... on HunterViewer{
+						applicants(vacancy_id: "%s"){ id }
+					}
+				}
+			}
+		`, ownerToken, moc.Vacancies[2].ID.Hex()),
+	}
+
+	for i, query := range input {
+		// request
+		response, err := gqlRequestAndRespond(handler, query, nil)
+		failOnError(assert, err)
+		assert.Contains(response, "errors", fmt.Sprintf("Case [%v]: %s", i+1, msgNoError))
+	}
+}
@@ -0,0 +1,349 @@
+package functionaltests
+
+import (
+	"fmt"
+	"testing"
+
+	moc "../../mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// tests that HunterViewer.Company returns the current Hunter's Company
+func TestHunterViewer_Company(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as company owner
+	token, _ := login(crud, moc.Accounts[2].ID, "none")
+	company := moc.Companies[0]
+
+	// prepare query
+	query := fmt.Sprintf(`
+		query{
+			view(token: "%s", enforce: HUNTER){
+				... on HunterViewer{
+					company{
+						id
+						name
+						registration_number
+						members{
+							id
+							role
+						}
+						invites{
+							email
+							role
+							status
+						}
+					}
+				}
+			}
+		}
+	`, token)
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	// prepare expected
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"view": map[string]interface{}{
+				"company": map[string]interface{}{
+					"id":                  company.ID.Hex(),
+					"name":                company.Name,
+					"registration_number": company.RegistrationNumber,
+					"members": []interface{}{
+						map[string]interface{}{
+							"id":   moc.Hunters[0].ID.Hex(),
+							"role": moc.Hunters[0].Role,
+						},
+						map[string]interface{}{
+							"id":   moc.Hunters[1].ID.Hex(),
+							"role": moc.Hunters[1].Role,
+						},
+					},
+					"invites": []interface{}{
+						map[string]interface{}{
+							"email":  moc.CompanyInvites[0].Email,
+							"role":   moc.CompanyInvites[0].Role,
+							"status": moc.CompanyInvites[0].Status,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests that HunterEditor.CreateCompany makes the current Hunter the owner of a new Company
+func TestHunterEditor_CreateCompany(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as sys, whose hunter profile has no company
+	token, _ := login(crud, getSysUserAccount().ID, "none")
+
+	// prepare data
+	name := "Mahikeng Mining"
+	regNumber := "2018/000111/07"
+	province := "NORTH_WEST"
+	city := "Mahikeng"
+
+	// prepare query
+	query := fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: HUNTER){
+				... on HunterEditor{
+					createCompany(info: {
+						name: "%s",
+						registration_number: "%s",
+						province: %s,
+						city: "%s"
+					}){
+						name
+						registration_number
+						province
+						city
+						members{
+							id
+							role
+						}
+					}
+				}
+			}
+		}
+	`, token, name, regNumber, province, city)
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	// prepare expected
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"edit": map[string]interface{}{
+				"createCompany": map[string]interface{}{
+					"name":                name,
+					"registration_number": regNumber,
+					"province":            province,
+					"city":                city,
+					"members": []interface{}{
+						map[string]interface{}{
+							"id":   moc.Hunters[3].ID.Hex(),
+							"role": "OWNER",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests that company mutations are restricted by membership and role
+func TestHunterEditor_CompanyInvalid(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// get access tokens
+	ownerToken, _ := login(crud, moc.Accounts[2].ID, "none")
+	recruiterToken, _ := login(crud, moc.Accounts[3].ID, "none")
+	sysToken, _ := login(crud, getSysUserAccount().ID, "none")
+
+	// prepare query
+	queryFormat := `
+		mutation{
+			edit(token: "%s", enforce: HUNTER){
+				... on HunterEditor{
+					%s
+				}
+			}
+		}
+	`
+
+	// invalid inputs
+	input := []string{
+		fmt.Sprintf(queryFormat, ownerToken, `
+			# case 1 create company while in a company
+			createCompany(info: {
+				name: "Second Co",
+				registration_number: "2019/000001/07",
+				province: GAUTENG,
+				city: "Pretoria"
+			}){ id }
+		`),
+		fmt.Sprintf(queryFormat, recruiterToken, `
+			# case 2 recruiter invites a hunter
+			inviteHunter(email: "moti@gmail.com", role: VIEWER){ id }
+		`),
+		fmt.Sprintf(queryFormat, ownerToken, `
+			# case 3 sole owner leaves while members remain
+			leaveCompany
+		`),
+		fmt.Sprintf(queryFormat, ownerToken, fmt.Sprintf(`
+			# case 4 owner changes role of another company's hunter
+			setMemberRole(hunter_id: "%s", role: VIEWER){ id }
+		`, moc.Hunters[2].ID.Hex())),
+		fmt.Sprintf(queryFormat, sysToken, `
+			# case 5 company documents can't be linked, only uploaded
+			addCompanyDocument(url: "http://google.com/reg.pdf"){ id }
+		`),
+	}
+
+	for i, query := range input {
+		// request
+		response, err := gqlRequestAndRespond(handler, query, nil)
+		failOnError(assert, err)
+		assert.Contains(response, "errors", fmt.Sprintf("Case [%v]: %s", i+1, msgNoError))
+	}
+}
+
+// tests that an invited Hunter can accept a CompanyInvite
+func TestHunterEditor_InviteAndAccept(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as owner and invitee
+	ownerToken, _ := login(crud, moc.Accounts[2].ID, "none")
+	sysToken, _ := login(crud, getSysUserAccount().ID, "none")
+
+	// owner invites a new hunter
+	query := fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: HUNTER){
+				... on HunterEditor{
+					inviteHunter(email: "JAKE@gmail.com", role: VIEWER){
+						email
+						role
+						status
+						company{
+							name
+						}
+					}
+				}
+			}
+		}
+	`, ownerToken)
+
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"edit": map[string]interface{}{
+				"inviteHunter": map[string]interface{}{
+					"email":  "jake@gmail.com",
+					"role":   "VIEWER",
+					"status": "PENDING",
+					"company": map[string]interface{}{
+						"name": moc.Companies[0].Name,
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(expected, response, msgInvalidResult)
+
+	// sys accepts the mock invite
+	query = fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: HUNTER){
+				... on HunterEditor{
+					acceptInvite(id: "%s"){
+						id
+					}
+				}
+			}
+		}
+	`, sysToken, moc.CompanyInvites[0].ID.Hex())
+
+	response, err = gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	expected = map[string]interface{}{
+		"data": map[string]interface{}{
+			"edit": map[string]interface{}{
+				"acceptInvite": map[string]interface{}{
+					"id": moc.Companies[0].ID.Hex(),
+				},
+			},
+		},
+	}
+	assert.Equal(expected, response, msgInvalidResult)
+
+	// the invite can't be accepted twice
+	response, err = gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+	assert.Contains(response, "errors", msgNoError)
+}
+
+// tests that an owner can manage the Company's members
+func TestHunterEditor_ManageMembers(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as owner
+	token, _ := login(crud, moc.Accounts[2].ID, "none")
+	member := moc.Hunters[1]
+
+	// change the member's role
+	query := fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: HUNTER){
+				... on HunterEditor{
+					setMemberRole(hunter_id: "%s", role: VIEWER){
+						id
+						role
+					}
+				}
+			}
+		}
+	`, token, member.ID.Hex())
+
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"edit": map[string]interface{}{
+				"setMemberRole": map[string]interface{}{
+					"id":   member.ID.Hex(),
+					"role": "VIEWER",
+				},
+			},
+		},
+	}
+	assert.Equal(expected, response, msgInvalidResult)
+
+	// remove the member
+	query = fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: HUNTER){
+				... on HunterEditor{
+					removeMember(hunter_id: "%s")
+				}
+			}
+		}
+	`, token, member.ID.Hex())
+
+	response, err = gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	expected = map[string]interface{}{
+		"data": map[string]interface{}{
+			"edit": map[string]interface{}{
+				"removeMember": "Member successfully removed.",
+			},
+		},
+	}
+	assert.Equal(expected, response, msgInvalidResult)
+}
@@ -0,0 +1,121 @@
+package functionaltests
+
+import (
+	"fmt"
+	"testing"
+
+	config "../../config"
+	moc "../../mocks"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// tests that RecruitViewer.Completeness reports the missing profile items
+func TestRecruitViewer_Completeness(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Recruits[0]
+	token, _ := login(crud, moc.Accounts[0].ID, "none")
+	query := fmt.Sprintf(`
+		query{
+			view(token: "%s", enforce: RECRUIT){
+				... on RecruitViewer{
+					completeness{
+						percent
+						missing
+					}
+				}
+			}
+		}
+	`, token)
+
+	// Recruits[0] has no videos, history or verified qualifications
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"view": map[string]interface{}{
+				"completeness": map[string]interface{}{
+					"percent": float64(35),
+					"missing": []interface{}{"VIDEO_1", "VIDEO_2", "WORK_EXPERIENCE", "EDUCATION", "VERIFIED_QUALIFICATION"},
+				},
+			},
+		},
+	}
+	assert.Equal(expected, response, msgInvalidResult)
+
+	// add a video and verify a qualification
+	failOnError(assert, crud.UpdateID(config.RecruitsCollection, moc.Recruits[0].ID, bson.M{"vid1_url": "http://youtube.com/v1"}))
+	failOnError(assert, crud.UpdateID(config.DocumentsCollection, moc.Documents[0].ID, bson.M{"verified": true}))
+
+	response, err = gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+	expected["data"].(map[string]interface{})["view"] = map[string]interface{}{
+		"completeness": map[string]interface{}{
+			"percent": float64(60),
+			"missing": []interface{}{"VIDEO_2", "WORK_EXPERIENCE", "EDUCATION"},
+		},
+	}
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests that sys admins can list the recruits below a completeness threshold
+func TestSysViewer_IncompleteRecruits(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	token, _ := login(crud, getSysUserAccount().ID, "none")
+	query := `
+		query{
+			view(token: "%s", enforce: SYSTEM){
+				... on SysViewer{
+					incompleteRecruits(below: %d){
+						recruit{
+							id
+						}
+						completeness{
+							percent
+						}
+					}
+				}
+			}
+		}
+	`
+
+	// fill in most of Recruits[1]'s profile
+	failOnError(assert, crud.UpdateID(config.RecruitsCollection, moc.Recruits[1].ID, bson.M{
+		"vid1_url": "http://youtube.com/v1",
+		"vid2_url": "http://youtube.com/v2",
+	}))
+
+	response, err := gqlRequestAndRespond(handler, fmt.Sprintf(query, token, 50), nil)
+	failOnError(assert, err)
+	data := assertGqlData("view", response, assert)
+	recruits := data["view"].(map[string]interface{})["incompleteRecruits"].([]interface{})
+	if assert.Len(recruits, 2) {
+		for _, raw := range recruits {
+			recruit := raw.(map[string]interface{})
+			assert.NotEqual(moc.Recruits[1].ID.Hex(), recruit["recruit"].(map[string]interface{})["id"])
+			assert.Equal(float64(35), recruit["completeness"].(map[string]interface{})["percent"])
+		}
+	}
+
+	response, err = gqlRequestAndRespond(handler, fmt.Sprintf(query, token, 100), nil)
+	failOnError(assert, err)
+	data = assertGqlData("view", response, assert)
+	recruits = data["view"].(map[string]interface{})["incompleteRecruits"].([]interface{})
+	if assert.Len(recruits, 3) {
+		// least complete first
+		last := recruits[2].(map[string]interface{})
+		assert.Equal(moc.Recruits[1].ID.Hex(), last["recruit"].(map[string]interface{})["id"])
+		assert.Equal(float64(95), last["completeness"].(map[string]interface{})["percent"])
+	}
+
+	// threshold must be a percentage
+	response, err = gqlRequestAndRespond(handler, fmt.Sprintf(query, token, 0), nil)
+	failOnError(assert, err)
+	assert.NotNil(response["errors"], msgInvalidResult)
+}
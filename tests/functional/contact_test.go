@@ -0,0 +1,375 @@
+package functionaltests
+
+import (
+	"fmt"
+	"testing"
+
+	moc "../../mocks"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// tests that hunters only see the contact details they were granted
+func TestRecruitContactMasking(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	cases := []struct {
+		account  int
+		recruit  int
+		expected map[string]interface{}
+	}{
+		// Hunters[0] was granted access to Recruits[0]
+		{2, 0, map[string]interface{}{"phone": "012 345 2378", "email": "mark@gmail.com", "contact_unlocked": true}},
+		// Hunters[1] works at the same company, but never asked
+		{3, 0, map[string]interface{}{"phone": "*** *** **78", "email": "m***@gmail.com", "contact_unlocked": false}},
+		// Hunters[2] is still waiting on Recruits[1]
+		{4, 1, map[string]interface{}{"phone": "*** *** **78", "email": "j***@gmail.com", "contact_unlocked": false}},
+	}
+
+	for i, c := range cases {
+		token, _ := login(crud, moc.Accounts[c.account].ID, "none")
+		query := fmt.Sprintf(`
+			query{
+				view(token: "%s", enforce: HUNTER){
+					... on HunterViewer{
+						recruit(id: "%s"){
+							phone
+							email
+							contact_unlocked
+						}
+					}
+				}
+			}
+		`, token, moc.Recruits[c.recruit].ID.Hex())
+
+		response, err := gqlRequestAndRespond(handler, query, nil)
+		failOnError(assert, err)
+		expected := map[string]interface{}{
+			"data": map[string]interface{}{
+				"view": map[string]interface{}{
+					"recruit": c.expected,
+				},
+			},
+		}
+		assert.Equal(expected, response, fmt.Sprintf("Case [%v]: %s", i+1, msgInvalidResult))
+	}
+
+	// recruits always see their own details
+	token, _ := login(crud, moc.Accounts[0].ID, "none")
+	query := fmt.Sprintf(`
+		query{
+			view(token: "%s", enforce: RECRUIT){
+				... on RecruitViewer{
+					profile{
+						phone
+						email
+					}
+				}
+			}
+		}
+	`, token)
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+	assert.Equal(map[string]interface{}{
+		"data": map[string]interface{}{
+			"view": map[string]interface{}{
+				"profile": map[string]interface{}{"phone": "012 345 2378", "email": "mark@gmail.com"},
+			},
+		},
+	}, response, msgInvalidResult)
+}
+
+// tests requesting, approving and revoking access to a Recruit's contact details
+func TestContactRequestFlow(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	hunterToken, _ := login(crud, moc.Accounts[3].ID, "none")
+	recruitToken, _ := login(crud, moc.Accounts[1].ID, "none")
+	sysToken, _ := login(crud, getSysUserAccount().ID, "none")
+	recruitID := moc.Recruits[1].ID.Hex()
+
+	// Hunters[1] asks Recruits[1] for their details
+	query := fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: HUNTER){
+				... on HunterEditor{
+					requestContact(recruit_id: "%s", message: "Are you open to a chat?"){
+						id
+						message
+						status
+						responded_at
+						recruit{
+							phone
+						}
+					}
+				}
+			}
+		}
+	`, hunterToken, recruitID)
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+	assert.NotContains(response, "errors", msgUnexpectedError)
+	request := response["data"].(map[string]interface{})["edit"].(map[string]interface{})["requestContact"].(map[string]interface{})
+	requestID := request["id"].(string)
+	delete(request, "id")
+	assert.Equal(map[string]interface{}{
+		"message":      "Are you open to a chat?",
+		"status":       "PENDING",
+		"responded_at": nil,
+		"recruit":      map[string]interface{}{"phone": "*** *** **78"},
+	}, request, msgInvalidResult)
+
+	// asking twice fails
+	response, err = gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+	assert.Contains(response, "errors", msgNoError)
+
+	// the recruit sees the request and approves it
+	query = fmt.Sprintf(`
+		query{
+			view(token: "%s", enforce: RECRUIT){
+				... on RecruitViewer{
+					contact_requests(status: PENDING){
+						id
+						hunter{
+							id
+						}
+					}
+				}
+			}
+		}
+	`, recruitToken)
+	response, err = gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+	pending := response["data"].(map[string]interface{})["view"].(map[string]interface{})["contact_requests"].([]interface{})
+	assert.Len(pending, 2, msgInvalidResult)
+	assert.Equal(requestID, pending[0].(map[string]interface{})["id"], msgInvalidResult)
+	assert.Equal(moc.Hunters[1].ID.Hex(), pending[0].(map[string]interface{})["hunter"].(map[string]interface{})["id"], msgInvalidResult)
+
+	query = fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: RECRUIT){
+				... on RecruitEditor{
+					respondContactRequest(id: "%s", approve: true){
+						status
+						recruit{
+							phone
+							email
+						}
+					}
+				}
+			}
+		}
+	`, recruitToken, requestID)
+	response, err = gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+	assert.Equal(map[string]interface{}{
+		"data": map[string]interface{}{
+			"edit": map[string]interface{}{
+				"respondContactRequest": map[string]interface{}{
+					"status":  "APPROVED",
+					"recruit": map[string]interface{}{"phone": "013 345 2378", "email": "johndoe@gmail.com"},
+				},
+			},
+		},
+	}, response, msgInvalidResult)
+
+	// the hunter now sees the phone number, but not the email, which Recruits[1] doesn't share
+	contactQuery := fmt.Sprintf(`
+		query{
+			view(token: "%s", enforce: HUNTER){
+				... on HunterViewer{
+					recruit(id: "%s"){
+						phone
+						email
+					}
+				}
+			}
+		}
+	`, hunterToken, recruitID)
+	response, err = gqlRequestAndRespond(handler, contactQuery, nil)
+	failOnError(assert, err)
+	assert.Equal(map[string]interface{}{
+		"data": map[string]interface{}{
+			"view": map[string]interface{}{
+				"recruit": map[string]interface{}{"phone": "013 345 2378", "email": "j***@gmail.com"},
+			},
+		},
+	}, response, msgInvalidResult)
+
+	// the unlock was recorded
+	query = fmt.Sprintf(`
+		query{
+			view(token: "%s", enforce: SYSTEM){
+				... on SysViewer{
+					contact_unlocks(recruit_id: "%s"){
+						request_id
+						hunter_id
+						company_id
+						granted_by
+						phone
+						email
+					}
+				}
+			}
+		}
+	`, sysToken, recruitID)
+	response, err = gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+	assert.Equal(map[string]interface{}{
+		"data": map[string]interface{}{
+			"view": map[string]interface{}{
+				"contact_unlocks": []interface{}{
+					map[string]interface{}{
+						"request_id": requestID,
+						"hunter_id":  moc.Hunters[1].ID.Hex(),
+						"company_id": moc.Companies[0].ID.Hex(),
+						"granted_by": moc.Accounts[1].ID.Hex(),
+						"phone":      true,
+						"email":      false,
+					},
+				},
+			},
+		},
+	}, response, msgInvalidResult)
+
+	// revoking hides the details again
+	query = fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: RECRUIT){
+				... on RecruitEditor{
+					revokeContactAccess(id: "%s"){
+						status
+					}
+				}
+			}
+		}
+	`, recruitToken, requestID)
+	response, err = gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+	assert.NotContains(response, "errors", msgUnexpectedError)
+
+	response, err = gqlRequestAndRespond(handler, contactQuery, nil)
+	failOnError(assert, err)
+	assert.Equal(map[string]interface{}{
+		"data": map[string]interface{}{
+			"view": map[string]interface{}{
+				"recruit": map[string]interface{}{"phone": "*** *** **78", "email": "j***@gmail.com"},
+			},
+		},
+	}, response, msgInvalidResult)
+}
+
+// tests that privacy settings limit what an approved request reveals
+func TestRecruitEditor_UpdatePrivacy(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	recruitToken, _ := login(crud, moc.Accounts[0].ID, "none")
+	hunterToken, _ := login(crud, moc.Accounts[2].ID, "none")
+
+	query := fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: RECRUIT){
+				... on RecruitEditor{
+					updatePrivacy(share_phone: false){
+						allow_requests
+						share_phone
+						share_email
+					}
+				}
+			}
+		}
+	`, recruitToken)
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+	assert.Equal(map[string]interface{}{
+		"data": map[string]interface{}{
+			"edit": map[string]interface{}{
+				"updatePrivacy": map[string]interface{}{
+					"allow_requests": true,
+					"share_phone":    false,
+					"share_email":    true,
+				},
+			},
+		},
+	}, response, msgInvalidResult)
+
+	query = fmt.Sprintf(`
+		query{
+			view(token: "%s", enforce: HUNTER){
+				... on HunterViewer{
+					recruit(id: "%s"){
+						phone
+						email
+					}
+				}
+			}
+		}
+	`, hunterToken, moc.Recruits[0].ID.Hex())
+	response, err = gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+	assert.Equal(map[string]interface{}{
+		"data": map[string]interface{}{
+			"view": map[string]interface{}{
+				"recruit": map[string]interface{}{"phone": "*** *** **78", "email": "mark@gmail.com"},
+			},
+		},
+	}, response, msgInvalidResult)
+}
+
+// tests invalid contact requests and responses
+func TestContactRequestInvalid(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	hunterToken, _ := login(crud, moc.Accounts[2].ID, "none")
+	recruit0Token, _ := login(crud, moc.Accounts[0].ID, "none")
+	recruit1Token, _ := login(crud, moc.Accounts[1].ID, "none")
+
+	queries := []string{
+		// the sysadmin's recruit profile doesn't accept requests
+		fmt.Sprintf(`mutation{ edit(token: "%s", enforce: HUNTER){ ... on HunterEditor{
+			requestContact(recruit_id: "%s"){ id }
+		}}}`, hunterToken, moc.Recruits[2].ID.Hex()),
+		// unknown recruit
+		fmt.Sprintf(`mutation{ edit(token: "%s", enforce: HUNTER){ ... on HunterEditor{
+			requestContact(recruit_id: "%s"){ id }
+		}}}`, hunterToken, bson.NewObjectId().Hex()),
+		// answering another recruit's request
+		fmt.Sprintf(`mutation{ edit(token: "%s", enforce: RECRUIT){ ... on RecruitEditor{
+			respondContactRequest(id: "%s", approve: true){ id }
+		}}}`, recruit0Token, moc.ContactRequests[1].ID.Hex()),
+		// answering an answered request
+		fmt.Sprintf(`mutation{ edit(token: "%s", enforce: RECRUIT){ ... on RecruitEditor{
+			respondContactRequest(id: "%s", approve: false){ id }
+		}}}`, recruit0Token, moc.ContactRequests[0].ID.Hex()),
+		// revoking a pending request
+		fmt.Sprintf(`mutation{ edit(token: "%s", enforce: RECRUIT){ ... on RecruitEditor{
+			revokeContactAccess(id: "%s"){ id }
+		}}}`, recruit1Token, moc.ContactRequests[1].ID.Hex()),
+	}
+
+	for i, query := range queries {
+		response, err := gqlRequestAndRespond(handler, query, nil)
+		failOnError(assert, err)
+		assert.Contains(response, "errors", fmt.Sprintf("Case [%v]: %s", i+1, msgNoError))
+	}
+
+	// declining leaves the details hidden
+	query := fmt.Sprintf(`mutation{ edit(token: "%s", enforce: RECRUIT){ ... on RecruitEditor{
+		respondContactRequest(id: "%s", approve: false){ status responded_at }
+	}}}`, recruit1Token, moc.ContactRequests[1].ID.Hex())
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+	assert.NotContains(response, "errors", msgUnexpectedError)
+	declined := response["data"].(map[string]interface{})["edit"].(map[string]interface{})["respondContactRequest"].(map[string]interface{})
+	assert.Equal("DECLINED", declined["status"], msgInvalidResult)
+	assert.NotNil(declined["responded_at"], msgInvalidResult)
+}
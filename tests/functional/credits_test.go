@@ -0,0 +1,293 @@
+package functionaltests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	config "../../config"
+	moc "../../mocks"
+	models "../../models"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// creditsQuery is the query of the logged in hunter's balance and latest entries
+const creditsQuery = `
+	query{
+		view(token: "%s", enforce: HUNTER){
+			... on HunterViewer{
+				credits{
+					balance
+					entries(first: %d){
+						seq
+						kind
+						amount
+						balance
+						reason
+						subject_id
+					}
+				}
+			}
+		}
+	}
+`
+
+// tests that HunterViewer.Credits lists the ledger, newest first
+func TestHunterViewer_Credits(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Hunters[0]
+	token, _ := login(crud, moc.Accounts[2].ID, "none")
+
+	response, err := gqlRequestAndRespond(handler, fmt.Sprintf(creditsQuery, token, 10), nil)
+	failOnError(assert, err)
+
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"view": map[string]interface{}{
+				"credits": map[string]interface{}{
+					"balance": float64(9),
+					"entries": []interface{}{
+						map[string]interface{}{
+							"seq":        float64(2),
+							"kind":       "SPEND",
+							"amount":     float64(-1),
+							"balance":    float64(9),
+							"reason":     "Contact request",
+							"subject_id": moc.ContactRequests[0].ID.Hex(),
+						},
+						map[string]interface{}{
+							"seq":        float64(1),
+							"kind":       "PURCHASE",
+							"amount":     float64(10),
+							"balance":    float64(10),
+							"reason":     "Starter pack",
+							"subject_id": nil,
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests that sys admins can grant and adjust credits, and that hunters pay for contact requests
+func TestCreditsGrantAndSpend(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// the sysadmin's hunter profile has no credits
+	sys := getSysUserAccount()
+	token, _ := login(crud, sys.ID, "none")
+	hunterID := moc.Hunters[3].ID.Hex()
+
+	balance := func() interface{} {
+		response, err := gqlRequestAndRespond(handler, fmt.Sprintf(creditsQuery, token, 1), nil)
+		failOnError(assert, err)
+		return response["data"].(map[string]interface{})["view"].(map[string]interface{})["credits"].(map[string]interface{})["balance"]
+	}
+	requestContact := fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: HUNTER){
+				... on HunterEditor{
+					requestContact(recruit_id: "%s"){ status }
+				}
+			}
+		}
+	`, token, moc.Recruits[0].ID.Hex())
+	editCredits := func(method string, amount int) map[string]interface{} {
+		response, err := gqlRequestAndRespond(handler, fmt.Sprintf(`
+			mutation{
+				edit(token: "%s", enforce: SYSTEM){
+					... on SysEditor{
+						%s(hunter_id: "%s", amount: %d, reason: "Invoice 42"){ seq kind amount balance }
+					}
+				}
+			}
+		`, token, method, hunterID, amount), nil)
+		failOnError(assert, err)
+		return response
+	}
+
+	// without credits requests fail
+	response, err := gqlRequestAndRespond(handler, requestContact, nil)
+	failOnError(assert, err)
+	assert.Contains(response, "errors", msgNoError)
+
+	// grant credits
+	response = editCredits("grantCredits", 3)
+	assert.Equal(map[string]interface{}{
+		"data": map[string]interface{}{
+			"edit": map[string]interface{}{
+				"grantCredits": map[string]interface{}{
+					"seq": float64(1), "kind": "PURCHASE", "amount": float64(3), "balance": float64(3),
+				},
+			},
+		},
+	}, response, msgInvalidResult)
+
+	// the request is paid for
+	response, err = gqlRequestAndRespond(handler, requestContact, nil)
+	failOnError(assert, err)
+	assert.NotContains(response, "errors", msgUnexpectedError)
+	assert.Equal(float64(2), balance(), msgInvalidResult)
+
+	// adjustments can't overdraw the balance
+	response = editCredits("adjustCredits", -3)
+	assert.Contains(response, "errors", msgNoError)
+	response = editCredits("adjustCredits", -2)
+	assert.NotContains(response, "errors", msgUnexpectedError)
+	assert.Equal(float64(0), balance(), msgInvalidResult)
+
+	// invalid grants
+	for i, amount := range []int{0, -5} {
+		response = editCredits("grantCredits", amount)
+		assert.Contains(response, "errors", fmt.Sprintf("Case [%v]: %s", i+1, msgNoError))
+	}
+	response, err = gqlRequestAndRespond(handler, fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: SYSTEM){
+				... on SysEditor{
+					grantCredits(hunter_id: "%s", amount: 5, reason: "Invoice 43"){ seq }
+				}
+			}
+		}
+	`, token, bson.NewObjectId().Hex()), nil)
+	failOnError(assert, err)
+	assert.Contains(response, "errors", msgNoError)
+}
+
+// tests that declined contact requests are refunded
+func TestCreditsRefundDeclined(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	recruitToken, _ := login(crud, moc.Accounts[1].ID, "none")
+	hunterToken, _ := login(crud, moc.Accounts[4].ID, "none")
+
+	response, err := gqlRequestAndRespond(handler, fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: RECRUIT){
+				... on RecruitEditor{
+					respondContactRequest(id: "%s", approve: false){ status }
+				}
+			}
+		}
+	`, recruitToken, moc.ContactRequests[1].ID.Hex()), nil)
+	failOnError(assert, err)
+	assert.NotContains(response, "errors", msgUnexpectedError)
+
+	response, err = gqlRequestAndRespond(handler, fmt.Sprintf(creditsQuery, hunterToken, 1), nil)
+	failOnError(assert, err)
+	assert.Equal(map[string]interface{}{
+		"data": map[string]interface{}{
+			"view": map[string]interface{}{
+				"credits": map[string]interface{}{
+					"balance": float64(10),
+					"entries": []interface{}{
+						map[string]interface{}{
+							"seq":        float64(3),
+							"kind":       "REFUND",
+							"amount":     float64(1),
+							"balance":    float64(10),
+							"reason":     "Contact request declined",
+							"subject_id": moc.ContactRequests[1].ID.Hex(),
+						},
+					},
+				},
+			},
+		},
+	}, response, msgInvalidResult)
+}
+
+// tests that publishing a vacancy is paid for once
+func TestCreditsVacancyPosting(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Companies[0] owner, who has 9 credits
+	token, _ := login(crud, moc.Accounts[2].ID, "none")
+	vacancyID := moc.Vacancies[1].ID.Hex()
+
+	setStatus := func(status string) map[string]interface{} {
+		response, err := gqlRequestAndRespond(handler, fmt.Sprintf(`
+			mutation{
+				edit(token: "%s", enforce: HUNTER){
+					... on HunterEditor{
+						updateVacancy(id: "%s", info: { status: %s }){ status }
+					}
+				}
+			}
+		`, token, vacancyID, status), nil)
+		failOnError(assert, err)
+		return response
+	}
+	balance := func() interface{} {
+		response, err := gqlRequestAndRespond(handler, fmt.Sprintf(creditsQuery, token, 1), nil)
+		failOnError(assert, err)
+		return response["data"].(map[string]interface{})["view"].(map[string]interface{})["credits"].(map[string]interface{})["balance"]
+	}
+
+	// publishing the draft costs 5 credits, reopening it is free
+	for i, status := range []string{"OPEN", "CLOSED", "OPEN"} {
+		response := setStatus(status)
+		assert.NotContains(response, "errors", fmt.Sprintf("Step [%v]: %s", i+1, msgUnexpectedError))
+		assert.Equal(float64(4), balance(), fmt.Sprintf("Step [%v]: %s", i+1, msgInvalidResult))
+	}
+
+	// a second posting can't be paid for
+	response, err := gqlRequestAndRespond(handler, fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: HUNTER){
+				... on HunterEditor{
+					createVacancy(info: {
+						title: "Lab Assistant",
+						description: "Keep the lab running.",
+						industry_id: "%s",
+						province: GAUTENG,
+						city: "Pretoria",
+						employment_type: FULL_TIME,
+						closing_date: "2030-01-01T00:00:00Z",
+						status: OPEN
+					}){ id }
+				}
+			}
+		}
+	`, token, moc.Industries[0].ID.Hex()), nil)
+	failOnError(assert, err)
+	assert.Contains(response, "errors", msgNoError)
+	assert.Equal(float64(4), balance(), msgInvalidResult)
+
+	// and isn't posted
+	rawVacancies, _ := crud.FindAll(config.VacanciesCollection, bson.M{"title": "Lab Assistant"})
+	assert.Len(rawVacancies, 0)
+
+	// nor can publishing a second draft, which is left as it was
+	draft := models.Vacancy{
+		ID:             bson.NewObjectId(),
+		CompanyID:      moc.Companies[0].ID,
+		IndustryID:     moc.Industries[0].ID,
+		Title:          "Lab Assistant",
+		Description:    "Keep the lab running.",
+		Province:       "GAUTENG",
+		City:           "Pretoria",
+		EmploymentType: "FULL_TIME",
+		ClosingDate:    time.Now().AddDate(0, 1, 0),
+		CreatedAt:      time.Now(),
+		Status:         models.VacancyDraft,
+	}
+	failOnError(assert, crud.Insert(config.VacanciesCollection, draft))
+	vacancyID = draft.ID.Hex()
+	assert.Contains(setStatus("OPEN"), "errors", msgNoError)
+	assert.Equal(float64(4), balance(), msgInvalidResult)
+	rawDraft, err := crud.FindID(config.VacanciesCollection, draft.ID)
+	failOnError(assert, err)
+	assert.Equal(models.VacancyDraft, models.TransformVacancy(rawDraft).Status, msgInvalidResult)
+}
@@ -1,115 +1,288 @@
 package functionaltests
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	config "../../config"
 	moc "../../mocks"
+	models "../../models"
+	resolver "../../resolvers"
+	route "../../routing"
+	storage "../../storage"
 	"github.com/stretchr/testify/assert"
 )
 
-func TestCreateDocumentValid(t *testing.T) {
-	handler := createLoadedGqlHandler()
+// pdfFile is the content of an uploaded pdf
+var pdfFile = []byte("%PDF-1.4\n% a qualification\n")
 
-	// prepare request
-	method := "createDocument"
-	url := "http://yurp.com"
-	ownerID := moc.Recruits[0].ID.Hex()
-	query := fmt.Sprintf(`
-		mutation{
-			%s(doc_type:QUALIFICATION, owner_type: RECRUIT,owner_id: "%s", url: "%s"){
-				id
-				url
-				owner_id
-			}
-		}
-	`, method, ownerID, url)
+// uploadDocument sends a file to the upload handler
+func uploadDocument(handler http.HandlerFunc, token, docType string, file []byte) *httptest.ResponseRecorder {
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	if docType != "" {
+		form.WriteField("doc_type", docType)
+	}
+	part, _ := form.CreateFormFile("uploadFile", "file")
+	part.Write(file)
+	form.Close()
 
-	// request and respond
-	response, err := gqlRequestAndRespond(handler, query, nil)
+	req := httptest.NewRequest(http.MethodPost, "/upload", body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	handler(w, req)
+	return w
+}
 
-	//process response
+// tests that uploads create documents owned by the uploader
+func TestUploadDocumentValid(t *testing.T) {
 	assert := assert.New(t)
-	if err != nil {
-		assert.Fail("Failed to process response:", err)
-	}
+	crud := moc.NewLoadedCRUD()
+	dir, err := ioutil.TempDir("", "files")
+	failOnError(assert, err)
+	defer os.RemoveAll(dir)
+	gqlResolver := &resolver.RootResolver{}
+	gqlResolver.Init(crud)
+	handler := route.NewUploadHandler(gqlResolver, storage.NewLocalStore(dir), route.DefaultMaxDocumentSize)
 
-	dataPortion, dOk := response["data"].(map[string]interface{})
-	resultDocument, rOk := dataPortion[method].(map[string]interface{})
+	// qualifications belong to the uploader's recruit profile
+	token, _ := login(crud, moc.Accounts[0].ID, "none")
+	w := uploadDocument(handler, token, "", pdfFile)
+	assert.Equal(http.StatusCreated, w.Code)
+	var document models.Document
+	failOnError(assert, json.NewDecoder(w.Body).Decode(&document))
+	assert.Equal("QUALIFICATION", document.DocType)
+	assert.Equal("RECRUIT", document.OwnerType)
+	assert.Equal(moc.Recruits[0].ID, document.OwnerID)
+	assert.False(document.Verified)
+	assert.True(strings.HasPrefix(document.URL, "/file/") && strings.HasSuffix(document.URL, ".pdf"), document.URL)
 
-	//make assertions
-	assert.NotContains(response, "errors", msgUnexpectedError)
-	assert.Contains(response, "data", msgInvalidResponse)
-	assert.Contains(response["data"], method, msgMissingResponseData)
-	assert.True(dOk, msgInvalidResponseType)
-	assert.True(rOk, fmt.Sprintf("Invalid data[\"%s\"] response type.", method))
+	stored, err := ioutil.ReadFile(filepath.Join(dir, strings.TrimPrefix(document.URL, "/file/")))
+	failOnError(assert, err)
+	assert.Equal(pdfFile, stored)
+	rawDocument, err := crud.FindID(config.DocumentsCollection, document.ID)
+	failOnError(assert, err)
+	assert.Equal(document, models.TransformDocument(rawDocument))
+
+	// company documents belong to the uploader's company
+	token, _ = login(crud, moc.Accounts[2].ID, "none")
+	w = uploadDocument(handler, token, "COMPANY", pdfFile)
+	assert.Equal(http.StatusCreated, w.Code)
+	failOnError(assert, json.NewDecoder(w.Body).Decode(&document))
+	assert.Equal("COMPANY", document.OwnerType)
+	assert.Equal(moc.Companies[0].ID, document.OwnerID)
 
-	if rOk {
-		assert.Contains(resultDocument, "id", msgMissingResponseData)
+	// scans are larger than a few kilobytes
+	scan := append(append([]byte{}, pdfFile...), bytes.Repeat([]byte("page "), 400<<10)...)
+	w = uploadDocument(handler, token, "COMPANY", scan)
+	assert.Equal(http.StatusCreated, w.Code, w.Body.String())
+	failOnError(assert, json.NewDecoder(w.Body).Decode(&document))
+	stored, err = ioutil.ReadFile(filepath.Join(dir, strings.TrimPrefix(document.URL, "/file/")))
+	failOnError(assert, err)
+	assert.Equal(scan, stored)
+}
+
+// tests that uploads need a token of an account that can own the document
+func TestUploadDocumentInvalid(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	dir, err := ioutil.TempDir("", "files")
+	failOnError(assert, err)
+	defer os.RemoveAll(dir)
+	gqlResolver := &resolver.RootResolver{}
+	gqlResolver.Init(crud)
+	handler := route.NewUploadHandler(gqlResolver, storage.NewLocalStore(dir), route.DefaultMaxDocumentSize)
 
-		assert.Contains(resultDocument, "url", msgMissingResponseData)
-		assert.Equal(resultDocument["url"], url, msgInvalidResult)
+	rawDocuments, err := crud.FindAll(config.DocumentsCollection, nil)
+	failOnError(assert, err)
+	documentCount := len(rawDocuments)
 
-		assert.Contains(resultDocument, "owner_id", msgMissingResponseData)
-		assert.Equal(resultDocument["owner_id"], ownerID, msgInvalidResult)
+	recruitToken, _ := login(crud, moc.Accounts[0].ID, "none")
+	hunterToken, _ := login(crud, moc.Accounts[2].ID, "none")
+	cases := []struct {
+		token   string
+		docType string
+		file    []byte
+		code    int
+	}{
+		{"", "QUALIFICATION", pdfFile, http.StatusUnauthorized},
+		{"token", "QUALIFICATION", pdfFile, http.StatusUnauthorized},
+		{hunterToken, "QUALIFICATION", pdfFile, http.StatusBadRequest},
+		{recruitToken, "COMPANY", pdfFile, http.StatusBadRequest},
+		{recruitToken, "RESUME", pdfFile, http.StatusBadRequest},
+		{recruitToken, "QUALIFICATION", []byte("just some text"), http.StatusBadRequest},
+	}
+	for i, c := range cases {
+		w := uploadDocument(handler, c.token, c.docType, c.file)
+		assert.Equal(c.code, w.Code, "Case [%v]: %s", i+1, w.Body.String())
 	}
-}
 
-func TestCreateDocumentInvalid(t *testing.T) {
-	handler := createLoadedGqlHandler()
+	// files over the limit are refused, also when the request doesn't say how large it is
+	limited := route.NewUploadHandler(gqlResolver, storage.NewLocalStore(dir), 1<<10)
+	large := append(append([]byte{}, pdfFile...), bytes.Repeat([]byte("page "), 1<<10)...)
+	w := uploadDocument(limited, recruitToken, "QUALIFICATION", large)
+	assert.Equal(http.StatusRequestEntityTooLarge, w.Code)
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	part, _ := form.CreateFormFile("uploadFile", "file")
+	part.Write(large)
+	form.Close()
+	req := httptest.NewRequest(http.MethodPost, "/upload", body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+recruitToken)
+	req.ContentLength = -1
+	w = httptest.NewRecorder()
+	limited(w, req)
+	assert.Equal(http.StatusRequestEntityTooLarge, w.Code)
 
-	// prepare request
-	method := "createDocument"
-	queryFormat := `
+	// nothing was stored
+	files, err := ioutil.ReadDir(dir)
+	failOnError(assert, err)
+	assert.Len(files, 0)
+	rawDocuments, err = crud.FindAll(config.DocumentsCollection, nil)
+	failOnError(assert, err)
+	assert.Len(rawDocuments, documentCount)
+
+	// documents can't be created without uploading them
+	response, err := gqlRequestAndRespond(createGqlHandler(crud), `
 		mutation{
-			%s(%s){
-				id
-				document
+			createDocument(doc_type: QUALIFICATION, owner_type: RECRUIT, owner_id: "id", url: "http://yurp.com"){ id }
+		}
+	`, nil)
+	failOnError(assert, err)
+	assert.Contains(response, "errors", msgNoError)
+}
+
+// tests that uploaded documents are served from the store and removed along with their Document
+func TestDocumentFiles(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	dir, err := ioutil.TempDir("", "files")
+	failOnError(assert, err)
+	defer os.RemoveAll(dir)
+	os.Setenv("STATIC_FILE_DIR", dir)
+	defer os.Unsetenv("STATIC_FILE_DIR")
+	gqlResolver := &resolver.RootResolver{}
+	gqlResolver.Init(crud)
+	store := storage.NewLocalStore(dir)
+	uploadHandler := route.NewUploadHandler(gqlResolver, store, route.DefaultMaxDocumentSize)
+	fileHandler := http.StripPrefix(storage.URLPrefix, route.NewFileHandler(store))
+	gqlHandler := createGqlHandler(crud)
+	serve := func(method, url string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		fileHandler.ServeHTTP(w, req)
+		return w
+	}
+
+	token, _ := login(crud, moc.Accounts[0].ID, "none")
+	w := uploadDocument(uploadHandler, token, "", pdfFile)
+	assert.Equal(http.StatusCreated, w.Code)
+	var document models.Document
+	failOnError(assert, json.NewDecoder(w.Body).Decode(&document))
+
+	// the documents' urls are signed, documents that link elsewhere keep their urls
+	sysToken, _ := login(crud, getSysUserAccount().ID, "none")
+	response, err := gqlRequestAndRespond(gqlHandler, fmt.Sprintf(`
+		query{
+			view(token: "%s"){
+				... on SysViewer{
+					documents{
+						id
+						url
+					}
+				}
 			}
 		}
-	`
-
-	// invalid inputs
-	url := "http://yurp.com"
-	ownerID := moc.Recruits[0].ID.Hex()
-
-	input := []string{
-		fmt.Sprintf(`
-			# case 1 no owner_id
-			url: "%s",
-			owner_type: RECRUIT,
-			doc_type: QUALIFICATION,
-		`, url),
-		fmt.Sprintf(`
-			# case 2 no url
-			owner_id: "%s",
-			owner_type: RECRUIT,
-			doc_type: QUALIFICATION,
-		`, ownerID),
-		fmt.Sprintf(`
-			# case 3 no owner_type
-			owner_id: "%s",
-			url: "%s",
-			doc_type: QUALIFICATION,
-		`, ownerID, url),
-		fmt.Sprintf(`
-			# case 4 no doc_type
-			owner_id: "%s",
-			url: "%s",
-			owner_type: RECRUIT,
-		`, ownerID, url),
+	`, sysToken), nil)
+	failOnError(assert, err)
+	assert.NotContains(response, "errors", msgUnexpectedError)
+	signedURL := ""
+	for _, raw := range response["data"].(map[string]interface{})["view"].(map[string]interface{})["documents"].([]interface{}) {
+		result := raw.(map[string]interface{})
+		if result["id"] == document.ID.Hex() {
+			signedURL = result["url"].(string)
+		} else {
+			assert.True(strings.HasPrefix(result["url"].(string), "http://google.com"), result["url"])
+		}
 	}
+	assert.True(strings.HasPrefix(signedURL, document.URL+"?"), signedURL)
+
+	// the file is served with its type and in ranges at its signed url
+	w = serve(http.MethodGet, signedURL, nil)
+	assert.Equal(http.StatusOK, w.Code)
+	assert.Equal("application/pdf", w.Header().Get("Content-Type"))
+	assert.Equal(pdfFile, w.Body.Bytes())
+	w = serve(http.MethodHead, signedURL, nil)
+	assert.Equal(http.StatusOK, w.Code)
+	assert.Equal(fmt.Sprint(len(pdfFile)), w.Header().Get("Content-Length"))
+	w = serve(http.MethodGet, signedURL, map[string]string{"Range": "bytes=0-3"})
+	assert.Equal(http.StatusPartialContent, w.Code)
+	assert.Equal("%PDF", w.Body.String())
+	missingURL, err := store.SignedURL("missing.pdf", time.Minute)
+	failOnError(assert, err)
+	assert.Equal(http.StatusNotFound, serve(http.MethodGet, missingURL, nil).Code)
+
+	// files aren't served without a valid signature
+	assert.Equal(http.StatusForbidden, serve(http.MethodGet, document.URL, nil).Code)
+	assert.Equal(http.StatusForbidden, serve(http.MethodGet, strings.Replace(signedURL, "signature=", "signature=0", 1), nil).Code)
+	assert.Equal(http.StatusForbidden, serve(http.MethodGet, storage.URLPrefix+"missing.pdf"+strings.TrimPrefix(signedURL, document.URL), nil).Code)
+	assert.Equal(http.StatusForbidden, serve(http.MethodGet, storage.URLPrefix+"../documents_test.go", nil).Code)
+	expiredURL, err := store.SignedURL(strings.TrimPrefix(document.URL, storage.URLPrefix), -time.Minute)
+	failOnError(assert, err)
+	assert.Equal(http.StatusForbidden, serve(http.MethodGet, expiredURL, nil).Code)
 
-	for i, in := range input {
-		query := fmt.Sprintf(queryFormat, method, in)
-		// request and respond
-		response, err := gqlRequestAndRespond(handler, query, nil)
-		//process response
-		assert := assert.New(t)
-		if err != nil {
-			assert.Fail("Failed to process response:", err)
+	// removing a company document removes its file but not the others
+	hunterToken, _ := login(crud, moc.Accounts[2].ID, "none")
+	w = uploadDocument(uploadHandler, hunterToken, "COMPANY", pdfFile)
+	assert.Equal(http.StatusCreated, w.Code)
+	var companyDocument models.Document
+	failOnError(assert, json.NewDecoder(w.Body).Decode(&companyDocument))
+	response, err = gqlRequestAndRespond(gqlHandler, fmt.Sprintf(`
+		mutation{
+			edit(token: "%s"){
+				... on HunterEditor{
+					removeCompanyDocument(id: "%s")
+				}
+			}
 		}
+	`, hunterToken, companyDocument.ID.Hex()), nil)
+	failOnError(assert, err)
+	assert.NotContains(response, "errors", msgUnexpectedError)
+	_, err = store.Stat(strings.TrimPrefix(companyDocument.URL, storage.URLPrefix))
+	assert.Equal(storage.ErrNotFound, err)
+	_, err = store.Stat(strings.TrimPrefix(document.URL, storage.URLPrefix))
+	assert.Nil(err)
 
-		assert.Contains(response, "errors", fmt.Sprintf("Case [%v]: %s", i+1, msgNoError))
-	}
+	// removing the uploaded document removes its file
+	response, err = gqlRequestAndRespond(gqlHandler, fmt.Sprintf(`
+		mutation{
+			edit(token: "%s"){
+				... on SysEditor{
+					removeDocument(id: "%s")
+				}
+			}
+		}
+	`, sysToken, document.ID.Hex()), nil)
+	failOnError(assert, err)
+	assert.NotContains(response, "errors", msgUnexpectedError)
+	_, err = store.Stat(strings.TrimPrefix(document.URL, storage.URLPrefix))
+	assert.Equal(storage.ErrNotFound, err)
+	assert.Equal(http.StatusNotFound, serve(http.MethodGet, signedURL, nil).Code)
 }
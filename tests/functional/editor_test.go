@@ -5,8 +5,10 @@ import (
 	"testing"
 	"time"
 
+	config "../../config"
 	moc "../../mocks"
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/mgo.v2/bson"
 )
 
 // tests that AccountEditor.UpdateAccount updates the current  account
@@ -131,10 +133,10 @@ func TestAccountEditor_CreateRecruit(t *testing.T) {
 						vid1_url: "%s",
 						vid2_url: "%s",
 						birth_year: %v,
-						qa1_question_id: "%s",
-						qa1_answer: "%s",
-						qa2_question_id: "%s",
-						qa2_answer: "%s",
+						answers: [
+							{question_id: "%s", answer: "%s"},
+							{question_id: "%s", answer: "%s"},
+						],
 					}){
 						phone
 						email
@@ -145,12 +147,10 @@ func TestAccountEditor_CreateRecruit(t *testing.T) {
 						vid1_url
 						vid2_url
 						age
-						qa1{
-							question
-							answer
-						}, 
-						qa2{
-							question
+						answers{
+							question{
+								question
+							}
 							answer
 						}
 					}
@@ -180,13 +180,15 @@ func TestAccountEditor_CreateRecruit(t *testing.T) {
 					"vid1_url":   vid1URL,
 					"vid2_url":   vid2URL,
 					"age":        float64(time.Now().Year() - birthYear),
-					"qa1": map[string]interface{}{
-						"question": qa1Question.Question,
-						"answer":   qa1Answer,
-					},
-					"qa2": map[string]interface{}{
-						"question": qa2Question.Question,
-						"answer":   qa2Answer,
+					"answers": []interface{}{
+						map[string]interface{}{
+							"question": map[string]interface{}{"question": qa1Question.Question},
+							"answer":   qa1Answer,
+						},
+						map[string]interface{}{
+							"question": map[string]interface{}{"question": qa2Question.Question},
+							"answer":   qa2Answer,
+						},
 					},
 				},
 			},
@@ -196,6 +198,93 @@ func TestAccountEditor_CreateRecruit(t *testing.T) {
 	assert.Equal(expected, response, msgInvalidResult)
 }
 
+func TestAccountEditor_CreateHunter(t *testing.T) {
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+	assert := assert.New(t)
+
+	// login as plain user
+	account := getPlainUserAccount()
+	token, _ := login(crud, account.ID, "none")
+
+	// prepare data
+	phone := "082 345 6789"
+	email := "morlin@hirewell.co.za"
+	position := "Recruiter"
+
+	// prepare query
+	query := fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: ACCOUNT){
+				... on AccountEditor{
+					createHunter(info:{
+						phone: "%s",
+						email: "%s",
+						position: "%s",
+					}){
+						name
+						phone
+						email
+						position
+					}
+				}
+			}
+		}
+	`, token, phone, email, position)
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	// prepare expected
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"edit": map[string]interface{}{
+				"createHunter": map[string]interface{}{
+					"name":     account.Name,
+					"phone":    phone,
+					"email":    email,
+					"position": position,
+				},
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests that AccountEditor.CreateHunter fails for accounts with a hunter profile
+func TestAccountEditor_CreateHunterInvalid(t *testing.T) {
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+	assert := assert.New(t)
+
+	// login as hunter user
+	token, _ := login(crud, getHunterUserAccount().ID, "none")
+
+	// prepare query
+	query := fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: ACCOUNT){
+				... on AccountEditor{
+					createHunter(info:{
+						phone: "082 345 6789",
+						email: "lisa@hirewell.co.za",
+						position: "Recruiter",
+					}){
+						id
+					}
+				}
+			}
+		}
+	`, token)
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+	assert.Contains(response, "errors", msgNoError)
+}
+
 // tests that RecruitEditor.UpdateRecruit updates Recruit
 func TestRecruitEditor_UpdateRecruit(t *testing.T) {
 	assert := assert.New(t)
@@ -292,17 +381,20 @@ func TestRecruitEditor_UpdateQAs(t *testing.T) {
 		mutation{
 			edit(token: "%s", enforce: RECRUIT){
 				... on RecruitEditor{
-					updateQAs(
-						qa1:{
+					updateQAs(answers: [
+						{
 							question_id: "%s",
 							answer: "%s"
-						}, 
-						qa2:{
+						},
+						{
 							question_id: "%s",
 							answer: "%s"
 						}
-					){
-						question
+					]){
+						question_id
+						question{
+							question
+						}
 						answer
 					}
 				}
@@ -319,12 +411,14 @@ func TestRecruitEditor_UpdateQAs(t *testing.T) {
 			"edit": map[string]interface{}{
 				"updateQAs": []interface{}{
 					map[string]interface{}{
-						"question": qa1Question.Question,
-						"answer":   qa1Answer,
+						"question_id": qa1Question.ID.Hex(),
+						"question":    map[string]interface{}{"question": qa1Question.Question},
+						"answer":      qa1Answer,
 					},
 					map[string]interface{}{
-						"question": qa2Question.Question,
-						"answer":   qa2Answer,
+						"question_id": qa2Question.ID.Hex(),
+						"question":    map[string]interface{}{"question": qa2Question.Question},
+						"answer":      qa2Answer,
 					},
 				},
 			},
@@ -369,6 +463,100 @@ func TestRecruitEditor_RemoveRecruit(t *testing.T) {
 	assert.Equal(expected, response, msgInvalidResult)
 }
 
+// tests that HunterEditor.UpdateHunter updates the current Hunter profile
+func TestHunterEditor_UpdateHunter(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as hunter user
+	token, _ := login(crud, getHunterUserAccount().ID, "none")
+
+	// prep data
+	phone := "083 111 2222"
+	position := "Head of People"
+
+	// prepare query
+	query := fmt.Sprintf(`
+		mutation {
+			edit(token: "%s", enforce: HUNTER){
+				... on HunterEditor{
+					updateHunter(info: {
+						phone: "%s",
+						position: "%s",
+					}){
+						phone
+						email
+						position
+					}
+				}
+			}
+		}
+	`, token, phone, position)
+
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"edit": map[string]interface{}{
+				"updateHunter": map[string]interface{}{
+					"phone":    phone,
+					"email":    moc.Hunters[0].Email,
+					"position": position,
+				},
+			},
+		},
+	}
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+func TestHunterEditor_RemoveHunter(t *testing.T) {
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+	assert := assert.New(t)
+
+	removeHunter := func(accountID bson.ObjectId) map[string]interface{} {
+		token, _ := login(crud, accountID, "none")
+		response, err := gqlRequestAndRespond(handler, fmt.Sprintf(`
+			mutation{
+				edit(token: "%s", enforce: HUNTER){
+					... on HunterEditor{
+						removeHunter
+					}
+				}
+			}
+		`, token), nil)
+		failOnError(assert, err)
+		return response
+	}
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"edit": map[string]interface{}{
+				"removeHunter": "Hunter successfully removed.",
+			},
+		},
+	}
+
+	// the only owner can't leave a company with other members behind
+	response := removeHunter(moc.Accounts[2].ID)
+	assert.Contains(response, "errors", msgNoError)
+	_, err := crud.FindID(config.HuntersCollection, moc.Hunters[0].ID)
+	assert.Nil(err)
+
+	// other members can
+	assert.Equal(expected, removeHunter(moc.Accounts[3].ID), msgInvalidResult)
+	_, err = crud.FindID(config.HuntersCollection, moc.Hunters[1].ID)
+	assert.NotNil(err)
+	_, err = crud.FindID(config.CompaniesCollection, moc.Companies[0].ID)
+	assert.Nil(err)
+
+	// and the last member takes the company along
+	assert.Equal(expected, removeHunter(moc.Accounts[2].ID), msgInvalidResult)
+	_, err = crud.FindID(config.CompaniesCollection, moc.Companies[0].ID)
+	assert.NotNil(err)
+}
+
 // tests that edit mutation cannot be accessed without a valid token
 func TestEditWithInvalidToken(t *testing.T) {
 	crud := moc.NewLoadedCRUD()
@@ -0,0 +1,228 @@
+package functionaltests
+
+import (
+	"fmt"
+	"testing"
+
+	moc "../../mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// tests that hunters see a Recruit's history, most recent first
+func TestRecruit_History(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	token, _ := login(crud, moc.Accounts[2].ID, "none")
+	query := fmt.Sprintf(`
+		query{
+			view(token: "%s", enforce: HUNTER){
+				... on HunterViewer{
+					recruit(id: "%s"){
+						work_experience{
+							employer
+							title
+							start_date
+							end_date
+							current
+						}
+						education{
+							institution
+							qualification
+							nqf_level
+							year
+						}
+					}
+				}
+			}
+		}
+	`, token, moc.Recruits[1].ID.Hex())
+
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"view": map[string]interface{}{
+				"recruit": map[string]interface{}{
+					"work_experience": []interface{}{
+						map[string]interface{}{
+							"employer":   "Vodacom",
+							"title":      "Call Centre Agent",
+							"start_date": "2016-07-01T00:00:00Z",
+							"end_date":   nil,
+							"current":    true,
+						},
+						map[string]interface{}{
+							"employer":   "Pick n Pay",
+							"title":      "Cashier",
+							"start_date": "2014-02-01T00:00:00Z",
+							"end_date":   "2016-06-30T00:00:00Z",
+							"current":    false,
+						},
+					},
+					"education": []interface{}{
+						map[string]interface{}{
+							"institution":   "University of Johannesburg",
+							"qualification": "National Diploma in Marketing",
+							"nqf_level":     float64(6),
+							"year":          float64(2016),
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests adding, updating and removing work experience
+func TestRecruitEditor_WorkExperience(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	token, _ := login(crud, moc.Accounts[0].ID, "none")
+	mutation := `
+		mutation{
+			edit(token: "%s", enforce: RECRUIT){
+				... on RecruitEditor{
+					%s
+				}
+			}
+		}
+	`
+	fields := "{ id employer title end_date current description }"
+
+	// add a current position
+	response, err := gqlRequestAndRespond(handler, fmt.Sprintf(mutation, token,
+		`addWorkExperience(info: {employer: " Shoprite ", title: "Packer", start_date: "2010-01-15"})`+fields), nil)
+	failOnError(assert, err)
+	data := assertGqlData("edit", response, assert)
+	work := data["edit"].(map[string]interface{})["addWorkExperience"].(map[string]interface{})
+	assert.Equal("Shoprite", work["employer"])
+	assert.Equal(true, work["current"])
+	assert.Equal("", work["description"])
+	id := work["id"].(string)
+
+	// end it
+	response, err = gqlRequestAndRespond(handler, fmt.Sprintf(mutation, token, fmt.Sprintf(
+		`updateWorkExperience(id: "%s", info: {employer: "Shoprite", title: "Supervisor", start_date: "2010-01-15", end_date: "2012-03-31", description: "Ran the night shift."})`+fields, id)), nil)
+	failOnError(assert, err)
+	data = assertGqlData("edit", response, assert)
+	assert.Equal(map[string]interface{}{
+		"id":          id,
+		"employer":    "Shoprite",
+		"title":       "Supervisor",
+		"end_date":    "2012-03-31T00:00:00Z",
+		"current":     false,
+		"description": "Ran the night shift.",
+	}, data["edit"].(map[string]interface{})["updateWorkExperience"], msgInvalidResult)
+
+	// positions can't end before they start
+	response, err = gqlRequestAndRespond(handler, fmt.Sprintf(mutation, token, fmt.Sprintf(
+		`updateWorkExperience(id: "%s", info: {employer: "Shoprite", title: "Supervisor", start_date: "2010-01-15", end_date: "2009-01-01"})`+fields, id)), nil)
+	failOnError(assert, err)
+	assert.NotNil(response["errors"], msgInvalidResult)
+
+	// the profile has the entry
+	profile := fmt.Sprintf(`
+		query{
+			view(token: "%s", enforce: RECRUIT){
+				... on RecruitViewer{
+					profile{
+						work_experience{
+							title
+						}
+					}
+				}
+			}
+		}
+	`, token)
+	response, err = gqlRequestAndRespond(handler, profile, nil)
+	failOnError(assert, err)
+	data = assertGqlData("view", response, assert)
+	assert.Equal([]interface{}{map[string]interface{}{"title": "Supervisor"}},
+		data["view"].(map[string]interface{})["profile"].(map[string]interface{})["work_experience"], msgInvalidResult)
+
+	// remove it
+	response, err = gqlRequestAndRespond(handler, fmt.Sprintf(mutation, token, fmt.Sprintf(`removeWorkExperience(id: "%s")`, id)), nil)
+	failOnError(assert, err)
+	data = assertGqlData("edit", response, assert)
+	assert.Equal("Work experience successfully removed.", data["edit"].(map[string]interface{})["removeWorkExperience"])
+
+	response, err = gqlRequestAndRespond(handler, profile, nil)
+	failOnError(assert, err)
+	data = assertGqlData("view", response, assert)
+	assert.Equal([]interface{}{}, data["view"].(map[string]interface{})["profile"].(map[string]interface{})["work_experience"], msgInvalidResult)
+
+	// it's gone
+	response, err = gqlRequestAndRespond(handler, fmt.Sprintf(mutation, token, fmt.Sprintf(`removeWorkExperience(id: "%s")`, id)), nil)
+	failOnError(assert, err)
+	assert.NotNil(response["errors"], msgInvalidResult)
+}
+
+// tests adding, updating and removing education
+func TestRecruitEditor_Education(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	token, _ := login(crud, moc.Accounts[1].ID, "none")
+	mutation := `
+		mutation{
+			edit(token: "%s", enforce: RECRUIT){
+				... on RecruitEditor{
+					%s
+				}
+			}
+		}
+	`
+
+	// NQF levels only go up to 10
+	response, err := gqlRequestAndRespond(handler, fmt.Sprintf(mutation, token,
+		`addEducation(info: {institution: "Wits", qualification: "PhD", nqf_level: 11, year: 2019}){ id }`), nil)
+	failOnError(assert, err)
+	assert.NotNil(response["errors"], msgInvalidResult)
+
+	response, err = gqlRequestAndRespond(handler, fmt.Sprintf(mutation, token,
+		`addEducation(info: {institution: "Wits", qualification: "BCom Honours", nqf_level: 8, year: 2018}){ id nqf_level }`), nil)
+	failOnError(assert, err)
+	data := assertGqlData("edit", response, assert)
+	education := data["edit"].(map[string]interface{})["addEducation"].(map[string]interface{})
+	assert.Equal(float64(8), education["nqf_level"])
+	id := education["id"].(string)
+
+	response, err = gqlRequestAndRespond(handler, fmt.Sprintf(mutation, token, fmt.Sprintf(
+		`updateEducation(id: "%s", info: {institution: "Wits", qualification: "BCom Honours", nqf_level: 8, year: 2017}){ year }`, id)), nil)
+	failOnError(assert, err)
+	data = assertGqlData("edit", response, assert)
+	assert.Equal(map[string]interface{}{"year": float64(2017)}, data["edit"].(map[string]interface{})["updateEducation"], msgInvalidResult)
+
+	// the newest qualification is listed first
+	query := fmt.Sprintf(`
+		query{
+			view(token: "%s", enforce: RECRUIT){
+				... on RecruitViewer{
+					profile{
+						education{
+							qualification
+						}
+					}
+				}
+			}
+		}
+	`, token)
+	response, err = gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+	data = assertGqlData("view", response, assert)
+	assert.Equal([]interface{}{
+		map[string]interface{}{"qualification": "BCom Honours"},
+		map[string]interface{}{"qualification": "National Diploma in Marketing"},
+	}, data["view"].(map[string]interface{})["profile"].(map[string]interface{})["education"], msgInvalidResult)
+
+	response, err = gqlRequestAndRespond(handler, fmt.Sprintf(mutation, token, fmt.Sprintf(`removeEducation(id: "%s")`, id)), nil)
+	failOnError(assert, err)
+	data = assertGqlData("edit", response, assert)
+	assert.Equal("Education successfully removed.", data["edit"].(map[string]interface{})["removeEducation"])
+}
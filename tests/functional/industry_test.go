@@ -0,0 +1,192 @@
+package functionaltests
+
+import (
+	"fmt"
+	"testing"
+
+	config "../../config"
+	moc "../../mocks"
+	models "../../models"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// tests that recruits can choose the industries they want to work in
+func TestRecruitEditor_Industries(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	token, _ := login(crud, moc.Accounts[0].ID, "none")
+	edit := func(field string) map[string]interface{} {
+		response, err := gqlRequestAndRespond(handler, fmt.Sprintf(editMutation, token, "RECRUIT", "Recruit", field), nil)
+		failOnError(assert, err)
+		return response
+	}
+
+	// industries are listed in the recruit's order, without duplicates
+	response := edit(fmt.Sprintf(`updateRecruit(info: {industry_ids: ["%s", "%s", "%s"]}){ industries{ id name } }`,
+		moc.Industries[1].ID.Hex(), moc.Industries[0].ID.Hex(), moc.Industries[1].ID.Hex()))
+	data := assertGqlData("edit", response, assert)
+	assert.Equal(map[string]interface{}{
+		"industries": []interface{}{
+			map[string]interface{}{"id": moc.Industries[1].ID.Hex(), "name": "architecture"},
+			map[string]interface{}{"id": moc.Industries[0].ID.Hex(), "name": "statistics"},
+		},
+	}, data["edit"].(map[string]interface{})["updateRecruit"], msgInvalidResult)
+
+	// industries need to exist
+	response = edit(fmt.Sprintf(`updateRecruit(info: {industry_ids: ["%s"]}){ city }`, bson.NewObjectId().Hex()))
+	assert.NotNil(response["errors"], msgInvalidResult)
+	response = edit(`updateRecruit(info: {industry_ids: ["industry"]}){ city }`)
+	assert.NotNil(response["errors"], msgInvalidResult)
+
+	rawRecruit, err := crud.FindID(config.RecruitsCollection, moc.Recruits[0].ID)
+	failOnError(assert, err)
+	assert.Equal([]bson.ObjectId{moc.Industries[1].ID, moc.Industries[0].ID}, rawRecruit.(bson.M)["industry_ids"], msgInvalidResult)
+
+	// and can be cleared
+	response = edit(`updateRecruit(info: {industry_ids: []}){ industries{ name } }`)
+	data = assertGqlData("edit", response, assert)
+	assert.Equal(map[string]interface{}{"industries": []interface{}{}}, data["edit"].(map[string]interface{})["updateRecruit"], msgInvalidResult)
+}
+
+// tests that removing an industry removes it from recruits along with its skills
+func TestSysEditor_RemoveIndustryCascade(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	token, _ := login(crud, getSysUserAccount().ID, "none")
+	edit := func(field string) map[string]interface{} {
+		response, err := gqlRequestAndRespond(handler, fmt.Sprintf(editMutation, token, "SYSTEM", "Sys", field), nil)
+		failOnError(assert, err)
+		return response
+	}
+
+	response := edit(fmt.Sprintf(`removeIndustry(id: "%s")`, moc.Industries[0].ID.Hex()))
+	data := assertGqlData("edit", response, assert)
+	assert.Equal("Industry successfully removed.", data["edit"].(map[string]interface{})["removeIndustry"])
+
+	rawRecruit, err := crud.FindID(config.RecruitsCollection, moc.Recruits[0].ID)
+	failOnError(assert, err)
+	assert.Len(rawRecruit.(bson.M)["industry_ids"], 0)
+	assert.Len(rawRecruit.(bson.M)["skills"], 0)
+
+	rawRecruit, err = crud.FindID(config.RecruitsCollection, moc.Recruits[1].ID)
+	failOnError(assert, err)
+	assert.Equal([]bson.ObjectId{moc.Industries[1].ID}, rawRecruit.(bson.M)["industry_ids"], msgInvalidResult)
+	assert.Len(rawRecruit.(bson.M)["skills"], 1)
+
+	rawSkills, err := crud.FindAll(config.SkillsCollection, bson.M{"industry_id": moc.Industries[0].ID})
+	failOnError(assert, err)
+	assert.Len(rawSkills, 0)
+	_, err = crud.FindOne(config.EndorsementsCollection, bson.M{"skill_id": moc.Skills[0].ID})
+	assert.NotNil(err, msgInvalidResult)
+
+	// the industry is gone
+	response = edit(fmt.Sprintf(`removeIndustry(id: "%s")`, moc.Industries[0].ID.Hex()))
+	assert.NotNil(response["errors"], msgInvalidResult)
+
+	// new industries can be chosen by recruits
+	response = edit(`createIndustry(name: "Mining"){ id name }`)
+	data = assertGqlData("edit", response, assert)
+	industry := data["edit"].(map[string]interface{})["createIndustry"].(map[string]interface{})
+	assert.True(bson.IsObjectIdHex(industry["id"].(string)), msgInvalidResult)
+}
+
+// tests that industries can be nested in other industries
+func TestSysEditor_IndustryHierarchy(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	token, _ := login(crud, getSysUserAccount().ID, "none")
+	edit := func(field string) map[string]interface{} {
+		response, err := gqlRequestAndRespond(handler, fmt.Sprintf(editMutation, token, "SYSTEM", "Sys", field), nil)
+		failOnError(assert, err)
+		return response
+	}
+	create := func(name, parentID string) string {
+		response := edit(fmt.Sprintf(`createIndustry(name: "%s", parent_id: "%s"){ id }`, name, parentID))
+		data := assertGqlData("edit", response, assert)
+		return data["edit"].(map[string]interface{})["createIndustry"].(map[string]interface{})["id"].(string)
+	}
+	names := func(industries interface{}) []string {
+		result := make([]string, 0)
+		for _, i := range industries.([]interface{}) {
+			result = append(result, i.(map[string]interface{})["name"].(string))
+		}
+		return result
+	}
+
+	// Engineering > Civil > Structural
+	engineering := create("Engineering", "")
+	civil := create("Civil", engineering)
+	create("Mechanical", engineering)
+	structural := create("Structural", civil)
+	response := edit(fmt.Sprintf(`updateIndustry(id: "%s", name: "Civil"){ parent_id children{ name } ancestors{ name } }`, civil))
+	data := assertGqlData("edit", response, assert)
+	industry := data["edit"].(map[string]interface{})["updateIndustry"].(map[string]interface{})
+	assert.Equal(engineering, industry["parent_id"], msgInvalidResult)
+	assert.Equal([]string{"structural"}, names(industry["children"]), msgInvalidResult)
+	assert.Equal([]string{"engineering"}, names(industry["ancestors"]), msgInvalidResult)
+
+	// an industry can't be nested in itself or in its sub-industries
+	response = edit(fmt.Sprintf(`updateIndustry(id: "%s", name: "Engineering", parent_id: "%s"){ id }`, engineering, structural))
+	assert.NotNil(response["errors"], msgInvalidResult)
+	response = edit(fmt.Sprintf(`updateIndustry(id: "%s", name: "Engineering", parent_id: "%s"){ id }`, engineering, engineering))
+	assert.NotNil(response["errors"], msgInvalidResult)
+	response = edit(fmt.Sprintf(`createIndustry(name: "Nowhere", parent_id: "%s"){ id }`, bson.NewObjectId().Hex()))
+	assert.NotNil(response["errors"], msgInvalidResult)
+
+	// nor too deep
+	parentID := structural
+	for depth := 4; depth <= models.MaxIndustryDepth; depth++ {
+		parentID = create(fmt.Sprintf("Level %d", depth), parentID)
+	}
+	response = edit(fmt.Sprintf(`createIndustry(name: "Too deep", parent_id: "%s"){ id }`, parentID))
+	assert.NotNil(response["errors"], msgInvalidResult)
+
+	// sub-industries inherit the questions of their parents
+	for _, id := range []string{engineering, civil} {
+		response = edit(fmt.Sprintf(`createQuestion(industry_id: "%s", question: "Why %s?"){ id }`, id, id))
+		assertGqlData("edit", response, assert)
+	}
+	questions := func(args string) []string {
+		response, err := gqlRequestAndRespond(handler, fmt.Sprintf(`query{ randomQuestions(industry_id: "%s", count: 5%s){ question } }`, structural, args), nil)
+		failOnError(assert, err)
+		data := assertGqlData("randomQuestions", response, assert)
+		result := make([]string, 0)
+		for _, q := range data["randomQuestions"].([]interface{}) {
+			result = append(result, q.(map[string]interface{})["question"].(string))
+		}
+		return result
+	}
+	assert.ElementsMatch([]string{"Why " + engineering + "?", "Why " + civil + "?"}, questions(""), msgInvalidResult)
+	assert.Len(questions(", inherited: false"), 0, msgInvalidResultCount)
+
+	// removing an industry moves its sub-industries up
+	response = edit(fmt.Sprintf(`removeIndustry(id: "%s")`, civil))
+	assertGqlData("edit", response, assert)
+	response = edit(fmt.Sprintf(`updateIndustry(id: "%s", name: "Structural"){ parent_id ancestors{ name } }`, structural))
+	data = assertGqlData("edit", response, assert)
+	assert.Equal(map[string]interface{}{
+		"parent_id": engineering,
+		"ancestors": []interface{}{map[string]interface{}{"name": "engineering"}},
+	}, data["edit"].(map[string]interface{})["updateIndustry"], msgInvalidResult)
+
+	// unless one of them has the same name as an industry there
+	create("Mechanical", structural)
+	response = edit(fmt.Sprintf(`removeIndustry(id: "%s")`, structural))
+	assert.NotNil(response["errors"], msgInvalidResult)
+
+	// an empty parent makes an industry top level
+	response = edit(fmt.Sprintf(`updateIndustry(id: "%s", name: "Structural", parent_id: ""){ parent_id ancestors{ name } }`, structural))
+	data = assertGqlData("edit", response, assert)
+	assert.Equal(map[string]interface{}{"parent_id": nil, "ancestors": []interface{}{}},
+		data["edit"].(map[string]interface{})["updateIndustry"], msgInvalidResult)
+	rawIndustry, err := crud.FindID(config.IndustriesCollection, bson.ObjectIdHex(structural))
+	failOnError(assert, err)
+	assert.NotContains(rawIndustry, "parent_id", msgInvalidResult)
+}
@@ -0,0 +1,516 @@
+package functionaltests
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	moc "../../mocks"
+	route "../../routing"
+	utils "../../utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// takeIcsURL takes the ics_url out of a resolved interview, checking it's the
+// interview's calendar path with a calendar token
+func takeIcsURL(assert *assert.Assertions, interview interface{}, id string) string {
+	fields, ok := interview.(map[string]interface{})
+	if !ok {
+		assert.Fail(msgInvalidResult)
+		return ""
+	}
+	url, _ := fields["ics_url"].(string)
+	assert.Regexp("^/calendar/"+id+`\.ics\?token=[\w-]+\.[\w-]+\.[\w-]+$`, url, msgInvalidResult)
+	delete(fields, "ics_url")
+	return url
+}
+
+// tests that RecruitViewer.Interviews lists the Recruit's interviews
+func TestRecruitViewer_Interviews(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Recruits[0]
+	token, _ := login(crud, moc.Accounts[0].ID, "none")
+	interview := moc.Interviews[0]
+
+	// prepare query
+	query := fmt.Sprintf(`
+		query{
+			view(token: "%s", enforce: RECRUIT){
+				... on RecruitViewer{
+					interviews{
+						id
+						status
+						location
+						ics_url
+						application{
+							id
+						}
+					}
+				}
+			}
+		}
+	`, token)
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+	if interviews, ok := response["data"].(map[string]interface{})["view"].(map[string]interface{})["interviews"].([]interface{}); ok && len(interviews) == 1 {
+		takeIcsURL(assert, interviews[0], interview.ID.Hex())
+	}
+
+	// prepare expected
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"view": map[string]interface{}{
+				"interviews": []interface{}{
+					map[string]interface{}{
+						"id":          interview.ID.Hex(),
+						"status":      "SCHEDULED",
+						"location":    interview.Location,
+						"application": map[string]interface{}{"id": interview.ApplicationID.Hex()},
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests that HunterViewer.Interviews lists the Company's interviews
+func TestHunterViewer_Interviews(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Companies[0] recruiter
+	token, _ := login(crud, moc.Accounts[3].ID, "none")
+	interview := moc.Interviews[1]
+
+	// prepare query
+	query := fmt.Sprintf(`
+		query{
+			view(token: "%s", enforce: HUNTER){
+				... on HunterViewer{
+					interviews(vacancy_id: "%s"){
+						id
+						status
+						start
+						ics_url
+						slots{
+							start
+						}
+						recruit{
+							id
+						}
+					}
+				}
+			}
+		}
+	`, token, interview.VacancyID.Hex())
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	// prepare expected
+	slots := make([]interface{}, 0)
+	for _, slot := range interview.Slots {
+		slots = append(slots, map[string]interface{}{
+			"start": slot.Start.UTC().Format(time.RFC3339),
+		})
+	}
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"view": map[string]interface{}{
+				"interviews": []interface{}{
+					map[string]interface{}{
+						"id":      interview.ID.Hex(),
+						"status":  "PROPOSED",
+						"start":   nil,
+						"ics_url": nil,
+						"slots":   slots,
+						"recruit": map[string]interface{}{"id": interview.RecruitID.Hex()},
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests that HunterEditor.ProposeInterview proposes slots to an applicant
+func TestHunterEditor_ProposeInterview(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Companies[0] owner
+	token, _ := login(crud, moc.Accounts[2].ID, "none")
+	application := moc.Applications[0]
+	start := time.Now().AddDate(0, 0, 5).UTC().Truncate(time.Second)
+
+	// prepare query
+	query := fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: HUNTER){
+				... on HunterEditor{
+					proposeInterview(application_id: "%s", slots: [{start: "%s", end: "%s"}], location: "Sandton"){
+						status
+						location
+						sequence
+						slots{
+							start
+							end
+						}
+						recruit{
+							id
+						}
+					}
+				}
+			}
+		}
+	`, token, application.ID.Hex(), start.Format(time.RFC3339), start.Add(time.Hour).Format(time.RFC3339))
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	// prepare expected
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"edit": map[string]interface{}{
+				"proposeInterview": map[string]interface{}{
+					"status":   "PROPOSED",
+					"location": "Sandton",
+					"sequence": float64(0),
+					"slots": []interface{}{
+						map[string]interface{}{
+							"start": start.Format(time.RFC3339),
+							"end":   start.Add(time.Hour).Format(time.RFC3339),
+						},
+					},
+					"recruit": map[string]interface{}{"id": application.RecruitID.Hex()},
+				},
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests that RecruitEditor.AcceptInterview schedules an Interview
+func TestRecruitEditor_AcceptInterview(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Recruits[1]
+	token, _ := login(crud, moc.Accounts[1].ID, "none")
+	interview := moc.Interviews[1]
+
+	// prepare query
+	query := fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: RECRUIT){
+				... on RecruitEditor{
+					acceptInterview(id: "%s", slot: 1){
+						status
+						start
+						end
+						ics_url
+					}
+				}
+			}
+		}
+	`, token, interview.ID.Hex())
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+	if edit, ok := response["data"].(map[string]interface{})["edit"].(map[string]interface{}); ok {
+		takeIcsURL(assert, edit["acceptInterview"], interview.ID.Hex())
+	}
+
+	// prepare expected
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"edit": map[string]interface{}{
+				"acceptInterview": map[string]interface{}{
+					"status": "SCHEDULED",
+					"start":  interview.Slots[1].Start.UTC().Format(time.RFC3339),
+					"end":    interview.Slots[1].End.UTC().Format(time.RFC3339),
+				},
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests that HunterEditor.RescheduleInterview moves a scheduled Interview back to a proposal
+func TestHunterEditor_RescheduleInterview(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Companies[1] owner
+	token, _ := login(crud, moc.Accounts[4].ID, "none")
+	interview := moc.Interviews[0]
+	start := time.Now().AddDate(0, 0, 10).UTC().Truncate(time.Second)
+
+	// prepare query
+	query := fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: HUNTER){
+				... on HunterEditor{
+					rescheduleInterview(id: "%s", slots: [{start: "%s", end: "%s"}]){
+						status
+						start
+						location
+						sequence
+					}
+				}
+			}
+		}
+	`, token, interview.ID.Hex(), start.Format(time.RFC3339), start.Add(time.Hour).Format(time.RFC3339))
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	// prepare expected
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"edit": map[string]interface{}{
+				"rescheduleInterview": map[string]interface{}{
+					"status":   "PROPOSED",
+					"start":    nil,
+					"location": interview.Location,
+					"sequence": float64(interview.Sequence + 1),
+				},
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests that RecruitEditor.CancelInterview cancels an Interview
+func TestRecruitEditor_CancelInterview(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Recruits[0]
+	token, _ := login(crud, moc.Accounts[0].ID, "none")
+	interview := moc.Interviews[0]
+
+	// prepare query
+	query := fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: RECRUIT){
+				... on RecruitEditor{
+					cancelInterview(id: "%s"){
+						status
+						sequence
+					}
+				}
+			}
+		}
+	`, token, interview.ID.Hex())
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	// prepare expected
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"edit": map[string]interface{}{
+				"cancelInterview": map[string]interface{}{
+					"status":   "CANCELLED",
+					"sequence": float64(interview.Sequence + 1),
+				},
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests that invalid interview requests fail
+func TestInterviewInvalid(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// get access tokens
+	recruitToken, _ := login(crud, moc.Accounts[0].ID, "none")
+	ownerToken, _ := login(crud, moc.Accounts[2].ID, "none")
+	future := time.Now().AddDate(0, 0, 2).UTC().Format(time.RFC3339)
+	past := time.Now().AddDate(0, 0, -2).UTC().Format(time.RFC3339)
+
+	// invalid inputs
+	input := []string{
+		fmt.Sprintf(`
+			# case 1 propose a slot in the past
+			mutation{
+				edit(token: "%s", enforce: HUNTER){
+					... on HunterEditor{
+						proposeInterview(application_id: "%s", slots: [{start: "%s", end: "%s"}], location: "Sandton"){ id }
+					}
+				}
+			}
+		`, ownerToken, moc.Applications[0].ID.Hex(), past, future),
+		fmt.Sprintf(`
+			# case 2 propose a second interview for an application
+			mutation{
+				edit(token: "%s", enforce: HUNTER){
+					... on HunterEditor{
+						proposeInterview(application_id: "%s", slots: [{start: "%s", end: "%s"}], location: "Sandton"){ id }
+					}
+				}
+			}
+		`, ownerToken, moc.Applications[1].ID.Hex(), future, future),
+		fmt.Sprintf(`
+			# case 3 propose an interview for another company's application
+			mutation{
+				edit(token: "%s", enforce: HUNTER){
+					... on HunterEditor{
+						proposeInterview(application_id: "%s", slots: [], location: "Sandton"){ id }
+					}
+				}
+			}
+		`, ownerToken, moc.Applications[2].ID.Hex()),
+		fmt.Sprintf(`
+			# case 4 accept another recruit's interview
+			mutation{
+				edit(token: "%s", enforce: RECRUIT){
+					... on RecruitEditor{
+						acceptInterview(id: "%s", slot: 0){ id }
+					}
+				}
+			}
+		`, recruitToken, moc.Interviews[1].ID.Hex()),
+		fmt.Sprintf(`
+			# case 5 accept an interview that has already been scheduled
+			mutation{
+				edit(token: "%s", enforce: RECRUIT){
+					... on RecruitEditor{
+						acceptInterview(id: "%s", slot: 0){ id }
+					}
+				}
+			}
+		`, recruitToken, moc.Interviews[0].ID.Hex()),
+		fmt.Sprintf(`
+			# case 6 cancel another company's interview
+			mutation{
+				edit(token: "%s", enforce: HUNTER){
+					... on HunterEditor{
+						cancelInterview(id: "%s"){ id }
+					}
+				}
+			}
+		`, ownerToken, moc.Interviews[0].ID.Hex()),
+	}
+
+	for i, query := range input {
+		// request
+		response, err := gqlRequestAndRespond(handler, query, nil)
+		failOnError(assert, err)
+		assert.Contains(response, "errors", fmt.Sprintf("Case [%v]: %s", i+1, msgNoError))
+	}
+}
+
+// tests that scheduled interviews are served as iCalendar files to their participants only,
+// with the calendar token of their ics_url
+func TestInterviewCalendar(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	router := route.NewRouter(crud)
+	handler := createGqlHandler(crud)
+	interview := moc.Interviews[0]
+	get := func(path string) *http.Response {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w.Result()
+	}
+
+	// the recruit and the company's hunters may download the invite
+	for _, i := range []int{0, 4} {
+		token, _ := login(crud, moc.Accounts[i].ID, "none")
+		response, err := gqlRequestAndRespond(handler, fmt.Sprintf(`
+			query{
+				view(token: "%s"){
+					... on RecruitViewer{
+						interviews{
+							ics_url
+						}
+					}
+					... on HunterViewer{
+						interviews{
+							ics_url
+						}
+					}
+				}
+			}
+		`, token), nil)
+		failOnError(assert, err)
+		interviews, _ := response["data"].(map[string]interface{})["view"].(map[string]interface{})["interviews"].([]interface{})
+		if !assert.NotEmpty(interviews, msgInvalidResult) {
+			continue
+		}
+		res := get(takeIcsURL(assert, interviews[0], interview.ID.Hex()))
+		body, err := ioutil.ReadAll(res.Body)
+		failOnError(assert, err)
+
+		assert.Equal(200, res.StatusCode, msgInvalidResult)
+		assert.Equal("text/calendar; charset=utf-8", res.Header.Get("Content-Type"), msgInvalidResult)
+		ics := string(body)
+		assert.True(strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\n"), msgInvalidResult)
+		assert.Contains(ics, "METHOD:REQUEST\r\n", msgInvalidResult)
+		assert.Contains(ics, "UID:"+interview.ID.Hex()+"@irecruit\r\n", msgInvalidResult)
+		assert.Contains(ics, "DTSTART:"+interview.Start.UTC().Format("20060102T150405Z")+"\r\n", msgInvalidResult)
+		assert.Contains(ics, "SUMMARY:Interview: Research Intern at Cape Hire\r\n", msgInvalidResult)
+		assert.Contains(ics, "LOCATION:12 Long Street\\, Cape Town\r\n", msgInvalidResult)
+
+		// the hunter hasn't been granted the recruit's contact details
+		if i == 0 {
+			assert.Contains(ics, "mailto:"+moc.Accounts[0].Email, msgInvalidResult)
+		} else {
+			assert.NotContains(ics, "mailto:"+moc.Accounts[0].Email, msgInvalidResult)
+		}
+	}
+
+	// access tokens, tokens for other interviews, other companies' hunters and missing tokens are refused
+	path := "/calendar/" + interview.ID.Hex() + ".ics?token="
+	accessToken, _ := login(crud, moc.Accounts[0].ID, "none")
+	otherInterview, _ := utils.CreateCalendarToken(moc.Accounts[0].ID.Hex(), moc.Interviews[1].ID.Hex())
+	otherCompany, _ := utils.CreateCalendarToken(moc.Accounts[2].ID.Hex(), interview.ID.Hex())
+	for _, token := range []string{accessToken, otherInterview, otherCompany, ""} {
+		assert.Equal(404, get(path+token).StatusCode, msgInvalidResult)
+	}
+
+	// and calendar tokens can't be used as access tokens
+	calendarToken, _ := utils.CreateCalendarToken(moc.Accounts[0].ID.Hex(), interview.ID.Hex())
+	response, err := gqlRequestAndRespond(handler, fmt.Sprintf(`
+		query{
+			view(token: "%s", enforce: RECRUIT){
+				... on RecruitViewer{
+					id
+				}
+			}
+		}
+	`, calendarToken), nil)
+	failOnError(assert, err)
+	assert.Contains(response, "errors", msgNoError)
+}
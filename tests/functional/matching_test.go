@@ -0,0 +1,163 @@
+package functionaltests
+
+import (
+	"fmt"
+	"testing"
+
+	moc "../../mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// tests that HunterViewer.RecommendedRecruits ranks the recruits who haven't applied
+func TestHunterViewer_RecommendedRecruits(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Companies[1] owner
+	token, _ := login(crud, moc.Accounts[4].ID, "none")
+
+	// prepare query
+	query := fmt.Sprintf(`
+		query{
+			view(token: "%s", enforce: HUNTER){
+				... on HunterViewer{
+					recommendedRecruits(vacancy_id: "%s"){
+						recruit{
+							id
+						}
+						score
+						components{
+							name
+							score
+						}
+					}
+				}
+			}
+		}
+	`, token, moc.Vacancies[2].ID.Hex())
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	// prepare expected
+	components := func(industry, location, answers, availability int) []interface{} {
+		return []interface{}{
+			map[string]interface{}{"name": "INDUSTRY", "score": float64(industry)},
+			map[string]interface{}{"name": "LOCATION", "score": float64(location)},
+			map[string]interface{}{"name": "ANSWERS", "score": float64(answers)},
+			map[string]interface{}{"name": "AVAILABILITY", "score": float64(availability)},
+		}
+	}
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"view": map[string]interface{}{
+				"recommendedRecruits": []interface{}{
+					map[string]interface{}{
+						"recruit":    map[string]interface{}{"id": moc.Recruits[2].ID.Hex()},
+						"score":      float64(60),
+						"components": components(40, 0, 10, 10),
+					},
+					map[string]interface{}{
+						"recruit":    map[string]interface{}{"id": moc.Recruits[1].ID.Hex()},
+						"score":      float64(10),
+						"components": components(0, 0, 0, 10),
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+
+	// only recruits with all of the skills are recommended
+	query = fmt.Sprintf(`
+		query{
+			view(token: "%s", enforce: HUNTER){
+				... on HunterViewer{
+					recommendedRecruits(vacancy_id: "%s", skill_ids: ["%s"]){
+						recruit{
+							id
+						}
+					}
+				}
+			}
+		}
+	`, token, moc.Vacancies[2].ID.Hex(), moc.Skills[2].ID.Hex())
+	response, err = gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+	assert.Equal(map[string]interface{}{
+		"data": map[string]interface{}{
+			"view": map[string]interface{}{
+				"recommendedRecruits": []interface{}{
+					map[string]interface{}{"recruit": map[string]interface{}{"id": moc.Recruits[1].ID.Hex()}},
+				},
+			},
+		},
+	}, response, msgInvalidResult)
+}
+
+// tests that RecruitViewer.RecommendedVacancies ranks open vacancies with explanations
+func TestRecruitViewer_RecommendedVacancies(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Recruits[2]
+	token, _ := login(crud, getSysUserAccount().ID, "none")
+
+	// prepare query
+	query := fmt.Sprintf(`
+		query{
+			view(token: "%s", enforce: RECRUIT){
+				... on RecruitViewer{
+					recommendedVacancies{
+						vacancy{
+							id
+						}
+						score
+						components{
+							name
+							explanation
+						}
+					}
+				}
+			}
+		}
+	`, token)
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	// prepare expected
+	components := func(location string) []interface{} {
+		return []interface{}{
+			map[string]interface{}{"name": "INDUSTRY", "explanation": "Works in the vacancy's industry."},
+			map[string]interface{}{"name": "LOCATION", "explanation": location},
+			map[string]interface{}{"name": "ANSWERS", "explanation": "Answered 1 question in the vacancy's industry."},
+			map[string]interface{}{"name": "AVAILABILITY", "explanation": "Is not committed to another vacancy."},
+		}
+	}
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"view": map[string]interface{}{
+				"recommendedVacancies": []interface{}{
+					map[string]interface{}{
+						"vacancy":    map[string]interface{}{"id": moc.Vacancies[0].ID.Hex()},
+						"score":      float64(70),
+						"components": components("Lives in a neighbouring province."),
+					},
+					map[string]interface{}{
+						"vacancy":    map[string]interface{}{"id": moc.Vacancies[2].ID.Hex()},
+						"score":      float64(60),
+						"components": components("Lives far from the vacancy."),
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
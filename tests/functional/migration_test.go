@@ -0,0 +1,90 @@
+package functionaltests
+
+import (
+	"testing"
+	"time"
+
+	config "../../config"
+	migrations "../../migrations"
+	moc "../../mocks"
+	models "../../models"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// legacyRecruit is a recruit stored before answers referenced their questions,
+// with its birth year decoded as mgo decodes int32 values
+func legacyRecruit(qa1, qa2 string, industryIDs ...bson.ObjectId) bson.M {
+	doc := bson.M{
+		"_id":        bson.NewObjectId(),
+		"birth_year": 1990,
+		"province":   "GAUTENG",
+		"city":       "Pretoria",
+		"gender":     "male",
+		"disability": "",
+		"vid1_url":   "none",
+		"vid2_url":   "none",
+		"phone":      "012 345 6789",
+		"email":      "legacy@mail.com",
+		"qa1":        bson.M{"question": qa1, "answer": "First answer."},
+		"qa2":        bson.M{"question": qa2, "answer": "Second answer."},
+	}
+	if len(industryIDs) > 0 {
+		doc["industry_ids"] = industryIDs
+	}
+	return doc
+}
+
+// tests that the qa1 and qa2 fields of recruits are converted into answers
+func TestMigrateRecruitAnswers(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+
+	// "What's your favourite song?" is asked in both Questions[1] and Questions[5],
+	// Questions[0] used to be "What's your favorite color?"
+	migrated := legacyRecruit("What's your favorite color?", "What's your favourite song?")
+	preferred := legacyRecruit("What's your favourite song?", "What's your favourite letter?", moc.Industries[1].ID)
+	unknown := legacyRecruit("What's your favourite colour?", "What's your favourite planet?")
+	crud.TempStorage[config.RecruitsCollection] = append(crud.TempStorage[config.RecruitsCollection], migrated, preferred, unknown)
+
+	failOnError(assert, migrations.Run(crud))
+
+	answers := func(doc bson.M) []models.QA {
+		raw, err := crud.FindID(config.RecruitsCollection, doc["_id"])
+		failOnError(assert, err)
+		return models.TransformRecruit(raw).Answers
+	}
+	assert.Equal([]models.QA{
+		{QuestionID: moc.Questions[0].ID, Revision: 1, Answer: "First answer."},
+		{QuestionID: moc.Questions[1].ID, Revision: 1, Answer: "Second answer."},
+	}, answers(migrated))
+	assert.Equal([]models.QA{
+		{QuestionID: moc.Questions[1].ID, Revision: 1, Answer: "First answer."},
+		{QuestionID: moc.Questions[3].ID, Revision: 1, Answer: "Second answer."},
+	}, answers(preferred))
+
+	// the legacy fields are dropped, the others are kept
+	raw, err := crud.FindID(config.RecruitsCollection, migrated["_id"])
+	failOnError(assert, err)
+	assert.NotContains(raw, "qa1")
+	assert.NotContains(raw, "qa2")
+	assert.Equal(int32(1990), models.TransformRecruit(raw).BirthYear)
+
+	// recruits with an unknown question are left alone until it exists
+	raw, err = crud.FindID(config.RecruitsCollection, unknown["_id"])
+	failOnError(assert, err)
+	assert.Contains(raw, "qa2")
+	assert.NotContains(raw, "answers")
+
+	planet := models.Question{ID: bson.NewObjectId(), IndustryID: moc.Industries[0].ID}
+	planet.Revise("What's your favourite planet?", time.Now())
+	failOnError(assert, crud.Insert(config.QuestionsCollection, planet))
+	failOnError(assert, migrations.Run(crud))
+	assert.Equal([]models.QA{
+		{QuestionID: moc.Questions[0].ID, Revision: 2, Answer: "First answer."},
+		{QuestionID: planet.ID, Revision: 1, Answer: "Second answer."},
+	}, answers(unknown))
+
+	// recruits that already have answers aren't touched
+	assert.Len(answers(bson.M{"_id": moc.Recruits[0].ID}), 2)
+}
@@ -0,0 +1,308 @@
+package functionaltests
+
+import (
+	"fmt"
+	"testing"
+
+	config "../../config"
+	moc "../../mocks"
+	models "../../models"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// tests that Viewer.Notifications lists the account's notifications and preferences
+func TestViewer_Notifications(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Recruits[0]
+	token, _ := login(crud, moc.Accounts[0].ID, "none")
+
+	// prepare query
+	query := fmt.Sprintf(`
+		query{
+			view(token: "%s", enforce: ACCOUNT){
+				notifications{
+					unread
+					items{
+						id
+						event
+						read
+						subject_id
+					}
+					preferences{
+						event
+						in_app
+						email
+					}
+				}
+			}
+		}
+	`, token)
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	// prepare expected, newest first
+	items := make([]interface{}, 0)
+	for i := len(moc.Notifications) - 1; i >= 0; i-- {
+		notification := moc.Notifications[i]
+		items = append(items, map[string]interface{}{
+			"id":         notification.ID.Hex(),
+			"event":      notification.Event,
+			"read":       notification.Read,
+			"subject_id": notification.SubjectID.Hex(),
+		})
+	}
+	preferences := make([]interface{}, 0)
+	for _, event := range models.NotificationEvents {
+		email := event != models.EventProfileUpdated && event != models.EventNewMessage &&
+			event != models.EventContactAnswered
+		preferences = append(preferences, map[string]interface{}{
+			"event":  event,
+			"in_app": true,
+			"email":  email,
+		})
+	}
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"view": map[string]interface{}{
+				"notifications": map[string]interface{}{
+					"unread":      float64(1),
+					"items":       items,
+					"preferences": preferences,
+				},
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests that AccountEditor.MarkNotificationsRead marks all notifications as read
+func TestAccountEditor_MarkNotificationsRead(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Recruits[0]
+	token, _ := login(crud, moc.Accounts[0].ID, "none")
+
+	// prepare query
+	query := fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: ACCOUNT){
+				... on AccountEditor{
+					markNotificationsRead{
+						unread
+						items(unread_only: true){
+							id
+						}
+					}
+				}
+			}
+		}
+	`, token)
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	// prepare expected
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"edit": map[string]interface{}{
+				"markNotificationsRead": map[string]interface{}{
+					"unread": float64(0),
+					"items":  []interface{}{},
+				},
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests that AccountEditor.SetNotificationPreference stores the account's preference
+func TestAccountEditor_SetNotificationPreference(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// Accounts[0] has no settings yet, Accounts[1] has
+	for _, i := range []int{0, 1} {
+		token, _ := login(crud, moc.Accounts[i].ID, "none")
+
+		// set preference
+		query := fmt.Sprintf(`
+			mutation{
+				edit(token: "%s", enforce: ACCOUNT){
+					... on AccountEditor{
+						setNotificationPreference(event: APPLICATION_MOVED, in_app: true, email: false){
+							event
+						}
+					}
+				}
+			}
+		`, token)
+		response, err := gqlRequestAndRespond(handler, query, nil)
+		failOnError(assert, err)
+		assert.NotContains(response, "errors", msgUnexpectedError)
+
+		// check preferences
+		query = fmt.Sprintf(`
+			query{
+				view(token: "%s", enforce: ACCOUNT){
+					notifications{
+						preferences{
+							event
+							in_app
+							email
+						}
+					}
+				}
+			}
+		`, token)
+		response, err = gqlRequestAndRespond(handler, query, nil)
+		failOnError(assert, err)
+
+		data := response["data"].(map[string]interface{})["view"].(map[string]interface{})["notifications"].(map[string]interface{})
+		preferences := data["preferences"].([]interface{})
+		assert.Contains(preferences, map[string]interface{}{"event": "APPLICATION_MOVED", "in_app": true, "email": false}, msgInvalidResult)
+		if i == 1 {
+			assert.Contains(preferences, map[string]interface{}{"event": "NEW_MESSAGE", "in_app": false, "email": true}, msgInvalidResult)
+		}
+	}
+
+	_, err := crud.FindOne(config.NotificationSettingsCollection, bson.M{"account_id": moc.Accounts[0].ID})
+	assert.Nil(err, msgInvalidResult)
+}
+
+// tests that domain events end up in the right inboxes
+func TestNotificationEvents(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// get access tokens
+	recruitToken, _ := login(crud, moc.Accounts[0].ID, "none")
+	otherRecruitToken, _ := login(crud, moc.Accounts[1].ID, "none")
+	hunterToken, _ := login(crud, moc.Accounts[3].ID, "none")
+	sysToken, _ := login(crud, getSysUserAccount().ID, "none")
+
+	// events received by each account
+	received := func(accountID bson.ObjectId) []string {
+		rawNotifications, err := crud.FindAll(config.NotificationsCollection, bson.M{"account_id": accountID})
+		failOnError(assert, err)
+		events := make([]string, 0)
+		for _, raw := range rawNotifications {
+			notification := models.TransformNotification(raw)
+			if notification.CreatedAt.After(moc.Notifications[1].CreatedAt) {
+				events = append(events, notification.Event)
+			}
+		}
+		return events
+	}
+
+	steps := []string{
+		fmt.Sprintf(`
+			mutation{
+				edit(token: "%s", enforce: HUNTER){
+					... on HunterEditor{
+						moveApplication(id: "%s", stage: INTERVIEW){ id }
+					}
+				}
+			}
+		`, hunterToken, moc.Applications[1].ID.Hex()),
+		fmt.Sprintf(`
+			mutation{
+				edit(token: "%s", enforce: RECRUIT){
+					... on RecruitEditor{
+						sendMessage(thread_id: "%s", body: "Thanks!"){ id }
+					}
+				}
+			}
+		`, otherRecruitToken, moc.Threads[0].ID.Hex()),
+		fmt.Sprintf(`
+			mutation{
+				edit(token: "%s", enforce: HUNTER){
+					... on HunterEditor{
+						sendMessage(thread_id: "%s", body: "You're welcome."){ id }
+					}
+				}
+			}
+		`, hunterToken, moc.Threads[0].ID.Hex()),
+		fmt.Sprintf(`
+			mutation{
+				edit(token: "%s", enforce: SYSTEM){
+					... on SysEditor{
+						verifyDocument(id: "%s"){ verified }
+					}
+				}
+			}
+		`, sysToken, moc.Documents[0].ID.Hex()),
+		fmt.Sprintf(`
+			mutation{
+				edit(token: "%s", enforce: RECRUIT){
+					... on RecruitEditor{
+						updateRecruit(info: {city: "Centurion"}){ city }
+					}
+				}
+			}
+		`, recruitToken),
+	}
+	for i, query := range steps {
+		response, err := gqlRequestAndRespond(handler, query, nil)
+		failOnError(assert, err)
+		assert.NotContains(response, "errors", fmt.Sprintf("Step [%v]: %s", i+1, msgUnexpectedError))
+	}
+
+	// Recruits[0] had a document verified
+	assert.Equal([]string{"DOCUMENT_VERIFIED"}, received(moc.Accounts[0].ID), msgInvalidResult)
+	// Recruits[1] doesn't want messages in the app
+	assert.Equal([]string{"APPLICATION_MOVED"}, received(moc.Accounts[1].ID), msgInvalidResult)
+	// Companies[0] got the recruit's message and Recruits[0]'s profile update
+	assert.Equal([]string{"NEW_MESSAGE", "PROFILE_UPDATED"}, received(moc.Accounts[2].ID), msgInvalidResult)
+	assert.Equal([]string{"NEW_MESSAGE", "PROFILE_UPDATED"}, received(moc.Accounts[3].ID), msgInvalidResult)
+	// Companies[1] got Recruits[0]'s profile update
+	assert.Equal([]string{"PROFILE_UPDATED"}, received(moc.Accounts[4].ID), msgInvalidResult)
+}
+
+// tests that new accounts are welcomed, both in-app and by email
+func TestNotificationAccountCreated(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	query := `
+		mutation{
+			createAccount(info: {email: "new@gmail.com", password: "password", name: "New", surname: "User"}){
+				accessToken
+			}
+		}
+	`
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+	assert.NotContains(response, "errors", msgUnexpectedError)
+
+	rawAccount, err := crud.FindOne(config.AccountsCollection, bson.M{"email": "new@gmail.com"})
+	failOnError(assert, err)
+	account := models.TransformAccount(rawAccount)
+
+	rawNotification, err := crud.FindOne(config.NotificationsCollection, bson.M{"account_id": account.ID})
+	failOnError(assert, err)
+	notification := models.TransformNotification(rawNotification)
+	assert.Equal(models.EventAccountCreated, notification.Event, msgInvalidResult)
+	assert.False(notification.Read, msgInvalidResult)
+
+	// the welcome email is queued in the outbox
+	rawEmail, err := crud.FindOne(config.OutboxCollection, bson.M{"to": "new@gmail.com"})
+	failOnError(assert, err)
+	email := models.TransformEmail(rawEmail)
+	assert.Equal("welcome", email.Template, msgInvalidResult)
+	assert.Equal(models.EmailPending, email.Status, msgInvalidResult)
+	assert.Contains(email.Text, "Hi New,", msgInvalidResult)
+}
@@ -0,0 +1,161 @@
+package functionaltests
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	moc "../../mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// questionRevisionsQuery is the query of a question's revisions and their answer counts
+const questionRevisionsQuery = `
+	query{
+		view(token: "%s", enforce: SYSTEM){
+			... on SysViewer{
+				questionRevisions(question_id: "%s"){
+					revision{
+						revision
+						question
+					}
+					answers
+				}
+			}
+		}
+	}
+`
+
+// tests that rewording a question keeps the wording recruits answered
+func TestQuestionRevisions(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	sysToken, _ := login(crud, getSysUserAccount().ID, "none")
+	recruitToken, _ := login(crud, moc.Accounts[0].ID, "none")
+	edit := func(token, enforce, editor, field string) map[string]interface{} {
+		response, err := gqlRequestAndRespond(handler, fmt.Sprintf(editMutation, token, enforce, editor, field), nil)
+		failOnError(assert, err)
+		return response
+	}
+	answerCounts := func() []interface{} {
+		response, err := gqlRequestAndRespond(handler, fmt.Sprintf(questionRevisionsQuery, sysToken, moc.Questions[0].ID.Hex()), nil)
+		failOnError(assert, err)
+		data := assertGqlData("view", response, assert)
+		counts := make([]interface{}, 0)
+		for _, r := range data["view"].(map[string]interface{})["questionRevisions"].([]interface{}) {
+			counts = append(counts, r.(map[string]interface{})["answers"])
+		}
+		return counts
+	}
+
+	// rewording adds a revision, the same wording doesn't
+	for i := 0; i < 2; i++ {
+		response := edit(sysToken, "SYSTEM", "Sys", fmt.Sprintf(`updateQuestion(id: "%s", question: "Which colour do you like most?"){ question revision revisions{ revision question } }`,
+			moc.Questions[0].ID.Hex()))
+		data := assertGqlData("edit", response, assert)
+		assert.Equal(map[string]interface{}{
+			"question": "Which colour do you like most?",
+			"revision": float64(3),
+			"revisions": []interface{}{
+				map[string]interface{}{"revision": float64(1), "question": "What's your favorite color?"},
+				map[string]interface{}{"revision": float64(2), "question": "What's your favourite colour?"},
+				map[string]interface{}{"revision": float64(3), "question": "Which colour do you like most?"},
+			},
+		}, data["edit"].(map[string]interface{})["updateQuestion"], msgInvalidResult)
+	}
+	assert.Equal([]interface{}{float64(1), float64(0), float64(0)}, answerCounts(), msgInvalidResult)
+
+	// answers show the wording they answered
+	response := edit(recruitToken, "RECRUIT", "Recruit", fmt.Sprintf(`updateQAs(answers: [
+		{question_id: "%s", answer: "Blue, like the ocean."},
+		{question_id: "%s", answer: "Butternut, I know it's basic."}
+	]){ revision question_revision{ question } question{ question } }`, moc.Questions[0].ID.Hex(), moc.Questions[4].ID.Hex()))
+	data := assertGqlData("edit", response, assert)
+	assert.Equal(map[string]interface{}{
+		"revision":          float64(1),
+		"question_revision": map[string]interface{}{"question": "What's your favorite color?"},
+		"question":          map[string]interface{}{"question": "Which colour do you like most?"},
+	}, data["edit"].(map[string]interface{})["updateQAs"].([]interface{})[0], msgInvalidResult)
+
+	// changing the answer answers the current revision
+	response = edit(recruitToken, "RECRUIT", "Recruit", fmt.Sprintf(`updateQAs(answers: [{question_id: "%s", answer: "Green."}]){ revision }`,
+		moc.Questions[0].ID.Hex()))
+	data = assertGqlData("edit", response, assert)
+	assert.Equal([]interface{}{map[string]interface{}{"revision": float64(3)}}, data["edit"].(map[string]interface{})["updateQAs"], msgInvalidResult)
+	assert.Equal([]interface{}{float64(0), float64(0), float64(1)}, answerCounts(), msgInvalidResult)
+}
+
+// randomQuestionsQuery is the query of random questions, args are added to the industry
+const randomQuestionsQuery = `
+	query{
+		randomQuestions(industry_id: "%s"%s){
+			id
+		}
+	}
+`
+
+// tests that random questions are picked from the active questions that weren't answered yet
+func TestRandomQuestions_Selection(t *testing.T) {
+	assert := assert.New(t)
+	os.Setenv("QUESTION_SEED", "2019")
+	defer os.Unsetenv("QUESTION_SEED")
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	sysToken, _ := login(crud, getSysUserAccount().ID, "none")
+	recruitToken, _ := login(crud, moc.Accounts[0].ID, "none")
+	pick := func(h http.Handler, args string) ([]string, map[string]interface{}) {
+		response, err := gqlRequestAndRespond(h, fmt.Sprintf(randomQuestionsQuery, moc.Industries[0].ID.Hex(), args), nil)
+		failOnError(assert, err)
+		ids := make([]string, 0)
+		if data, ok := response["data"].(map[string]interface{}); ok {
+			for _, q := range data["randomQuestions"].([]interface{}) {
+				ids = append(ids, q.(map[string]interface{})["id"].(string))
+			}
+		}
+		return ids, response
+	}
+	edit := func(field string) {
+		response, err := gqlRequestAndRespond(handler, fmt.Sprintf(editMutation, sysToken, "SYSTEM", "Sys", field), nil)
+		failOnError(assert, err)
+		assertGqlData("edit", response, assert)
+	}
+
+	// the count is configurable, but there are only so many questions
+	ids, _ := pick(handler, `, count: 3`)
+	assert.ElementsMatch([]string{moc.Questions[0].ID.Hex(), moc.Questions[2].ID.Hex(), moc.Questions[4].ID.Hex()}, ids, msgInvalidResult)
+	ids, _ = pick(handler, `, count: 10`)
+	assert.Len(ids, 3, msgInvalidResultCount)
+	_, response := pick(handler, `, count: 0`)
+	assert.NotNil(response["errors"], msgInvalidResult)
+
+	// the same seed picks the same questions
+	first, _ := pick(createGqlHandler(crud), ``)
+	second, _ := pick(createGqlHandler(crud), ``)
+	assert.Len(first, 2, msgInvalidResultCount)
+	assert.Equal(first, second, msgInvalidResult)
+
+	// questions the recruit answered aren't asked again
+	ids, _ = pick(handler, fmt.Sprintf(`, token: "%s"`, recruitToken))
+	assert.Equal([]string{moc.Questions[2].ID.Hex()}, ids, msgInvalidResult)
+	_, response = pick(handler, `, token: "token"`)
+	assert.NotNil(response["errors"], msgInvalidResult)
+
+	// questions can be picked by difficulty
+	ids, _ = pick(handler, `, difficulty: HARD`)
+	assert.Len(ids, 0, msgInvalidResultCount)
+	edit(fmt.Sprintf(`updateQuestion(id: "%s", question: "%s", difficulty: HARD, weight: 5){ id }`, moc.Questions[2].ID.Hex(), moc.Questions[2].Question))
+	ids, _ = pick(handler, `, difficulty: HARD`)
+	assert.Equal([]string{moc.Questions[2].ID.Hex()}, ids, msgInvalidResult)
+
+	// archived questions aren't asked until they're activated again
+	edit(fmt.Sprintf(`archiveQuestion(id: "%s"){ status }`, moc.Questions[2].ID.Hex()))
+	ids, _ = pick(handler, fmt.Sprintf(`, token: "%s"`, recruitToken))
+	assert.Len(ids, 0, msgInvalidResultCount)
+	edit(fmt.Sprintf(`activateQuestion(id: "%s"){ status }`, moc.Questions[2].ID.Hex()))
+	ids, _ = pick(handler, fmt.Sprintf(`, token: "%s"`, recruitToken))
+	assert.Len(ids, 1, msgInvalidResultCount)
+}
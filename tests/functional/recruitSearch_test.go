@@ -0,0 +1,221 @@
+package functionaltests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	config "../../config"
+	moc "../../mocks"
+	models "../../models"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// tests that HunterViewer.SearchRecruits filters, sorts and pages recruits
+func TestHunterViewer_SearchRecruits(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as a hunter
+	token, _ := login(crud, moc.Accounts[2].ID, "none")
+
+	// prepare query
+	queryFormat := `
+		query{
+			view(token: "%s", enforce: HUNTER){
+				... on HunterViewer{
+					searchRecruits%s{
+						total
+						recruits{
+							id
+						}
+					}
+				}
+			}
+		}
+	`
+
+	// ages are relative to the current year
+	year := time.Now().Year()
+	ageOf := func(i int) int {
+		return year - int(moc.Recruits[i].BirthYear)
+	}
+
+	// search arguments and the recruits they should return
+	input := []string{
+		``,
+		`(filter: {province: GAUTENG})`,
+		`(filter: {city: "johannesburg"})`,
+		`(filter: {gender: FEMALE})`,
+		`(filter: {gender: MALE})`,
+		fmt.Sprintf(`(filter: {min_age: %d})`, ageOf(2)),
+		fmt.Sprintf(`(filter: {max_age: %d})`, ageOf(1)),
+		fmt.Sprintf(`(filter: {min_age: %d, max_age: %d})`, ageOf(2), ageOf(2)),
+		`(filter: {has_disability: true})`,
+		`(filter: {has_disability: false})`,
+		fmt.Sprintf(`(filter: {industry_id: "%s"})`, moc.Industries[0].ID.Hex()),
+		fmt.Sprintf(`(filter: {industry_id: "%s"})`, moc.Industries[1].ID.Hex()),
+		`(filter: {text: "KNOW"})`,
+		fmt.Sprintf(`(filter: {skill_ids: ["%s"]})`, moc.Skills[0].ID.Hex()),
+		fmt.Sprintf(`(filter: {skill_ids: ["%s", "%s"]})`, moc.Skills[0].ID.Hex(), moc.Skills[2].ID.Hex()),
+		`(filter: {province: GAUTENG, has_disability: false})`,
+		`(sort: YOUNGEST)`,
+		`(sort: OLDEST)`,
+		`(sort: OLDEST, first: 1, skip: 1)`,
+	}
+	output := [][]int{
+		{0, 1, 2},
+		{0, 1},
+		{1},
+		{},
+		{0, 1, 2},
+		{0, 2},
+		{1},
+		{2},
+		{1},
+		{0, 2},
+		{0, 2},
+		{1, 2},
+		{0, 2},
+		{0, 1},
+		{1},
+		{0},
+		{1, 2, 0},
+		{0, 2, 1},
+		{2},
+	}
+	totals := []int{3, 2, 1, 0, 3, 2, 1, 1, 1, 2, 2, 2, 2, 2, 1, 1, 3, 3, 3}
+
+	for i, in := range input {
+		// request
+		response, err := gqlRequestAndRespond(handler, fmt.Sprintf(queryFormat, token, in), nil)
+		failOnError(assert, err)
+
+		// prepare expected
+		recruits := make([]interface{}, 0)
+		for _, index := range output[i] {
+			recruits = append(recruits, map[string]interface{}{
+				"id": moc.Recruits[index].ID.Hex(),
+			})
+		}
+		expected := map[string]interface{}{
+			"data": map[string]interface{}{
+				"view": map[string]interface{}{
+					"searchRecruits": map[string]interface{}{
+						"total":    float64(totals[i]),
+						"recruits": recruits,
+					},
+				},
+			},
+		}
+
+		assert.Equal(expected, response, fmt.Sprintf("Case [%v]: %s", i+1, msgInvalidResult))
+	}
+}
+
+// tests that filtering by an industry finds the recruits of its sub-industries too
+func TestHunterViewer_SearchRecruitsSubIndustries(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// a two level hierarchy, Recruits[1] prefers the lowest industry and
+	// Recruits[2] answered a question of the middle one
+	parent := models.Industry{ID: bson.NewObjectId(), Name: "Engineering", MinAnswers: models.DefaultMinAnswers, MaxAnswers: models.DefaultMaxAnswers}
+	child := models.Industry{ID: bson.NewObjectId(), Name: "Civil Engineering", ParentID: parent.ID, MinAnswers: models.DefaultMinAnswers, MaxAnswers: models.DefaultMaxAnswers}
+	grandchild := models.Industry{ID: bson.NewObjectId(), Name: "Structural Engineering", ParentID: child.ID, MinAnswers: models.DefaultMinAnswers, MaxAnswers: models.DefaultMaxAnswers}
+	failOnError(assert, crud.Insert(config.IndustriesCollection, parent, child, grandchild))
+	failOnError(assert, crud.UpdateID(config.RecruitsCollection, moc.Recruits[1].ID, bson.M{"industry_ids": []bson.ObjectId{grandchild.ID}}))
+	failOnError(assert, crud.UpdateID(config.QuestionsCollection, moc.Questions[2].ID, bson.M{"industry_id": child.ID}))
+
+	// login as a hunter
+	token, _ := login(crud, moc.Accounts[2].ID, "none")
+
+	// prepare query
+	queryFormat := `
+		query{
+			view(token: "%s", enforce: HUNTER){
+				... on HunterViewer{
+					searchRecruits(filter: {industry_id: "%s"}){
+						recruits{
+							id
+						}
+					}
+				}
+			}
+		}
+	`
+
+	// industries and the recruits they should return
+	input := []bson.ObjectId{parent.ID, child.ID, grandchild.ID}
+	output := [][]int{
+		{1, 2},
+		{1, 2},
+		{1},
+	}
+
+	for i, in := range input {
+		// request
+		response, err := gqlRequestAndRespond(handler, fmt.Sprintf(queryFormat, token, in.Hex()), nil)
+		failOnError(assert, err)
+
+		// prepare expected
+		recruits := make([]interface{}, 0)
+		for _, index := range output[i] {
+			recruits = append(recruits, map[string]interface{}{
+				"id": moc.Recruits[index].ID.Hex(),
+			})
+		}
+		expected := map[string]interface{}{
+			"data": map[string]interface{}{
+				"view": map[string]interface{}{
+					"searchRecruits": map[string]interface{}{
+						"recruits": recruits,
+					},
+				},
+			},
+		}
+
+		assert.Equal(expected, response, fmt.Sprintf("Case [%v]: %s", i+1, msgInvalidResult))
+	}
+}
+
+// tests that invalid recruit searches fail
+func TestHunterViewer_SearchRecruitsInvalid(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as a hunter
+	token, _ := login(crud, moc.Accounts[2].ID, "none")
+
+	// prepare query
+	queryFormat := `
+		query{
+			view(token: "%s", enforce: HUNTER){
+				... on HunterViewer{
+					searchRecruits%s{
+						total
+					}
+				}
+			}
+		}
+	`
+
+	input := []string{
+		`(first: 0)`,
+		`(first: 1000)`,
+		`(skip: -1)`,
+		`(filter: {industry_id: "abc"})`,
+		`(filter: {skill_ids: ["abc"]})`,
+	}
+
+	for i, in := range input {
+		// request
+		response, err := gqlRequestAndRespond(handler, fmt.Sprintf(queryFormat, token, in), nil)
+		failOnError(assert, err)
+		assert.Contains(response, "errors", fmt.Sprintf("Case [%v]: %s", i+1, msgNoError))
+	}
+}
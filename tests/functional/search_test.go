@@ -0,0 +1,206 @@
+package functionaltests
+
+import (
+	"fmt"
+	"testing"
+
+	moc "../../mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// searchQueryFormat searches as a HunterViewer, given a token and search arguments
+const searchQueryFormat = `
+	query{
+		view(token: "%s", enforce: HUNTER){
+			... on HunterViewer{
+				search%s{
+					id
+					kind
+					highlights{
+						field
+						fragment
+					}
+				}
+			}
+		}
+	}
+`
+
+// tests that HunterViewer.Search finds ranked and highlighted matches
+func TestHunterViewer_Search(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as a hunter
+	token, _ := login(crud, moc.Accounts[2].ID, "none")
+
+	// prepare inputs
+	input := []string{
+		`(query: "butternut")`,
+		`(query: "favourite soup", kinds: [QUESTION], first: 1)`,
+		`(query: "nothing matches this")`,
+		`(query: "qualification", kinds: [DOCUMENT])`,
+		`(query: "qualification")`,
+	}
+	output := []interface{}{
+		[]interface{}{
+			map[string]interface{}{
+				"id":   moc.Recruits[0].ID.Hex(),
+				"kind": "RECRUIT",
+				"highlights": []interface{}{
+					map[string]interface{}{
+						"field":    "answers",
+						"fragment": "Blue, like the ocean.\n<em>Butternut</em>, I know it&#39;s basic.",
+					},
+				},
+			},
+		},
+		[]interface{}{
+			map[string]interface{}{
+				"id":   moc.Questions[4].ID.Hex(),
+				"kind": "QUESTION",
+				"highlights": []interface{}{
+					map[string]interface{}{
+						"field":    "question",
+						"fragment": "What&#39;s your <em>favourite</em> <em>soup</em>?",
+					},
+				},
+			},
+		},
+		[]interface{}{},
+		// documents are only searched by sysadmins
+		[]interface{}{},
+		[]interface{}{},
+	}
+
+	for i, in := range input {
+		// request
+		response, err := gqlRequestAndRespond(handler, fmt.Sprintf(searchQueryFormat, token, in), nil)
+		failOnError(assert, err)
+
+		// prepare expected
+		expected := map[string]interface{}{
+			"data": map[string]interface{}{
+				"view": map[string]interface{}{
+					"search": output[i],
+				},
+			},
+		}
+
+		assert.Equal(expected, response, fmt.Sprintf("Case [%v]: %s", i+1, msgInvalidResult))
+	}
+}
+
+// tests that SysViewer.Search finds documents
+func TestSysViewer_SearchDocuments(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as the sysadmin
+	token, _ := login(crud, getSysUserAccount().ID, "none")
+
+	// request
+	query := fmt.Sprintf(`
+		query{
+			view(token: "%s", enforce: SYSTEM){
+				... on SysViewer{
+					search(query: "qualification", kinds: [DOCUMENT]){
+						kind
+					}
+				}
+			}
+		}
+	`, token)
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	// prepare expected
+	hits := make([]interface{}, 0)
+	for range moc.Documents {
+		hits = append(hits, map[string]interface{}{"kind": "DOCUMENT"})
+	}
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"view": map[string]interface{}{
+				"search": hits,
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests that editor mutations keep the search index up to date
+func TestSearchIndexUpdates(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// get access tokens
+	recruitToken, _ := login(crud, moc.Accounts[0].ID, "none")
+	hunterToken, _ := login(crud, moc.Accounts[2].ID, "none")
+
+	// searches for a term, returning the ids of the hits
+	searchIDs := func(term string) []interface{} {
+		query := fmt.Sprintf(searchQueryFormat, hunterToken, fmt.Sprintf(`(query: "%s")`, term))
+		response, err := gqlRequestAndRespond(handler, query, nil)
+		failOnError(assert, err)
+
+		ids := make([]interface{}, 0)
+		view := response["data"].(map[string]interface{})["view"].(map[string]interface{})
+		for _, hit := range view["search"].([]interface{}) {
+			ids = append(ids, hit.(map[string]interface{})["id"])
+		}
+		return ids
+	}
+	assert.Equal([]interface{}{}, searchIDs("cheesecake"), msgInvalidResult)
+
+	// update the answers
+	query := fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: RECRUIT){
+				... on RecruitEditor{
+					updateQAs(answers: [{question_id: "%s", answer: "Cheesecake, every time."}]){
+						answer
+					}
+				}
+			}
+		}
+	`, recruitToken, moc.Questions[0].ID.Hex())
+	_, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+	assert.Equal([]interface{}{moc.Recruits[0].ID.Hex()}, searchIDs("cheesecake"), msgInvalidResult)
+
+	// update the answers along with the profile
+	query = fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: RECRUIT){
+				... on RecruitEditor{
+					updateRecruit(info: {answers: [{question_id: "%s", answer: "Milktart, with cinnamon."}]}){
+						id
+					}
+				}
+			}
+		}
+	`, recruitToken, moc.Questions[0].ID.Hex())
+	_, err = gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+	assert.Equal([]interface{}{moc.Recruits[0].ID.Hex()}, searchIDs("milktart"), msgInvalidResult)
+	assert.Equal([]interface{}{}, searchIDs("cheesecake"), msgInvalidResult)
+
+	// remove the recruit
+	query = fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: RECRUIT){
+				... on RecruitEditor{
+					removeRecruit
+				}
+			}
+		}
+	`, recruitToken)
+	_, err = gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+	assert.Equal([]interface{}{}, searchIDs("milktart"), msgInvalidResult)
+}
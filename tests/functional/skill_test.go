@@ -0,0 +1,213 @@
+package functionaltests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	config "../../config"
+	moc "../../mocks"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// editMutation is a mutation on the editor of the logged in account
+const editMutation = `
+	mutation{
+		edit(token: "%s", enforce: %s){
+			... on %sEditor{
+				%s
+			}
+		}
+	}
+`
+
+// recruitSkillsQuery is the query of the logged in recruit's skills
+const recruitSkillsQuery = `
+	query{
+		view(token: "%s", enforce: RECRUIT){
+			... on RecruitViewer{
+				profile{
+					skills{
+						skill{
+							name
+						}
+						level
+						endorsements
+					}
+				}
+			}
+		}
+	}
+`
+
+// tests listing the skills of an industry
+func TestSkills(t *testing.T) {
+	assert := assert.New(t)
+	handler := createLoadedGqlHandler()
+
+	query := fmt.Sprintf(`
+		query{
+			skills(industry_id: "%s"){
+				id
+				name
+			}
+		}
+	`, moc.Industries[0].ID.Hex())
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+	assert.Equal(map[string]interface{}{
+		"data": map[string]interface{}{
+			"skills": []interface{}{
+				map[string]interface{}{"id": moc.Skills[0].ID.Hex(), "name": "data analysis"},
+				map[string]interface{}{"id": moc.Skills[1].ID.Hex(), "name": "r programming"},
+			},
+		},
+	}, response, msgInvalidResult)
+}
+
+// tests that sys admins can manage the skills taxonomy
+func TestSysEditor_Skills(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	token, _ := login(crud, getSysUserAccount().ID, "none")
+	edit := func(field string) map[string]interface{} {
+		response, err := gqlRequestAndRespond(handler, fmt.Sprintf(editMutation, token, "SYSTEM", "Sys", field), nil)
+		failOnError(assert, err)
+		return response
+	}
+
+	// create a skill
+	response := edit(fmt.Sprintf(`createSkill(industry_id: "%s", name: " Revit "){ id industry_id name }`, moc.Industries[1].ID.Hex()))
+	data := assertGqlData("edit", response, assert)
+	skill := data["edit"].(map[string]interface{})["createSkill"].(map[string]interface{})
+	assert.Equal("revit", skill["name"])
+	assert.Equal(moc.Industries[1].ID.Hex(), skill["industry_id"])
+
+	// names are unique within an industry
+	response = edit(fmt.Sprintf(`createSkill(industry_id: "%s", name: "AutoCAD"){ id }`, moc.Industries[1].ID.Hex()))
+	assert.NotNil(response["errors"], msgInvalidResult)
+	response = edit(fmt.Sprintf(`updateSkill(id: "%s", name: "autocad"){ id }`, skill["id"]))
+	assert.NotNil(response["errors"], msgInvalidResult)
+
+	response = edit(fmt.Sprintf(`updateSkill(id: "%s", name: "Revit MEP"){ name }`, skill["id"]))
+	data = assertGqlData("edit", response, assert)
+	assert.Equal(map[string]interface{}{"name": "revit mep"}, data["edit"].(map[string]interface{})["updateSkill"], msgInvalidResult)
+
+	// removing a skill removes it from recruits and drops its endorsements
+	response = edit(fmt.Sprintf(`removeSkill(id: "%s")`, moc.Skills[0].ID.Hex()))
+	data = assertGqlData("edit", response, assert)
+	assert.Equal("Skill successfully removed.", data["edit"].(map[string]interface{})["removeSkill"])
+
+	rawRecruit, err := crud.FindID(config.RecruitsCollection, moc.Recruits[1].ID)
+	failOnError(assert, err)
+	skills := rawRecruit.(bson.M)["skills"]
+	assert.Len(skills, 1)
+	_, err = crud.FindOne(config.EndorsementsCollection, bson.M{"skill_id": moc.Skills[0].ID})
+	assert.NotNil(err, msgInvalidResult)
+
+	response = edit(fmt.Sprintf(`removeSkill(id: "%s")`, moc.Skills[0].ID.Hex()))
+	assert.NotNil(response["errors"], msgInvalidResult)
+}
+
+// tests that recruits can rate themselves on skills
+func TestRecruitEditor_Skills(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	token, _ := login(crud, moc.Accounts[0].ID, "none")
+	edit := func(field string) map[string]interface{} {
+		response, err := gqlRequestAndRespond(handler, fmt.Sprintf(editMutation, token, "RECRUIT", "Recruit", field), nil)
+		failOnError(assert, err)
+		return response
+	}
+
+	// levels go from 1 to 5
+	response := edit(fmt.Sprintf(`setSkill(skill_id: "%s", level: 6){ level }`, moc.Skills[1].ID.Hex()))
+	assert.NotNil(response["errors"], msgInvalidResult)
+
+	response = edit(fmt.Sprintf(`setSkill(skill_id: "%s", level: 3){ skill{ name } level endorsements }`, moc.Skills[1].ID.Hex()))
+	data := assertGqlData("edit", response, assert)
+	assert.Equal(map[string]interface{}{
+		"skill":        map[string]interface{}{"name": "r programming"},
+		"level":        float64(3),
+		"endorsements": float64(0),
+	}, data["edit"].(map[string]interface{})["setSkill"], msgInvalidResult)
+
+	// changing the level keeps the endorsements
+	response = edit(fmt.Sprintf(`setSkill(skill_id: "%s", level: 5){ level endorsements }`, moc.Skills[0].ID.Hex()))
+	data = assertGqlData("edit", response, assert)
+	assert.Equal(map[string]interface{}{"level": float64(5), "endorsements": float64(1)},
+		data["edit"].(map[string]interface{})["setSkill"], msgInvalidResult)
+
+	response, err := gqlRequestAndRespond(handler, fmt.Sprintf(recruitSkillsQuery, token), nil)
+	failOnError(assert, err)
+	data = assertGqlData("view", response, assert)
+	assert.Equal([]interface{}{
+		map[string]interface{}{"skill": map[string]interface{}{"name": "r programming"}, "level": float64(3), "endorsements": float64(0)},
+		map[string]interface{}{"skill": map[string]interface{}{"name": "data analysis"}, "level": float64(5), "endorsements": float64(1)},
+	}, data["view"].(map[string]interface{})["profile"].(map[string]interface{})["skills"], msgInvalidResult)
+
+	// removing a skill drops its endorsements
+	response = edit(fmt.Sprintf(`removeSkill(skill_id: "%s")`, moc.Skills[0].ID.Hex()))
+	data = assertGqlData("edit", response, assert)
+	assert.Equal("Skill successfully removed.", data["edit"].(map[string]interface{})["removeSkill"])
+	_, err = crud.FindOne(config.EndorsementsCollection, bson.M{"recruit_id": moc.Recruits[0].ID})
+	assert.NotNil(err, msgInvalidResult)
+
+	response = edit(fmt.Sprintf(`removeSkill(skill_id: "%s")`, moc.Skills[0].ID.Hex()))
+	assert.NotNil(response["errors"], msgInvalidResult)
+}
+
+// tests that only hunters who interviewed a recruit can endorse their skills
+func TestHunterEditor_EndorseSkill(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// Hunters[2] interviews Recruits[0] next week
+	token, _ := login(crud, moc.Accounts[4].ID, "none")
+	endorse := func(recruit, skill bson.ObjectId) map[string]interface{} {
+		field := fmt.Sprintf(`endorseSkill(recruit_id: "%s", skill_id: "%s"){ recruit_id skill_id hunter_id }`, recruit.Hex(), skill.Hex())
+		response, err := gqlRequestAndRespond(handler, fmt.Sprintf(editMutation, token, "HUNTER", "Hunter", field), nil)
+		failOnError(assert, err)
+		return response
+	}
+
+	response := endorse(moc.Recruits[0].ID, moc.Skills[0].ID)
+	assert.NotNil(response["errors"], msgInvalidResult)
+
+	// once the interview happened
+	failOnError(assert, crud.UpdateID(config.InterviewsCollection, moc.Interviews[0].ID, bson.M{
+		"start": time.Now().Add(-2 * time.Hour),
+		"end":   time.Now().Add(-time.Hour),
+	}))
+	response = endorse(moc.Recruits[0].ID, moc.Skills[0].ID)
+	data := assertGqlData("edit", response, assert)
+	assert.Equal(map[string]interface{}{
+		"recruit_id": moc.Recruits[0].ID.Hex(),
+		"skill_id":   moc.Skills[0].ID.Hex(),
+		"hunter_id":  moc.HunterIDs[2].Hex(),
+	}, data["edit"].(map[string]interface{})["endorseSkill"], msgInvalidResult)
+
+	// only once, and only skills the recruit has
+	response = endorse(moc.Recruits[0].ID, moc.Skills[0].ID)
+	assert.NotNil(response["errors"], msgInvalidResult)
+	response = endorse(moc.Recruits[0].ID, moc.Skills[2].ID)
+	assert.NotNil(response["errors"], msgInvalidResult)
+
+	// Recruits[1] was never interviewed by Hunters[2]
+	response = endorse(moc.Recruits[1].ID, moc.Skills[2].ID)
+	assert.NotNil(response["errors"], msgInvalidResult)
+
+	recruitToken, _ := login(crud, moc.Accounts[0].ID, "none")
+	response, err := gqlRequestAndRespond(handler, fmt.Sprintf(recruitSkillsQuery, recruitToken), nil)
+	failOnError(assert, err)
+	data = assertGqlData("view", response, assert)
+	assert.Equal([]interface{}{
+		map[string]interface{}{"skill": map[string]interface{}{"name": "data analysis"}, "level": float64(4), "endorsements": float64(2)},
+	}, data["view"].(map[string]interface{})["profile"].(map[string]interface{})["skills"], msgInvalidResult)
+}
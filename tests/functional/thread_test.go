@@ -0,0 +1,422 @@
+package functionaltests
+
+import (
+	"fmt"
+	"testing"
+
+	moc "../../mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// tests that RecruitViewer.Threads lists the Recruit's threads with their messages
+func TestRecruitViewer_Threads(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Recruits[1]
+	token, _ := login(crud, moc.Accounts[1].ID, "none")
+	thread := moc.Threads[0]
+
+	// prepare query
+	query := fmt.Sprintf(`
+		query{
+			view(token: "%s", enforce: RECRUIT){
+				... on RecruitViewer{
+					unread_messages
+					threads{
+						id
+						subject
+						unread
+						application{
+							id
+						}
+						company{
+							id
+						}
+						messages{
+							body
+							sender_role
+							sender_name
+							mine
+						}
+					}
+				}
+			}
+		}
+	`, token)
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	// prepare expected
+	messages := make([]interface{}, 0)
+	senders := []int{2, 1}
+	for i, message := range moc.Messages {
+		sender := moc.Accounts[senders[i]]
+		messages = append(messages, map[string]interface{}{
+			"body":        message.Body,
+			"sender_role": message.SenderRole,
+			"sender_name": sender.Name + " " + sender.Surname,
+			"mine":        message.SenderID == moc.Accounts[1].ID,
+		})
+	}
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"view": map[string]interface{}{
+				"unread_messages": float64(0),
+				"threads": []interface{}{
+					map[string]interface{}{
+						"id":          thread.ID.Hex(),
+						"subject":     thread.Subject,
+						"unread":      float64(0),
+						"application": map[string]interface{}{"id": thread.ApplicationID.Hex()},
+						"company":     map[string]interface{}{"id": thread.CompanyID.Hex()},
+						"messages":    messages,
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests that HunterViewer.Thread counts the messages the Hunter hasn't read
+func TestHunterViewer_Thread(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Companies[0] owner, who sent the first message
+	token, _ := login(crud, moc.Accounts[2].ID, "none")
+	thread := moc.Threads[0]
+
+	// prepare query
+	query := fmt.Sprintf(`
+		query{
+			view(token: "%s", enforce: HUNTER){
+				... on HunterViewer{
+					unread_messages
+					thread(id: "%s"){
+						unread
+						recruit{
+							id
+						}
+						messages(first: 1, skip: 1){
+							body
+						}
+						last_message{
+							body
+							mine
+						}
+					}
+				}
+			}
+		}
+	`, token, thread.ID.Hex())
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	// prepare expected
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"view": map[string]interface{}{
+				"unread_messages": float64(1),
+				"thread": map[string]interface{}{
+					"unread":  float64(1),
+					"recruit": map[string]interface{}{"id": thread.RecruitID.Hex()},
+					"messages": []interface{}{
+						map[string]interface{}{"body": moc.Messages[1].Body},
+					},
+					"last_message": map[string]interface{}{
+						"body": moc.Messages[1].Body,
+						"mine": false,
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests that HunterEditor.StartThread starts a thread with a Recruit about a vacancy
+func TestHunterEditor_StartThread(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Companies[0] recruiter
+	token, _ := login(crud, moc.Accounts[3].ID, "none")
+	vacancy := moc.Vacancies[0]
+	recruit := moc.Recruits[2]
+
+	// prepare query
+	query := fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: HUNTER){
+				... on HunterEditor{
+					startThread(vacancy_id: "%s", recruit_id: "%s", subject: "Data analyst role", message: "Would you be interested?"){
+						subject
+						unread
+						application{
+							id
+						}
+						vacancy{
+							id
+						}
+						recruit{
+							id
+						}
+						messages{
+							body
+							sender_role
+							mine
+						}
+					}
+				}
+			}
+		}
+	`, token, vacancy.ID.Hex(), recruit.ID.Hex())
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	// prepare expected
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"edit": map[string]interface{}{
+				"startThread": map[string]interface{}{
+					"subject":     "Data analyst role",
+					"unread":      float64(0),
+					"application": nil,
+					"vacancy":     map[string]interface{}{"id": vacancy.ID.Hex()},
+					"recruit":     map[string]interface{}{"id": recruit.ID.Hex()},
+					"messages": []interface{}{
+						map[string]interface{}{
+							"body":        "Would you be interested?",
+							"sender_role": "HUNTER",
+							"mine":        true,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests that RecruitEditor.StartThread reuses the existing thread about an application
+func TestRecruitEditor_StartThread(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Recruits[1]
+	token, _ := login(crud, moc.Accounts[1].ID, "none")
+	thread := moc.Threads[0]
+
+	// prepare query
+	query := fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: RECRUIT){
+				... on RecruitEditor{
+					startThread(application_id: "%s", subject: "Another question", message: "Is parking available?"){
+						id
+						subject
+						last_message{
+							body
+							mine
+						}
+					}
+				}
+			}
+		}
+	`, token, thread.ApplicationID.Hex())
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	// prepare expected
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"edit": map[string]interface{}{
+				"startThread": map[string]interface{}{
+					"id":      thread.ID.Hex(),
+					"subject": thread.Subject,
+					"last_message": map[string]interface{}{
+						"body": "Is parking available?",
+						"mine": true,
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests that sending messages and marking threads as read update the unread counts
+func TestThreadUnreadCounts(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// get access tokens
+	recruitToken, _ := login(crud, moc.Accounts[1].ID, "none")
+	hunterToken, _ := login(crud, moc.Accounts[3].ID, "none")
+	thread := moc.Threads[0]
+
+	unreadQuery := fmt.Sprintf(`
+		query{
+			view(token: "%s", enforce: HUNTER){
+				... on HunterViewer{
+					unread_messages
+				}
+			}
+		}
+	`, hunterToken)
+	steps := []struct {
+		query  string
+		unread float64
+	}{
+		{ // the recruiter hasn't read anything yet
+			``,
+			2,
+		},
+		{
+			fmt.Sprintf(`
+				mutation{
+					edit(token: "%s", enforce: HUNTER){
+						... on HunterEditor{
+							markThreadRead(id: "%s"){ unread }
+						}
+					}
+				}
+			`, hunterToken, thread.ID.Hex()),
+			0,
+		},
+		{
+			fmt.Sprintf(`
+				mutation{
+					edit(token: "%s", enforce: RECRUIT){
+						... on RecruitEditor{
+							sendMessage(thread_id: "%s", body: "See you then."){ mine }
+						}
+					}
+				}
+			`, recruitToken, thread.ID.Hex()),
+			1,
+		},
+	}
+
+	for i, step := range steps {
+		if step.query != "" {
+			response, err := gqlRequestAndRespond(handler, step.query, nil)
+			failOnError(assert, err)
+			assert.NotContains(response, "errors", fmt.Sprintf("Step [%v]: %s", i+1, msgUnexpectedError))
+		}
+
+		response, err := gqlRequestAndRespond(handler, unreadQuery, nil)
+		failOnError(assert, err)
+		expected := map[string]interface{}{
+			"data": map[string]interface{}{
+				"view": map[string]interface{}{
+					"unread_messages": step.unread,
+				},
+			},
+		}
+		assert.Equal(expected, response, fmt.Sprintf("Step [%v]: %s", i+1, msgInvalidResult))
+	}
+}
+
+// tests that only a thread's participants can use it
+func TestThreadInvalid(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// get access tokens
+	recruitToken, _ := login(crud, moc.Accounts[0].ID, "none")
+	otherHunterToken, _ := login(crud, moc.Accounts[4].ID, "none")
+	ownerToken, _ := login(crud, moc.Accounts[2].ID, "none")
+	thread := moc.Threads[0]
+
+	// invalid inputs
+	input := []string{
+		fmt.Sprintf(`
+			# case 1 view another recruit's thread
+			query{
+				view(token: "%s", enforce: RECRUIT){
+					... on RecruitViewer{
+						thread(id: "%s"){ id }
+					}
+				}
+			}
+		`, recruitToken, thread.ID.Hex()),
+		fmt.Sprintf(`
+			# case 2 send a message to another company's thread
+			mutation{
+				edit(token: "%s", enforce: HUNTER){
+					... on HunterEditor{
+						sendMessage(thread_id: "%s", body: "Hello"){ id }
+					}
+				}
+			}
+		`, otherHunterToken, thread.ID.Hex()),
+		fmt.Sprintf(`
+			# case 3 send an empty message
+			mutation{
+				edit(token: "%s", enforce: HUNTER){
+					... on HunterEditor{
+						sendMessage(thread_id: "%s", body: "  "){ id }
+					}
+				}
+			}
+		`, ownerToken, thread.ID.Hex()),
+		fmt.Sprintf(`
+			# case 4 ask about a draft vacancy
+			mutation{
+				edit(token: "%s", enforce: RECRUIT){
+					... on RecruitEditor{
+						startThread(vacancy_id: "%s", subject: "Hi", message: "Hello"){ id }
+					}
+				}
+			}
+		`, recruitToken, moc.Vacancies[1].ID.Hex()),
+		fmt.Sprintf(`
+			# case 5 start a thread about another company's vacancy
+			mutation{
+				edit(token: "%s", enforce: HUNTER){
+					... on HunterEditor{
+						startThread(vacancy_id: "%s", recruit_id: "%s", subject: "Hi", message: "Hello"){ id }
+					}
+				}
+			}
+		`, otherHunterToken, moc.Vacancies[0].ID.Hex(), moc.Recruits[0].ID.Hex()),
+		fmt.Sprintf(`
+			# case 6 mark another recruit's thread as read
+			mutation{
+				edit(token: "%s", enforce: RECRUIT){
+					... on RecruitEditor{
+						markThreadRead(id: "%s"){ id }
+					}
+				}
+			}
+		`, recruitToken, thread.ID.Hex()),
+	}
+
+	for i, query := range input {
+		// request
+		response, err := gqlRequestAndRespond(handler, query, nil)
+		failOnError(assert, err)
+		assert.Contains(response, "errors", fmt.Sprintf("Case [%v]: %s", i+1, msgNoError))
+	}
+}
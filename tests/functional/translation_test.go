@@ -0,0 +1,103 @@
+package functionaltests
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	moc "../../mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// translatedQuestionQuery is the query of a question and its industry
+const translatedQuestionQuery = `
+	query{
+		view(token: "%s", enforce: SYSTEM){
+			... on SysViewer{
+				questions{
+					id
+					question
+				}
+			}
+		}
+		industries{
+			id
+			name
+		}
+	}
+`
+
+// tests that questions and industries are shown in the request's language
+func TestTranslations(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	token, _ := login(crud, getSysUserAccount().ID, "none")
+	edit := func(field string) map[string]interface{} {
+		response, err := gqlRequestAndRespond(handler, fmt.Sprintf(editMutation, token, "SYSTEM", "Sys", field), nil)
+		failOnError(assert, err)
+		return response
+	}
+	view := func(acceptLanguage string) (string, string) {
+		req := createGqlRequest(fmt.Sprintf(translatedQuestionQuery, token), nil)
+		req.Header.Set("Accept-Language", acceptLanguage)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		response, err := getJSONResponse(w.Result())
+		failOnError(assert, err)
+		data := assertGqlData("view", response, assert)
+
+		question, industry := "", ""
+		for _, q := range data["view"].(map[string]interface{})["questions"].([]interface{}) {
+			if q.(map[string]interface{})["id"] == moc.Questions[0].ID.Hex() {
+				question = q.(map[string]interface{})["question"].(string)
+			}
+		}
+		for _, i := range data["industries"].([]interface{}) {
+			if i.(map[string]interface{})["id"] == moc.Industries[0].ID.Hex() {
+				industry = i.(map[string]interface{})["name"].(string)
+			}
+		}
+		return question, industry
+	}
+
+	response := edit(fmt.Sprintf(`setQuestionTranslation(id: "%s", locale: "zu", text: " Uthanda muphi umbala? "){ translations{ locale text } }`, moc.Questions[0].ID.Hex()))
+	data := assertGqlData("edit", response, assert)
+	assert.Equal(map[string]interface{}{
+		"translations": []interface{}{map[string]interface{}{"locale": "zu", "text": "Uthanda muphi umbala?"}},
+	}, data["edit"].(map[string]interface{})["setQuestionTranslation"], msgInvalidResult)
+	response = edit(fmt.Sprintf(`setIndustryTranslation(id: "%s", locale: "af", name: "Statistiek"){ name }`, moc.Industries[0].ID.Hex()))
+	data = assertGqlData("edit", response, assert)
+	assert.Equal(map[string]interface{}{"name": "statistics"}, data["edit"].(map[string]interface{})["setIndustryTranslation"], msgInvalidResult)
+
+	// the most preferred locale with a translation is shown, English otherwise
+	question, industry := view("zu-ZA, af;q=0.9")
+	assert.Equal("Uthanda muphi umbala?", question, msgInvalidResult)
+	assert.Equal("statistiek", industry, msgInvalidResult)
+	question, industry = view("")
+	assert.Equal(moc.Questions[0].Question, question, msgInvalidResult)
+	assert.Equal("statistics", industry, msgInvalidResult)
+	question, _ = view("en, zu")
+	assert.Equal(moc.Questions[0].Question, question, msgInvalidResult)
+
+	// only supported locales, other than English, can be translated to
+	for _, locale := range []string{"en", "fr", ""} {
+		response = edit(fmt.Sprintf(`setQuestionTranslation(id: "%s", locale: "%s", text: "?"){ id }`, moc.Questions[0].ID.Hex(), locale))
+		assert.NotNil(response["errors"], msgInvalidResult)
+	}
+
+	// translations can be removed
+	response = edit(fmt.Sprintf(`removeIndustryTranslation(id: "%s", locale: "af"){ translations{ locale } }`, moc.Industries[0].ID.Hex()))
+	data = assertGqlData("edit", response, assert)
+	assert.Equal(map[string]interface{}{"translations": []interface{}{}}, data["edit"].(map[string]interface{})["removeIndustryTranslation"], msgInvalidResult)
+	response = edit(fmt.Sprintf(`removeIndustryTranslation(id: "%s", locale: "af"){ name }`, moc.Industries[0].ID.Hex()))
+	assert.NotNil(response["errors"], msgInvalidResult)
+
+	// and rewording a question drops the translations of the old wording
+	response = edit(fmt.Sprintf(`updateQuestion(id: "%s", question: "Which colour do you like most?"){ translations{ locale } }`, moc.Questions[0].ID.Hex()))
+	data = assertGqlData("edit", response, assert)
+	assert.Equal(map[string]interface{}{"translations": []interface{}{}}, data["edit"].(map[string]interface{})["updateQuestion"], msgInvalidResult)
+	question, _ = view("zu")
+	assert.Equal("Which colour do you like most?", question, msgInvalidResult)
+}
@@ -0,0 +1,299 @@
+package functionaltests
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	config "../../config"
+	moc "../../mocks"
+	models "../../models"
+	route "../../routing"
+	storage "../../storage"
+	upload "../../upload"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// mp4Header is the start of an mp4 file
+var mp4Header = []byte{
+	0x00, 0x00, 0x00, 0x18, 'f', 't', 'y', 'p', 'm', 'p', '4', '2',
+	0x00, 0x00, 0x00, 0x00, 'm', 'p', '4', '2', 'i', 's', 'o', 'm',
+}
+
+// uploadMetadata creates an Upload-Metadata header
+func uploadMetadata(target, fileType string) string {
+	encode := func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+	return "target " + encode(target) + ",filetype " + encode(fileType) + ",filename " + encode("intro.mp4")
+}
+
+// tusRequest sends a tus request to an upload handler
+func tusRequest(handler http.Handler, method, path, token string, headers map[string]string, body []byte) *http.Response {
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	req.Header.Set("Tus-Resumable", upload.TusVersion)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w.Result()
+}
+
+// tusPatch sends a part of an upload
+func tusPatch(handler http.Handler, location, token string, offset int, body []byte) *http.Response {
+	return tusRequest(handler, http.MethodPatch, location, token, map[string]string{
+		"Content-Type":  "application/offset+octet-stream",
+		"Upload-Offset": strconv.Itoa(offset),
+	}, body)
+}
+
+// hookStore runs afterPut once a file is stored
+type hookStore struct {
+	storage.Store
+	afterPut func()
+}
+
+func (s *hookStore) Put(key string, r io.Reader, size int64, contentType string) error {
+	if err := s.Store.Put(key, r, size, contentType); err != nil {
+		return err
+	}
+	if s.afterPut != nil {
+		s.afterPut()
+	}
+	return nil
+}
+
+// tests that recruit videos can be uploaded in parts and resumed
+func TestTusUpload(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	dir, err := ioutil.TempDir("", "uploads")
+	failOnError(assert, err)
+	defer os.RemoveAll(dir)
+	store := storage.NewLocalStore(filepath.Join(dir, "files"))
+	handler := upload.NewHandler(crud, store, filepath.Join(dir, "parts"), "/uploads")
+
+	token, _ := login(crud, moc.Accounts[0].ID, "none")
+	request := func(method, path, token string, headers map[string]string, body []byte) *http.Response {
+		return tusRequest(handler, method, path, token, headers, body)
+	}
+	create := func(token, length, metadata string) *http.Response {
+		return request(http.MethodPost, "/uploads", token, map[string]string{"Upload-Length": length, "Upload-Metadata": metadata}, nil)
+	}
+	patch := func(location string, offset int, body []byte) *http.Response {
+		return tusPatch(handler, location, token, offset, body)
+	}
+
+	// the server describes itself
+	res := request(http.MethodOptions, "/uploads", "", nil, nil)
+	assert.Equal(http.StatusNoContent, res.StatusCode)
+	assert.Equal("creation,expiration,termination", res.Header.Get("Tus-Extension"))
+	assert.Equal(upload.TusVersion, res.Header.Get("Tus-Version"))
+
+	// requests need the protocol version and a recruit's token
+	req := httptest.NewRequest(http.MethodPost, "/uploads", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(http.StatusPreconditionFailed, w.Code)
+	assert.Equal(http.StatusUnauthorized, create("", "100", uploadMetadata("vid1", "video/mp4")).StatusCode)
+	hunterToken, _ := login(crud, moc.Accounts[2].ID, "none")
+	assert.Equal(http.StatusForbidden, create(hunterToken, "100", uploadMetadata("vid1", "video/mp4")).StatusCode)
+
+	// only videos within their type's size limit
+	assert.Equal(http.StatusUnsupportedMediaType, create(token, "100", uploadMetadata("vid1", "application/pdf")).StatusCode)
+	assert.Equal(http.StatusRequestEntityTooLarge, create(token, strconv.Itoa(201<<20), uploadMetadata("vid1", "video/3gpp")).StatusCode)
+	assert.Equal(http.StatusCreated, create(token, strconv.Itoa(201<<20), uploadMetadata("vid1", "video/mp4")).StatusCode)
+	assert.Equal(http.StatusBadRequest, create(token, "100", uploadMetadata("vid3", "video/mp4")).StatusCode)
+	assert.Equal(http.StatusBadRequest, create(token, "", uploadMetadata("vid1", "video/mp4")).StatusCode)
+
+	// an upload is created and sent in parts
+	video := append(append([]byte{}, mp4Header...), bytes.Repeat([]byte("frame"), 20)...)
+	res = create(token, strconv.Itoa(len(video)), uploadMetadata("vid1", "video/mp4"))
+	assert.Equal(http.StatusCreated, res.StatusCode)
+	location := res.Header.Get("Location")
+	assert.Regexp("^/uploads/[0-9a-f]{24}$", location)
+
+	res = patch(location, 0, video[:40])
+	assert.Equal(http.StatusNoContent, res.StatusCode)
+	assert.Equal("40", res.Header.Get("Upload-Offset"))
+	assert.NotEmpty(res.Header.Get("Upload-Expires"))
+
+	// parts have to continue where the upload is at
+	assert.Equal(http.StatusConflict, patch(location, 30, video[30:]).StatusCode)
+	res = request(http.MethodHead, location, token, nil, nil)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal("40", res.Header.Get("Upload-Offset"))
+	assert.Equal(strconv.Itoa(len(video)), res.Header.Get("Upload-Length"))
+	assert.Equal("no-store", res.Header.Get("Cache-Control"))
+
+	// and are only visible to their uploader
+	otherToken, _ := login(crud, moc.Accounts[1].ID, "none")
+	assert.Equal(http.StatusNotFound, request(http.MethodHead, location, otherToken, nil, nil).StatusCode)
+
+	// completing the upload sets the recruit's video
+	res = patch(location, 40, video[40:])
+	assert.Equal(http.StatusNoContent, res.StatusCode)
+	assert.Equal(strconv.Itoa(len(video)), res.Header.Get("Upload-Offset"))
+
+	id := location[len("/uploads/"):]
+	rawRecruit, err := crud.FindID(config.RecruitsCollection, moc.Recruits[0].ID)
+	failOnError(assert, err)
+	assert.Equal("/file/uploads/"+id+".mp4", rawRecruit.(bson.M)["vid1_url"])
+	stored, err := ioutil.ReadFile(filepath.Join(dir, "files", "uploads", id+".mp4"))
+	failOnError(assert, err)
+	assert.Equal(video, stored)
+	_, err = os.Stat(filepath.Join(dir, "parts", id+".part"))
+	assert.True(os.IsNotExist(err))
+
+	// the video is served at a signed url
+	response, err := gqlRequestAndRespond(createGqlHandler(crud), fmt.Sprintf(`
+		query{
+			view(token: "%s"){
+				... on RecruitViewer{
+					profile{
+						vid1_url
+					}
+				}
+			}
+		}
+	`, token), nil)
+	failOnError(assert, err)
+	assert.NotContains(response, "errors", msgUnexpectedError)
+	signedURL := response["data"].(map[string]interface{})["view"].(map[string]interface{})["profile"].(map[string]interface{})["vid1_url"].(string)
+	assert.True(strings.HasPrefix(signedURL, "/file/uploads/"+id+".mp4?"), signedURL)
+	w = httptest.NewRecorder()
+	http.StripPrefix(storage.URLPrefix, route.NewFileHandler(store)).ServeHTTP(w, httptest.NewRequest(http.MethodGet, signedURL, nil))
+	assert.Equal(http.StatusOK, w.Code)
+	assert.Equal(video, w.Body.Bytes())
+
+	// files have to be of the type they're said to be
+	res = create(token, "100", uploadMetadata("vid2", "video/mp4"))
+	fake := res.Header.Get("Location")
+	assert.Equal(http.StatusUnsupportedMediaType, patch(fake, 0, []byte("%PDF-1.4 not a video")).StatusCode)
+	assert.Equal(http.StatusNotFound, request(http.MethodHead, fake, token, nil, nil).StatusCode)
+
+	// a new video replaces the earlier one
+	res = create(token, strconv.Itoa(len(mp4Header)), uploadMetadata("vid1", "video/mp4"))
+	replacement := res.Header.Get("Location")
+	assert.Equal(http.StatusNoContent, patch(replacement, 0, mp4Header).StatusCode)
+	_, err = store.Stat("uploads/" + id + ".mp4")
+	assert.Equal(storage.ErrNotFound, err)
+	assert.Equal(http.StatusNotFound, request(http.MethodHead, location, token, nil, nil).StatusCode)
+
+	// and terminating it removes it from the profile
+	res = request(http.MethodPost, replacement, token, map[string]string{"X-HTTP-Method-Override": http.MethodDelete}, nil)
+	assert.Equal(http.StatusNoContent, res.StatusCode)
+	rawRecruit, err = crud.FindID(config.RecruitsCollection, moc.Recruits[0].ID)
+	failOnError(assert, err)
+	assert.Equal("", rawRecruit.(bson.M)["vid1_url"])
+}
+
+// tests that an upload whose completion couldn't be stored can be completed again
+func TestTusUploadCompleteFailure(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	dir, err := ioutil.TempDir("", "uploads")
+	failOnError(assert, err)
+	defer os.RemoveAll(dir)
+	store := &hookStore{Store: storage.NewLocalStore(filepath.Join(dir, "files"))}
+	handler := upload.NewHandler(crud, store, filepath.Join(dir, "parts"), "/uploads")
+	token, _ := login(crud, moc.Accounts[0].ID, "none")
+
+	video := append(append([]byte{}, mp4Header...), bytes.Repeat([]byte("frame"), 20)...)
+	res := tusRequest(handler, http.MethodPost, "/uploads", token, map[string]string{
+		"Upload-Length":   strconv.Itoa(len(video)),
+		"Upload-Metadata": uploadMetadata("vid1", "video/mp4"),
+	}, nil)
+	location := res.Header.Get("Location")
+	id := bson.ObjectIdHex(location[len("/uploads/"):])
+	assert.Equal(http.StatusNoContent, tusPatch(handler, location, token, 0, video[:40]).StatusCode)
+
+	// the upload's record goes missing while its file is stored, so it can't be updated
+	var saved models.Upload
+	store.afterPut = func() {
+		rawUpload, err := crud.FindID(config.UploadsCollection, id)
+		failOnError(assert, err)
+		saved = models.TransformUpload(rawUpload)
+		crud.DeleteID(config.UploadsCollection, id)
+	}
+	assert.Equal(http.StatusInternalServerError, tusPatch(handler, location, token, 40, video[40:]).StatusCode)
+
+	// the stored file is removed again, the parts are kept and the recruit's video isn't set
+	_, err = store.Stat("uploads/" + id.Hex() + ".mp4")
+	assert.Equal(storage.ErrNotFound, err)
+	_, err = os.Stat(filepath.Join(dir, "parts", id.Hex()+".part"))
+	assert.Nil(err)
+	rawRecruit, err := crud.FindID(config.RecruitsCollection, moc.Recruits[0].ID)
+	failOnError(assert, err)
+	assert.NotEqual("/file/uploads/"+id.Hex()+".mp4", rawRecruit.(bson.M)["vid1_url"])
+
+	// once the record is back the last part can be sent again
+	store.afterPut = nil
+	failOnError(assert, crud.Insert(config.UploadsCollection, saved))
+	res = tusRequest(handler, http.MethodHead, location, token, nil, nil)
+	assert.Equal("40", res.Header.Get("Upload-Offset"))
+	assert.Equal(http.StatusNoContent, tusPatch(handler, location, token, 40, video[40:]).StatusCode)
+
+	rawRecruit, err = crud.FindID(config.RecruitsCollection, moc.Recruits[0].ID)
+	failOnError(assert, err)
+	assert.Equal("/file/uploads/"+id.Hex()+".mp4", rawRecruit.(bson.M)["vid1_url"])
+	stored, err := store.Stat("uploads/" + id.Hex() + ".mp4")
+	failOnError(assert, err)
+	assert.Equal(int64(len(video)), stored.Size)
+	_, err = os.Stat(filepath.Join(dir, "parts", id.Hex()+".part"))
+	assert.True(os.IsNotExist(err))
+}
+
+// tests that unfinished uploads expire
+func TestTusUploadExpiry(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	dir, err := ioutil.TempDir("", "uploads")
+	failOnError(assert, err)
+	defer os.RemoveAll(dir)
+	handler := upload.NewHandler(crud, storage.NewLocalStore(filepath.Join(dir, "files")), filepath.Join(dir, "parts"), "/uploads")
+	token, _ := login(crud, moc.Accounts[0].ID, "none")
+
+	create := func() (string, bson.ObjectId) {
+		res := tusRequest(handler, http.MethodPost, "/uploads", token, map[string]string{
+			"Upload-Length":   "100",
+			"Upload-Metadata": uploadMetadata("vid1", "video/mp4"),
+		}, nil)
+		location := res.Header.Get("Location")
+		assert.Equal(http.StatusNoContent, tusPatch(handler, location, token, 0, mp4Header).StatusCode)
+		return location, bson.ObjectIdHex(location[len("/uploads/"):])
+	}
+	stale, staleID := create()
+	fresh, freshID := create()
+	failOnError(assert, crud.UpdateID(config.UploadsCollection, staleID, bson.M{"updated_at": time.Now().Add(-models.UploadExpiry - time.Minute)}))
+
+	// expired uploads are removed with their parts
+	removed, err := handler.RemoveExpired(time.Now())
+	failOnError(assert, err)
+	assert.Equal(1, removed)
+	_, err = crud.FindID(config.UploadsCollection, staleID)
+	assert.NotNil(err)
+	_, err = os.Stat(filepath.Join(dir, "parts", staleID.Hex()+".part"))
+	assert.True(os.IsNotExist(err))
+	assert.Equal(http.StatusNotFound, tusRequest(handler, http.MethodHead, stale, token, nil, nil).StatusCode)
+	assert.Equal(http.StatusOK, tusRequest(handler, http.MethodHead, fresh, token, nil, nil).StatusCode)
+
+	// and can't be continued if they're asked for before they're removed
+	failOnError(assert, crud.UpdateID(config.UploadsCollection, freshID, bson.M{"updated_at": time.Now().Add(-models.UploadExpiry - time.Minute)}))
+	assert.Equal(http.StatusNotFound, tusPatch(handler, fresh, token, len(mp4Header), []byte("frame")).StatusCode)
+	_, err = crud.FindID(config.UploadsCollection, freshID)
+	assert.NotNil(err)
+}
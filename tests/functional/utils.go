@@ -159,6 +159,17 @@ func getNonRecruitUserAccount() models.Account {
 	return account
 }
 
+// getHunterUserAccount returns a non-sys user account with a hunter profile
+func getHunterUserAccount() models.Account {
+	var account models.Account
+	for _, acc := range moc.Accounts {
+		if !utils.IsNullID(acc.HunterID) && !utils.IsSysAccount(&acc) {
+			return acc
+		}
+	}
+	return account
+}
+
 // getPlainUserAccount returns a non-sys user account without a hunter or recruit  profile
 func getPlainUserAccount() models.Account {
 	var account models.Account
@@ -0,0 +1,389 @@
+package functionaltests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	moc "../../mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// tests that the public vacancies query lists open vacancies matching the filter
+func TestVacancies(t *testing.T) {
+	assert := assert.New(t)
+	handler := createLoadedGqlHandler()
+
+	// prepare query
+	queryFormat := `
+		query{
+			vacancies%s{
+				id
+			}
+		}
+	`
+
+	// filters and the vacancies they should match
+	input := []string{
+		``,
+		`(filter: {province: WESTERN_CAPE})`,
+		`(filter: {min_salary: 10000})`,
+		fmt.Sprintf(`(filter: {industry_id: "%s"})`, moc.Industries[1].ID.Hex()),
+	}
+	output := [][]int{
+		{0, 2},
+		{2},
+		{0},
+		{},
+	}
+
+	for i, in := range input {
+		// request
+		response, err := gqlRequestAndRespond(handler, fmt.Sprintf(queryFormat, in), nil)
+		failOnError(assert, err)
+
+		// prepare expected
+		vacancies := make([]interface{}, 0)
+		for _, index := range output[i] {
+			vacancies = append(vacancies, map[string]interface{}{
+				"id": moc.Vacancies[index].ID.Hex(),
+			})
+		}
+		expected := map[string]interface{}{
+			"data": map[string]interface{}{
+				"vacancies": vacancies,
+			},
+		}
+
+		assert.Equal(expected, response, fmt.Sprintf("Case [%v]: %s", i+1, msgInvalidResult))
+	}
+}
+
+// tests that HunterViewer.Vacancies lists all of the Company's vacancies
+func TestHunterViewer_Vacancies(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Companies[0] recruiter
+	token, _ := login(crud, moc.Accounts[3].ID, "none")
+
+	// prepare query
+	query := fmt.Sprintf(`
+		query{
+			view(token: "%s", enforce: HUNTER){
+				... on HunterViewer{
+					vacancies{
+						title
+						status
+						industry{
+							id
+						}
+						company{
+							id
+						}
+					}
+				}
+			}
+		}
+	`, token)
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	// prepare expected
+	vacancies := make([]interface{}, 0)
+	for _, vacancy := range moc.Vacancies[:2] {
+		vacancies = append(vacancies, map[string]interface{}{
+			"title":    vacancy.Title,
+			"status":   vacancy.Status,
+			"industry": map[string]interface{}{"id": vacancy.IndustryID.Hex()},
+			"company":  map[string]interface{}{"id": moc.Companies[0].ID.Hex()},
+		})
+	}
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"view": map[string]interface{}{
+				"vacancies": vacancies,
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests that HunterEditor.CreateVacancy posts a vacancy for the Hunter's Company
+func TestHunterEditor_CreateVacancy(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Companies[1] owner
+	token, _ := login(crud, moc.Accounts[4].ID, "none")
+
+	// prepare data
+	title := "Quantity Surveyor"
+	closing := time.Now().AddDate(0, 1, 0)
+	industryID := moc.Industries[1].ID.Hex()
+
+	// prepare query
+	query := fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: HUNTER){
+				... on HunterEditor{
+					createVacancy(info: {
+						title: "%s",
+						description: "Cost out our builds.",
+						industry_id: "%s",
+						province: WESTERN_CAPE,
+						city: "Stellenbosch",
+						employment_type: FULL_TIME,
+						salary_min: 30000,
+						salary_max: 40000,
+						closing_date: "%s",
+						status: OPEN
+					}){
+						title
+						city
+						employment_type
+						salary_min
+						salary_max
+						closing_date
+						status
+						company{
+							id
+						}
+					}
+				}
+			}
+		}
+	`, token, title, industryID, closing.Format("2006-01-02"))
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	// prepare expected
+	closingDate, _ := time.Parse("2006-01-02", closing.Format("2006-01-02"))
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"edit": map[string]interface{}{
+				"createVacancy": map[string]interface{}{
+					"title":           title,
+					"city":            "Stellenbosch",
+					"employment_type": "FULL_TIME",
+					"salary_min":      float64(30000),
+					"salary_max":      float64(40000),
+					"closing_date":    closingDate.Format(time.RFC3339),
+					"status":          "OPEN",
+					"company": map[string]interface{}{
+						"id": moc.Companies[1].ID.Hex(),
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests that HunterEditor.UpdateVacancy can publish a draft vacancy
+func TestHunterEditor_UpdateVacancy(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Companies[0] owner
+	token, _ := login(crud, moc.Accounts[2].ID, "none")
+	vacancy := moc.Vacancies[1]
+
+	// prepare query
+	query := fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: HUNTER){
+				... on HunterEditor{
+					updateVacancy(id: "%s", info: {
+						salary_max: 27000,
+						status: OPEN
+					}){
+						id
+						title
+						salary_min
+						salary_max
+						status
+					}
+				}
+			}
+		}
+	`, token, vacancy.ID.Hex())
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	// prepare expected
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"edit": map[string]interface{}{
+				"updateVacancy": map[string]interface{}{
+					"id":         vacancy.ID.Hex(),
+					"title":      vacancy.Title,
+					"salary_min": float64(vacancy.SalaryMin),
+					"salary_max": float64(27000),
+					"status":     "OPEN",
+				},
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests that HunterEditor.CloseVacancy closes a vacancy
+func TestHunterEditor_CloseVacancy(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Companies[0] recruiter
+	token, _ := login(crud, moc.Accounts[3].ID, "none")
+	vacancy := moc.Vacancies[0]
+
+	// prepare query
+	query := fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: HUNTER){
+				... on HunterEditor{
+					closeVacancy(id: "%s"){
+						id
+						status
+					}
+				}
+			}
+		}
+	`, token, vacancy.ID.Hex())
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	// prepare expected
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"edit": map[string]interface{}{
+				"closeVacancy": map[string]interface{}{
+					"id":     vacancy.ID.Hex(),
+					"status": "CLOSED",
+				},
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+func TestHunterEditor_RemoveVacancy(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// login as Companies[1] owner
+	token, _ := login(crud, moc.Accounts[4].ID, "none")
+
+	// prepare query
+	query := fmt.Sprintf(`
+		mutation{
+			edit(token: "%s", enforce: HUNTER){
+				... on HunterEditor{
+					removeVacancy(id: "%s")
+				}
+			}
+		}
+	`, token, moc.Vacancies[2].ID.Hex())
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	// prepare expected
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"edit": map[string]interface{}{
+				"removeVacancy": "Vacancy successfully removed.",
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests that invalid vacancy mutations fail
+func TestHunterEditor_VacancyInvalid(t *testing.T) {
+	assert := assert.New(t)
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+
+	// get access tokens
+	ownerToken, _ := login(crud, moc.Accounts[2].ID, "none")
+	sysToken, _ := login(crud, getSysUserAccount().ID, "none")
+	closing := time.Now().AddDate(0, 1, 0).Format("2006-01-02")
+
+	// prepare query
+	queryFormat := `
+		mutation{
+			edit(token: "%s", enforce: HUNTER){
+				... on HunterEditor{
+					%s
+				}
+			}
+		}
+	`
+
+	// invalid inputs
+	input := []string{
+		fmt.Sprintf(queryFormat, ownerToken, fmt.Sprintf(`
+			# case 1 close another company's vacancy
+			closeVacancy(id: "%s"){ id }
+		`, moc.Vacancies[2].ID.Hex())),
+		fmt.Sprintf(queryFormat, sysToken, fmt.Sprintf(`
+			# case 2 create vacancy without a company
+			createVacancy(info: {
+				title: "Tester", description: "Test things.", industry_id: "%s",
+				province: GAUTENG, city: "Pretoria", employment_type: FULL_TIME,
+				salary_min: 1, salary_max: 2, closing_date: "%s"
+			}){ id }
+		`, moc.Industries[0].ID.Hex(), closing)),
+		fmt.Sprintf(queryFormat, ownerToken, fmt.Sprintf(`
+			# case 3 inverted salary range
+			createVacancy(info: {
+				title: "Tester", description: "Test things.", industry_id: "%s",
+				province: GAUTENG, city: "Pretoria", employment_type: FULL_TIME,
+				salary_min: 20, salary_max: 2, closing_date: "%s"
+			}){ id }
+		`, moc.Industries[0].ID.Hex(), closing)),
+		fmt.Sprintf(queryFormat, ownerToken, fmt.Sprintf(`
+			# case 4 unknown industry
+			createVacancy(info: {
+				title: "Tester", description: "Test things.", industry_id: "%s",
+				province: GAUTENG, city: "Pretoria", employment_type: FULL_TIME,
+				salary_min: 1, salary_max: 2, closing_date: "%s"
+			}){ id }
+		`, moc.Companies[0].ID.Hex(), closing)),
+		fmt.Sprintf(queryFormat, ownerToken, fmt.Sprintf(`
+			# case 5 open vacancy closing in the past
+			createVacancy(info: {
+				title: "Tester", description: "Test things.", industry_id: "%s",
+				province: GAUTENG, city: "Pretoria", employment_type: FULL_TIME,
+				salary_min: 1, salary_max: 2, closing_date: "2001-01-01", status: OPEN
+			}){ id }
+		`, moc.Industries[0].ID.Hex())),
+	}
+
+	for i, query := range input {
+		// request
+		response, err := gqlRequestAndRespond(handler, query, nil)
+		failOnError(assert, err)
+		assert.Contains(response, "errors", fmt.Sprintf("Case [%v]: %s", i+1, msgNoError))
+	}
+}
@@ -126,6 +126,102 @@ func TestViewRecruitViewer(t *testing.T) {
 	assert.Equal(expectedStr, actualStr, msgInvalidResult)
 }
 
+// tests view on HunterViewer
+func TestViewHunterViewer(t *testing.T) {
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+	assert := assert.New(t)
+
+	// login as hunter account
+	account := getHunterUserAccount()
+	token, _ := login(crud, account.ID, "none")
+	hunter := moc.Hunters[0]
+
+	// prepare query
+	query := fmt.Sprintf(`
+		query{
+			view(token: "%s", enforce: HUNTER){
+				... on HunterViewer{
+					id
+					profile{
+						name
+						phone
+						email
+						position
+					}
+				}
+			}
+		}
+	`, token)
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	// expected
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"view": map[string]interface{}{
+				"id": hunter.ID.Hex(),
+				"profile": map[string]interface{}{
+					"name":     account.Name,
+					"phone":    hunter.Phone,
+					"email":    hunter.Email,
+					"position": hunter.Position,
+				},
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
+// tests that HunterViewer.Recruit looks up a recruit by ID
+func TestViewHunterViewerRecruit(t *testing.T) {
+	crud := moc.NewLoadedCRUD()
+	handler := createGqlHandler(crud)
+	assert := assert.New(t)
+
+	// login as hunter account
+	token, _ := login(crud, getHunterUserAccount().ID, "none")
+	recruit := moc.Recruits[1]
+	account := moc.Accounts[1]
+
+	// prepare query
+	query := fmt.Sprintf(`
+		query{
+			view(token: "%s", enforce: HUNTER){
+				... on HunterViewer{
+					recruit(id: "%s"){
+						id
+						name
+						city
+					}
+				}
+			}
+		}
+	`, token, recruit.ID.Hex())
+
+	// request
+	response, err := gqlRequestAndRespond(handler, query, nil)
+	failOnError(assert, err)
+
+	// expected
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"view": map[string]interface{}{
+				"recruit": map[string]interface{}{
+					"id":   recruit.ID.Hex(),
+					"name": account.Name,
+					"city": recruit.City,
+				},
+			},
+		},
+	}
+
+	assert.Equal(expected, response, msgInvalidResult)
+}
+
 // tests view on AccountViewer
 func TestViewAccountViewer(t *testing.T) {
 	crud := moc.NewLoadedCRUD()
@@ -217,6 +313,7 @@ func TestViewEnforceValid(t *testing.T) {
 	// get access tokens
 	sysToken, _ := login(crud, getSysUserAccount().ID, "none")
 	recruitToken, _ := login(crud, getRecruitUserAccount().ID, "none")
+	hunterToken, _ := login(crud, getHunterUserAccount().ID, "none")
 
 	// prepare query
 	queryFormat := `query{
@@ -243,6 +340,10 @@ func TestViewEnforceValid(t *testing.T) {
 			# case 3 recruit account enforce ACCOUNT
 			token: "%s", enforce: ACCOUNT
 		`, recruitToken),
+		fmt.Sprintf(`
+			# case 5 enforce HUNTER
+			token: "%s", enforce: HUNTER
+		`, hunterToken),
 	}
 
 	for i, in := range input {
@@ -288,6 +389,10 @@ func TestViewEnforceInvalid(t *testing.T) {
 			# case 3 enforce ACCOUNT on bad token
 			token: "%s", enforce: ACCOUNT
 		`, badToken),
+		fmt.Sprintf(`
+			# case 4 enforce HUNTER on Non-HunterAccount
+			token: "%s", enforce: HUNTER
+		`, plainToken),
 	}
 
 	for i, in := range input {
@@ -326,15 +431,10 @@ func TestViewRecruitList(t *testing.T) {
 						vid1_url,
 						vid2_url,
 						age,
-						qa1{
-							question
-							answer
-						},
-						qa2{
-							question
+						answers{
+							question_id
 							answer
 						}
-
 					}
 				}
 			}
@@ -349,6 +449,13 @@ func TestViewRecruitList(t *testing.T) {
 	year := int32(time.Now().Year())
 	recruits := make([]interface{}, 0)
 	for _, r := range moc.Recruits {
+		answers := make([]interface{}, 0)
+		for _, qa := range r.Answers {
+			answers = append(answers, map[string]interface{}{
+				"question_id": qa.QuestionID.Hex(),
+				"answer":      qa.Answer,
+			})
+		}
 		recruits = append(recruits, map[string]interface{}{
 			"id":         r.ID.Hex(),
 			"email":      r.Email,
@@ -360,14 +467,7 @@ func TestViewRecruitList(t *testing.T) {
 			"vid1_url":   r.Vid1Url,
 			"vid2_url":   r.Vid2Url,
 			"age":        float64(year - r.BirthYear),
-			"qa1": map[string]interface{}{
-				"question": r.Qa1.Question,
-				"answer":   r.Qa1.Answer,
-			},
-			"qa2": map[string]interface{}{
-				"question": r.Qa2.Question,
-				"answer":   r.Qa2.Answer,
-			},
+			"answers":    answers,
 		})
 	}
 	expected := map[string]interface{}{
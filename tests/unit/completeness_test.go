@@ -0,0 +1,55 @@
+package unittests
+
+import (
+	"testing"
+
+	models "../../models"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// tests that RecruitCompleteness weighs the filled in profile items
+func TestRecruitCompleteness(t *testing.T) {
+	assert := assert.New(t)
+
+	recruit := models.Recruit{ID: bson.NewObjectId(), Vid1Url: " None ", Vid2Url: ""}
+	completeness := models.RecruitCompleteness(recruit, nil)
+	assert.Equal(0, completeness.Percent)
+	assert.Equal([]string{
+		models.ProfileVideo1,
+		models.ProfileVideo2,
+		models.ProfileAnswers,
+		models.ProfileWorkExperience,
+		models.ProfileEducation,
+		models.ProfileQualification,
+		models.ProfileVerifiedQualification,
+	}, completeness.Missing)
+
+	recruit.Vid1Url = "http://youtube.com/v1"
+	recruit.Answers = []models.QA{{QuestionID: bson.NewObjectId(), Answer: "Because."}}
+	documents := []models.Document{
+		// other owners' and other types of documents don't count
+		{OwnerID: bson.NewObjectId(), DocType: models.QualificationDocument, Verified: true},
+		{OwnerID: recruit.ID, DocType: "ID", Verified: true},
+		{OwnerID: recruit.ID, DocType: models.QualificationDocument},
+	}
+	completeness = models.RecruitCompleteness(recruit, documents)
+	assert.Equal(55, completeness.Percent)
+	assert.Equal([]string{
+		models.ProfileVideo2,
+		models.ProfileWorkExperience,
+		models.ProfileEducation,
+		models.ProfileVerifiedQualification,
+	}, completeness.Missing)
+
+	recruit.Vid2Url = "http://youtube.com/v2"
+	documents[2].Verified = true
+	completeness = models.RecruitCompleteness(recruit, documents)
+	assert.Equal(80, completeness.Percent)
+
+	recruit.WorkExperience = []models.WorkExperience{{Employer: "Acme", Title: "Clerk"}}
+	recruit.Education = []models.Education{{Institution: "UCT", Qualification: "BCom"}}
+	completeness = models.RecruitCompleteness(recruit, documents)
+	assert.Equal(100, completeness.Percent)
+	assert.Empty(completeness.Missing)
+}
@@ -0,0 +1,136 @@
+package unittests
+
+import (
+	"sync"
+	"testing"
+
+	config "../../config"
+	credits "../../credits"
+	db "../../database"
+	models "../../models"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// newLedger creates a Ledger over an empty mock credits collection
+func newLedger() (*credits.Ledger, *db.CRUD) {
+	crud := db.NewCRUD(nil)
+	crud.Insert(config.CreditsCollection)
+	return credits.NewLedger(crud), crud
+}
+
+func TestLedgerBalance(t *testing.T) {
+	assert := assert.New(t)
+	ledger, crud := newLedger()
+	hunterID, sysID, subjectID := bson.NewObjectId(), bson.NewObjectId(), bson.NewObjectId()
+
+	entry, err := ledger.Grant(hunterID, 10, "Starter pack", sysID)
+	assert.Nil(err)
+	assert.Equal(1, entry.Seq)
+	assert.Equal(10, entry.Balance)
+
+	entry, err = ledger.Spend(hunterID, 3, "Contact request", subjectID, hunterID)
+	assert.Nil(err)
+	assert.Equal(2, entry.Seq)
+	assert.Equal(-3, entry.Amount)
+	assert.Equal(7, entry.Balance)
+
+	// balances can't become negative
+	_, err = ledger.Spend(hunterID, 8, "Contact request", bson.NewObjectId(), hunterID)
+	assert.Equal(credits.ErrInsufficientCredits, err)
+	_, err = ledger.Adjust(hunterID, -8, "Correction", sysID)
+	assert.Equal(credits.ErrInsufficientCredits, err)
+
+	entry, err = ledger.Adjust(hunterID, -2, "Correction", sysID)
+	assert.Nil(err)
+	assert.Equal(5, entry.Balance)
+
+	// spends are refunded once
+	entry, err = ledger.Refund(hunterID, "Declined", subjectID, hunterID)
+	assert.Nil(err)
+	assert.Equal(3, entry.Amount)
+	assert.Equal(8, entry.Balance)
+	_, err = ledger.Refund(hunterID, "Declined", subjectID, hunterID)
+	assert.NotNil(err)
+
+	// invalid entries are rejected
+	_, err = ledger.Grant(hunterID, 0, "Nothing", sysID)
+	assert.NotNil(err)
+	_, err = ledger.Grant(hunterID, 5, "", sysID)
+	assert.NotNil(err)
+
+	balance, err := credits.Balance(crud, hunterID)
+	assert.Nil(err)
+	assert.Equal(8, balance)
+
+	entries, total, err := credits.History(crud, hunterID, 0, 2)
+	assert.Nil(err)
+	assert.Equal(4, total)
+	assert.Equal([]int{4, 3}, []int{entries[0].Seq, entries[1].Seq})
+	assert.Equal(models.CreditRefund, entries[0].Kind)
+
+	// other hunters have their own ledgers
+	balance, _ = credits.Balance(crud, bson.NewObjectId())
+	assert.Equal(0, balance)
+}
+
+func TestLedgerSpendOnce(t *testing.T) {
+	assert := assert.New(t)
+	ledger, crud := newLedger()
+	hunterID, vacancyID := bson.NewObjectId(), bson.NewObjectId()
+	ledger.Grant(hunterID, 10, "Starter pack", bson.NewObjectId())
+
+	entry, err := ledger.SpendOnce(hunterID, 5, "Vacancy posting", vacancyID, hunterID)
+	assert.Nil(err)
+	if !assert.NotNil(entry) {
+		return
+	}
+
+	// spends are keyed by their subject so the db keeps other processes from spending on it
+	// too, other entries have no key so the sparse unique index leaves them out
+	assert.Equal(vacancyID, entry.SpendKey)
+	rawEntries, _ := crud.FindAll(config.CreditsCollection, bson.M{"spend_key": bson.M{"$exists": true}})
+	assert.Len(rawEntries, 1)
+	assert.Equal(vacancyID, models.TransformCreditEntry(fromMongo(t, *entry)).SpendKey)
+
+	entry, err = ledger.SpendOnce(hunterID, 5, "Vacancy posting", vacancyID, hunterID)
+	assert.Nil(err)
+	assert.Nil(entry)
+
+	balance, _ := credits.Balance(crud, hunterID)
+	assert.Equal(5, balance)
+}
+
+func TestLedgerConcurrentSpends(t *testing.T) {
+	assert := assert.New(t)
+	ledger, crud := newLedger()
+	hunterID := bson.NewObjectId()
+	ledger.Grant(hunterID, 10, "Starter pack", bson.NewObjectId())
+
+	// 50 simultaneous spends of 1 credit, only 10 of which can be paid for
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	spent := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := ledger.Spend(hunterID, 1, "Contact request", bson.NewObjectId(), hunterID); err == nil {
+				mu.Lock()
+				spent++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(10, spent)
+
+	// every entry follows the one before it
+	entries, total, err := credits.History(crud, hunterID, 0, 0)
+	assert.Nil(err)
+	assert.Equal(11, total)
+	for i, entry := range entries {
+		assert.Equal(11-i, entry.Seq)
+		assert.Equal(i, entry.Balance)
+	}
+}
@@ -18,6 +18,20 @@ type person struct {
 	Food string
 }
 
+type address struct {
+	City string
+}
+
+type resident struct {
+	ID      bson.ObjectId `bson:"_id"`
+	Name    string
+	Age     int32
+	Food    string
+	Address address
+	Tags    []string
+	Visits  []address
+}
+
 // globs
 
 const collection = "collection"
@@ -110,6 +124,27 @@ func TestCrudUpdateID(t *testing.T) {
 
 }
 
+func TestCrudUpdateOne(t *testing.T) {
+	crud := db.NewCRUD(nil)
+	r0 := resident{ID: bson.NewObjectId(), Name: "John", Age: 23, Tags: []string{"early"}}
+	crud.Insert(collection, r0)
+
+	// prepare results
+	update := bson.M{"$set": bson.M{"Age": int32(24)}, "$push": bson.M{"Tags": "late"}}
+	err1 := crud.UpdateOne(collection, bson.M{"_id": r0.ID, "Age": int32(23)}, update)
+	err2 := crud.UpdateOne(collection, bson.M{"_id": r0.ID, "Age": int32(23)}, update)
+	i1, _ := crud.FindID(collection, r0.ID)
+	r1 := i1.(bson.M)
+
+	// make asserts
+	assert := assert.New(t)
+	assert.Nil(err1, "crud.UpdateOne failed to update a matching entry")
+	assert.NotNil(err2, "crud.UpdateOne doesn't return an error when no entry matches")
+	assert.Equal(int32(24), r1["Age"], "crud.UpdateOne did not set the field")
+	assert.Equal([]interface{}{"early", "late"}, r1["Tags"], "crud.UpdateOne did not push to the field once")
+	assert.Equal("John", r1["Name"], "crud.UpdateOne changed other fields")
+}
+
 func TestCrudDeleteID(t *testing.T) {
 	crud := loadedCRUD()
 	p0 := people[0].(person)
@@ -126,3 +161,81 @@ func TestCrudDeleteID(t *testing.T) {
 	assert.Nil(r1, "crud.DeleteID did not remove value from the db.")
 	assert.NotNil(err, "crud.DeleteID doesn't return an error on nil result")
 }
+
+func TestCrudFindAllOperators(t *testing.T) {
+	crud := loadedCRUD()
+	crud.Insert(collection, resident{
+		ID:      bson.NewObjectId(),
+		Name:    "Thabo",
+		Age:     30,
+		Food:    "Pap",
+		Address: address{"Soweto"},
+		Tags:    []string{"braai", "soccer"},
+	})
+
+	// prepare results
+	r1, _ := crud.FindAll(collection, bson.M{"Age": bson.M{"$gte": 30, "$lt": 42}})                              // Lisa, Thabo
+	r2, _ := crud.FindAll(collection, bson.M{"Food": bson.M{"$in": []string{"Cake", "Yoghurt"}}})                // Lisa, Mark
+	r3, _ := crud.FindAll(collection, bson.M{"$or": []bson.M{{"Name": "John"}, {"Age": 42}}})                    // John, Mark
+	r4, _ := crud.FindAll(collection, bson.M{"Name": bson.RegEx{Pattern: "^ma", Options: "i"}})                  // Mark, Martha
+	r5, _ := crud.FindAll(collection, bson.M{"Address.City": "Soweto"})                                          // Thabo
+	r6, _ := crud.FindAll(collection, bson.M{"Tags": "soccer"})                                                  // Thabo
+	r7, _ := crud.FindAll(collection, bson.M{"$nor": []bson.M{{"Food": "Ice-Cream"}}, "Age": bson.M{"$ne": 30}}) // Lisa, Mark
+
+	// make assertions
+	assert := assert.New(t)
+	assert.Equal(2, len(r1), "crud.FindAll does not handle comparison operators")
+	assert.Equal(2, len(r2), "crud.FindAll does not handle $in")
+	assert.Equal(2, len(r3), "crud.FindAll does not handle $or")
+	assert.Equal(2, len(r4), "crud.FindAll does not handle regular expressions")
+	assert.Equal(1, len(r5), "crud.FindAll does not handle dotted field paths")
+	assert.Equal(1, len(r6), "crud.FindAll does not match array elements")
+	assert.Equal(2, len(r7), "crud.FindAll does not handle $nor and $ne")
+}
+
+func TestCrudFindAllArrayPaths(t *testing.T) {
+	crud := loadedCRUD()
+	thabo := resident{
+		ID:     bson.NewObjectId(),
+		Name:   "Thabo",
+		Visits: []address{{"Durban"}, {"Polokwane"}},
+	}
+	crud.Insert(collection, thabo)
+
+	// make assertions
+	assert := assert.New(t)
+	r1, _ := crud.FindAll(collection, bson.M{"Visits.City": "Polokwane"})
+	assert.Equal(1, len(r1), "crud.FindAll does not descend into arrays of documents")
+	r2, _ := crud.FindAll(collection, bson.M{"Visits.City": bson.M{"$in": []string{"Durban", "Kimberley"}}})
+	assert.Equal(1, len(r2), "crud.FindAll does not descend into arrays of documents")
+
+	// updates store values as is
+	crud.UpdateID(collection, thabo.ID, bson.M{"Visits": []address{{"Kimberley"}}})
+	r3, _ := crud.FindAll(collection, bson.M{"Visits.City": "Kimberley"})
+	assert.Equal(1, len(r3), "crud.FindAll does not descend into arrays of structs")
+	r4, _ := crud.FindAll(collection, bson.M{"Visits.City": "Durban"})
+	assert.Equal(0, len(r4), "crud.FindAll matches stale values")
+}
+
+func TestCrudFindPage(t *testing.T) {
+	crud := loadedCRUD()
+
+	// prepare results
+	r1, total1, _ := crud.FindPage(collection, nil, []string{"-Age", "Name"}, 0, 0)
+	r2, total2, _ := crud.FindPage(collection, bson.M{"Food": "Ice-Cream"}, []string{"Name"}, 1, 1)
+	r3, total3, _ := crud.FindPage(collection, nil, nil, 10, 2)
+
+	// make assertions
+	assert := assert.New(t)
+	assert.Equal(len(people), total1, "crud.FindPage does not return the total number of matches")
+	names := make([]string, 0)
+	for _, r := range r1 {
+		names = append(names, r.(bson.M)["Name"].(string))
+	}
+	assert.Equal([]string{"Mark", "Lisa", "John", "Martha"}, names, "crud.FindPage does not sort results")
+	assert.Equal(2, total2, "crud.FindPage does not return the total number of matches")
+	assert.Equal(1, len(r2), "crud.FindPage does not limit results")
+	assert.Equal("Martha", r2[0].(bson.M)["Name"], "crud.FindPage does not skip results")
+	assert.Equal(len(people), total3, "crud.FindPage does not return the total number of matches")
+	assert.Equal(0, len(r3), "crud.FindPage does not handle skipping past the last result")
+}
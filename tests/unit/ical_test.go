@@ -0,0 +1,75 @@
+package unittests
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	ical "../../ical"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendarBytes(t *testing.T) {
+	assert := assert.New(t)
+
+	start := time.Date(2019, 3, 4, 9, 30, 0, 0, time.UTC)
+	calendar := ical.Calendar{
+		Method: ical.MethodRequest,
+		Events: []ical.Event{
+			{
+				UID:         "abc@irecruit",
+				Sequence:    2,
+				Stamp:       start,
+				Start:       start,
+				End:         start.Add(time.Hour),
+				Summary:     "Interview; round 1, Acme",
+				Description: "Line one\nline two",
+				Status:      ical.StatusConfirmed,
+				Organizer:   &ical.Person{Name: "Lisa, HR", Email: "lisa@acme.co.za"},
+			},
+		},
+	}
+
+	expected := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:" + ical.ProdID,
+		"CALSCALE:GREGORIAN",
+		"METHOD:REQUEST",
+		"BEGIN:VEVENT",
+		"UID:abc@irecruit",
+		"SEQUENCE:2",
+		"DTSTAMP:20190304T093000Z",
+		"DTSTART:20190304T093000Z",
+		"DTEND:20190304T103000Z",
+		"SUMMARY:Interview\\; round 1\\, Acme",
+		"DESCRIPTION:Line one\\nline two",
+		"STATUS:CONFIRMED",
+		"ORGANIZER;CN=\"Lisa, HR\":mailto:lisa@acme.co.za",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+
+	assert.Equal(expected, string(calendar.Bytes()))
+}
+
+func TestCalendarFolding(t *testing.T) {
+	assert := assert.New(t)
+
+	calendar := ical.Calendar{
+		Events: []ical.Event{
+			{Summary: strings.Repeat("é", 100)},
+		},
+	}
+
+	for _, line := range strings.Split(string(calendar.Bytes()), "\r\n") {
+		assert.True(len(line) <= 75, "line too long: %q", line)
+		assert.True(utf8.ValidString(line), "invalid utf-8: %q", line)
+	}
+
+	// unfolding restores the original value
+	unfolded := strings.Replace(string(calendar.Bytes()), "\r\n ", "", -1)
+	assert.Contains(unfolded, "\r\nSUMMARY:"+strings.Repeat("é", 100)+"\r\n")
+}
@@ -0,0 +1,42 @@
+package unittests
+
+import (
+	"testing"
+
+	mware "../../middleware"
+	models "../../models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]string{}, mware.ParseAcceptLanguage(""))
+	assert.Equal([]string{"zu-za", "zu", "af", "en"}, mware.ParseAcceptLanguage("af;q=0.8, zu-ZA, en;q=0.5, *;q=0.1"))
+	assert.Equal([]string{"xh", "en"}, mware.ParseAcceptLanguage("de;q=0, xh, en;q=bad"))
+}
+
+func TestTranslate(t *testing.T) {
+	assert := assert.New(t)
+	translations := []models.Translation{{Locale: "zu", Text: "Uthanda muphi umbala?"}, {Locale: "af", Text: "Watter kleur?"}}
+
+	// the first locale there's a translation to is used
+	assert.Equal("Uthanda muphi umbala?", models.Translate("Which colour?", translations, []string{"xh", "zu", "af"}))
+	assert.Equal("Watter kleur?", models.Translate("Which colour?", translations, []string{"af-za", "af"}))
+	// the default locale has no translation, it's the text itself
+	assert.Equal("Which colour?", models.Translate("Which colour?", translations, []string{"en", "zu"}))
+	assert.Equal("Which colour?", models.Translate("Which colour?", translations, nil))
+
+	// one translation per locale, but not to the default locale
+	translations = models.SetTranslation(translations, models.Translation{Locale: "zu", Text: "Umbala?"})
+	assert.Equal([]models.Translation{{Locale: "af", Text: "Watter kleur?"}, {Locale: "zu", Text: "Umbala?"}}, translations)
+	translation := models.Translation{Locale: models.DefaultLocale, Text: "Colour?"}
+	assert.NotNil(translation.OK())
+	translation = models.Translation{Locale: "fr", Text: "Couleur?"}
+	assert.NotNil(translation.OK())
+
+	translations, ok := models.RemoveTranslation(translations, "zu")
+	assert.True(ok)
+	_, ok = models.RemoveTranslation(translations, "zu")
+	assert.False(ok)
+}
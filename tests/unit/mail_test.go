@@ -0,0 +1,325 @@
+package unittests
+
+import (
+	"bufio"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	config "../../config"
+	db "../../database"
+	mail "../../mail"
+	models "../../models"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// fakeSMTP is a minimal in-process SMTP server that records the messages it receives
+type fakeSMTP struct {
+	listener net.Listener
+	failData bool
+
+	mu       sync.Mutex
+	from     []string
+	rcpts    [][]string
+	messages []string
+}
+
+// newFakeSMTP starts a fake SMTP server on a random local port
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeSMTP{listener: listener}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+// serve handles a single SMTP session
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	var from string
+	var rcpts []string
+	reply("220 localhost fake ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"):
+			reply("250-localhost")
+			reply("250 8BITMIME")
+		case strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			from = pathAddress(line[len("MAIL FROM:"):])
+			rcpts = nil
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			rcpts = append(rcpts, pathAddress(line[len("RCPT TO:"):]))
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data []string
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				l = strings.TrimRight(l, "\r\n")
+				if l == "." {
+					break
+				}
+				data = append(data, strings.TrimPrefix(l, "."))
+			}
+			s.mu.Lock()
+			fail := s.failData
+			s.mu.Unlock()
+			if fail {
+				reply("451 Try again later")
+				continue
+			}
+			s.mu.Lock()
+			s.from = append(s.from, from)
+			s.rcpts = append(s.rcpts, rcpts)
+			s.messages = append(s.messages, strings.Join(data, "\r\n"))
+			s.mu.Unlock()
+			reply("250 OK")
+		case cmd == "RSET", cmd == "NOOP":
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+// pathAddress returns the address of an SMTP path such as "<a@mail.com> BODY=8BITMIME"
+func pathAddress(path string) string {
+	path = strings.TrimSpace(path)
+	if end := strings.Index(path, ">"); end >= 0 {
+		path = path[:end]
+	}
+	return strings.TrimPrefix(path, "<")
+}
+
+func TestSMTPSender(t *testing.T) {
+	assert := assert.New(t)
+
+	server := newFakeSMTP(t)
+	defer server.listener.Close()
+	host, port, _ := net.SplitHostPort(server.listener.Addr().String())
+	sender := mail.NewSMTPSender(host, port, "", "")
+
+	msg := mail.Message{
+		From:    "iRecruit <no-reply@irecruit.co.za>",
+		To:      []string{"Jane Doe <jane@mail.com>", "john@mail.com"},
+		Subject: "Your application moved",
+		Text:    "Hi Jane,\n.leading dot\n",
+		HTML:    "<p>Hi Jane,</p>",
+	}
+	assert.Nil(sender.Send(msg))
+
+	server.mu.Lock()
+	assert.Equal([]string{"no-reply@irecruit.co.za"}, server.from)
+	assert.Equal([][]string{{"jane@mail.com", "john@mail.com"}}, server.rcpts)
+	assert.Len(server.messages, 1)
+	data := server.messages[0]
+	server.mu.Unlock()
+	assert.Contains(data, "Subject: Your application moved\r\n")
+	assert.Contains(data, "To: Jane Doe <jane@mail.com>, john@mail.com\r\n")
+	assert.Contains(data, "Content-Type: multipart/alternative;")
+	assert.Contains(data, "\r\n.leading dot")
+	assert.Contains(data, "<p>Hi Jane,</p>")
+
+	// temporary failures are reported
+	server.mu.Lock()
+	server.failData = true
+	server.mu.Unlock()
+	assert.NotNil(sender.Send(msg))
+
+	// header injection is rejected before connecting
+	msg.Subject = "Hi\r\nBcc: everyone@mail.com"
+	assert.NotNil(sender.Send(msg))
+}
+
+func TestMessageValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	valid := mail.Message{From: "a@mail.com", To: []string{"b@mail.com"}, Subject: "Hi"}
+	assert.Nil(valid.Validate())
+
+	invalid := []mail.Message{
+		{From: "not an address", To: []string{"b@mail.com"}},
+		{From: "a@mail.com"},
+		{From: "a@mail.com", To: []string{"b@mail.com\r\nBcc: c@mail.com"}},
+		{From: "a@mail.com", To: []string{"b@mail.com"}, Subject: "Hi\nBcc: c@mail.com"},
+	}
+	for i, msg := range invalid {
+		assert.NotNil(msg.Validate(), "Case [%v]", i+1)
+	}
+}
+
+func TestMailRender(t *testing.T) {
+	assert := assert.New(t)
+
+	content, err := mail.Render("notification", mail.NotificationData{
+		Name:  "<b>Jane</b>",
+		Title: "Moved\nto interview",
+		Body:  "Tom & Jerry moved you",
+	})
+	assert.Nil(err)
+	assert.Equal("Moved to interview", content.Subject)
+	assert.Contains(content.Text, "Hi <b>Jane</b>,")
+	assert.Contains(content.Text, "Tom & Jerry moved you")
+	assert.Contains(content.HTML, "Hi &lt;b&gt;Jane&lt;/b&gt;,")
+	assert.Contains(content.HTML, "Tom &amp; Jerry moved you")
+	assert.NotContains(content.HTML, "<b>Jane</b>")
+
+	_, err = mail.Render("missing", nil)
+	assert.NotNil(err)
+}
+
+func TestFileSender(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "mail")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	sender := &mail.FileSender{Dir: filepath.Join(dir, "out")}
+	assert.Nil(sender.Send(mail.Message{From: "a@mail.com", To: []string{"b@mail.com"}, Subject: "Hi", Text: "Hello"}))
+
+	files, err := filepath.Glob(filepath.Join(dir, "out", "*.eml"))
+	assert.Nil(err)
+	assert.Len(files, 1)
+	data, _ := ioutil.ReadFile(files[0])
+	assert.Contains(string(data), "Subject: Hi\r\n")
+}
+
+func TestBackoff(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(time.Minute, mail.Backoff(1))
+	assert.Equal(2*time.Minute, mail.Backoff(2))
+	assert.Equal(4*time.Minute, mail.Backoff(3))
+	assert.Equal(2*time.Hour, mail.Backoff(20))
+}
+
+func TestOutboxRetry(t *testing.T) {
+	assert := assert.New(t)
+
+	crud := db.NewCRUD(nil)
+	sender := &mail.MemorySender{Err: errors.New("451 try again")}
+	outbox := mail.NewOutbox(crud, sender)
+
+	queued, err := outbox.Enqueue("jane@mail.com", "welcome", mail.NotificationData{Name: "Jane"})
+	assert.Nil(err)
+	assert.Equal(models.EmailPending, queued.Status)
+	assert.Equal("Welcome to iRecruit", queued.Subject)
+
+	find := func() models.Email {
+		raw, err := crud.FindID(config.OutboxCollection, queued.ID)
+		assert.Nil(err)
+		return models.TransformEmail(raw)
+	}
+
+	// the first attempt fails and is retried after the backoff
+	now := time.Now()
+	sent, err := outbox.FlushAt(now)
+	assert.Nil(err)
+	assert.Equal(0, sent)
+	email := find()
+	assert.Equal(models.EmailPending, email.Status)
+	assert.Equal(1, email.Attempts)
+	assert.Equal("451 try again", email.LastError)
+	assert.True(email.NextAttemptAt.Equal(now.Add(mail.Backoff(1))))
+
+	// nothing is due before the backoff has passed
+	sent, err = outbox.FlushAt(now.Add(30 * time.Second))
+	assert.Nil(err)
+	assert.Equal(0, sent)
+	assert.Equal(1, find().Attempts)
+
+	// once the sender recovers the email is sent
+	sender.Err = nil
+	later := now.Add(mail.Backoff(1))
+	sent, err = outbox.FlushAt(later)
+	assert.Nil(err)
+	assert.Equal(1, sent)
+	email = find()
+	assert.Equal(models.EmailSent, email.Status)
+	assert.Equal(2, email.Attempts)
+	assert.Equal("", email.LastError)
+	assert.True(email.SentAt.Equal(later))
+	assert.Len(sender.Sent(), 1)
+	assert.Equal([]string{"jane@mail.com"}, sender.Sent()[0].To)
+
+	// sent emails aren't sent again
+	sent, _ = outbox.FlushAt(later.Add(time.Hour))
+	assert.Equal(0, sent)
+	assert.Len(sender.Sent(), 1)
+}
+
+func TestOutboxGivesUp(t *testing.T) {
+	assert := assert.New(t)
+
+	crud := db.NewCRUD(nil)
+	sender := &mail.MemorySender{Err: errors.New("550 no such user")}
+	outbox := mail.NewOutbox(crud, sender)
+	queued, err := outbox.Enqueue("gone@mail.com", "notification", mail.NotificationData{Title: "Hi"})
+	assert.Nil(err)
+
+	at := time.Now()
+	for i := 0; i < mail.MaxAttempts; i++ {
+		outbox.FlushAt(at)
+		at = at.Add(mail.Backoff(mail.MaxAttempts))
+	}
+
+	raw, _ := crud.FindID(config.OutboxCollection, queued.ID)
+	email := models.TransformEmail(raw)
+	assert.Equal(models.EmailFailed, email.Status)
+	assert.Equal(mail.MaxAttempts, email.Attempts)
+	assert.Equal("550 no such user", email.LastError)
+}
+
+func TestNotificationBackend(t *testing.T) {
+	assert := assert.New(t)
+
+	crud := db.NewCRUD(nil)
+	backend := mail.NewNotificationBackend(mail.NewOutbox(crud, &mail.MemorySender{}))
+	account := models.Account{ID: bson.NewObjectId(), Email: "jane@mail.com", Name: "Jane"}
+
+	assert.Nil(backend.Deliver(account, models.Notification{Event: models.EventAccountCreated, Title: "Welcome"}))
+	assert.Nil(backend.Deliver(account, models.Notification{Event: models.EventNewMessage, Title: "New message", Body: "Hello"}))
+
+	stored, err := crud.FindAll(config.OutboxCollection, bson.M{"to": "jane@mail.com"})
+	assert.Nil(err)
+	templates := make([]string, 0)
+	for _, raw := range stored {
+		templates = append(templates, models.TransformEmail(raw).Template)
+	}
+	assert.Equal([]string{"welcome", "notification"}, templates)
+}
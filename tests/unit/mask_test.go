@@ -0,0 +1,25 @@
+package unittests
+
+import (
+	"testing"
+
+	utils "../../utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskPhone(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("*** *** **78", utils.MaskPhone("012 345 2378"))
+	assert.Equal("+** ** *** **67", utils.MaskPhone("+27 82 123 4567"))
+	assert.Equal("*23", utils.MaskPhone("123"))
+	assert.Equal("", utils.MaskPhone(""))
+}
+
+func TestMaskEmail(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("m***@gmail.com", utils.MaskEmail("mark@gmail.com"))
+	assert.Equal("j***@gmail.com", utils.MaskEmail("j@gmail.com"))
+	assert.Equal("é***@mail.co.za", utils.MaskEmail("éva@mail.co.za"))
+	assert.Equal("***", utils.MaskEmail("not an email"))
+	assert.Equal("***", utils.MaskEmail("@gmail.com"))
+}
@@ -0,0 +1,41 @@
+package unittests
+
+import (
+	"testing"
+
+	matching "../../matching"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	assert := assert.New(t)
+	opening := matching.Opening{IndustryID: "it", Province: "GAUTENG", City: "Johannesburg"}
+
+	// candidates and their expected component scores
+	input := []matching.Candidate{
+		{Province: "GAUTENG", City: "johannesburg", Answers: map[string]int{"it": 2}, Availability: matching.Available},
+		{Province: "GAUTENG", City: "Pretoria", Answers: map[string]int{"it": 1}, Availability: matching.HasOffer},
+		{Province: "LIMPOPO", City: "Polokwane", Answers: map[string]int{"it": 3}, Availability: matching.Hired},
+		{Province: "WESTERN_CAPE", City: "Cape Town", Answers: map[string]int{"law": 2}, Availability: matching.Available},
+	}
+	output := [][]int{
+		{40, 30, 20, 10},
+		{40, 20, 10, 5},
+		{40, 10, 20, 0},
+		{0, 0, 0, 10},
+	}
+
+	for i, candidate := range input {
+		score := matching.Match(candidate, opening)
+
+		total := 0
+		scores := make([]int, 0)
+		for _, component := range score.Components {
+			scores = append(scores, component.Score)
+			total += component.Score
+			assert.NotEmpty(component.Explanation, "Match does not explain component %s", component.Name)
+		}
+		assert.Equal(output[i], scores, "Case [%v]: Match returns unexpected component scores", i+1)
+		assert.Equal(total, score.Total, "Case [%v]: Match total is not the sum of its components", i+1)
+	}
+}
@@ -0,0 +1,200 @@
+package unittests
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	db "../../database"
+)
+
+// wire protocol op codes
+const (
+	opReply = 1
+	opQuery = 2004
+)
+
+// wireServer is a mongo server that only speaks as much of the wire protocol as mgo needs to
+// send it commands. It claims wire version 2, so mgo sends writes as commands, answers every
+// command with ok and records the queries and update documents of update commands
+type wireServer struct {
+	listener net.Listener
+	mu       sync.Mutex
+	queries  []bson.M
+	updates  []bson.M
+}
+
+// newWireServer starts a wireServer on a free local port
+func newWireServer() (*wireServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &wireServer{listener: listener}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s, nil
+}
+
+// serve answers the queries sent over a connection
+func (s *wireServer) serve(conn net.Conn) {
+	defer conn.Close()
+	header := make([]byte, 16)
+	for {
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		body := make([]byte, binary.LittleEndian.Uint32(header[0:4])-16)
+		if _, err := io.ReadFull(conn, body); err != nil {
+			return
+		}
+		if binary.LittleEndian.Uint32(header[12:16]) != opQuery {
+			continue
+		}
+
+		// flags, collection name, skip and limit come before the query
+		start := 4 + bytes.IndexByte(body[4:], 0) + 1 + 8
+		data, err := bson.Marshal(s.reply(body[start:]))
+		if err != nil {
+			return
+		}
+
+		reply := make([]byte, 36, 36+len(data))
+		binary.LittleEndian.PutUint32(reply[0:4], uint32(36+len(data)))
+		copy(reply[8:12], header[4:8])
+		binary.LittleEndian.PutUint32(reply[12:16], opReply)
+		binary.LittleEndian.PutUint32(reply[32:36], 1)
+		if _, err := conn.Write(append(reply, data...)); err != nil {
+			return
+		}
+	}
+}
+
+// reply answers a command
+func (s *wireServer) reply(raw []byte) bson.M {
+	var command bson.D
+	if err := bson.Unmarshal(raw, &command); err != nil || len(command) == 0 {
+		return bson.M{"ok": 0}
+	}
+	switch command[0].Name {
+	case "getnonce":
+		return bson.M{"nonce": "2375531c32080ae8", "ok": 1}
+	case "ismaster", "isMaster":
+		return bson.M{"ismaster": true, "maxWireVersion": 2, "minWireVersion": 0, "ok": 1}
+	case "update":
+		var update struct {
+			Updates []struct {
+				Q bson.M `bson:"q"`
+				U bson.M `bson:"u"`
+			} `bson:"updates"`
+		}
+		bson.Unmarshal(raw, &update)
+		s.mu.Lock()
+		for _, u := range update.Updates {
+			s.queries = append(s.queries, u.Q)
+			s.updates = append(s.updates, u.U)
+		}
+		s.mu.Unlock()
+		return bson.M{"n": len(update.Updates), "nModified": len(update.Updates), "ok": 1}
+	default:
+		return bson.M{"ok": 1}
+	}
+}
+
+// dialWireServer starts a wireServer and connects to it
+func dialWireServer() (*wireServer, *mgo.Session, error) {
+	server, err := newWireServer()
+	if err != nil {
+		return nil, nil, err
+	}
+	session, err := mgo.DialWithInfo(&mgo.DialInfo{
+		Addrs:   []string{server.listener.Addr().String()},
+		Direct:  true,
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		server.listener.Close()
+		return nil, nil, err
+	}
+	return server, session, nil
+}
+
+// tests that UpdateID only sets the given fields when it runs against mongo,
+// which replaces the whole document by an update without operators
+func TestCrudUpdateIDMongo(t *testing.T) {
+	assert := assert.New(t)
+	server, session, err := dialWireServer()
+	if !assert.Nil(err) {
+		return
+	}
+	defer server.listener.Close()
+	defer session.Close()
+	crud := &db.CRUD{Session: session}
+	defer crud.CloseCopy()
+
+	assert.Nil(crud.UpdateID(collection, bson.NewObjectId(), bson.M{"Name": "New Monicker"}))
+	assert.Equal([]bson.M{{"$set": bson.M{"Name": "New Monicker"}}}, server.updates)
+}
+
+// tests that UpdateOne sends its query and operators to mongo as they are
+func TestCrudUpdateOneMongo(t *testing.T) {
+	assert := assert.New(t)
+	server, session, err := dialWireServer()
+	if !assert.Nil(err) {
+		return
+	}
+	defer server.listener.Close()
+	defer session.Close()
+	crud := &db.CRUD{Session: session}
+	defer crud.CloseCopy()
+
+	id := bson.NewObjectId()
+	query := bson.M{"_id": id, "stage": "APPLIED"}
+	update := bson.M{"$set": bson.M{"stage": "SCREENED"}, "$push": bson.M{"history": bson.M{"stage": "SCREENED"}}}
+	assert.Nil(crud.UpdateOne(collection, query, update))
+	assert.Equal([]bson.M{query}, server.queries)
+	assert.Equal([]bson.M{update}, server.updates)
+}
+
+// tests that a cloned CRUD has its own session, so closing either doesn't affect the other
+func TestCrudClone(t *testing.T) {
+	assert := assert.New(t)
+	server, session, err := dialWireServer()
+	if !assert.Nil(err) {
+		return
+	}
+	defer server.listener.Close()
+	crud := &db.CRUD{Session: session}
+	defer crud.Close()
+
+	worker := crud.Clone()
+	assert.NotEqual(crud.Session, worker.Session)
+	assert.Nil(worker.UpdateID(collection, bson.NewObjectId(), bson.M{"Name": "Worker"}))
+	crud.CloseCopy()
+	assert.Nil(worker.UpdateID(collection, bson.NewObjectId(), bson.M{"Name": "Worker"}))
+	worker.Close()
+	assert.Nil(crud.UpdateID(collection, bson.NewObjectId(), bson.M{"Name": "Request"}))
+	assert.Len(server.updates, 3)
+
+	// mock clones share their storage
+	mock := loadedCRUD()
+	p0 := people[0].(person)
+	assert.Nil(mock.Clone().UpdateID(collection, p0.ID, bson.M{"Name": "Worker"}))
+	r0, _ := mock.FindID(collection, p0.ID)
+	assert.Equal("Worker", r0.(bson.M)["Name"])
+}
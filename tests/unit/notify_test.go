@@ -0,0 +1,80 @@
+package unittests
+
+import (
+	"testing"
+
+	config "../../config"
+	db "../../database"
+	models "../../models"
+	notify "../../notify"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestDispatcherNotify(t *testing.T) {
+	assert := assert.New(t)
+
+	// prepare accounts, the second of which opted out of in-app welcomes
+	crud := db.NewCRUD(nil)
+	accounts := []models.Account{
+		{ID: bson.NewObjectId(), Email: "a@mail.com", HunterID: models.NullObjectID, RecruitID: models.NullObjectID},
+		{ID: bson.NewObjectId(), Email: "b@mail.com", HunterID: models.NullObjectID, RecruitID: models.NullObjectID},
+	}
+	for _, account := range accounts {
+		crud.Insert(config.AccountsCollection, account)
+	}
+	crud.Insert(config.NotificationSettingsCollection, models.NotificationSettings{
+		ID:        bson.NewObjectId(),
+		AccountID: accounts[1].ID,
+		Preferences: []models.NotificationPreference{
+			{Event: models.EventAccountCreated, InApp: false, Email: true},
+		},
+	})
+
+	backend := &notify.MemoryBackend{}
+	dispatcher := notify.NewDispatcher(crud, backend)
+
+	// welcomes are emailed by default, unknown accounts are skipped
+	dispatcher.Notify(
+		[]bson.ObjectId{accounts[0].ID, accounts[1].ID, bson.NewObjectId()},
+		notify.Event{Type: models.EventAccountCreated, Title: "Welcome"},
+	)
+	// messages are not emailed by default
+	dispatcher.Notify(
+		[]bson.ObjectId{accounts[0].ID},
+		notify.Event{Type: models.EventNewMessage, Title: "Hello"},
+	)
+
+	stored, err := crud.FindAll(config.NotificationsCollection, bson.M{})
+	assert.Nil(err)
+	events := make(map[bson.ObjectId][]string)
+	for _, raw := range stored {
+		notification := models.TransformNotification(raw)
+		events[notification.AccountID] = append(events[notification.AccountID], notification.Event)
+		assert.Equal(models.NullObjectID, notification.SubjectID)
+	}
+	assert.Equal(map[bson.ObjectId][]string{
+		accounts[0].ID: {models.EventAccountCreated, models.EventNewMessage},
+	}, events)
+
+	deliveries := backend.Deliveries()
+	if assert.Len(deliveries, 2) {
+		assert.Equal("a@mail.com", deliveries[0].Account.Email)
+		assert.Equal("b@mail.com", deliveries[1].Account.Email)
+		assert.Equal("Welcome", deliveries[1].Notification.Title)
+	}
+}
+
+func TestNotificationSettings(t *testing.T) {
+	assert := assert.New(t)
+
+	settings := models.NotificationSettings{}
+	assert.Equal(models.NotificationPreference{Event: models.EventNewMessage, InApp: true, Email: false}, settings.Preference(models.EventNewMessage))
+
+	assert.Nil(settings.SetPreference(models.NotificationPreference{Event: models.EventNewMessage, Email: true}))
+	assert.Nil(settings.SetPreference(models.NotificationPreference{Event: models.EventNewMessage, InApp: true, Email: true}))
+	assert.Len(settings.Preferences, 1)
+	assert.Equal(models.NotificationPreference{Event: models.EventNewMessage, InApp: true, Email: true}, settings.Preference(models.EventNewMessage))
+
+	assert.NotNil(settings.SetPreference(models.NotificationPreference{Event: "UNKNOWN"}))
+}
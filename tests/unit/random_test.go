@@ -0,0 +1,58 @@
+package unittests
+
+import (
+	"testing"
+
+	utils "../../utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// tests that weighted picks are reproducible and follow the weights
+func TestPickWeightedN(t *testing.T) {
+	assert := assert.New(t)
+	weights := []int{1, 0, 3, 1, -2, 5}
+
+	// the same seed picks the same indexes
+	a, b := utils.NewRand(42), utils.NewRand(42)
+	for i := 0; i < 10; i++ {
+		assert.Equal(a.PickWeightedN(2, weights), b.PickWeightedN(2, weights))
+	}
+
+	// indexes without a positive weight are never picked, and no index twice
+	r := utils.NewRand(7)
+	for i := 0; i < 50; i++ {
+		picked := r.PickWeightedN(4, weights)
+		assert.Len(picked, 4)
+		assert.NotContains(picked, 1)
+		assert.NotContains(picked, 4)
+		seen := make(map[int]bool)
+		for _, p := range picked {
+			assert.False(seen[p])
+			seen[p] = true
+		}
+	}
+
+	// heavier indexes are picked more often
+	counts := make(map[int]int)
+	for i := 0; i < 2000; i++ {
+		counts[r.PickWeightedN(1, weights)[0]]++
+	}
+	assert.True(counts[5] > counts[2], "%v", counts)
+	assert.True(counts[2] > counts[0], "%v", counts)
+
+	// asking for more than there is returns what there is
+	assert.Len(r.PickWeightedN(10, weights), 4)
+	assert.Len(r.PickWeightedN(3, nil), 0)
+}
+
+// tests that picking more than the input holds doesn't panic
+func TestPickRandomN(t *testing.T) {
+	assert := assert.New(t)
+	input := []interface{}{"a", "b", "c"}
+
+	picked := utils.PickRandomN(5, input)
+	assert.ElementsMatch(input, picked)
+	assert.Equal([]interface{}{"a", "b", "c"}, input)
+	assert.Len(utils.PickRandomN(2, []interface{}{}), 0)
+	assert.Len(utils.PickRandomN(2, input), 2)
+}
@@ -0,0 +1,90 @@
+package unittests
+
+import (
+	"testing"
+
+	search "../../search"
+	"github.com/stretchr/testify/assert"
+)
+
+// helpers
+
+func loadedIndex() *search.Index {
+	index := search.NewIndex()
+	index.Put(search.Document{ID: "1", Kind: "RECRUIT", Fields: map[string]string{
+		"answer": "I designed bridges for the city council.",
+	}})
+	index.Put(search.Document{ID: "2", Kind: "RECRUIT", Fields: map[string]string{
+		"answer": "Bridges, bridges and more bridges.",
+	}})
+	index.Put(search.Document{ID: "3", Kind: "QUESTION", Fields: map[string]string{
+		"question": "Which bridge design are you most proud of?",
+	}})
+	return index
+}
+
+// tests
+
+func TestIndexSearch(t *testing.T) {
+	assert := assert.New(t)
+	index := loadedIndex()
+
+	// prepare results
+	r1 := index.Search("bridges", nil, 0)                 // expect all, most mentions first
+	r2 := index.Search("bridge", []string{"QUESTION"}, 0) // expect question only
+	r3 := index.Search("designing", nil, 0)               // expect stemmed matches
+	r4 := index.Search("the and of", nil, 0)              // expect no stop word matches
+	r5 := index.Search("bridges", nil, 1)                 // expect limited results
+
+	// make assertions
+	assert.Equal(3, len(r1), "index.Search does not return all matches")
+	assert.Equal("2", r1[0].ID, "index.Search does not rank by relevance")
+	assert.Equal(1, len(r2), "index.Search does not filter by kind")
+	assert.Equal("3", r2[0].ID, "index.Search does not filter by kind")
+	assert.Equal(2, len(r3), "index.Search does not match stemmed terms")
+	assert.Equal(0, len(r4), "index.Search matches stop words")
+	assert.Equal(1, len(r5), "index.Search does not limit results")
+}
+
+func TestIndexHighlights(t *testing.T) {
+	assert := assert.New(t)
+	index := loadedIndex()
+
+	hits := index.Search("designed city", nil, 0)
+	assert.Equal(2, len(hits), "index.Search does not return the matching documents")
+	assert.Equal("1", hits[0].ID, "index.Search does not rank by relevance")
+	assert.Equal([]search.Highlight{{
+		Field:    "answer",
+		Fragment: "I <em>designed</em> bridges for the <em>city</em> council.",
+	}}, hits[0].Highlights, "index.Search does not highlight matches")
+
+	// long fields are cut down around the matches
+	index.Put(search.Document{ID: "4", Kind: "RECRUIT", Fields: map[string]string{
+		"answer": "Before I started out as an engineer I spent a long time studying <art> history, which later helped me with drafting.",
+	}})
+	hits = index.Search("art", nil, 0)
+	assert.Equal(1, len(hits), "index.Search does not return the matching document")
+	assert.Equal(
+		"…an engineer I spent a long time studying &lt;<em>art</em>&gt; history, which later helped me with drafting…",
+		hits[0].Highlights[0].Fragment,
+		"index.Search does not trim and escape fragments",
+	)
+}
+
+func TestIndexPutAndDelete(t *testing.T) {
+	assert := assert.New(t)
+	index := loadedIndex()
+
+	// replace a document
+	index.Put(search.Document{ID: "1", Kind: "RECRUIT", Fields: map[string]string{
+		"answer": "I repair tunnels.",
+	}})
+	assert.Equal(3, index.Len(), "index.Put does not replace documents")
+	assert.Equal(0, len(index.Search("council", nil, 0)), "index.Put keeps replaced terms")
+	assert.Equal(1, len(index.Search("tunnel", nil, 0)), "index.Put does not index new terms")
+
+	// delete a document
+	index.Delete("QUESTION", "3")
+	assert.Equal(2, index.Len(), "index.Delete does not remove documents")
+	assert.Equal(0, len(index.Search("proud", nil, 0)), "index.Delete keeps deleted terms")
+}
@@ -2,6 +2,7 @@ package unittests
 
 import (
 	"testing"
+	"time"
 
 	"gopkg.in/mgo.v2/bson"
 
@@ -58,3 +59,630 @@ func TestDocumentTransformer(t *testing.T) {
 
 	assert.Equal(expected, models.TransformDocument(b))
 }
+
+func TestHunterTransformer(t *testing.T) {
+	assert := assert.New(t)
+
+	b := bson.M{
+		"_id":        bson.NewObjectId(),
+		"phone":      "011 234 5678",
+		"email":      "lisa@acme.co.za",
+		"position":   "Recruiter",
+		"company_id": bson.NewObjectId(),
+		"role":       "RECRUITER",
+	}
+
+	expected := models.Hunter{
+		ID:        b["_id"].(bson.ObjectId),
+		Phone:     b["phone"].(string),
+		Email:     b["email"].(string),
+		Position:  b["position"].(string),
+		CompanyID: b["company_id"].(bson.ObjectId),
+		Role:      b["role"].(string),
+	}
+
+	assert.Equal(expected, models.TransformHunter(b))
+}
+
+func TestCompanyTransformer(t *testing.T) {
+	assert := assert.New(t)
+
+	b := bson.M{
+		"_id":                 bson.NewObjectId(),
+		"name":                "Acme Holdings",
+		"registration_number": "2011/123456/07",
+		"province":            "GAUTENG",
+		"city":                "Johannesburg",
+	}
+
+	expected := models.Company{
+		ID:                 b["_id"].(bson.ObjectId),
+		Name:               b["name"].(string),
+		RegistrationNumber: b["registration_number"].(string),
+		Province:           b["province"].(string),
+		City:               b["city"].(string),
+	}
+
+	assert.Equal(expected, models.TransformCompany(b))
+
+	// configured pipelines are read as mgo decodes them
+	b["pipeline"] = []interface{}{
+		bson.M{"stage": "APPLIED", "next": []interface{}{"HIRED", "REJECTED"}},
+		bson.M{"stage": "HIRED", "next": []interface{}{}},
+		bson.M{"stage": "REJECTED", "next": []interface{}{}},
+	}
+	expected.Pipeline = models.Pipeline{
+		{Stage: "APPLIED", Next: []string{"HIRED", "REJECTED"}},
+		{Stage: "HIRED", Next: []string{}},
+		{Stage: "REJECTED", Next: []string{}},
+	}
+	assert.Equal(expected, models.TransformCompany(b))
+	assert.Equal(expected, models.TransformCompany(fromMongo(t, expected)))
+}
+
+func TestCompanyInviteTransformer(t *testing.T) {
+	assert := assert.New(t)
+
+	b := bson.M{
+		"_id":        bson.NewObjectId(),
+		"company_id": bson.NewObjectId(),
+		"invited_by": bson.NewObjectId(),
+		"email":      "erin@gmail.com",
+		"role":       "VIEWER",
+		"status":     "PENDING",
+	}
+
+	expected := models.CompanyInvite{
+		ID:        b["_id"].(bson.ObjectId),
+		CompanyID: b["company_id"].(bson.ObjectId),
+		InvitedBy: b["invited_by"].(bson.ObjectId),
+		Email:     b["email"].(string),
+		Role:      b["role"].(string),
+		Status:    b["status"].(string),
+	}
+
+	assert.Equal(expected, models.TransformCompanyInvite(b))
+}
+
+func TestVacancyTransformer(t *testing.T) {
+	assert := assert.New(t)
+
+	b := bson.M{
+		"_id":             bson.NewObjectId(),
+		"company_id":      bson.NewObjectId(),
+		"industry_id":     bson.NewObjectId(),
+		"created_by":      bson.NewObjectId(),
+		"title":           "Junior Data Analyst",
+		"description":     "Crunch numbers.",
+		"province":        "GAUTENG",
+		"city":            "Johannesburg",
+		"employment_type": "FULL_TIME",
+		"salary_min":      15000,
+		"salary_max":      22000,
+		"closing_date":    time.Now().AddDate(0, 1, 0),
+		"created_at":      time.Now(),
+		"status":          "OPEN",
+	}
+
+	expected := models.Vacancy{
+		ID:             b["_id"].(bson.ObjectId),
+		CompanyID:      b["company_id"].(bson.ObjectId),
+		IndustryID:     b["industry_id"].(bson.ObjectId),
+		CreatedBy:      b["created_by"].(bson.ObjectId),
+		Title:          b["title"].(string),
+		Description:    b["description"].(string),
+		Province:       b["province"].(string),
+		City:           b["city"].(string),
+		EmploymentType: b["employment_type"].(string),
+		SalaryMin:      b["salary_min"].(int),
+		SalaryMax:      b["salary_max"].(int),
+		ClosingDate:    b["closing_date"].(time.Time),
+		CreatedAt:      b["created_at"].(time.Time),
+		Status:         b["status"].(string),
+	}
+
+	assert.Equal(expected, models.TransformVacancy(b))
+}
+
+func TestApplicationTransformer(t *testing.T) {
+	assert := assert.New(t)
+
+	changedAt := time.Now()
+	recruitID := bson.NewObjectId()
+	b := bson.M{
+		"_id":        bson.NewObjectId(),
+		"vacancy_id": bson.NewObjectId(),
+		"company_id": bson.NewObjectId(),
+		"recruit_id": recruitID,
+		"stage":      "APPLIED",
+		"history": []interface{}{
+			bson.M{
+				"stage":      "APPLIED",
+				"note":       "",
+				"changed_by": recruitID,
+				"changed_at": changedAt,
+			},
+		},
+		"created_at": changedAt,
+		"updated_at": changedAt,
+	}
+
+	expected := models.Application{
+		ID:        b["_id"].(bson.ObjectId),
+		VacancyID: b["vacancy_id"].(bson.ObjectId),
+		CompanyID: b["company_id"].(bson.ObjectId),
+		RecruitID: recruitID,
+		Stage:     "APPLIED",
+		History: []models.StageChange{
+			{
+				Stage:     "APPLIED",
+				ChangedBy: recruitID,
+				ChangedAt: changedAt,
+			},
+		},
+		CreatedAt: changedAt,
+		UpdatedAt: changedAt,
+	}
+
+	assert.Equal(expected, models.TransformApplication(b))
+}
+
+func TestInterviewTransformer(t *testing.T) {
+	assert := assert.New(t)
+
+	start := time.Now()
+	end := start.Add(time.Hour)
+	b := bson.M{
+		"_id":            bson.NewObjectId(),
+		"application_id": bson.NewObjectId(),
+		"vacancy_id":     bson.NewObjectId(),
+		"company_id":     bson.NewObjectId(),
+		"recruit_id":     bson.NewObjectId(),
+		"hunter_id":      bson.NewObjectId(),
+		"slots": []interface{}{
+			map[string]interface{}{
+				"start": start,
+				"end":   end,
+			},
+		},
+		"start":      start,
+		"end":        end,
+		"location":   "Sandton",
+		"notes":      "Ask for Lisa.",
+		"status":     "SCHEDULED",
+		"sequence":   1,
+		"created_at": start,
+		"updated_at": start,
+	}
+
+	expected := models.Interview{
+		ID:            b["_id"].(bson.ObjectId),
+		ApplicationID: b["application_id"].(bson.ObjectId),
+		VacancyID:     b["vacancy_id"].(bson.ObjectId),
+		CompanyID:     b["company_id"].(bson.ObjectId),
+		RecruitID:     b["recruit_id"].(bson.ObjectId),
+		HunterID:      b["hunter_id"].(bson.ObjectId),
+		Slots:         []models.InterviewSlot{{Start: start, End: end}},
+		Start:         start,
+		End:           end,
+		Location:      "Sandton",
+		Notes:         "Ask for Lisa.",
+		Status:        "SCHEDULED",
+		Sequence:      1,
+		CreatedAt:     start,
+		UpdatedAt:     start,
+	}
+
+	assert.Equal(expected, models.TransformInterview(b))
+}
+
+func TestThreadTransformer(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	accountID := bson.NewObjectId()
+	b := bson.M{
+		"_id":            bson.NewObjectId(),
+		"application_id": bson.NewObjectId(),
+		"vacancy_id":     bson.NewObjectId(),
+		"company_id":     bson.NewObjectId(),
+		"recruit_id":     bson.NewObjectId(),
+		"subject":        "Interview availability",
+		"reads": []interface{}{
+			map[string]interface{}{
+				"account_id": accountID,
+				"read_at":    now,
+			},
+		},
+		"created_at": now,
+		"updated_at": now,
+	}
+
+	expected := models.Thread{
+		ID:            b["_id"].(bson.ObjectId),
+		ApplicationID: b["application_id"].(bson.ObjectId),
+		VacancyID:     b["vacancy_id"].(bson.ObjectId),
+		CompanyID:     b["company_id"].(bson.ObjectId),
+		RecruitID:     b["recruit_id"].(bson.ObjectId),
+		Subject:       "Interview availability",
+		Reads:         []models.ThreadRead{{AccountID: accountID, ReadAt: now}},
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	thread := models.TransformThread(b)
+	assert.Equal(expected, thread)
+	assert.Equal(now, thread.ReadAt(accountID))
+	assert.True(thread.ReadAt(bson.NewObjectId()).IsZero())
+}
+
+func TestMessageTransformer(t *testing.T) {
+	assert := assert.New(t)
+
+	b := bson.M{
+		"_id":         bson.NewObjectId(),
+		"thread_id":   bson.NewObjectId(),
+		"sender_id":   bson.NewObjectId(),
+		"sender_role": "RECRUIT",
+		"body":        "Hello",
+		"created_at":  time.Now(),
+	}
+
+	expected := models.Message{
+		ID:         b["_id"].(bson.ObjectId),
+		ThreadID:   b["thread_id"].(bson.ObjectId),
+		SenderID:   b["sender_id"].(bson.ObjectId),
+		SenderRole: "RECRUIT",
+		Body:       "Hello",
+		CreatedAt:  b["created_at"].(time.Time),
+	}
+
+	assert.Equal(expected, models.TransformMessage(b))
+}
+
+func TestContactRequestTransformer(t *testing.T) {
+	assert := assert.New(t)
+
+	b := bson.M{
+		"_id":          bson.NewObjectId(),
+		"recruit_id":   bson.NewObjectId(),
+		"hunter_id":    bson.NewObjectId(),
+		"company_id":   bson.NewObjectId(),
+		"message":      "Hi",
+		"status":       "APPROVED",
+		"phone":        true,
+		"email":        false,
+		"created_at":   time.Now(),
+		"responded_at": time.Now(),
+	}
+
+	expected := models.ContactRequest{
+		ID:          b["_id"].(bson.ObjectId),
+		RecruitID:   b["recruit_id"].(bson.ObjectId),
+		HunterID:    b["hunter_id"].(bson.ObjectId),
+		CompanyID:   b["company_id"].(bson.ObjectId),
+		Message:     "Hi",
+		Status:      "APPROVED",
+		Phone:       true,
+		Email:       false,
+		CreatedAt:   b["created_at"].(time.Time),
+		RespondedAt: b["responded_at"].(time.Time),
+	}
+
+	assert.Equal(expected, models.TransformContactRequest(b))
+}
+
+func TestRecruitPrivacyTransformer(t *testing.T) {
+	assert := assert.New(t)
+
+	// recruits stored before privacy settings existed get the defaults
+	b := bson.M{
+		"_id":        bson.NewObjectId(),
+		"birth_year": int32(1990),
+		"province":   "GAUTENG",
+		"city":       "Pretoria",
+		"gender":     "MALE",
+		"disability": "",
+		"vid1_url":   "none",
+		"vid2_url":   "none",
+		"phone":      "012 345 6789",
+		"email":      "a@mail.com",
+		"qa1":        bson.M{"question": "Q1", "answer": "A1"},
+		"qa2":        bson.M{"question": "Q2", "answer": "A2"},
+	}
+	assert.Equal(models.DefaultRecruitPrivacy, models.TransformRecruit(b).Privacy)
+
+	b["privacy"] = map[string]interface{}{"allow_requests": false, "share_phone": true, "share_email": false}
+	assert.Equal(models.RecruitPrivacy{SharePhone: true}, models.TransformRecruit(b).Privacy)
+
+	// as mgo reads them back
+	recruit := models.TransformRecruit(b)
+	assert.Equal(recruit, models.TransformRecruit(fromMongo(t, recruit)))
+}
+
+func TestCreditEntryTransformer(t *testing.T) {
+	assert := assert.New(t)
+
+	b := bson.M{
+		"_id":        bson.NewObjectId(),
+		"hunter_id":  bson.NewObjectId(),
+		"seq":        3,
+		"kind":       "SPEND",
+		"amount":     -5,
+		"balance":    5,
+		"reason":     "Vacancy posting",
+		"subject_id": bson.NewObjectId(),
+		"created_by": bson.NewObjectId(),
+		"created_at": time.Now(),
+	}
+
+	expected := models.CreditEntry{
+		ID:        b["_id"].(bson.ObjectId),
+		HunterID:  b["hunter_id"].(bson.ObjectId),
+		Seq:       3,
+		Kind:      "SPEND",
+		Amount:    -5,
+		Balance:   5,
+		Reason:    "Vacancy posting",
+		SubjectID: b["subject_id"].(bson.ObjectId),
+		CreatedBy: b["created_by"].(bson.ObjectId),
+		CreatedAt: b["created_at"].(time.Time),
+	}
+
+	assert.Equal(expected, models.TransformCreditEntry(b))
+}
+
+func TestRecruitHistoryTransformer(t *testing.T) {
+	assert := assert.New(t)
+
+	work := models.WorkExperience{
+		ID:          bson.NewObjectId(),
+		Employer:    "Acme",
+		Title:       "Clerk",
+		StartDate:   time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC),
+		Description: "Filing.",
+	}
+	education := models.Education{ID: bson.NewObjectId(), Institution: "UCT", Qualification: "BCom", NQFLevel: 7, Year: 2014}
+
+	// recruits stored before history existed have none
+	b := bson.M{
+		"_id":        bson.NewObjectId(),
+		"birth_year": int32(1990),
+		"province":   "GAUTENG",
+		"city":       "Pretoria",
+		"gender":     "MALE",
+		"disability": "",
+		"vid1_url":   "none",
+		"vid2_url":   "none",
+		"phone":      "012 345 6789",
+		"email":      "a@mail.com",
+		"qa1":        bson.M{"question": "Q1", "answer": "A1"},
+		"qa2":        bson.M{"question": "Q2", "answer": "A2"},
+	}
+	recruit := models.TransformRecruit(b)
+	assert.Equal([]models.WorkExperience{}, recruit.WorkExperience)
+	assert.Equal([]models.Education{}, recruit.Education)
+
+	b["work_experience"] = []interface{}{bson.M{
+		"_id":         work.ID,
+		"employer":    work.Employer,
+		"title":       work.Title,
+		"start_date":  work.StartDate,
+		"end_date":    time.Time{},
+		"description": work.Description,
+	}}
+	b["education"] = []interface{}{map[string]interface{}{
+		"_id":           education.ID,
+		"institution":   education.Institution,
+		"qualification": education.Qualification,
+		"nqf_level":     education.NQFLevel,
+		"year":          education.Year,
+	}}
+	recruit = models.TransformRecruit(b)
+	assert.Equal([]models.WorkExperience{work}, recruit.WorkExperience)
+	assert.Equal([]models.Education{education}, recruit.Education)
+
+	b["education"] = []models.Education{education}
+	assert.Equal([]models.Education{education}, models.TransformRecruit(b).Education)
+
+	// as mgo reads them back
+	assert.Equal(education, models.TransformEducation(fromMongo(t, education)))
+}
+
+func TestSkillTransformer(t *testing.T) {
+	assert := assert.New(t)
+
+	skill := models.Skill{ID: bson.NewObjectId(), IndustryID: bson.NewObjectId(), Name: "welding"}
+	b := bson.M{"_id": skill.ID, "industry_id": skill.IndustryID, "name": skill.Name}
+	assert.Equal(skill, models.TransformSkill(b))
+
+	recruitSkills := []models.RecruitSkill{{SkillID: skill.ID, Level: 3}}
+	assert.Equal(recruitSkills, models.TransformRecruitSkills([]interface{}{
+		map[string]interface{}{"skill_id": skill.ID, "level": int32(3)},
+	}))
+	assert.Equal(recruitSkills, models.TransformRecruitSkills(recruitSkills))
+	assert.Equal(recruitSkills[0], models.TransformRecruitSkill(fromMongo(t, recruitSkills[0])))
+	assert.Equal([]models.RecruitSkill{}, models.TransformRecruitSkills(nil))
+}
+
+func TestObjectIDsTransformer(t *testing.T) {
+	assert := assert.New(t)
+
+	ids := []bson.ObjectId{bson.NewObjectId(), bson.NewObjectId()}
+	assert.Equal(ids, models.TransformObjectIDs([]interface{}{ids[0], ids[1]}))
+	assert.Equal(ids, models.TransformObjectIDs(ids))
+	assert.Equal([]bson.ObjectId{}, models.TransformObjectIDs(nil))
+}
+
+func TestQATransformer(t *testing.T) {
+	assert := assert.New(t)
+
+	qa := models.QA{QuestionID: bson.NewObjectId(), Revision: 2, Answer: "Because."}
+	assert.Equal([]models.QA{qa, qa}, models.TransformQAs([]interface{}{
+		bson.M{"question_id": qa.QuestionID, "revision": qa.Revision, "answer": qa.Answer},
+		map[string]interface{}{"question_id": qa.QuestionID, "revision": qa.Revision, "answer": qa.Answer},
+	}))
+	assert.Equal([]models.QA{qa}, models.TransformQAs([]models.QA{qa}))
+
+	// answers given before questions were versioned are to the first revision
+	legacy := models.TransformQA(bson.M{"question_id": qa.QuestionID, "answer": qa.Answer})
+	assert.Equal(int32(1), legacy.Revision)
+
+	// recruits that haven't been migrated have no answers
+	assert.Equal([]models.QA{}, models.TransformQAs(nil))
+}
+
+func TestIndustryTransformer(t *testing.T) {
+	assert := assert.New(t)
+
+	// industries stored before answer limits existed get the defaults
+	b := bson.M{"_id": bson.NewObjectId(), "name": "mining"}
+	industry := models.TransformIndustry(b)
+	assert.Equal(int32(models.DefaultMinAnswers), industry.MinAnswers)
+	assert.Equal(int32(models.DefaultMaxAnswers), industry.MaxAnswers)
+
+	b["min_answers"] = int32(2)
+	b["max_answers"] = int32(4)
+	industry = models.TransformIndustry(b)
+	assert.Equal(models.Industry{ID: b["_id"].(bson.ObjectId), Name: "mining", MinAnswers: 2, MaxAnswers: 4, Translations: []models.Translation{}}, industry)
+
+	// limits are kept as mgo reads them back
+	assert.Equal(industry, models.TransformIndustry(fromMongo(t, industry)))
+
+	// sub-industries keep their parent, which can't be themselves
+	b["parent_id"] = bson.NewObjectId()
+	industry = models.TransformIndustry(b)
+	assert.Equal(b["parent_id"], industry.ParentID)
+	assert.Nil(industry.OK())
+	industry.ParentID = industry.ID
+	assert.NotNil(industry.OK())
+}
+
+func TestQuestionTransformer(t *testing.T) {
+	assert := assert.New(t)
+
+	// questions stored before they were versioned are on their first revision
+	b := bson.M{"_id": bson.NewObjectId(), "industry_id": bson.NewObjectId(), "question": "Why?"}
+	question := models.TransformQuestion(b)
+	assert.Equal(int32(1), question.Revision)
+	assert.Equal([]models.QuestionRevision{{Revision: 1, Question: "Why?"}}, question.Revisions)
+	assert.Equal(models.QuestionActive, question.Status)
+	assert.Equal(int32(models.DefaultQuestionWeight), question.Weight)
+	assert.Equal(models.QuestionMedium, question.Difficulty)
+	assert.Nil(question.OK())
+
+	at := time.Date(2019, 5, 6, 0, 0, 0, 0, time.UTC)
+	question.Revise("Why not?", at)
+	b["question"] = question.Question
+	b["revision"] = question.Revision
+	b["revisions"] = []interface{}{
+		map[string]interface{}{"revision": int32(1), "question": "Why?", "created_at": time.Time{}},
+		bson.M{"revision": int32(2), "question": "Why not?", "created_at": at},
+	}
+	assert.Equal(question, models.TransformQuestion(b))
+	assert.Nil(question.OK())
+
+	// stored status, weight and difficulty are kept
+	b["status"] = models.QuestionArchived
+	b["weight"] = int32(10)
+	b["difficulty"] = models.QuestionHard
+	archived := models.TransformQuestion(b)
+	assert.Equal(models.QuestionArchived, archived.Status)
+	assert.Equal(int32(10), archived.Weight)
+	assert.Equal(models.QuestionHard, archived.Difficulty)
+	assert.Nil(archived.OK())
+
+	// and validated
+	archived.Weight = models.MaxQuestionWeight + 1
+	assert.NotNil(archived.OK())
+	archived.Weight = 1
+	archived.Difficulty = "IMPOSSIBLE"
+	assert.NotNil(archived.OK())
+
+	// the current wording has to be the last revision
+	question.Question = "How?"
+	assert.NotNil(question.OK())
+}
+
+func TestUploadTransformer(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	b := bson.M{
+		"_id":          bson.NewObjectId(),
+		"account_id":   bson.NewObjectId(),
+		"recruit_id":   bson.NewObjectId(),
+		"target":       models.UploadVideo1,
+		"filename":     "intro.mp4",
+		"file_type":    "video/mp4",
+		"length":       int64(2048),
+		"offset":       int64(1024),
+		"metadata":     "target dmlkMQ==",
+		"url":          "",
+		"created_at":   now,
+		"updated_at":   now,
+		"completed_at": time.Time{},
+	}
+
+	expected := models.Upload{
+		ID:        b["_id"].(bson.ObjectId),
+		AccountID: b["account_id"].(bson.ObjectId),
+		RecruitID: b["recruit_id"].(bson.ObjectId),
+		Target:    models.UploadVideo1,
+		Filename:  "intro.mp4",
+		FileType:  "video/mp4",
+		Length:    2048,
+		Offset:    1024,
+		Metadata:  "target dmlkMQ==",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	upload := models.TransformUpload(b)
+	assert.Equal(expected, upload)
+	assert.Nil(upload.OK())
+	assert.False(upload.Complete())
+
+	// uploads can't be larger than their type allows
+	upload.Length = models.UploadSizeLimits["video/mp4"] + 1
+	assert.NotNil(upload.OK())
+}
+
+// fromMongo encodes a model the way it's stored and decodes it the way mgo reads it back,
+// which turns int32 fields into int
+func fromMongo(t *testing.T, in interface{}) bson.M {
+	data, err := bson.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := bson.M{}
+	if err := bson.Unmarshal(data, out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestStoredQuestionTransformer(t *testing.T) {
+	assert := assert.New(t)
+
+	at := time.Unix(1557100800, 0)
+	question := models.Question{
+		ID:           bson.NewObjectId(),
+		IndustryID:   bson.NewObjectId(),
+		Question:     "Why?",
+		Revision:     1,
+		Revisions:    []models.QuestionRevision{{Revision: 1, Question: "Why?", CreatedAt: at}},
+		Status:       models.QuestionActive,
+		Weight:       70,
+		Difficulty:   models.QuestionHard,
+		Translations: []models.Translation{{Locale: "af", Text: "Hoekom?"}},
+	}
+	question.Revise("Why not?", at.Add(time.Hour))
+	stored := fromMongo(t, question)
+	assert.IsType(0, stored["revision"])
+	assert.Equal(question, models.TransformQuestion(stored))
+
+	// answers keep the revision they were given against
+	qa := models.QA{QuestionID: question.ID, Revision: 2, Answer: "Because."}
+	assert.Equal(qa, models.TransformQA(fromMongo(t, qa)))
+}
@@ -0,0 +1,539 @@
+package upload
+
+import (
+	"encoding/base64"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	config "../config"
+	db "../database"
+	er "../errors"
+	models "../models"
+	storage "../storage"
+	utils "../utils"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// TusVersion is the version of the tus resumable upload protocol the Handler implements
+const TusVersion = "1.0.0"
+
+// tusExtensions are the tus protocol extensions the Handler supports
+const tusExtensions = "creation,expiration,termination"
+
+// tusContentType is the content type of PATCH requests
+const tusContentType = "application/offset+octet-stream"
+
+// Handler implements the tus 1.0 resumable upload protocol for recruit videos, see
+// https://tus.io/protocols/resumable-upload.html. A client creates an upload with a POST
+// to the handler's path and sends its bytes with PATCH requests to the upload's url. When
+// a connection drops the client asks for the upload's offset with a HEAD request and
+// continues from there. Completed uploads are moved to the store and set the recruit's video url
+type Handler struct {
+	crud     *db.CRUD
+	store    storage.Store
+	dir      string
+	basePath string
+	locks    *uploadLocks
+}
+
+// uploadLocks are the locks of the uploads requests are made to
+type uploadLocks struct {
+	mu   sync.Mutex
+	byID map[bson.ObjectId]*uploadLock
+}
+
+// uploadLock makes requests to the same upload wait for each other, it's kept while requests hold it
+type uploadLock struct {
+	sync.Mutex
+	refs int
+}
+
+// NewHandler creates a Handler mounted on basePath, it writes the parts of uploads
+// to dir on local disk and puts completed files in store
+func NewHandler(crud *db.CRUD, store storage.Store, dir, basePath string) *Handler {
+	return &Handler{
+		crud:     crud,
+		store:    store,
+		dir:      dir,
+		basePath: strings.TrimSuffix(basePath, "/"),
+		locks:    &uploadLocks{byID: make(map[bson.ObjectId]*uploadLock)},
+	}
+}
+
+// ServeHTTP handles tus requests
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer h.crud.CloseCopy()
+	w.Header().Set("Tus-Resumable", TusVersion)
+
+	// clients that can't send every method override it
+	method := r.Method
+	if override := r.Header.Get("X-HTTP-Method-Override"); override != "" {
+		method = override
+	}
+	if method == http.MethodOptions {
+		h.options(w)
+		return
+	}
+	if r.Header.Get("Tus-Resumable") != TusVersion {
+		w.Header().Set("Tus-Version", TusVersion)
+		http.Error(w, "Unsupported tus version.", http.StatusPreconditionFailed)
+		return
+	}
+
+	account, err := h.authenticate(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, h.basePath), "/")
+	if id == "" {
+		if method != http.MethodPost {
+			http.Error(w, "Method not allowed.", http.StatusMethodNotAllowed)
+			return
+		}
+		h.create(w, r, account)
+		return
+	}
+
+	// uploads are only visible to the account that created them
+	if !bson.IsObjectIdHex(id) {
+		http.NotFound(w, r)
+		return
+	}
+	h.lock(bson.ObjectIdHex(id))
+	defer h.unlock(bson.ObjectIdHex(id))
+	rawUpload, err := h.crud.FindID(config.UploadsCollection, bson.ObjectIdHex(id))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	upload := models.TransformUpload(rawUpload)
+	if upload.AccountID != account.ID {
+		http.NotFound(w, r)
+		return
+	}
+
+	// expired uploads are removed when they're asked for, or by RemoveExpired
+	if upload.Expired(time.Now()) {
+		h.remove(&upload)
+		http.NotFound(w, r)
+		return
+	}
+
+	switch method {
+	case http.MethodHead:
+		h.head(w, &upload)
+	case http.MethodPatch:
+		h.patch(w, r, &upload)
+	case http.MethodDelete:
+		h.terminate(w, &upload)
+	default:
+		http.Error(w, "Method not allowed.", http.StatusMethodNotAllowed)
+	}
+}
+
+// options describes what the Handler supports
+func (h *Handler) options(w http.ResponseWriter) {
+	var maxSize int64
+	for _, limit := range models.UploadSizeLimits {
+		if limit > maxSize {
+			maxSize = limit
+		}
+	}
+	w.Header().Set("Tus-Version", TusVersion)
+	w.Header().Set("Tus-Extension", tusExtensions)
+	w.Header().Set("Tus-Max-Size", strconv.FormatInt(maxSize, 10))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// create creates an upload from its Upload-Length and Upload-Metadata, the metadata
+// needs the target of the upload and the file's type
+func (h *Handler) create(w http.ResponseWriter, r *http.Request, account *models.Account) {
+	if utils.IsNullID(account.RecruitID) {
+		http.Error(w, "Only recruits can upload videos.", http.StatusForbidden)
+		return
+	}
+	length, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil || length <= 0 {
+		http.Error(w, "Invalid Upload-Length.", http.StatusBadRequest)
+		return
+	}
+	metadata, err := parseMetadata(r.Header.Get("Upload-Metadata"))
+	if err != nil {
+		http.Error(w, "Invalid Upload-Metadata.", http.StatusBadRequest)
+		return
+	}
+	if _, ok := models.UploadTargetFields[metadata["target"]]; !ok {
+		http.Error(w, "Invalid upload target.", http.StatusBadRequest)
+		return
+	}
+	limit, ok := models.UploadSizeLimits[metadata["filetype"]]
+	if !ok {
+		http.Error(w, "Invalid file type.", http.StatusUnsupportedMediaType)
+		return
+	}
+	if length > limit {
+		http.Error(w, "File too large.", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	now := time.Now()
+	upload := models.Upload{
+		ID:        bson.NewObjectId(),
+		AccountID: account.ID,
+		RecruitID: account.RecruitID,
+		Target:    metadata["target"],
+		Filename:  metadata["filename"],
+		FileType:  metadata["filetype"],
+		Length:    length,
+		Metadata:  r.Header.Get("Upload-Metadata"),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := upload.OK(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// the file's parts are written to a file that isn't served until it's complete
+	if err := os.MkdirAll(h.dir, 0755); err != nil {
+		log.Println("Failed to create upload directory =>", err)
+		http.Error(w, "Failed to create upload.", http.StatusInternalServerError)
+		return
+	}
+	file, err := os.Create(h.partPath(&upload))
+	if err != nil {
+		log.Println("Failed to create upload file =>", err)
+		http.Error(w, "Failed to create upload.", http.StatusInternalServerError)
+		return
+	}
+	file.Close()
+	if err := h.crud.Insert(config.UploadsCollection, upload); err != nil {
+		log.Println("Failed to store upload =>", err)
+		os.Remove(h.partPath(&upload))
+		http.Error(w, "Failed to create upload.", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Location", h.basePath+"/"+upload.ID.Hex())
+	setExpires(w, &upload)
+	w.WriteHeader(http.StatusCreated)
+}
+
+// head tells the client how much of the upload was received
+func (h *Handler) head(w http.ResponseWriter, upload *models.Upload) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	w.Header().Set("Upload-Length", strconv.FormatInt(upload.Length, 10))
+	if upload.Metadata != "" {
+		w.Header().Set("Upload-Metadata", upload.Metadata)
+	}
+	setExpires(w, upload)
+	w.WriteHeader(http.StatusOK)
+}
+
+// patch writes a part of the upload at its offset. What's received is kept even when the
+// connection drops, so the client can continue from the new offset
+func (h *Handler) patch(w http.ResponseWriter, r *http.Request, upload *models.Upload) {
+	if r.Header.Get("Content-Type") != tusContentType {
+		http.Error(w, "Invalid Content-Type.", http.StatusUnsupportedMediaType)
+		return
+	}
+	offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		http.Error(w, "Invalid Upload-Offset.", http.StatusBadRequest)
+		return
+	}
+	if offset != upload.Offset {
+		http.Error(w, "Upload-Offset doesn't match the upload's offset.", http.StatusConflict)
+		return
+	}
+	if upload.Complete() {
+		// a client retrying after the recruit's video couldn't be set sets it again
+		if err := h.setVideo(upload); err != nil {
+			http.Error(w, "Failed to complete upload.", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	file, err := os.OpenFile(h.partPath(upload), os.O_WRONLY, 0644)
+	if err != nil {
+		log.Println("Failed to open upload file =>", err)
+		http.Error(w, "Failed to write upload.", http.StatusInternalServerError)
+		return
+	}
+	if _, err := file.Seek(upload.Offset, io.SeekStart); err != nil {
+		file.Close()
+		log.Println("Failed to seek upload file =>", err)
+		http.Error(w, "Failed to write upload.", http.StatusInternalServerError)
+		return
+	}
+	n, copyErr := io.Copy(file, io.LimitReader(r.Body, upload.Length-upload.Offset))
+	file.Close()
+
+	// the start of the file has to be of the type the client said it is
+	if upload.Offset == 0 && n > 0 && !h.sniffed(upload) {
+		h.remove(upload)
+		http.Error(w, "The file isn't of the given type.", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	upload.Offset += n
+	upload.UpdatedAt = time.Now()
+	if upload.Complete() {
+		if err := h.complete(upload); err != nil {
+			http.Error(w, "Failed to complete upload.", http.StatusInternalServerError)
+			return
+		}
+	} else if err := h.crud.ReplaceID(config.UploadsCollection, upload.ID, *upload); err != nil {
+		log.Println("Failed to update upload =>", err)
+		http.Error(w, "Failed to write upload.", http.StatusInternalServerError)
+		return
+	}
+	if copyErr != nil {
+		log.Println("Upload interrupted =>", copyErr)
+		http.Error(w, "Upload interrupted.", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	setExpires(w, upload)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// terminate removes an upload, a completed upload is also removed from the recruit's profile
+func (h *Handler) terminate(w http.ResponseWriter, upload *models.Upload) {
+	if err := h.remove(upload); err != nil {
+		http.Error(w, "Failed to remove upload.", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// -----------------
+// helpers
+// -----------------
+
+// authenticate finds the account of the access token in the Authorization header
+func (h *Handler) authenticate(r *http.Request) (*models.Account, error) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	claims, err := utils.GetTokenClaims(token)
+	if err != nil || claims.Refresh || !bson.IsObjectIdHex(claims.AccountID) {
+		return nil, er.InvalidToken()
+	}
+	rawAccount, err := h.crud.FindID(config.AccountsCollection, bson.ObjectIdHex(claims.AccountID))
+	if err != nil {
+		return nil, er.InvalidToken()
+	}
+	account := models.TransformAccount(rawAccount)
+	return &account, nil
+}
+
+// lock waits for the requests to the same upload before it to finish
+func (h *Handler) lock(id bson.ObjectId) {
+	h.locks.mu.Lock()
+	lock, ok := h.locks.byID[id]
+	if !ok {
+		lock = &uploadLock{}
+		h.locks.byID[id] = lock
+	}
+	lock.refs++
+	h.locks.mu.Unlock()
+	lock.Lock()
+}
+
+// unlock lets the next request to the upload continue, the lock is dropped
+// once no request holds it so finished and abandoned uploads don't keep theirs
+func (h *Handler) unlock(id bson.ObjectId) {
+	h.locks.mu.Lock()
+	lock := h.locks.byID[id]
+	lock.refs--
+	if lock.refs == 0 {
+		delete(h.locks.byID, id)
+	}
+	h.locks.mu.Unlock()
+	lock.Unlock()
+}
+
+// sniffed checks that the start of an upload's file is of the upload's type, types
+// that can't be told from their content are trusted
+func (h *Handler) sniffed(upload *models.Upload) bool {
+	file, err := os.Open(h.partPath(upload))
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(file, head)
+	fileType := http.DetectContentType(head[:n])
+	return fileType == upload.FileType || fileType == "application/octet-stream"
+}
+
+// complete puts a fully received upload's file in the store, stores the upload as complete
+// and sets the recruit's video url to it, replacing the earlier upload to the same target.
+// The received parts are only removed once the upload is stored, until then the client can
+// send the last part again
+func (h *Handler) complete(upload *models.Upload) error {
+	key := "uploads/" + upload.ID.Hex() + models.UploadExtensions[upload.FileType]
+	file, err := os.Open(h.partPath(upload))
+	if err != nil {
+		log.Println("Failed to open completed upload =>", err)
+		return err
+	}
+	err = h.store.Put(key, file, upload.Length, upload.FileType)
+	file.Close()
+	if err != nil {
+		log.Println("Failed to store completed upload =>", err)
+		return err
+	}
+
+	upload.URL = storage.URL(key)
+	upload.CompletedAt = upload.UpdatedAt
+	if err := h.crud.ReplaceID(config.UploadsCollection, upload.ID, *upload); err != nil {
+		log.Println("Failed to update completed upload =>", err)
+		h.store.Delete(key)
+		return err
+	}
+
+	rawUploads, err := h.crud.FindAll(config.UploadsCollection, bson.M{"recruit_id": upload.RecruitID, "target": upload.Target})
+	if err != nil {
+		log.Println("Failed to find earlier uploads =>", err)
+	}
+	for _, raw := range rawUploads {
+		if previous := models.TransformUpload(raw); previous.ID != upload.ID && previous.Complete() {
+			h.remove(&previous)
+		}
+	}
+	if err := h.setVideo(upload); err != nil {
+		return err
+	}
+
+	os.Remove(h.partPath(upload))
+	return nil
+}
+
+// setVideo sets the recruit's video url to a completed upload
+func (h *Handler) setVideo(upload *models.Upload) error {
+	field := models.UploadTargetFields[upload.Target]
+	if err := h.crud.UpdateID(config.RecruitsCollection, upload.RecruitID, bson.M{field: upload.URL}); err != nil {
+		log.Println("Failed to set recruit video =>", err)
+		return err
+	}
+	return nil
+}
+
+// remove removes an upload along with its file, a completed upload's url is
+// removed from the recruit's profile if it's still there
+func (h *Handler) remove(upload *models.Upload) error {
+	if upload.URL != "" {
+		if key, ok := storage.KeyFromURL(upload.URL); ok {
+			if err := h.store.Delete(key); err != nil {
+				log.Println("Failed to remove uploaded file =>", err)
+			}
+		}
+		field := models.UploadTargetFields[upload.Target]
+		if rawRecruit, err := h.crud.FindID(config.RecruitsCollection, upload.RecruitID); err == nil {
+			if rawRecruit.(bson.M)[field] == upload.URL {
+				h.crud.UpdateID(config.RecruitsCollection, upload.RecruitID, bson.M{field: ""})
+			}
+		}
+	}
+	os.Remove(h.partPath(upload))
+
+	if err := h.crud.DeleteID(config.UploadsCollection, upload.ID); err != nil {
+		log.Println("Failed to remove upload =>", err)
+		return err
+	}
+	return nil
+}
+
+// RemoveExpired removes the unfinished uploads that expired by now along with their parts,
+// it returns how many were removed
+func (h *Handler) RemoveExpired(now time.Time) (int, error) {
+	defer h.crud.CloseCopy()
+
+	rawUploads, err := h.crud.FindAll(config.UploadsCollection, bson.M{
+		"url":        "",
+		"updated_at": bson.M{"$lt": now.Add(-models.UploadExpiry)},
+	})
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, raw := range rawUploads {
+		id := models.TransformUpload(raw).ID
+		h.lock(id)
+		if rawUpload, err := h.crud.FindID(config.UploadsCollection, id); err == nil {
+			if upload := models.TransformUpload(rawUpload); upload.Expired(now) && h.remove(&upload) == nil {
+				removed++
+			}
+		}
+		h.unlock(id)
+	}
+	return removed, nil
+}
+
+// Run removes expired uploads every interval until stop is closed. It runs alongside
+// the requests, so it uses its own copy of the db session
+func (h *Handler) Run(interval time.Duration, stop <-chan struct{}) {
+	worker := *h
+	worker.crud = h.crud.Clone()
+	defer worker.crud.Close()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := worker.RemoveExpired(time.Now()); err != nil {
+				log.Println("Failed to remove expired uploads =>", err)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// setExpires tells the client when an unfinished upload expires
+func setExpires(w http.ResponseWriter, upload *models.Upload) {
+	if !upload.Complete() {
+		w.Header().Set("Upload-Expires", upload.ExpiresAt().UTC().Format(http.TimeFormat))
+	}
+}
+
+// partPath is the path of the file an upload's parts are written to
+func (h *Handler) partPath(upload *models.Upload) string {
+	return filepath.Join(h.dir, upload.ID.Hex()+".part")
+}
+
+// parseMetadata parses an Upload-Metadata header, comma separated keys with base64 encoded values
+func parseMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	for _, pair := range strings.Split(header, ",") {
+		fields := strings.Fields(pair)
+		if len(fields) == 0 {
+			continue
+		}
+		value := ""
+		if len(fields) > 1 {
+			decoded, err := base64.StdEncoding.DecodeString(fields[1])
+			if err != nil {
+				return nil, err
+			}
+			value = string(decoded)
+		}
+		metadata[fields[0]] = value
+	}
+	return metadata, nil
+}
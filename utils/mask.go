@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MaskPhone hides all but the last two digits of a phone number, keeping its formatting
+func MaskPhone(phone string) string {
+	digits := 0
+	for _, c := range phone {
+		if unicode.IsDigit(c) {
+			digits++
+		}
+	}
+
+	var masked strings.Builder
+	seen := 0
+	for _, c := range phone {
+		if unicode.IsDigit(c) {
+			seen++
+			if seen <= digits-2 {
+				c = '*'
+			}
+		}
+		masked.WriteRune(c)
+	}
+	return masked.String()
+}
+
+// MaskEmail hides the local part of an email address except for its first character,
+// without revealing its length
+func MaskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 1 {
+		return "***"
+	}
+	first := []rune(email[:at])[0]
+	return string(first) + "***" + email[at:]
+}
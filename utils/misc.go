@@ -20,8 +20,13 @@ func IsNullID(id bson.ObjectId) bool {
 	return id == models.NullObjectID || id == ""
 }
 
-// PickRandomN create a random subset of length n from input slice
+// PickRandomN create a random subset of length n from input slice, or of all
+// of it when it's shorter than n. The input slice is left as it is
 func PickRandomN(n int, input []interface{}) []interface{} {
+	if n > len(input) {
+		n = len(input)
+	}
+	input = append(make([]interface{}, 0, len(input)), input...)
 	out := make([]interface{}, n)
 	for i := range out {
 		index := rand.Intn(len(input))
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// Rand is a source of random picks that can be shared between requests,
+// seeding it the same way makes it pick the same values
+type Rand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+// NewRand creates a Rand from a seed
+func NewRand(seed int64) *Rand {
+	return &Rand{r: rand.New(rand.NewSource(seed))}
+}
+
+// PickWeightedN picks up to n distinct indexes of weights, an index is picked
+// with a chance proportional to its weight. Indexes without a positive weight
+// are never picked
+func (r *Rand) PickWeightedN(n int, weights []int) []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	remaining := make([]int, len(weights))
+	total := 0
+	for i, w := range weights {
+		if w > 0 {
+			remaining[i] = w
+			total += w
+		}
+	}
+
+	picked := make([]int, 0, n)
+	for len(picked) < n && total > 0 {
+		target := r.r.Intn(total)
+		for i, w := range remaining {
+			if target < w {
+				picked = append(picked, i)
+				total -= w
+				remaining[i] = 0
+				break
+			}
+			target -= w
+		}
+	}
+	return picked
+}
@@ -9,11 +9,18 @@ import (
 
 var mySigningKey = []byte("AllYourBase")
 
+// calendarPurpose is the purpose of tokens that only download an interview's calendar file
+const calendarPurpose = "calendar"
+
+// CalendarTokenExpiry is how long a calendar token can be used
+const CalendarTokenExpiry = 15 * time.Minute
+
 // Claims struct contains the jwt token claims
 type Claims struct {
 	UserAgent string `json:"user_agent"`
 	AccountID string `json:"account_id"`
 	Refresh   bool   `json:"refresh"`
+	Purpose   string `json:"purpose,omitempty"`
 	jwt.StandardClaims
 }
 
@@ -50,8 +57,47 @@ func CreateAccessToken(accountID, ua string) (string, error) {
 	return tokenStr, err
 }
 
-// GetTokenClaims parses the given token
+// CreateCalendarToken creates a short lived token that only downloads the calendar file
+// of an interview, so it can be put in a url without giving away the account
+func CreateCalendarToken(accountID, interviewID string) (string, error) {
+	_, tokenStr, err := createToken(Claims{
+		AccountID: accountID,
+		Purpose:   calendarPurpose,
+		StandardClaims: jwt.StandardClaims{
+			Subject:   interviewID,
+			ExpiresAt: time.Now().Add(CalendarTokenExpiry).Unix(),
+			IssuedAt:  time.Now().Unix(),
+		},
+	})
+	return tokenStr, err
+}
+
+// GetTokenClaims parses the given access or refresh token, tokens made for a single purpose are refused
 func GetTokenClaims(tokenString string) (*Claims, error) {
+	claims, err := parseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != "" {
+		return nil, er.InvalidToken()
+	}
+	return claims, nil
+}
+
+// GetCalendarTokenClaims parses the given calendar token, it has to be for the interview
+func GetCalendarTokenClaims(tokenString, interviewID string) (*Claims, error) {
+	claims, err := parseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != calendarPurpose || claims.Subject != interviewID {
+		return nil, er.InvalidToken()
+	}
+	return claims, nil
+}
+
+// parseToken checks the given token's signature and expiry
+func parseToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		return mySigningKey, nil
 	})